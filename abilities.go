@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// runAbilities lists every evolution's ability, marking the pet's.
+func runAbilities() error {
+	state, _ := store.Load()
+	evolution := orDefault(state.Evolution, "Lonely")
+	fmt.Printf("\n%s✨ %s%s\n\n", colorBold, i18n.Tr("Abilities"), colorReset)
+	for _, a := range pet.Abilities {
+		marker, color := "  ", ""
+		if a.Evolution == evolution {
+			marker, color = "▸ ", colorBold
+		}
+		fmt.Printf("%s%s%s %-18s%s %s\n", marker, color, padRight(i18n.Tr(a.Evolution), 9), a.Name, colorReset, a.Description)
+	}
+	fmt.Println()
+	if a, ok := pet.AbilityFor(evolution); ok {
+		fmt.Printf("%s is a %s, so %s is active.\n", state.DisplayName(), i18n.Tr(evolution), a.Name)
+	} else {
+		fmt.Printf("%sAs a %s, %s has no ability yet. Evolve into one of these to gain theirs.%s\n", colorDim, i18n.Tr(evolution), state.DisplayName(), colorReset)
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+)
+
+const adoptedFileName = "gh-pet-adopted.json"
+
+// Thresholds for a repository's health. Issues older than staleIssueAge and
+// pull requests untouched for stalePRAge weigh on the repo pet's mood.
+const (
+	staleIssueAge = 30 * 24 * time.Hour
+	stalePRAge    = 14 * 24 * time.Hour
+)
+
+const repoHealthQuery = `query($owner: String!, $repo: String!, $since: GitTimestamp!) {
+  repository(owner: $owner, name: $repo) {
+    issues(states: OPEN, first: 100, orderBy: {field: CREATED_AT, direction: ASC}) { totalCount nodes { createdAt } }
+    pullRequests(states: OPEN, first: 100) { totalCount nodes { updatedAt } }
+    defaultBranchRef { target { ... on Commit { history(since: $since) { totalCount } statusCheckRollup { state } } } }
+  }
+}`
+
+// RepoHealth is what a repo pet knows about its repository.
+type RepoHealth struct {
+	OpenIssues    int    `json:"open_issues"`
+	StaleIssues   int    `json:"stale_issues"`
+	OpenPRs       int    `json:"open_prs"`
+	StalePRs      int    `json:"stale_prs"`
+	WeeklyCommits int    `json:"weekly_commits"`
+	CI            string `json:"ci,omitempty"`
+}
+
+// RepoPet is a pet bound to one repository. Its mood comes from the repo's
+// health, not from anyone's personal activity.
+type RepoPet struct {
+	Repo      string     `json:"repo"`
+	Name      string     `json:"name,omitempty"`
+	Adopted   time.Time  `json:"adopted"`
+	Checked   time.Time  `json:"checked,omitempty"`
+	Mood      int        `json:"mood"`
+	Evolution string     `json:"evolution"`
+	Health    RepoHealth `json:"health"`
+}
+
+type AdoptedPets struct {
+	Pets []RepoPet `json:"pets"`
+}
+
+func (a *AdoptedPets) find(repo string) *RepoPet {
+	for i := range a.Pets {
+		if strings.EqualFold(a.Pets[i].Repo, repo) {
+			return &a.Pets[i]
+		}
+	}
+	return nil
+}
+
+func (p RepoPet) displayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Repo
+}
+
+func runAdopt(args []string) error {
+	fs := newFlagSet("adopt")
+	name := fs.String("name", "", "name the repo pet")
+	release := fs.Bool("release", false, "release the repo pet instead of adopting it")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	// Allow the repository before or after the flags.
+	repo := ""
+	if fs.NArg() > 0 {
+		repo = fs.Arg(0)
+		if err := parseFlags(fs, fs.Args()[1:]); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	adopted, err := loadAdopted()
+	if err != nil {
+		return err
+	}
+	if repo == "" {
+		if *release || *name != "" {
+			return usageErrorf("usage: gh pet adopt <owner/repo> [--name name | --release]")
+		}
+		if len(adopted.Pets) == 0 {
+			fmt.Println("No repo pets yet. Adopt one with: gh pet adopt <owner/repo>")
+			return nil
+		}
+		for i := range adopted.Pets {
+			if err := checkRepoPet(ctx, &adopted.Pets[i]); err != nil {
+				fmt.Fprintf(os.Stderr, "GitPet: could not check %s: %v\n", adopted.Pets[i].Repo, err)
+			}
+			fmt.Println(renderRepoPet(adopted.Pets[i], loadTheme()))
+		}
+		return saveAdopted(adopted)
+	}
+
+	if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("expected owner/repo, got %q", repo)
+	}
+	pet := adopted.find(repo)
+	if *release {
+		if pet == nil {
+			return fmt.Errorf("%s has no repo pet", repo)
+		}
+		kept := adopted.Pets[:0]
+		for _, p := range adopted.Pets {
+			if !strings.EqualFold(p.Repo, repo) {
+				kept = append(kept, p)
+			}
+		}
+		adopted.Pets = kept
+		fmt.Printf("👋 Released %s's pet. It will be fine.\n", repo)
+		return saveAdopted(adopted)
+	}
+	if pet == nil {
+		adopted.Pets = append(adopted.Pets, RepoPet{Repo: repo, Adopted: time.Now().UTC()})
+		pet = &adopted.Pets[len(adopted.Pets)-1]
+		fmt.Printf("🏠 Adopted a pet for %s!\n", repo)
+	}
+	if *name != "" {
+		if err := validateName(*name); err != nil {
+			return err
+		}
+		pet.Name = *name
+	}
+	if err := checkRepoPet(ctx, pet); err != nil {
+		return err
+	}
+	if err := saveAdopted(adopted); err != nil {
+		return err
+	}
+	fmt.Println(renderRepoPet(*pet, loadTheme()))
+	return nil
+}
+
+// checkRepoPet refreshes the pet's health from GitHub and recomputes its mood.
+func checkRepoPet(ctx context.Context, pet *RepoPet) error {
+	health, err := fetchRepoHealth(ctx, pet.Repo, time.Now())
+	if err != nil {
+		return err
+	}
+	pet.Health = health
+	pet.Mood = repoMood(health)
+	pet.Evolution = repoEvolution(health)
+	pet.Checked = time.Now().UTC()
+	return nil
+}
+
+func fetchRepoHealth(ctx context.Context, repo string, now time.Time) (RepoHealth, error) {
+	owner, name, _ := strings.Cut(repo, "/")
+	var resp struct {
+		Repository json.RawMessage `json:"repository"`
+	}
+	vars := map[string]any{"owner": owner, "repo": name, "since": now.Add(-7 * 24 * time.Hour).UTC().Format(time.RFC3339)}
+	if err := gh.GraphQL(ctx, repoHealthQuery, vars, &resp); err != nil {
+		return RepoHealth{}, err
+	}
+	var data struct {
+		Issues struct {
+			TotalCount int `json:"totalCount"`
+			Nodes      []struct {
+				CreatedAt time.Time `json:"createdAt"`
+			} `json:"nodes"`
+		} `json:"issues"`
+		PullRequests struct {
+			TotalCount int `json:"totalCount"`
+			Nodes      []struct {
+				UpdatedAt time.Time `json:"updatedAt"`
+			} `json:"nodes"`
+		} `json:"pullRequests"`
+		DefaultBranchRef *struct {
+			Target struct {
+				History struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"history"`
+				StatusCheckRollup *struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"target"`
+		} `json:"defaultBranchRef"`
+	}
+	if string(resp.Repository) == "null" || json.Unmarshal(resp.Repository, &data) != nil {
+		return RepoHealth{}, fmt.Errorf("repository %s not found", repo)
+	}
+
+	health := RepoHealth{OpenIssues: data.Issues.TotalCount, OpenPRs: data.PullRequests.TotalCount}
+	for _, issue := range data.Issues.Nodes {
+		if now.Sub(issue.CreatedAt) > staleIssueAge {
+			health.StaleIssues++
+		}
+	}
+	for _, pr := range data.PullRequests.Nodes {
+		if now.Sub(pr.UpdatedAt) > stalePRAge {
+			health.StalePRs++
+		}
+	}
+	if ref := data.DefaultBranchRef; ref != nil {
+		health.WeeklyCommits = ref.Target.History.TotalCount
+		if ref.Target.StatusCheckRollup != nil {
+			health.CI = ref.Target.StatusCheckRollup.State
+		}
+	}
+	return health, nil
+}
+
+// repoMood starts neutral, rises with recent commits from anyone, and sinks
+// with aging issues, stale pull requests, and a red default branch.
+func repoMood(h RepoHealth) int {
+	mood := 60 + min(h.WeeklyCommits, 20)*2
+	mood -= min(h.StaleIssues*2, 30)
+	mood -= min(h.StalePRs*5, 25)
+	switch h.CI {
+	case "FAILURE", "ERROR":
+		mood -= 25
+	case "PENDING", "EXPECTED":
+		mood -= 5
+	}
+	if h.WeeklyCommits == 0 {
+		mood -= 20
+	}
+	return min(max(mood, 0), 100)
+}
+
+// repoEvolution picks a form from the repo's most pressing trait.
+func repoEvolution(h RepoHealth) string {
+	switch {
+	case h.CI == "FAILURE" || h.CI == "ERROR":
+		return "Void"
+	case h.WeeklyCommits == 0:
+		return "Lonely"
+	case h.StaleIssues > 10:
+		return "Curator"
+	case h.StalePRs > 0:
+		return "Guardian"
+	case h.CI == "SUCCESS":
+		return "Sentinel"
+	default:
+		return "Pioneer"
+	}
+}
+
+// repoConcerns is the repo pet's read on its home, in its own voice.
+func repoConcerns(h RepoHealth) []string {
+	var out []string
+	switch h.CI {
+	case "FAILURE", "ERROR":
+		out = append(out, "❌ The default branch is red. I'm hiding under the desk.")
+	case "SUCCESS":
+		out = append(out, "✅ CI is green.")
+	}
+	if h.WeeklyCommits == 0 {
+		out = append(out, "🕸️  Nobody committed this week. It's quiet in here.")
+	} else {
+		out = append(out, fmt.Sprintf("🌱 %s this week.", i18n.Plural(h.WeeklyCommits, "commit")))
+	}
+	if h.StaleIssues > 0 {
+		out = append(out, fmt.Sprintf("🍂 %s open for over a month (of %d open).", i18n.Plural(h.StaleIssues, "issue"), h.OpenIssues))
+	}
+	if h.StalePRs > 0 {
+		out = append(out, fmt.Sprintf("⏳ %s waiting over two weeks.", i18n.Plural(h.StalePRs, "pull request")))
+	}
+	return out
+}
+
+func renderRepoPet(pet RepoPet, theme Theme) string {
+	color := theme.accent(pet.Evolution)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n%s%s🏠 %s%s", theme.Bold, color, pet.displayName(), theme.Reset))
+	if pet.Name != "" {
+		sb.WriteString(fmt.Sprintf(" %s(%s)%s", theme.Dim, pet.Repo, theme.Reset))
+	}
+	sb.WriteString("\n" + color + skinnedArt(pet.Evolution) + theme.Reset + "\n")
+	sb.WriteString(fmt.Sprintf("  Mood      : %s %d %s\n", renderMoodBar(pet.Mood, theme), pet.Mood, moodFace(pet.Mood)))
+	sb.WriteString(fmt.Sprintf("  Evolution : %s\n", pet.Evolution))
+	for _, concern := range repoConcerns(pet.Health) {
+		sb.WriteString("  " + concern + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func loadAdopted() (AdoptedPets, error) {
+	path, err := adoptedPath()
+	if err != nil {
+		return AdoptedPets{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return AdoptedPets{}, nil
+		}
+		return AdoptedPets{}, err
+	}
+	var adopted AdoptedPets
+	if err := json.Unmarshal(data, &adopted); err != nil {
+		return AdoptedPets{}, err
+	}
+	return adopted, nil
+}
+
+func saveAdopted(adopted AdoptedPets) error {
+	path, err := adoptedPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(adopted, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func adoptedPath() (string, error) {
+	return dirs.DataPath(adoptedFileName)
+}
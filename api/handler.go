@@ -1,14 +1,30 @@
 package handler
 
 import (
+"bytes"
+"crypto/ecdsa"
+"crypto/sha256"
+"crypto/x509"
+"encoding/base64"
 "encoding/json"
+"encoding/pem"
 "errors"
 "fmt"
+"html"
 "io"
+"log/slog"
 "math/rand"
 "net/http"
+"net/url"
+"os"
+"regexp"
+"strconv"
 "strings"
+"sync"
+"sync/atomic"
 "time"
+
+"github.com/gitpet/gh-pet/internal/pet"
 )
 
 type Request struct {
@@ -16,39 +32,24 @@ Input string `json:"input"`
 User  struct {
 Login string `json:"login"`
 } `json:"user"`
+// Confirmations answer confirmation events from earlier replies, such as
+// the one @gitpet feed sends before it saves anything.
+Confirmations []ConfirmationReply `json:"confirmations,omitempty"`
 }
 
-type Event struct {
-Type      string          `json:"type"`
-CreatedAt time.Time       `json:"created_at"`
-Payload   json.RawMessage `json:"payload"`
-}
-
-type PushPayload struct {
-Size    int `json:"size"`
-Commits []struct {
-Message string `json:"message"`
-} `json:"commits"`
+// ConfirmationReply is the user accepting or dismissing a confirmation.
+type ConfirmationReply struct {
+State        string           `json:"state"`
+Confirmation feedConfirmation `json:"confirmation"`
 }
 
-type PullRequestPayload struct {
-PullRequest struct {
-Merged bool `json:"merged"`
-} `json:"pull_request"`
-}
-
-type ActivitySummary struct {
-Commits         int
-MergedPRs       int
-Reviews         int
-Issues          int
-DocComments     int
-RefactorCommits int
-NewRepos        int
-LargeCommits    int
-FixCommits      int
-DocCommits      int
-}
+// The handler scores with the CLI's internal/pet, so a week earns the same
+// in Copilot Chat as it does in the terminal.
+type (
+Event           = pet.Event
+ActivitySummary = pet.ActivitySummary
+ScoringConfig   = pet.ScoringConfig
+)
 
 type PetState struct {
 Mood      int
@@ -56,27 +57,124 @@ Kindness  int
 Logic     int
 Evolution string
 Activity  ActivitySummary
+Name      string
+Pronouns  string
+Emoji     string
 }
 
-const (
-colorYellow  = "\x1b[33m"
-colorBlue    = "\x1b[34m"
-colorMagenta = "\x1b[35m"
-colorGrey    = "\x1b[37m"
-colorReset   = "\x1b[0m"
-)
+// applyIdentity names the pet. The handler has no state file, so the name,
+// pronouns, and emoji come from GITPET_NAME, GITPET_PRONOUNS, and GITPET_EMOJI.
+func applyIdentity(state *PetState) {
+state.Name = strings.TrimSpace(os.Getenv("GITPET_NAME"))
+if state.Name == "" {
+state.Name = "GitPet"
+}
+state.Pronouns = strings.TrimSpace(os.Getenv("GITPET_PRONOUNS"))
+state.Emoji = strings.TrimSpace(os.Getenv("GITPET_EMOJI"))
+if state.Emoji == "" {
+state.Emoji = "🐾"
+}
+}
+
+// loadScoring reads the CLI's scoring weights. The handler has no config
+// file, so overrides come from the GITPET_SCORING environment variable as the
+// same JSON object the CLI reads under "scoring".
+func loadScoring() (ScoringConfig, error) {
+cfg := pet.DefaultScoring()
+raw := strings.TrimSpace(os.Getenv("GITPET_SCORING"))
+if raw == "" {
+return cfg, nil
+}
+if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+return pet.DefaultScoring(), fmt.Errorf("invalid GITPET_SCORING: %w", err)
+}
+if err := cfg.Validate(); err != nil {
+return pet.DefaultScoring(), fmt.Errorf("invalid GITPET_SCORING: %w", err)
+}
+return cfg, nil
+}
+
+// logger writes JSON lines to stderr, where Vercel collects function logs.
+// GITPET_DEBUG adds a line per GitHub call with its timing and rate limit.
+var logger = newLogger()
+
+// requests counts invocations handled by this instance, for the opt-in
+// GITPET_TELEMETRY=1 request log. Nothing identifying is recorded.
+var requests atomic.Int64
+
+func newLogger() *slog.Logger {
+level := slog.LevelInfo
+if os.Getenv("GITPET_DEBUG") != "" {
+level = slog.LevelDebug
+}
+return slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+}
+
+func logRequest(route, auth string, start time.Time) {
+if os.Getenv("GITPET_TELEMETRY") != "1" {
+return
+}
+logger.Info("request", "route", route, "auth", auth, "instance_requests", requests.Add(1), "duration_ms", time.Since(start).Milliseconds())
+}
 
 func Handler(w http.ResponseWriter, r *http.Request) {
+start := time.Now()
+route, authKind := "chat", "anonymous"
+defer func() { logRequest(route, authKind, start) }()
+if r.Method == http.MethodGet {
+route = "card"
+if r.URL.Query().Has("org") {
+route = "org"
+serveOrg(w, r)
+return
+}
+serveCard(w, r)
+return
+}
 if r.Method != http.MethodPost {
 http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 return
 }
 
+body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+if err != nil {
+http.Error(w, "cannot read body", http.StatusBadRequest)
+return
+}
+auth, err := authenticate(r, body)
+if err != nil {
+logger.Debug("rejected request", "err", err)
+authKind = "rejected"
+http.Error(w, err.Error(), http.StatusUnauthorized)
+return
+}
+authKind = auth.kind()
+
 var req Request
-if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+if err := json.Unmarshal(body, &req); err != nil {
 http.Error(w, "invalid json", http.StatusBadRequest)
 return
 }
+if org, team, ok := orgQuery(req.Input); ok {
+route = "org"
+dashboard, err := loadOrgDashboard(auth, org, team)
+if err != nil {
+writeError(w, err)
+return
+}
+writeText(w, dashboard.text())
+return
+}
+if len(req.Confirmations) > 0 {
+route = "confirm"
+confirmFeed(w, auth, req)
+return
+}
+if feedQueryPattern.MatchString(req.Input) {
+route = "feed"
+proposeFeed(w, auth, req)
+return
+}
 login := strings.TrimSpace(req.User.Login)
 if login == "" {
 login = guessLogin(req.Input)
@@ -86,18 +184,606 @@ writeError(w, errors.New("missing user login"))
 return
 }
 
-client := http.Client{Timeout: 10 * time.Second}
+events, err := fetchEvents(auth, login)
+if err != nil {
+writeError(w, err)
+return
+}
+
+scoring, err := loadScoring()
+if err != nil {
+writeError(w, err)
+return
+}
+
+state := buildState(events, scoring)
+// A pet saved by @gitpet feed is the user's own; show it rather than a
+// snapshot, for the user only.
+if store, ok := openStore(); ok && auth.authenticated && strings.EqualFold(login, req.User.Login) {
+if saved, found, err := store.load(login); err != nil {
+logger.Debug("store load failed", "err", err)
+} else if found {
+state = saved.state(state.Activity)
+}
+}
+applyIdentity(&state)
+text := renderStatus(state, login, cardImageURL(login))
+if !auth.authenticated {
+text += "\n\n_Public activity only: this request carried no GitHub-signed token._"
+}
+writeText(w, text)
+}
+
+// Cards are cached by browsers and GitHub's image proxy; error cards expire
+// sooner so a typo or a rate limit doesn't stick around.
+const (
+cardCacheControl  = "public, max-age=1800, s-maxage=1800, stale-while-revalidate=3600"
+errorCacheControl = "public, max-age=300"
+)
+
+var loginPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38}$`)
+
+// serveCard answers GET /api/card?login=<user> with an SVG of that user's pet,
+// for embedding in a profile README. It only reads public events and never
+// uses a token sent with the request.
+func serveCard(w http.ResponseWriter, r *http.Request) {
+w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+login := strings.TrimPrefix(strings.TrimSpace(r.URL.Query().Get("login")), "@")
+if !loginPattern.MatchString(login) {
+w.Header().Set("Cache-Control", errorCacheControl)
+fmt.Fprint(w, errorCard("Add ?login=your-username"))
+return
+}
+
+events, err := fetchEvents(githubAuth{}, login)
+if err != nil {
+w.Header().Set("Cache-Control", errorCacheControl)
+fmt.Fprint(w, errorCard("Could not fetch @"+login))
+return
+}
+scoring, err := loadScoring()
+if err != nil {
+scoring = pet.DefaultScoring()
+}
+state := buildState(events, scoring)
+applyIdentity(&state)
+w.Header().Set("Cache-Control", cardCacheControl)
+fmt.Fprint(w, renderCard(state, login))
+}
+
+// renderCard draws the pet's name, evolution, mood bar, and weekly activity in
+// the evolution's color.
+func renderCard(state PetState, login string) string {
+color := hexFor(state.Evolution)
+a := state.Activity
+return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="360" height="110" viewBox="0 0 360 110" role="img" aria-label="%[2]s: %[4]s, mood %[6]d">
+<rect x="1" y="1" width="358" height="108" rx="12" fill="#1e1e24" stroke="%[1]s" stroke-width="2"/>
+<text x="16" y="32" font-family="sans-serif" font-size="17" font-weight="bold" fill="%[1]s">%[2]s</text>
+<text x="344" y="32" font-family="sans-serif" font-size="12" fill="#8a8f98" text-anchor="end">@%[3]s</text>
+<text x="16" y="54" font-family="sans-serif" font-size="13" fill="#c8c8d0">%[4]s · %[5]s</text>
+<rect x="16" y="66" width="280" height="10" rx="5" fill="#3a3a44"/>
+<rect x="16" y="66" width="%[7]d" height="10" rx="5" fill="%[1]s"/>
+<text x="344" y="76" font-family="sans-serif" font-size="12" fill="#c8c8d0" text-anchor="end">%[6]d/100</text>
+<text x="16" y="97" font-family="sans-serif" font-size="11" fill="#8a8f98">7d: %[8]d commits · %[9]d PRs · %[10]d reviews · %[11]d docs</text>
+</svg>
+`, color, html.EscapeString(state.Emoji+" "+state.Name), html.EscapeString(login), state.Evolution, moodDescriptor(state.Mood), state.Mood, state.Mood*280/100, a.Commits, a.MergedPRs, a.Reviews, a.DocComments)
+}
+
+func errorCard(message string) string {
+return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="360" height="60" viewBox="0 0 360 60" role="img" aria-label="%[1]s">
+<rect x="1" y="1" width="358" height="58" rx="12" fill="#1e1e24" stroke="#8a8f98" stroke-width="2"/>
+<text x="16" y="35" font-family="sans-serif" font-size="13" fill="#c8c8d0">🐾 %[1]s</text>
+</svg>
+`, html.EscapeString(message))
+}
+
+// Org dashboards fetch every member's events, so each instance keeps them
+// for a while. orgMaxMembers bounds the calls one dashboard makes; larger
+// orgs show their first members alphabetically.
+const (
+orgCacheTTL      = 10 * time.Minute
+orgMaxMembers    = 24
+orgFetchParallel = 6
+orgColumns       = 4
+)
+
+var (
+teamPattern     = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,99}$`)
+orgQueryPattern = regexp.MustCompile(`(?i)^\s*(?:@\S+\s+)?(?:org\s+@?([A-Za-z0-9-]+)|team\s+@?([A-Za-z0-9-]+)/([A-Za-z0-9_.-]+))\s*$`)
+)
+
+// miniPet is one member's pet on an org dashboard.
+type miniPet struct {
+Login string
+State PetState
+// Failed is set when the member's events couldn't be fetched.
+Failed bool
+}
+
+// orgDashboard is a pet per member of an org, or of one of its teams.
+type orgDashboard struct {
+Org, Team string
+Pets      []miniPet
+// More counts members past orgMaxMembers that were left out.
+More int
+}
+
+var orgCache struct {
+sync.Mutex
+entries map[string]orgCacheEntry
+}
+
+type orgCacheEntry struct {
+fetched   time.Time
+dashboard orgDashboard
+}
+
+// orgQuery reads a chat message of "org acme" or "team acme/backend",
+// after an optional @mention.
+func orgQuery(input string) (org, team string, ok bool) {
+m := orgQueryPattern.FindStringSubmatch(input)
+if m == nil {
+return "", "", false
+}
+org, team = m[1], ""
+if org == "" {
+org, team = m[2], m[3]
+}
+return org, team, loginPattern.MatchString(org)
+}
+
+// serveOrg answers GET /api/org?org=<org>[&team=<slug>] with an SVG grid of
+// its members' pets. Without a token only public members are listed; a token
+// in the Authorization header shows the members it can see and unlocks
+// teams, and the answer is then cached privately.
+func serveOrg(w http.ResponseWriter, r *http.Request) {
+w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+query := r.URL.Query()
+org := strings.TrimPrefix(strings.TrimSpace(query.Get("org")), "@")
+team := strings.TrimSpace(query.Get("team"))
+if !loginPattern.MatchString(org) || team != "" && !teamPattern.MatchString(team) {
+w.Header().Set("Cache-Control", errorCacheControl)
+fmt.Fprint(w, errorCard("Add ?org=your-org, and &team=slug for one team"))
+return
+}
 token := readToken(r)
-events, err := fetchEvents(client, login, token)
+dashboard, err := loadOrgDashboard(githubAuth{token: token, authenticated: token != ""}, org, team)
+if err != nil {
+w.Header().Set("Cache-Control", errorCacheControl)
+fmt.Fprint(w, errorCard(err.Error()))
+return
+}
+if token != "" {
+w.Header().Set("Cache-Control", "private, max-age=600")
+} else {
+w.Header().Set("Cache-Control", cardCacheControl)
+}
+fmt.Fprint(w, dashboard.svg())
+}
+
+// loadOrgDashboard builds a pet for each member from their public events,
+// from the cache when it's fresh. Entries are kept per token, so members
+// only a token can see never reach a caller without it.
+func loadOrgDashboard(auth githubAuth, org, team string) (orgDashboard, error) {
+digest := sha256.Sum256([]byte(auth.token))
+key := strings.ToLower(org+"/"+team) + "@" + base64.RawURLEncoding.EncodeToString(digest[:8])
+orgCache.Lock()
+if entry, ok := orgCache.entries[key]; ok && time.Since(entry.fetched) < orgCacheTTL {
+orgCache.Unlock()
+return entry.dashboard, nil
+}
+orgCache.Unlock()
+
+if team != "" && !auth.authenticated {
+return orgDashboard{}, errors.New("team dashboards need a GitHub token")
+}
+url := fmt.Sprintf("https://api.github.com/orgs/%s/members?per_page=100", org)
+if team != "" {
+url = fmt.Sprintf("https://api.github.com/orgs/%s/teams/%s/members?per_page=100", org, team)
+}
+var members []struct {
+Login string `json:"login"`
+}
+if err := githubGet(auth, "members", url, &members); err != nil {
+return orgDashboard{}, fmt.Errorf("could not list members of %s: %w", strings.TrimSuffix(org+"/"+team, "/"), err)
+}
+scoring, err := loadScoring()
+if err != nil {
+scoring = pet.DefaultScoring()
+}
+d := orgDashboard{Org: org, Team: team}
+if len(members) > orgMaxMembers {
+d.More = len(members) - orgMaxMembers
+members = members[:orgMaxMembers]
+}
+d.Pets = make([]miniPet, len(members))
+var wg sync.WaitGroup
+slots := make(chan struct{}, orgFetchParallel)
+for i, m := range members {
+wg.Add(1)
+go func(i int, login string) {
+defer wg.Done()
+slots <- struct{}{}
+defer func() { <-slots }()
+d.Pets[i].Login = login
+events, err := fetchEvents(auth, login)
+if err != nil {
+d.Pets[i].Failed = true
+return
+}
+d.Pets[i].State = buildState(events, scoring)
+}(i, m.Login)
+}
+wg.Wait()
+
+orgCache.Lock()
+if orgCache.entries == nil {
+orgCache.entries = map[string]orgCacheEntry{}
+}
+for k, entry := range orgCache.entries {
+if time.Since(entry.fetched) >= orgCacheTTL {
+delete(orgCache.entries, k)
+}
+}
+orgCache.entries[key] = orgCacheEntry{fetched: time.Now(), dashboard: d}
+orgCache.Unlock()
+return d, nil
+}
+
+// name is the org, or org/team.
+func (d orgDashboard) name() string {
+if d.Team != "" {
+return d.Org + "/" + d.Team
+}
+return d.Org
+}
+
+// health is the average mood and how many pets are Radiant, Steady, Faint,
+// or Quiet, over the members whose events were fetched.
+func (d orgDashboard) health() (average int, moods map[string]int) {
+moods = map[string]int{}
+counted := 0
+for _, p := range d.Pets {
+if p.Failed {
+continue
+}
+average += p.State.Mood
+moods[moodDescriptor(p.State.Mood)]++
+counted++
+}
+if counted > 0 {
+average /= counted
+}
+return average, moods
+}
+
+// text is the dashboard for Copilot Chat: a line of team health, then a
+// line per member.
+func (d orgDashboard) text() string {
+if len(d.Pets) == 0 {
+return fmt.Sprintf("%s has no members I can see. A token from a member shows private memberships.", d.name())
+}
+average, moods := d.health()
+var parts []string
+for _, mood := range []string{"Radiant", "Steady", "Faint", "Quiet"} {
+if moods[mood] > 0 {
+parts = append(parts, fmt.Sprintf("%d %s", moods[mood], mood))
+}
+}
+health := fmt.Sprintf("Team health for %s: average mood %d/100", d.name(), average)
+if len(parts) > 0 {
+health += " (" + strings.Join(parts, ", ") + ")"
+}
+// Chat renders Markdown; a code block keeps the columns lined up.
+lines := []string{health + ".", "", "```"}
+for _, p := range d.Pets {
+if p.Failed {
+lines = append(lines, fmt.Sprintf("@%s: couldn't fetch activity", p.Login))
+continue
+}
+a := p.State.Activity
+filled := p.State.Mood / 10
+lines = append(lines, fmt.Sprintf("@%-20s %-8s %s%s %3d  %dc %dp %dr", p.Login, p.State.Evolution, strings.Repeat("█", filled), strings.Repeat("░", 10-filled), p.State.Mood, a.Commits, a.MergedPRs, a.Reviews))
+}
+lines = append(lines, "```")
+if d.More > 0 {
+lines = append(lines, fmt.Sprintf("…and %d more members.", d.More))
+}
+return strings.Join(lines, "\n")
+}
+
+// svg draws the dashboard as a grid of mini cards under a health summary.
+func (d orgDashboard) svg() string {
+const cellW, cellH, top = 180, 64, 52
+rows := (len(d.Pets) + orgColumns - 1) / orgColumns
+width := orgColumns*cellW + 16
+height := top + rows*cellH + 12
+average, _ := d.health()
+var sb strings.Builder
+fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="%[2]d" viewBox="0 0 %[1]d %[2]d" role="img" aria-label="%[3]s team health">
+<rect x="1" y="1" width="%[4]d" height="%[5]d" rx="12" fill="#1e1e24" stroke="#8a8f98" stroke-width="2"/>
+<text x="16" y="32" font-family="sans-serif" font-size="17" font-weight="bold" fill="#c8c8d0">🐾 %[3]s</text>
+<text x="%[6]d" y="32" font-family="sans-serif" font-size="12" fill="#8a8f98" text-anchor="end">%[7]d members · average mood %[8]d/100</text>
+`, width, height, html.EscapeString(d.name()), width-2, height-2, width-16, len(d.Pets)+d.More, average)
+for i, p := range d.Pets {
+x, y := 8+(i%orgColumns)*cellW, top+(i/orgColumns)*cellH
+color, label := hexFor(p.State.Evolution), fmt.Sprintf("%s · %d/100", p.State.Evolution, p.State.Mood)
+if p.Failed {
+color, label = "#8a8f98", "activity unavailable"
+}
+fmt.Fprintf(&sb, `<g transform="translate(%d %d)">
+<rect x="0" y="0" width="%d" height="%d" rx="8" fill="#26262e" stroke="%s"/>
+<text x="10" y="20" font-family="sans-serif" font-size="12" font-weight="bold" fill="%s">@%s</text>
+<text x="10" y="36" font-family="sans-serif" font-size="11" fill="#c8c8d0">%s</text>
+<rect x="10" y="44" width="150" height="6" rx="3" fill="#3a3a44"/>
+<rect x="10" y="44" width="%d" height="6" rx="3" fill="%s"/>
+</g>
+`, x, y, cellW-8, cellH-8, color, color, html.EscapeString(p.Login), label, p.State.Mood*150/100, color)
+}
+sb.WriteString("</svg>\n")
+return sb.String()
+}
+
+// feedQueryPattern matches a chat message asking to feed the pet, after an
+// optional @mention.
+var feedQueryPattern = regexp.MustCompile(`(?i)^\s*(?:@\S+\s+)?feed\s*$`)
+
+// storeClient talks to the pet store; a slow store fails the request rather
+// than hang it.
+var storeClient = &http.Client{Timeout: 5 * time.Second}
+
+// petStore keeps each user's pet between requests in Vercel KV, or any
+// Redis with Upstash's REST API, named by KV_REST_API_URL and
+// KV_REST_API_TOKEN. Without them the handler stays stateless.
+type petStore struct {
+url, token string
+}
+
+// savedPet is a user's pet as @gitpet feed left it. Version goes up with
+// every save, so a confirmation can't be accepted twice.
+type savedPet struct {
+Mood      int       `json:"mood"`
+Kindness  int       `json:"kindness"`
+Logic     int       `json:"logic"`
+Evolution string    `json:"evolution"`
+LastFed   time.Time `json:"last_fed"`
+Version   int       `json:"version"`
+}
+
+// feedConfirmation is what a feed's confirmation carries back: whose pet,
+// and which saved version the preview was built on.
+type feedConfirmation struct {
+ID    string `json:"id"`
+Login string `json:"login"`
+Base  int    `json:"base"`
+}
+
+func openStore() (petStore, bool) {
+s := petStore{url: strings.TrimRight(os.Getenv("KV_REST_API_URL"), "/"), token: os.Getenv("KV_REST_API_TOKEN")}
+return s, s.url != "" && s.token != ""
+}
+
+func storeKey(login string) string {
+return "gitpet:pet:" + strings.ToLower(login)
+}
+
+// load returns login's saved pet, and false if there isn't one yet.
+func (s petStore) load(login string) (savedPet, bool, error) {
+var reply struct {
+Result *string `json:"result"`
+}
+if err := s.call(http.MethodGet, "/get/"+storeKey(login), nil, &reply); err != nil {
+return savedPet{}, false, err
+}
+if reply.Result == nil {
+return savedPet{}, false, nil
+}
+var saved savedPet
+if err := json.Unmarshal([]byte(*reply.Result), &saved); err != nil {
+return savedPet{}, false, fmt.Errorf("stored pet for %s is unreadable: %w", login, err)
+}
+return saved, true, nil
+}
+
+// saveIfScript sets KEYS[1] to ARGV[2] only while the pet stored there is
+// still version ARGV[1], with no pet counting as version 0. Redis runs it
+// atomically, so two confirmations of the same preview can't both save.
+const saveIfScript = `local cur = redis.call('GET', KEYS[1])
+local version = 0
+if cur then version = cjson.decode(cur).version or 0 end
+if version ~= tonumber(ARGV[1]) then return 0 end
+redis.call('SET', KEYS[1], ARGV[2])
+return 1`
+
+// saveIf saves login's pet if the stored one is still version base, and
+// reports whether it did.
+func (s petStore) saveIf(login string, base int, saved savedPet) (bool, error) {
+data, err := json.Marshal(saved)
+if err != nil {
+return false, err
+}
+command, err := json.Marshal([]string{"EVAL", saveIfScript, "1", storeKey(login), strconv.Itoa(base), string(data)})
+if err != nil {
+return false, err
+}
+var reply struct {
+Result int `json:"result"`
+}
+if err := s.call(http.MethodPost, "", command, &reply); err != nil {
+return false, err
+}
+return reply.Result == 1, nil
+}
+
+func (s petStore) call(method, path string, body []byte, v any) error {
+req, err := http.NewRequest(method, s.url+path, bytes.NewReader(body))
+if err != nil {
+return err
+}
+req.Header.Set("Authorization", "Bearer "+s.token)
+resp, err := storeClient.Do(req)
+if err != nil {
+return fmt.Errorf("pet store: %w", err)
+}
+defer resp.Body.Close()
+if resp.StatusCode >= 400 {
+return fmt.Errorf("pet store: %s", resp.Status)
+}
+if v == nil {
+return nil
+}
+return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// state is the saved pet with this week's activity.
+func (p savedPet) state(week ActivitySummary) PetState {
+return PetState{Mood: p.Mood, Kindness: p.Kindness, Logic: p.Logic, Evolution: p.Evolution, Activity: week}
+}
+
+// fed is the pet after a feed. A first feed hatches it from the week's
+// activity, like a snapshot; later feeds add what happened since the last
+// one, scored the way the CLI scores a feed, so a feed with nothing new
+// costs idle_mood_decay mood.
+func (p savedPet) fed(found bool, events []Event, scoring ScoringConfig, now time.Time) savedPet {
+if !found {
+s := buildState(events, scoring)
+return savedPet{Mood: s.Mood, Kindness: s.Kindness, Logic: s.Logic, Evolution: s.Evolution, LastFed: now, Version: p.Version + 1}
+}
+// The pet's ability is the one it had before this feed.
+feed := scoring.WithAbility(p.Evolution, now)
+stats := pet.Stats{Mood: p.Mood, Kindness: p.Kindness, Logic: p.Logic}
+feed.Apply(&stats, scoredSince(events, feed, p.LastFed), 0)
+week := scoredSince(events, scoring, now.Add(-pet.SummaryWindow))
+return savedPet{Mood: stats.Mood, Kindness: stats.Kindness, Logic: stats.Logic, Evolution: pet.EvolutionFor(week), LastFed: now, Version: p.Version + 1}
+}
+
+// feedPreview loads login's saved pet and works out what feeding it now
+// would save, along with the week's activity.
+func feedPreview(auth githubAuth, store petStore, login string) (before, after savedPet, week ActivitySummary, found bool, err error) {
+if before, found, err = store.load(login); err != nil {
+return
+}
+events, err := fetchEvents(auth, login)
+if err != nil {
+return
+}
+scoring, err := loadScoring()
+if err != nil {
+return
+}
+now := time.Now()
+return before, before.fed(found, events, scoring, now), scoredSince(events, scoring, now.Add(-pet.SummaryWindow)), found, nil
+}
+
+// proposeFeed answers @gitpet feed with what the feed would change, and a
+// confirmation event; nothing is saved until the user accepts it.
+func proposeFeed(w http.ResponseWriter, auth githubAuth, req Request) {
+login := strings.TrimSpace(req.User.Login)
+store, ok := openStore()
+switch {
+case !ok:
+writeText(w, "This deployment has no pet store, so every reply is a fresh snapshot. Set KV_REST_API_URL and KV_REST_API_TOKEN to keep a pet between chats.")
+return
+case !auth.authenticated || login == "":
+writeText(w, "Feeding saves your pet, so it needs a request signed by GitHub with your token.")
+return
+}
+before, after, _, found, err := feedPreview(auth, store, login)
+if err != nil {
+writeError(w, err)
+return
+}
+var state PetState
+applyIdentity(&state)
+message := fmt.Sprintf("This hatches %s as a %s with mood %d, kindness %d, and %d logic shards.", state.Name, after.Evolution, after.Mood, after.Kindness, after.Logic)
+if found {
+message = fmt.Sprintf("%s goes from mood %d to %d, kindness %d to %d, and logic shards %d to %d, as a %s.", state.Name, before.Mood, after.Mood, before.Kindness, after.Kindness, before.Logic, after.Logic, after.Evolution)
+}
+confirmation, _ := json.Marshal(map[string]any{
+"type":         "action",
+"title":        fmt.Sprintf("Feed %s?", state.Name),
+"message":      message,
+"confirmation": feedConfirmation{ID: "feed", Login: login, Base: before.Version},
+})
+w.Header().Set("Content-Type", "application/x-ndjson")
+w.WriteHeader(http.StatusOK)
+writeEvent(w, "ack", "")
+writeEvent(w, "text", message+" Confirm to save it.")
+writeEvent(w, "confirmation", string(confirmation))
+writeEvent(w, "done", "")
+}
+
+// confirmFeed saves the feed the user accepted, unless the pet was fed
+// again since the preview.
+func confirmFeed(w http.ResponseWriter, auth githubAuth, req Request) {
+reply := req.Confirmations[0]
+login := strings.TrimSpace(req.User.Login)
+if reply.Confirmation.ID != "feed" {
+writeText(w, "I don't recognize that confirmation.")
+return
+}
+if reply.State != "accepted" {
+writeText(w, "No problem. Nothing was saved.")
+return
+}
+store, ok := openStore()
+if !ok || !auth.authenticated || !strings.EqualFold(login, reply.Confirmation.Login) {
+writeText(w, "That feed can't be saved from here; ask @gitpet feed again.")
+return
+}
+before, after, week, _, err := feedPreview(auth, store, login)
 if err != nil {
 writeError(w, err)
 return
 }
+saved := false
+if before.Version == reply.Confirmation.Base {
+if saved, err = store.saveIf(login, before.Version, after); err != nil {
+writeError(w, err)
+return
+}
+}
+if !saved {
+writeText(w, "Your pet was fed since that preview. Ask @gitpet feed again to see what's new.")
+return
+}
+state := after.state(week)
+applyIdentity(&state)
+writeText(w, "Saved! 🍖\n\n"+renderStatus(state, login, cardImageURL(login)))
+}
+
+func hexFor(evolution string) string {
+switch evolution {
+case "Pioneer":
+return "#f2b134"
+case "Guardian":
+return "#4a90e2"
+case "Bard":
+return "#c86dd7"
+case "Sentinel":
+return "#2ec4b6"
+case "Curator":
+return "#6abf69"
+default:
+return "#8a8f98"
+}
+}
 
-summary := summarize(events)
-state := buildState(summary)
-text := renderStatus(state, login)
+func moodDescriptor(mood int) string {
+switch {
+case mood >= 70:
+return "Radiant"
+case mood >= 40:
+return "Steady"
+case mood > 0:
+return "Faint"
+default:
+return "Quiet"
+}
+}
 
+// writeText answers a chat request with one message.
+func writeText(w http.ResponseWriter, text string) {
 w.Header().Set("Content-Type", "application/x-ndjson")
 w.WriteHeader(http.StatusOK)
 writeEvent(w, "ack", "")
@@ -122,146 +808,307 @@ writeEvent(w, "text", fmt.Sprintf("GitPet stumbled: %s", err.Error()))
 writeEvent(w, "done", "")
 }
 
-func fetchEvents(client http.Client, login, token string) ([]Event, error) {
-url := fmt.Sprintf("https://api.github.com/users/%s/events", login)
-req, err := http.NewRequest(http.MethodGet, url, nil)
+// githubClient is shared across invocations so warm serverless instances
+// reuse their connections to api.github.com.
+var githubClient = &http.Client{
+Timeout: 10 * time.Second,
+Transport: &http.Transport{
+Proxy:               http.ProxyFromEnvironment,
+MaxIdleConns:        20,
+MaxIdleConnsPerHost: 20,
+IdleConnTimeout:     90 * time.Second,
+},
+}
+
+// githubAuth is how a request may call GitHub. Only payloads signed by GitHub
+// get to use the caller's token; everything else is anonymous, sees public
+// events only, and shares GitHub's unauthenticated rate limit.
+type githubAuth struct {
+token         string
+authenticated bool
+}
+
+func (a githubAuth) kind() string {
+if a.authenticated {
+return "authenticated"
+}
+return "anonymous"
+}
+
+// authenticate checks the Copilot signature on body. Unsigned requests are
+// rejected unless GITPET_ALLOW_UNSIGNED=1, which is meant for local testing
+// and always runs anonymously.
+func authenticate(r *http.Request, body []byte) (githubAuth, error) {
+keyID := r.Header.Get("X-GitHub-Public-Key-Identifier")
+signature := r.Header.Get("X-GitHub-Public-Key-Signature")
+if keyID == "" && signature == "" {
+if os.Getenv("GITPET_ALLOW_UNSIGNED") == "1" {
+return githubAuth{}, nil
+}
+return githubAuth{}, errors.New("missing Copilot signature headers")
+}
+if err := verifySignature(keyID, signature, body); err != nil {
+return githubAuth{}, err
+}
+token := readToken(r)
+return githubAuth{token: token, authenticated: token != ""}, nil
+}
+
+const (
+copilotKeysURL = "https://api.github.com/meta/public_keys/copilot_api"
+copilotKeysTTL = time.Hour
+// An unknown key identifier refreshes the keys at most this often, so
+// rotations are picked up without letting callers hammer the endpoint.
+copilotKeysMinRefresh = time.Minute
+)
+
+var copilotKeys struct {
+sync.Mutex
+fetched time.Time
+keys    map[string]*ecdsa.PublicKey
+}
+
+func verifySignature(keyID, signature string, body []byte) error {
+key, err := copilotKey(keyID)
 if err != nil {
-return nil, err
+return err
 }
-req.Header.Set("Accept", "application/vnd.github+json")
-req.Header.Set("User-Agent", "gitpet-copilot-extension")
-if token != "" {
-req.Header.Set("Authorization", "Bearer "+token)
+sig, err := base64.StdEncoding.DecodeString(signature)
+if err != nil {
+return errors.New("malformed Copilot signature")
+}
+digest := sha256.Sum256(body)
+if !ecdsa.VerifyASN1(key, digest[:], sig) {
+return errors.New("invalid Copilot signature")
+}
+return nil
 }
 
-resp, err := client.Do(req)
+func copilotKey(id string) (*ecdsa.PublicKey, error) {
+copilotKeys.Lock()
+defer copilotKeys.Unlock()
+age := time.Since(copilotKeys.fetched)
+if key, ok := copilotKeys.keys[id]; ok && age < copilotKeysTTL {
+return key, nil
+}
+if copilotKeys.keys == nil || age >= copilotKeysMinRefresh {
+keys, err := fetchCopilotKeys()
 if err != nil {
 return nil, err
 }
-defer resp.Body.Close()
-if resp.StatusCode >= 400 {
-body, _ := io.ReadAll(resp.Body)
-return nil, fmt.Errorf("github api error: %s", strings.TrimSpace(string(body)))
+copilotKeys.keys = keys
+copilotKeys.fetched = time.Now()
+}
+if key, ok := copilotKeys.keys[id]; ok {
+return key, nil
+}
+return nil, fmt.Errorf("unknown Copilot key %q", id)
 }
 
-var events []Event
-if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+func fetchCopilotKeys() (map[string]*ecdsa.PublicKey, error) {
+req, err := http.NewRequest(http.MethodGet, copilotKeysURL, nil)
+if err != nil {
 return nil, err
 }
-return events, nil
+req.Header.Set("Accept", "application/vnd.github+json")
+req.Header.Set("User-Agent", "gitpet-copilot-extension")
+resp, err := githubClient.Do(req)
+if err != nil {
+return nil, fmt.Errorf("cannot fetch Copilot keys: %w", err)
+}
+defer resp.Body.Close()
+if resp.StatusCode != http.StatusOK {
+return nil, fmt.Errorf("cannot fetch Copilot keys: %s", resp.Status)
+}
+var payload struct {
+PublicKeys []struct {
+KeyIdentifier string `json:"key_identifier"`
+Key           string `json:"key"`
+} `json:"public_keys"`
+}
+if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+return nil, fmt.Errorf("cannot parse Copilot keys: %w", err)
+}
+keys := map[string]*ecdsa.PublicKey{}
+for _, k := range payload.PublicKeys {
+block, _ := pem.Decode([]byte(k.Key))
+if block == nil {
+continue
 }
-
-func summarize(events []Event) ActivitySummary {
-cutoff := time.Now().Add(-7 * 24 * time.Hour)
-summary := ActivitySummary{}
-for _, event := range events {
-if event.CreatedAt.Before(cutoff) {
+parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+if err != nil {
 continue
 }
-switch event.Type {
-case "PushEvent":
-var payload PushPayload
-if json.Unmarshal(event.Payload, &payload) == nil {
-summary.Commits += len(payload.Commits)
-if payload.Size >= 10 {
-summary.LargeCommits++
+if key, ok := parsed.(*ecdsa.PublicKey); ok {
+keys[k.KeyIdentifier] = key
 }
-for _, commit := range payload.Commits {
-classifyCommit(commit.Message, &summary)
 }
+return keys, nil
 }
-case "PullRequestEvent":
-var payload PullRequestPayload
-if json.Unmarshal(event.Payload, &payload) == nil && payload.PullRequest.Merged {
-summary.MergedPRs++
+
+// fetchEvents is login's recent public events, less what automation did
+// for them, as the CLI leaves it out; see pet.BotConfig.HumanEvents.
+func fetchEvents(auth githubAuth, login string) ([]Event, error) {
+var events []Event
+if err := githubGet(auth, "events", fmt.Sprintf("https://api.github.com/users/%s/events", login), &events); err != nil {
+return nil, err
 }
-case "PullRequestReviewEvent", "PullRequestReviewCommentEvent":
-summary.Reviews++
-summary.DocComments++
-case "IssueCommentEvent":
-summary.DocComments++
-case "IssuesEvent":
-summary.Issues++
-case "CreateEvent":
-summary.NewRepos++
+return pet.BotConfig{}.HumanEvents(events), nil
 }
+
+// githubGet decodes the JSON at url into v. endpoint names the call in debug
+// logs.
+func githubGet(auth githubAuth, endpoint, url string, v any) error {
+req, err := http.NewRequest(http.MethodGet, url, nil)
+if err != nil {
+return err
 }
-return summary
+req.Header.Set("Accept", "application/vnd.github+json")
+req.Header.Set("User-Agent", "gitpet-copilot-extension")
+if auth.authenticated {
+req.Header.Set("Authorization", "Bearer "+auth.token)
 }
 
-func classifyCommit(message string, summary *ActivitySummary) {
-lower := strings.ToLower(message)
-if strings.Contains(lower, "fix") || strings.Contains(lower, "bug") {
-summary.FixCommits++
+callStart := time.Now()
+resp, err := githubClient.Do(req)
+if err != nil {
+logger.Debug("github call failed", "endpoint", endpoint, "auth", auth.kind(), "err", err)
+return err
+}
+defer resp.Body.Close()
+logger.Debug("github call", "endpoint", endpoint, "auth", auth.kind(), "status", resp.StatusCode,
+"duration_ms", time.Since(callStart).Milliseconds(), "rate_remaining", resp.Header.Get("X-RateLimit-Remaining"))
+if (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+reset := "later"
+if epoch, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+reset = time.Unix(epoch, 0).UTC().Format("15:04 MST")
 }
-if strings.Contains(lower, "doc") || strings.Contains(lower, "readme") || strings.Contains(lower, "comment") {
-summary.DocCommits++
+return fmt.Errorf("GitHub's %s rate limit is used up; try again after %s", auth.kind(), reset)
 }
-if strings.Contains(lower, "refactor") || strings.Contains(lower, "cleanup") || strings.Contains(lower, "remove") || strings.Contains(lower, "delete") {
-summary.RefactorCommits++
+if resp.StatusCode >= 400 {
+body, _ := io.ReadAll(resp.Body)
+return fmt.Errorf("github api error: %s", strings.TrimSpace(string(body)))
 }
+return json.NewDecoder(resp.Body).Decode(v)
 }
 
-func buildState(summary ActivitySummary) PetState {
-activityTotal := summary.Commits + summary.MergedPRs + summary.Reviews + summary.DocComments + summary.RefactorCommits + summary.NewRepos + summary.Issues
-mood := 5
-if activityTotal == 0 {
-mood = 2
-} else {
-mood = min(100, 10+summary.Commits+summary.MergedPRs*5+summary.Reviews+summary.DocComments+summary.Issues)
+// scoredSince counts what events since cutoff did that earns anything,
+// leaving out the commits the CLI wouldn't credit either; see
+// pet.ScoringConfig.DiscountCommits.
+func scoredSince(events []Event, scoring ScoringConfig, cutoff time.Time) ActivitySummary {
+return scoring.DiscountCommits(events, pet.Summarize(events, cutoff), cutoff)
 }
+
+// buildState is a pet fed once with the past week's events, like the CLI's
+// shadow pet: it starts from a low mood, like a fresh egg, and holds only
+// what that one feed earns.
+func buildState(events []Event, scoring ScoringConfig) PetState {
+summary := scoredSince(events, scoring, time.Now().Add(-pet.SummaryWindow))
+stats := pet.Stats{Mood: 10}
+scoring.Apply(&stats, summary, 0)
 return PetState{
-Mood:      mood,
-Kindness:  summary.Reviews * 2,
-Logic:     summary.Commits + summary.MergedPRs*3,
-Evolution: evolutionFor(summary),
+Mood:      stats.Mood,
+Kindness:  stats.Kindness,
+Logic:     stats.Logic,
+Evolution: pet.EvolutionFor(summary),
 Activity:  summary,
 }
 }
 
-func renderStatus(state PetState, login string) string {
-art := renderArt(state)
-lines := []string{
-"GitPet Status",
-fmt.Sprintf("Keeper: %s", login),
-fmt.Sprintf("Evolution: %s", state.Evolution),
-fmt.Sprintf("Mood: %d | Kindness: %d | Logic Shards: %d", state.Mood, state.Kindness, state.Logic),
-fmt.Sprintf("Activity (7d): Commits %d, Merged PRs %d, Reviews %d, Issues %d, Docs/Comments %d", state.Activity.Commits, state.Activity.MergedPRs, state.Activity.Reviews, state.Activity.Issues, state.Activity.DocComments),
-activityTone(state.Activity),
-art,
-}
+// renderStatus writes the pet for Copilot Chat, which renders GitHub-flavored
+// Markdown rather than terminal colors: the art in a code block, mood as a
+// bar of emoji, and the stats in tables. imageURL, when set, adds the pet's
+// SVG card above them.
+func renderStatus(state PetState, login, imageURL string) string {
+name := markdownEscaper.Replace(state.Name)
+title := fmt.Sprintf("### %s %s", state.Emoji, name)
+if state.Pronouns != "" {
+title += fmt.Sprintf(" (%s)", markdownEscaper.Replace(state.Pronouns))
+}
+lines := []string{title + " · " + state.Evolution, ""}
+if imageURL != "" {
+lines = append(lines, fmt.Sprintf("![%s's card](%s)", name, imageURL), "")
+}
+art, special := renderArt(state)
+lines = append(lines, "```text", art, "```")
+if special != "" {
+lines = append(lines, "*"+special+"*")
+}
+a := state.Activity
+lines = append(lines, "",
+"| Stat | Value |",
+"|---|---|",
+fmt.Sprintf("| Mood | %s %d/100 · %s |", moodBar(state.Mood), state.Mood, moodDescriptor(state.Mood)),
+fmt.Sprintf("| Kindness | %d |", state.Kindness),
+fmt.Sprintf("| Logic Shards | %d |", state.Logic),
+"",
+"| Activity (7d) | Count |",
+"|---|---:|",
+fmt.Sprintf("| Commits | %d |", a.Commits),
+fmt.Sprintf("| Merged PRs | %d |", a.MergedPRs),
+fmt.Sprintf("| Reviews | %d |", a.Reviews),
+fmt.Sprintf("| Issues | %d |", pet.IssueTriage(a)),
+fmt.Sprintf("| Docs/Comments | %d |", a.DocComments),
+"",
+activityTone(name, a),
+"",
+"Keeper: @"+markdownEscaper.Replace(login),
+)
 return strings.Join(lines, "\n")
 }
 
-func renderArt(state PetState) string {
-if state.Evolution == "Lonely" {
-return "(._.)\n /|\\\n / \\\nThe Cache is quiet..."
-}
-color := colorFor(state.Evolution)
-reset := colorReset
-halo := ""
-if state.Kindness >= 2 {
-halo = fmt.Sprintf("%s  _  %s\n", color, reset)
+// markdownEscaper backslash-escapes what Markdown would read as formatting
+// in names from the environment or the chat.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "|", `\|`, "<", `\<`, ">", `\>`, "#", `\#`)
+
+// moodBar is mood as ten emoji squares, green when Radiant, yellow when
+// Steady, and red below.
+func moodBar(mood int) string {
+square := "🟥"
+switch {
+case mood >= 70:
+square = "🟩"
+case mood >= 40:
+square = "🟨"
 }
-special := ""
-if state.Evolution == "Pioneer" && rand.Intn(5) == 0 {
-special = fmt.Sprintf("%sFound a tiny treasure chest!%s", color, reset)
+filled := min(mood/10, 10)
+return strings.Repeat(square, filled) + strings.Repeat("⬜", 10-filled)
 }
-if state.Evolution == "Guardian" {
-special = fmt.Sprintf("%sShielding your logs: You got this.%s", color, reset)
+
+// cardImageURL is the pet's SVG card from this deployment, when
+// GITPET_PUBLIC_URL says where it's reachable, e.g.
+// https://gitpet.vercel.app. The card shows public activity only.
+func cardImageURL(login string) string {
+base := strings.TrimRight(strings.TrimSpace(os.Getenv("GITPET_PUBLIC_URL")), "/")
+if base == "" || !loginPattern.MatchString(login) {
+return ""
 }
-if state.Evolution == "Bard" {
-special = fmt.Sprintf("%sProverb: %s%s", color, dailyProverb(), reset)
+return base + "/api/card?login=" + url.QueryEscape(login)
 }
-art := artFor(state.Evolution)
-art = color + art + reset
-if halo != "" {
-art = halo + art
+
+// renderArt is the pet's ASCII art, with a halo once it's kind, and the line
+// its evolution adds below, if any.
+func renderArt(state PetState) (art, special string) {
+if state.Evolution == "Lonely" {
+return "(._.)\n /|\\\n / \\", "The Cache is quiet..."
 }
-if special != "" {
-return art + "\n" + special
+switch {
+case pet.FoundTreasure(state.Evolution):
+special = "Found a tiny treasure chest!"
+case state.Evolution == "Guardian":
+special = "Shielding your logs: You got this."
+case state.Evolution == "Bard":
+special = "Proverb: " + dailyProverb()
+case state.Evolution == "Sentinel":
+special = "Every test is a watchtower."
+case state.Evolution == "Curator":
+special = "Tending the issue garden."
+}
+art = artFor(state.Evolution)
+if state.Kindness >= 2 {
+art = "  _\n" + art
 }
-return art
+return art, special
 }
 
 func artFor(evolution string) string {
@@ -272,6 +1119,10 @@ case "Guardian":
 return "[===]\n(o_o)\n/|=|\\\n / \\"
 case "Bard":
 return " ~~~\n(o o)\n/|~|\\\n / \\\n (_)"
+case "Sentinel":
+return "[_v_]\n(o_o)\n/|v|\\\n / \\"
+case "Curator":
+return " [#]\n(o o)\n/|=|\\\n / \\"
 case "Void":
 return " . .\n( . )\n . ."
 default:
@@ -279,58 +1130,17 @@ return "(o_o)\n /|\\\n / \\"
 }
 }
 
-func evolutionFor(summary ActivitySummary) string {
-if summary.Commits+summary.MergedPRs+summary.Reviews+summary.DocComments+summary.RefactorCommits+summary.NewRepos+summary.Issues == 0 {
-return "Lonely"
-}
-pioneer := summary.Commits + summary.NewRepos*2
-guardian := summary.Reviews*2 + summary.MergedPRs*2 + summary.FixCommits
-bard := summary.DocComments*2 + summary.DocCommits
-voidScore := summary.RefactorCommits * 2
-best := "Pioneer"
-bestScore := pioneer
-if guardian > bestScore {
-best = "Guardian"
-bestScore = guardian
-}
-if bard > bestScore {
-best = "Bard"
-bestScore = bard
-}
-if voidScore > bestScore {
-best = "Void"
-bestScore = voidScore
-}
-_ = bestScore
-return best
-}
-
-func colorFor(evolution string) string {
-switch evolution {
-case "Pioneer":
-return colorYellow
-case "Guardian":
-return colorBlue
-case "Bard":
-return colorMagenta
-case "Void":
-return colorGrey
-default:
-return colorGrey
-}
-}
-
-func activityTone(summary ActivitySummary) string {
-total := summary.Commits + summary.MergedPRs + summary.Reviews + summary.DocComments + summary.NewRepos + summary.RefactorCommits + summary.Issues
+func activityTone(name string, summary ActivitySummary) string {
+total := pet.Activity(summary)
 switch {
 case total >= 20:
-return "Intensity: blazing. GitPet is thriving in the Cache."
+return fmt.Sprintf("Intensity: blazing. %s is thriving in the Cache.", name)
 case total >= 8:
-return "Intensity: steady. GitPet hums with creative heat."
+return fmt.Sprintf("Intensity: steady. %s hums with creative heat.", name)
 case total >= 1:
-return "Intensity: gentle. GitPet feels acknowledged."
+return fmt.Sprintf("Intensity: gentle. %s feels acknowledged.", name)
 default:
-return "Intensity: quiet. GitPet grows a little lonely."
+return fmt.Sprintf("Intensity: quiet. %s grows a little lonely.", name)
 }
 }
 
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+// trustKey makes key the only Copilot key, as if just fetched, so nothing
+// reaches GitHub.
+func trustKey(t *testing.T, id string, key *ecdsa.PublicKey) {
+	t.Helper()
+	copilotKeys.Lock()
+	defer copilotKeys.Unlock()
+	saved, fetched := copilotKeys.keys, copilotKeys.fetched
+	copilotKeys.keys = map[string]*ecdsa.PublicKey{id: key}
+	copilotKeys.fetched = time.Now()
+	t.Cleanup(func() {
+		copilotKeys.Lock()
+		defer copilotKeys.Unlock()
+		copilotKeys.keys, copilotKeys.fetched = saved, fetched
+	})
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, body []byte) string {
+	t.Helper()
+	digest := sha256.Sum256(body)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustKey(t, "copilot-1", &key.PublicKey)
+
+	body := []byte(`{"messages":[{"role":"user","content":"feed"}]}`)
+	tests := []struct {
+		name      string
+		keyID     string
+		signature string
+		body      []byte
+		wantErr   bool
+	}{
+		{"signed by the key", "copilot-1", sign(t, key, body), body, false},
+		{"body changed", "copilot-1", sign(t, key, body), []byte(`{"messages":[]}`), true},
+		{"signed by another key", "copilot-1", sign(t, other, body), body, true},
+		{"unknown key", "copilot-2", sign(t, key, body), body, true},
+		{"not base64", "copilot-1", "%%%", body, true},
+		{"empty signature", "copilot-1", "", body, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySignature(tt.keyID, tt.signature, tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifySignature() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFedScoresLikeTheCLI(t *testing.T) {
+	now := time.Now()
+	push := func(sha, message string, at time.Time) Event {
+		payload := fmt.Sprintf(`{"ref":"refs/heads/main","commits":[{"sha":%q,"message":%q}]}`, sha, message)
+		return Event{Type: "PushEvent", CreatedAt: at, Payload: json.RawMessage(payload)}
+	}
+	// Newest first, as GitHub lists them; the second push repeats the first.
+	var events []Event
+	for i := range 30 {
+		events = append(events, push(fmt.Sprintf("%040d", i), "ship it", now.Add(-time.Duration(i+1)*time.Minute)))
+	}
+	events = append(events, push(fmt.Sprintf("%040d", 0), "ship it", now.Add(-time.Hour)))
+
+	scoring := pet.DefaultScoring()
+	before := savedPet{Mood: 50, LastFed: now.Add(-2 * time.Hour), Evolution: "Pioneer"}
+	after := before.fed(true, events, scoring, now)
+	if gain := after.Mood - before.Mood; gain > scoring.MaxFeedMood {
+		t.Errorf("mood rose %d in one feed, more than max_feed_mood %d", gain, scoring.MaxFeedMood)
+	}
+	if got := buildState(events, scoring).Activity; got.IgnoredCommits == 0 {
+		t.Errorf("buildState credited every commit: %+v", got)
+	}
+
+	quiet := before.fed(true, nil, scoring, now)
+	if want := before.Mood - scoring.IdleMoodDecay; quiet.Mood != want {
+		t.Errorf("quiet feed: mood = %d, want %d", quiet.Mood, want)
+	}
+}
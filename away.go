@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// awayMaxDays is the longest holiday gh pet away books at once.
+const awayMaxDays = 90
+
+// beachArt is the pet on holiday.
+const beachArt = `   \ | /        ___
+  -- O --      /___\
+   / | \         |
+     (˘ ▽ ˘)っ🍹 |
+~~~~~~~~~~~~~~~~~~~~~`
+
+// runAway sends the pet on holiday until a date, brings it home early with
+// --end, or says where it is.
+func runAway(args []string) error {
+	fs := newFlagSet("away")
+	until := fs.String("until", "", "the last day you're away, as YYYY-MM-DD")
+	end := fs.Bool("end", false, "come home before the holiday is over")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *until != "" && *end {
+		return usageErrorf("--until and --end can't be combined")
+	}
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if state.Departed != "" {
+		return errDeparted
+	}
+	now := time.Now()
+	switch {
+	case *end:
+		return endAway(state, now)
+	case *until != "":
+		return startAway(state, *until, now)
+	}
+	if !state.Away(now) {
+		fmt.Printf("%s is home. Going offline? gh pet away --until YYYY-MM-DD\n", state.DisplayName())
+		return nil
+	}
+	fmt.Println(awayLine(state))
+	return nil
+}
+
+// startAway books a holiday from today through until, or moves the end of
+// the one under way.
+func startAway(state store.PetState, until string, now time.Time) error {
+	last, err := time.ParseInLocation(store.DayLayout, until, time.Local)
+	if err != nil {
+		return usageErrorf("--until must be a date like %s", now.AddDate(0, 0, 7).Format(store.DayLayout))
+	}
+	today := now.Format(store.DayLayout)
+	if until < today {
+		return usageErrorf("--until %s is already over", until)
+	}
+	if last.Sub(periodStart("day", now)) > awayMaxDays*24*time.Hour {
+		return usageErrorf("--until can be at most %d days away", awayMaxDays)
+	}
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	if state.Away(now) && len(history.Away) > 0 {
+		history.Away[len(history.Away)-1].To = until
+	} else {
+		history.Away = append(history.Away, store.AwaySpan{From: today, To: until})
+	}
+	if err := store.SaveHistory(history); err != nil {
+		return err
+	}
+	state.AwayUntil = until
+	// The countdown to the Void starts over when the Keeper is back.
+	state.MoodZeroSince = ""
+	if err := store.Save(state); err != nil {
+		return err
+	}
+	if err := store.AddJournalEntry("away", fmt.Sprintf("Off to the beach until %s. I'll keep your streak warm.", last.Format("Jan 2"))); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+	if professional() {
+		fmt.Printf("Away until %s. Mood decay and streak loss are paused.\n", until)
+		return nil
+	}
+	fmt.Println(beachArt)
+	fmt.Printf("\n%s🏖️  %s is off to the beach until %s.%s\n", colorBold, state.DisplayName(), last.Format("Mon Jan 2"), colorReset)
+	waits := "Mood won't fade while you're gone."
+	if streak := store.CurrentStreak(history, now); streak > 0 {
+		waits = fmt.Sprintf("Mood won't fade, and your %d-day streak waits for you.", streak)
+	}
+	fmt.Printf("%s%s Home early? gh pet away --end%s\n", colorDim, waits, colorReset)
+	return nil
+}
+
+// endAway brings the pet home before the holiday is over.
+func endAway(state store.PetState, now time.Time) error {
+	if !state.Away(now) {
+		return fmt.Errorf("%s isn't away", state.DisplayName())
+	}
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	today := now.Format(store.DayLayout)
+	if n := len(history.Away); n > 0 && history.Away[n-1].To > today {
+		history.Away[n-1].To = today
+		if err := store.SaveHistory(history); err != nil {
+			return err
+		}
+	}
+	days := comeHome(&state, history, now, true)
+	if err := store.Save(state); err != nil {
+		return err
+	}
+	printReunion(state, days)
+	return nil
+}
+
+// comeHome clears a holiday that's over, or any holiday when early, and
+// returns how many days it lasted; 0 means the Keeper wasn't coming home.
+func comeHome(state *store.PetState, history store.History, now time.Time, early bool) int {
+	if state.AwayUntil == "" || !early && state.Away(now) {
+		return 0
+	}
+	state.AwayUntil = ""
+	days := 1
+	if n := len(history.Away); n > 0 {
+		from, err1 := time.ParseInLocation(store.DayLayout, history.Away[n-1].From, time.Local)
+		to, err2 := time.ParseInLocation(store.DayLayout, history.Away[n-1].To, time.Local)
+		if err1 == nil && err2 == nil {
+			days = int(to.Sub(from).Hours()/24+0.5) + 1
+		}
+	}
+	if err := store.AddJournalEntry("away", fmt.Sprintf("You're back after %s! I saved you the best spot on the towel.", i18n.Plural(days, "day"))); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+	return days
+}
+
+// awayLine says where the pet is while the Keeper is away.
+func awayLine(state store.PetState) string {
+	until := state.AwayUntil
+	if t, err := time.ParseInLocation(store.DayLayout, until, time.Local); err == nil {
+		until = t.Format("Mon Jan 2")
+	}
+	if professional() {
+		return fmt.Sprintf("Away until %s. Mood decay and streak loss are paused.", until)
+	}
+	return "🏖️  On holiday until " + until
+}
+
+// printReunion greets the Keeper home, with a short run up the beach on a
+// terminal.
+func printReunion(state store.PetState, days int) {
+	if professional() {
+		fmt.Printf("Back after %s away.\n", i18n.Plural(days, "day"))
+		return
+	}
+	if stdoutIsTerminal() {
+		for _, frame := range []string{"🏖️  …", "🏖️  👀 is that…", "🏃 …it is!", "🤗"} {
+			fmt.Printf("\r%s %s\033[K", state.Signature(), frame)
+			time.Sleep(400 * time.Millisecond)
+		}
+		fmt.Print("\r\033[K")
+	}
+	fmt.Println(reunionLine(state, days))
+}
+
+// reunionLine welcomes the Keeper home after days away.
+func reunionLine(state store.PetState, days int) string {
+	return fmt.Sprintf("%s🤗 Welcome home! %s missed you for %s and kept your streak safe.%s", colorGreen, state.DisplayName(), i18n.Plural(days, "day"), colorReset)
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// archivePages is how many pages of 100 events GitHub serves before it stops
+// paginating: 300 events at most, from the last 90 days.
+const archivePages = 3
+
+// gitCommit is a commit found by git log in a repo with the hook.
+type gitCommit struct {
+	When    time.Time
+	Subject string
+}
+
+// runBackfill rebuilds the history GitPet missed from GitHub's events
+// archive, and optionally from git log in the repos with the hook, so a new
+// Keeper's streak starts where their work did. Weeks from before the pet
+// began are credited to it too, for logic, kindness, and achievements.
+func runBackfill(args []string) error {
+	fs := newFlagSet("backfill")
+	withGit := fs.Bool("git", false, "also count your commits from git log in the repos with the GitPet hook")
+	since := fs.String("since", "", "how far back git log reaches, as YYYY-MM-DD (default: a year ago)")
+	dryRun := fs.Bool("dry-run", false, "show what would be filled in without changing anything")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	now := time.Now()
+	from := now.AddDate(-1, 0, 0)
+	if *since != "" {
+		if !*withGit {
+			return usageErrorf("--since only applies with --git")
+		}
+		t, err := time.ParseInLocation(store.DayLayout, *since, time.Local)
+		if err != nil || !t.Before(now) {
+			return usageErrorf("--since must be a past date like %s", from.Format(store.DayLayout))
+		}
+		from = t
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if state.Departed != "" {
+		return errDeparted
+	}
+
+	ctx := context.Background()
+	stop := startSpinner("Reading your activity archive…")
+	login, err := ghLogin(ctx)
+	if err != nil {
+		stop()
+		return err
+	}
+	events, err := archiveEvents(ctx, login)
+	if err != nil {
+		stop()
+		return err
+	}
+	events = cfg.repoFilter().Events(cfg.Bots.HumanEvents(events))
+	var commits []gitCommit
+	if *withGit {
+		commits = gitLogCommits(ctx, cfg.Repos, from)
+	}
+	stop()
+
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	creditEnd := creditStart(state, history, now)
+	filled, earliest := backfillHistory(&history, events, commits)
+	pet := petExists()
+	var weeks []ActivitySummary
+	var logic, kindness int
+	var unlocked []string
+	if pet {
+		weeks = creditWeeks(events, commits, login, earliest, creditEnd)
+		for _, week := range weeks {
+			logic += cfg.Scoring.LogicFor(week)
+			kindness += cfg.Scoring.KindnessFor(week)
+			probe := state
+			probe.Activity = week
+			unlocked = append(unlocked, store.UnlockAchievements(&probe)...)
+			state.Achievements = probe.Achievements
+		}
+	}
+	if filled == 0 && len(weeks) == 0 {
+		fmt.Println("Nothing to backfill; your history already covers what GitHub still remembers.")
+		return nil
+	}
+	if !*dryRun {
+		if filled > 0 {
+			if err := store.SaveHistory(history); err != nil {
+				return err
+			}
+		}
+		if len(weeks) > 0 {
+			state.Logic += logic
+			state.Kindness += kindness
+			state.Backfilled = earliest.Format(store.DayLayout)
+			if err := store.Save(state); err != nil {
+				return err
+			}
+		}
+	}
+
+	verb := "Backfilled"
+	if *dryRun {
+		verb = "Would backfill"
+	}
+	fmt.Printf("\n%s📼 %s %s's history%s\n\n", colorBold, verb, state.DisplayName(), colorReset)
+	row := func(name, what string) {
+		fmt.Printf("  %-9s %s\n", name, what)
+	}
+	if len(events) > 0 {
+		row("GitHub", fmt.Sprintf("%s, back to %s", i18n.Plural(len(events), "event"), events[len(events)-1].CreatedAt.Local().Format("Jan 2")))
+	} else {
+		row("GitHub", "no events in the last 90 days")
+	}
+	if *withGit {
+		row("Git", fmt.Sprintf("%s since %s, from %s", i18n.Plural(len(commits), "commit"), from.Format("Jan 2, 2006"), i18n.Plural(len(cfg.Repos), "repo")))
+	}
+	row("History", fmt.Sprintf("%s filled in", i18n.Plural(filled, "day")))
+	if streak := store.CurrentStreak(history, now); streak > 0 {
+		row("Streak", i18n.Plural(streak, "day"))
+	}
+	switch {
+	case len(weeks) > 0:
+		row("Credit", fmt.Sprintf("+%d logic shards and +%d kindness for %s before %s began", logic, kindness, i18n.Plural(len(weeks), "week"), state.DisplayName()))
+	case !pet:
+		row("Credit", "none yet; gh pet hatch, then backfill again to credit the weeks before")
+	}
+	for _, name := range unlocked {
+		fmt.Printf("  %s🏆 %s%s\n", colorYellow, name, colorReset)
+	}
+	if *dryRun {
+		fmt.Printf("\n%sDry run; nothing was changed.%s\n", colorDim, colorReset)
+	}
+	return nil
+}
+
+// archiveEvents reads as much of login's events feed as GitHub serves,
+// newest first.
+func archiveEvents(ctx context.Context, login string) ([]Event, error) {
+	var all []Event
+	for page := 1; page <= archivePages; page++ {
+		var events []Event
+		if err := gh.Get(ctx, fmt.Sprintf("users/%s/events?per_page=100&page=%d", login, page), &events); err != nil {
+			// Past the pages it keeps, GitHub answers 422.
+			var apiErr *gh.APIError
+			if page > 1 && errors.As(err, &apiErr) && apiErr.Status == http.StatusUnprocessableEntity {
+				break
+			}
+			return nil, err
+		}
+		all = append(all, events...)
+		if len(events) < 100 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// gitLogCommits finds the commits authored since from, by the email each repo
+// has configured, across the repos with the hook. A commit in two clones is
+// counted once.
+func gitLogCommits(ctx context.Context, repos []string, from time.Time) []gitCommit {
+	seen := map[string]bool{}
+	var commits []gitCommit
+	for _, repo := range repos {
+		email, err := gh.GitOutput(ctx, "-C", repo, "config", "user.email")
+		if err != nil || len(strings.TrimSpace(string(email))) == 0 {
+			logging.Logger.Debug("backfill: no author email", "repo", repo, "err", err)
+			continue
+		}
+		out, err := gh.GitOutput(ctx, "-C", repo, "log", "--all", "--no-merges",
+			"--since="+from.Format(time.RFC3339), "--author="+regexp.QuoteMeta(strings.TrimSpace(string(email))),
+			"--format=%H%x09%aI%x09%s")
+		if err != nil {
+			logging.Logger.Debug("backfill: git log", "repo", repo, "err", err)
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			parts := strings.SplitN(line, "\t", 3)
+			if len(parts) < 3 || seen[parts[0]] {
+				continue
+			}
+			when, err := time.Parse(time.RFC3339, parts[1])
+			if err != nil {
+				continue
+			}
+			seen[parts[0]] = true
+			commits = append(commits, gitCommit{When: when, Subject: parts[2]})
+		}
+	}
+	return commits
+}
+
+// commitSummary counts commits the way a feed counts pushed ones.
+func commitSummary(commits []gitCommit) ActivitySummary {
+	var s ActivitySummary
+	for _, c := range commits {
+		s.Commits++
+		pet.ClassifyCommit(c.Subject, &s)
+	}
+	return s
+}
+
+// backfillHistory folds events and commits into the day records, keeping
+// whichever count saw more, and returns how many days it filled in or
+// raised and the earliest day with activity. Months already rolled up by
+// retention are left alone, so nothing is counted twice.
+func backfillHistory(history *store.History, events []Event, commits []gitCommit) (int, time.Time) {
+	summaries := map[string]ActivitySummary{}
+	for date, dayEvents := range store.EventsByDay(events) {
+		summaries[date] = pet.Summarize(dayEvents, time.Time{})
+	}
+	byDay := map[string][]gitCommit{}
+	for _, c := range commits {
+		date := c.When.Local().Format(store.DayLayout)
+		byDay[date] = append(byDay[date], c)
+	}
+	for date, dayCommits := range byDay {
+		s := summaries[date]
+		if git := commitSummary(dayCommits); git.Commits > s.Commits {
+			s.Commits, s.TestCommits = git.Commits, git.TestCommits
+		}
+		summaries[date] = s
+	}
+
+	filled := 0
+	var earliest time.Time
+	for date, s := range summaries {
+		day, _ := time.ParseInLocation(store.DayLayout, date, time.Local)
+		if earliest.IsZero() || day.Before(earliest) {
+			earliest = day
+		}
+		if history.RolledUp(date) {
+			continue
+		}
+		rec := history.Day(date)
+		before := *rec
+		rec.Fold(s)
+		if *rec != before {
+			filled++
+		}
+	}
+	return filled, earliest
+}
+
+// creditStart is the day the pet's own feeds begin: where the last backfill
+// credited back to, else when it hatched, else its earliest record. A feed
+// looks back a week, so that week is never credited either.
+func creditStart(state store.PetState, history store.History, now time.Time) time.Time {
+	start := now
+	for _, date := range []string{state.Backfilled, state.Hatched} {
+		if date != "" {
+			start, _ = time.ParseInLocation(store.DayLayout, date, time.Local)
+			break
+		}
+	}
+	if state.Backfilled == "" && state.Hatched == "" && len(history.Days) > 0 {
+		start = history.Days[0].Day()
+	}
+	if week := now.Add(-pet.SummaryWindow); state.Backfilled == "" && week.Before(start) {
+		start = week
+	}
+	return start
+}
+
+// creditWeeks sums the activity from earliest up to end a week at a time,
+// for crediting the pet with what it wasn't there to see. Within a week,
+// events and git log each count commits; whichever saw more is used.
+func creditWeeks(events []Event, commits []gitCommit, login string, earliest, end time.Time) []ActivitySummary {
+	var weeks []ActivitySummary
+	for to := end; !earliest.IsZero() && to.After(earliest); to = to.Add(-pet.SummaryWindow) {
+		from := to.Add(-pet.SummaryWindow)
+		var weekEvents []Event
+		for _, e := range events {
+			if !e.CreatedAt.Before(from) && e.CreatedAt.Before(to) {
+				weekEvents = append(weekEvents, e)
+			}
+		}
+		var weekCommits []gitCommit
+		for _, c := range commits {
+			if !c.When.Before(from) && c.When.Before(to) {
+				weekCommits = append(weekCommits, c)
+			}
+		}
+		s := pet.Summarize(weekEvents, time.Time{})
+		if git := commitSummary(weekCommits); git.Commits > s.Commits {
+			s.Commits, s.FixCommits, s.DocCommits, s.RefactorCommits, s.TestCommits = git.Commits, git.FixCommits, git.DocCommits, git.RefactorCommits, git.TestCommits
+		}
+		s.FirstTimers = activity.FirstTimerHelps(weekEvents, login)
+		if len(weekEvents) > 0 || s.Commits > 0 {
+			weeks = append(weeks, s)
+		}
+	}
+	return weeks
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+)
+
+const (
+	pendingFileName = "gh-pet-pending.txt"
+	// pendingTTL is how long a background sync's card waits for a prompt;
+	// after that it's old news and is dropped unseen.
+	pendingTTL = time.Hour
+)
+
+// spawnBackgroundSync starts gh pet post-commit --background detached from
+// the hook, so the commit finishes without waiting on the network.
+func spawnBackgroundSync() error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot find GitPet binary: %w", err)
+	}
+	cmd := exec.Command(exePath, "post-commit", "--background")
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// savePending keeps a background sync's card for the next prompt to show.
+func savePending(card string) error {
+	path, err := pendingPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(card), 0o600)
+}
+
+// takePending returns the card a background sync left, once: it's removed
+// as it's read. Cards older than pendingTTL are dropped.
+func takePending(now time.Time) (string, bool) {
+	path, err := pendingPath()
+	if err != nil {
+		return "", false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if os.Remove(path) != nil || err != nil || now.Sub(info.ModTime()) > pendingTTL {
+		return "", false
+	}
+	return string(data), true
+}
+
+// showPending prints a background sync's card straight to the terminal,
+// above the prompt that's being drawn. The prompt itself is captured by the
+// shell, so stdout can't carry it. Without a terminal the card stays for
+// the next prompt that has one.
+func showPending(now time.Time) {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer tty.Close()
+	if card, ok := takePending(now); ok {
+		fmt.Fprint(tty, card)
+	}
+}
+
+func pendingPath() (string, error) {
+	return dirs.DataPath(pendingFileName)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// boxMinWidth is the narrowest a box gets, even in a tiny terminal.
+const boxMinWidth = 24
+
+// box lays out framed output such as the status card. Rows are measured by
+// display width, so emoji, CJK text, and color codes don't push the right
+// border out of line. The frame grows to fit its widest row, up to the width
+// of the terminal; rows wider than that wrap.
+type box struct {
+	theme Theme
+	color string
+	// label is set into the top border, like a tab.
+	label string
+	rows  []boxRow
+}
+
+type boxRow struct {
+	text     string
+	centered bool
+	divider  bool
+}
+
+func newBox(theme Theme, color string) *box {
+	return &box{theme: theme, color: color}
+}
+
+// line adds a row of text, indented by two columns.
+func (bx *box) line(text string) {
+	bx.rows = append(bx.rows, boxRow{text: text})
+}
+
+// lines adds each line of a multi-line string, such as pet art.
+func (bx *box) lines(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		bx.line(line)
+	}
+}
+
+// title adds a row centered between the borders.
+func (bx *box) title(text string) {
+	bx.rows = append(bx.rows, boxRow{text: text, centered: true})
+}
+
+func (bx *box) divider() {
+	bx.rows = append(bx.rows, boxRow{divider: true})
+}
+
+// render draws the box at least minWidth columns wide inside the borders.
+func (bx *box) render(minWidth int) string {
+	inner := minWidth
+	for _, row := range bx.rows {
+		inner = max(inner, displayWidth(row.text)+3)
+	}
+	if bx.label != "" {
+		inner = max(inner, displayWidth(bx.label)+6)
+	}
+	if cols := terminalWidth(); cols > 0 {
+		inner = max(min(inner, cols-2), boxMinWidth)
+	}
+
+	b, t := bx.theme.Border, bx.theme
+	v := bx.color + b.Vertical + t.Reset
+	var sb strings.Builder
+	if bx.label != "" {
+		label := truncateWidth(bx.label, inner-6)
+		sb.WriteString(t.Bold + bx.color + b.TopLeft + strings.Repeat(b.Horizontal, 4) + label +
+			strings.Repeat(b.Horizontal, inner-4-displayWidth(label)) + b.TopRight + t.Reset + "\n")
+	} else {
+		sb.WriteString(t.Bold + bx.color + b.top(inner) + t.Reset + "\n")
+	}
+	for _, row := range bx.rows {
+		switch {
+		case row.divider:
+			sb.WriteString(bx.color + b.divider(inner) + t.Reset + "\n")
+		case row.centered:
+			for _, line := range wrapWidth(row.text, inner-2) {
+				sb.WriteString(v + " " + centered(line, inner-2) + " " + v + "\n")
+			}
+		default:
+			for _, line := range wrapWidth(row.text, inner-3) {
+				sb.WriteString(v + "  " + padRight(line, inner-3) + t.Reset + " " + v + "\n")
+			}
+		}
+	}
+	sb.WriteString(bx.color + b.bottom(inner) + t.Reset)
+	return sb.String()
+}
+
+// columnsEnv reads the terminal width from COLUMNS, or 0 when it isn't set.
+func columnsEnv() int {
+	cols, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return max(cols, 0)
+}
+
+// wrapWidth breaks s into lines at most width columns wide, at a space when
+// that fills at least half the line, and otherwise anywhere, as CJK text
+// without spaces needs.
+func wrapWidth(s string, width int) []string {
+	if displayWidth(s) <= width {
+		return []string{s}
+	}
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	lastSpace := -1 // byte offset of the last space in line
+	spaceWidth := 0 // columns before it
+	for i := 0; i < len(s); {
+		token, w := nextCell(s[i:])
+		i += len(token)
+		if lineWidth+w > width && lineWidth > 0 {
+			text := line.String()
+			rest := ""
+			if lastSpace > 0 && spaceWidth*2 >= width {
+				text, rest = text[:lastSpace], text[lastSpace+1:]
+			}
+			lines = append(lines, strings.TrimRight(text, " "))
+			line.Reset()
+			line.WriteString(rest)
+			lineWidth, lastSpace = displayWidth(rest), -1
+			if token == " " && lineWidth == 0 {
+				continue
+			}
+		}
+		if token == " " {
+			lastSpace, spaceWidth = line.Len(), lineWidth
+		}
+		line.WriteString(token)
+		lineWidth += w
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// truncateWidth cuts s to at most width columns, ending it with "…" when
+// anything was dropped.
+func truncateWidth(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	var sb strings.Builder
+	used := 0
+	for i := 0; i < len(s); {
+		token, w := nextCell(s[i:])
+		i += len(token)
+		if used+w > width-1 {
+			break
+		}
+		sb.WriteString(token)
+		used += w
+	}
+	return sb.String() + "…"
+}
+
+// nextCell splits off the first rune of s, or a whole ANSI escape sequence,
+// and returns it with the columns it takes.
+func nextCell(s string) (string, int) {
+	if n := escapeLen(s); n > 0 {
+		return s[:n], 0
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return s[:size], runeWidth(r)
+}
+
+// escapeLen returns the length of the ANSI CSI sequence s starts with, such
+// as a color code, or 0.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' || s[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7E {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// displayWidth approximates how many terminal columns s occupies: emoji and
+// East Asian wide characters take two, joiners, variation selectors, and
+// color codes take none.
+func displayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		token, w := nextCell(s[i:])
+		i += len(token)
+		width += w
+	}
+	return width
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F) || (r >= 0x1F3FB && r <= 0x1F3FF):
+		return 0
+	case r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF) || isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// isWide reports whether r is a CJK ideograph, kana, hangul, or fullwidth
+// form, which terminals draw two columns wide.
+func isWide(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115F) || (r >= 0x2E80 && r <= 0x303E) || (r >= 0x3041 && r <= 0x33FF) ||
+		(r >= 0x3400 && r <= 0x4DBF) || (r >= 0x4E00 && r <= 0x9FFF) || (r >= 0xA000 && r <= 0xA4CF) ||
+		(r >= 0xAC00 && r <= 0xD7A3) || (r >= 0xF900 && r <= 0xFAFF) || (r >= 0xFE30 && r <= 0xFE4F) ||
+		(r >= 0xFF00 && r <= 0xFF60) || (r >= 0xFFE0 && r <= 0xFFE6)
+}
+
+func centered(s string, width int) string {
+	pad := max(width-displayWidth(s), 0)
+	return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+}
+
+// padRight pads s with spaces to width columns, like %-*s but counting
+// wide characters twice.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-displayWidth(s), 0))
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+)
+
+// PromptConfig tunes the one-line prompt pet.
+type PromptConfig struct {
+	// Branch adds where the checkout stands: default or feature branch,
+	// ahead/behind upstream, and any merge or rebase in progress.
+	Branch bool `json:"branch"`
+}
+
+// promptGitBudget leaves the branch out of the prompt rather than let git
+// use up the whole promptBudget in a huge repository.
+const promptGitBudget = 120 * time.Millisecond
+
+// branchState is what the prompt knows about the current checkout.
+type branchState struct {
+	Name string
+	// Default is set on the repository's default branch, such as main.
+	Default       bool
+	Detached      bool
+	Ahead, Behind int
+	// Operation is "merge", "rebase", "cherry-pick", or "revert" while one
+	// is in progress.
+	Operation string
+	Conflicts bool
+	// Worktree is set in a linked worktree rather than the main checkout.
+	Worktree bool
+}
+
+// readBranchState asks git about the checkout in the current directory. It
+// reports false outside a repository or when git is too slow.
+func readBranchState(ctx context.Context) (branchState, bool) {
+	out, err := gh.GitOutput(ctx, "status", "--porcelain=v2", "--branch", "--untracked-files=no", "--ignore-submodules")
+	if err != nil {
+		return branchState{}, false
+	}
+	var b branchState
+	for _, line := range strings.Split(string(out), "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			b.Name = strings.TrimPrefix(line, "# branch.head ")
+			b.Detached = b.Name == "(detached)"
+		case strings.HasPrefix(line, "# branch.ab "):
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				b.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				b.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(line, "u "):
+			b.Conflicts = true
+		}
+	}
+
+	if out, err := gh.GitOutput(ctx, "rev-parse", "--path-format=absolute", "--git-dir", "--git-common-dir"); err == nil {
+		if dirs := strings.Fields(string(out)); len(dirs) == 2 {
+			b.Worktree = filepath.Clean(dirs[0]) != filepath.Clean(dirs[1])
+			b.Operation = gitOperation(dirs[0])
+		}
+	}
+	if !b.Detached {
+		b.Default = b.Name == defaultBranch(ctx, ".")
+	}
+	return b, true
+}
+
+// gitOperation names the multi-step operation under way in gitDir, going by
+// the files git leaves there while it waits for you.
+func gitOperation(gitDir string) string {
+	for _, op := range []struct{ file, name string }{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+	} {
+		if _, err := os.Stat(filepath.Join(gitDir, op.file)); err == nil {
+			return op.name
+		}
+	}
+	return ""
+}
+
+// defaultBranch is the branch origin's HEAD points at in the repo at dir, or
+// main or master when the remote doesn't say.
+func defaultBranch(ctx context.Context, dir string) string {
+	if out, err := gh.GitOutput(ctx, "-C", dir, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		_, name, _ := strings.Cut(strings.TrimSpace(string(out)), "/")
+		return name
+	}
+	if _, err := gh.GitOutput(ctx, "-C", dir, "rev-parse", "--verify", "--quiet", "refs/heads/main"); err == nil {
+		return "main"
+	}
+	return "master"
+}
+
+// promptTag is the branch's part of the prompt: ⌂ on the default branch, ⑂
+// on any other, ⊘ when detached, then ↑ahead ↓behind and |rebase while one
+// is in progress. A linked worktree is marked with ⧉.
+func (b branchState) promptTag() string {
+	var sb strings.Builder
+	sb.WriteString(" ")
+	if b.Worktree {
+		sb.WriteString("⧉")
+	}
+	switch {
+	case b.Detached:
+		sb.WriteString("⊘")
+	case b.Default:
+		sb.WriteString("⌂")
+	default:
+		sb.WriteString("⑂")
+	}
+	if b.Ahead > 0 {
+		sb.WriteString("↑" + strconv.Itoa(b.Ahead))
+	}
+	if b.Behind > 0 {
+		sb.WriteString("↓" + strconv.Itoa(b.Behind))
+	}
+	if b.Operation != "" {
+		sb.WriteString("|" + b.Operation)
+	}
+	return sb.String()
+}
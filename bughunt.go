@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Bug hunt is a game for the terminal: the pet chases the bugs loose in a
+// field, one for every fix commit this week, and catching them all before
+// time runs out earns logic shards, up to a daily cap.
+
+const (
+	bugHuntWidth  = 40
+	bugHuntHeight = 12
+	minBugs       = 3
+	maxBugs       = 24
+	bugHuntTick   = 100 * time.Millisecond
+	// Bugs scurry every bugMoveTicks ticks, so the pet is a little faster.
+	bugMoveTicks = 3
+)
+
+var bugGlyphs = []rune{'*', 'x', '%', '&'}
+
+type cell struct{ x, y int }
+
+type bug struct {
+	cell
+	glyph rune
+}
+
+type bugHunt struct {
+	pet   cell
+	bugs  []bug
+	total int
+	rng   *rand.Rand
+}
+
+// bugCount is how many bugs a hunt starts with: one per fix commit, within
+// bounds.
+func bugCount(fixes int) int {
+	return min(max(fixes, minBugs), maxBugs)
+}
+
+// bugHuntTime is how long a hunt with bugs lasts.
+func bugHuntTime(bugs int) time.Duration {
+	return 10*time.Second + time.Duration(bugs)*2*time.Second
+}
+
+func newBugHunt(bugs int, rng *rand.Rand) *bugHunt {
+	g := &bugHunt{pet: cell{bugHuntWidth / 2, bugHuntHeight / 2}, total: bugs, rng: rng}
+	taken := map[cell]bool{}
+	for len(g.bugs) < bugs {
+		c := cell{rng.Intn(bugHuntWidth), rng.Intn(bugHuntHeight)}
+		if taken[c] || distance(c, g.pet) < 4 {
+			continue
+		}
+		taken[c] = true
+		g.bugs = append(g.bugs, bug{cell: c, glyph: bugGlyphs[len(g.bugs)%len(bugGlyphs)]})
+	}
+	return g
+}
+
+func distance(a, b cell) int {
+	dx, dy := a.x-b.x, a.y-b.y
+	return max(dx, -dx) + max(dy, -dy)
+}
+
+func inField(c cell) bool {
+	return c.x >= 0 && c.x < bugHuntWidth && c.y >= 0 && c.y < bugHuntHeight
+}
+
+// move steps the pet, catching any bug it lands on.
+func (g *bugHunt) move(dx, dy int) {
+	next := cell{g.pet.x + dx, g.pet.y + dy}
+	if !inField(next) {
+		return
+	}
+	g.pet = next
+	for i, b := range g.bugs {
+		if b.cell == g.pet {
+			g.bugs = append(g.bugs[:i], g.bugs[i+1:]...)
+			return
+		}
+	}
+}
+
+// scurry moves each bug a step. Bugs wander, and those the pet gets close
+// to mostly run from it.
+func (g *bugHunt) scurry() {
+	steps := []cell{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {0, 0}}
+	occupied := map[cell]bool{g.pet: true}
+	for _, b := range g.bugs {
+		occupied[b.cell] = true
+	}
+	for i := range g.bugs {
+		b := &g.bugs[i]
+		step := steps[g.rng.Intn(len(steps))]
+		if distance(b.cell, g.pet) <= 4 && g.rng.Intn(3) > 0 {
+			// Of the steps, take the one that gets furthest away.
+			for _, s := range steps {
+				if to := (cell{b.x + s.x, b.y + s.y}); inField(to) && distance(to, g.pet) > distance(cell{b.x + step.x, b.y + step.y}, g.pet) {
+					step = s
+				}
+			}
+		}
+		to := cell{b.x + step.x, b.y + step.y}
+		if !inField(to) || occupied[to] {
+			continue
+		}
+		delete(occupied, b.cell)
+		occupied[to] = true
+		b.cell = to
+	}
+}
+
+// draw renders the field from the top of the screen.
+func (g *bugHunt) draw(name string, left time.Duration) string {
+	grid := make([][]string, bugHuntHeight)
+	for y := range grid {
+		grid[y] = make([]string, bugHuntWidth)
+		for x := range grid[y] {
+			grid[y][x] = " "
+		}
+	}
+	for _, b := range g.bugs {
+		grid[b.y][b.x] = colorRed + string(b.glyph) + colorReset
+	}
+	grid[g.pet.y][g.pet.x] = colorBold + colorGreen + "@" + colorReset
+
+	var sb strings.Builder
+	sb.WriteString("\x1b[H")
+	line := func(s string) {
+		sb.WriteString(s + "\x1b[K\n")
+	}
+	line(fmt.Sprintf("%s🐛 Bug hunt%s  %s is @   bugs left %d/%d   %ds", colorBold, colorReset, name, len(g.bugs), g.total, int(left.Seconds()+0.99)))
+	line("┌" + strings.Repeat("─", bugHuntWidth) + "┐")
+	for _, row := range grid {
+		line("│" + strings.Join(row, "") + "│")
+	}
+	line("└" + strings.Repeat("─", bugHuntWidth) + "┘")
+	line(colorDim + "Arrows, WASD, or hjkl to move · q to give up" + colorReset)
+	return sb.String()
+}
+
+// keyMove reads a key press: a direction to move, or quit.
+func keyMove(key []byte) (dx, dy int, quit bool) {
+	switch string(key) {
+	case "\x1b[A", "\x1bOA", "w", "W", "k":
+		return 0, -1, false
+	case "\x1b[B", "\x1bOB", "s", "S", "j":
+		return 0, 1, false
+	case "\x1b[C", "\x1bOC", "d", "D", "l":
+		return 1, 0, false
+	case "\x1b[D", "\x1bOD", "a", "A", "h":
+		return -1, 0, false
+	case "q", "Q", "\x1b", "\x03":
+		return 0, 0, true
+	}
+	return 0, 0, false
+}
+
+// splitKeys splits what one read returned into key presses, since a held
+// key can deliver several at once. Arrow keys are three bytes.
+func splitKeys(input []byte) [][]byte {
+	var keys [][]byte
+	for len(input) > 0 {
+		n := 1
+		if input[0] == 0x1b && len(input) >= 3 && (input[1] == '[' || input[1] == 'O') {
+			n = 3
+		}
+		keys = append(keys, input[:n])
+		input = input[n:]
+	}
+	return keys
+}
+
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Ways a hunt ends.
+const (
+	huntWon = iota
+	huntTimeUp
+	huntQuit
+)
+
+func runBugHunt() error {
+	if !stdoutIsTerminal() || !stdinIsTerminal() {
+		return fmt.Errorf("bug hunt is played in a terminal")
+	}
+	if w, h := terminalWidth(), terminalRows(); w > 0 && w < bugHuntWidth+2 || h > 0 && h < bugHuntHeight+4 {
+		return fmt.Errorf("bug hunt needs a terminal at least %d×%d", bugHuntWidth+2, bugHuntHeight+4)
+	}
+	if !petExists() {
+		return fmt.Errorf("there's no pet to play with yet; run gh pet hatch to meet yours")
+	}
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if state.Departed != "" {
+		return errDeparted
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	restore, err := rawInput()
+	if err != nil {
+		return fmt.Errorf("bug hunt can't read keys here: %w", err)
+	}
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+	// The game is drawn on the alternate screen, which is put away after.
+	fmt.Print("\x1b[?1049h\x1b[?25l\x1b[2J")
+	game := newBugHunt(bugCount(state.Activity.FixCommits), rand.New(rand.NewSource(time.Now().UnixNano())))
+	start := time.Now()
+	end := start.Add(bugHuntTime(game.total))
+	ticker := time.NewTicker(bugHuntTick)
+	outcome := huntTimeUp
+play:
+	for tick := 0; ; {
+		fmt.Print(game.draw(state.DisplayName(), time.Until(end)))
+		select {
+		case input, ok := <-keys:
+			if !ok {
+				outcome = huntQuit
+				break play
+			}
+			for _, key := range splitKeys(input) {
+				dx, dy, quit := keyMove(key)
+				if quit {
+					outcome = huntQuit
+					break play
+				}
+				game.move(dx, dy)
+			}
+		case <-ticker.C:
+			if tick++; tick%bugMoveTicks == 0 {
+				game.scurry()
+			}
+		}
+		if len(game.bugs) == 0 {
+			outcome = huntWon
+			break
+		}
+		if !time.Now().Before(end) {
+			break
+		}
+	}
+	ticker.Stop()
+	fmt.Print("\x1b[?25h\x1b[?1049l")
+	restore()
+
+	took := time.Since(start).Round(time.Second)
+	switch outcome {
+	case huntQuit:
+		fmt.Printf("🐛 Gave up with %s still loose.\n", i18n.Plural(len(game.bugs), "bug"))
+		return nil
+	case huntTimeUp:
+		fmt.Printf("⏰ Time's up: %d of %s got away. No shards this time.\n", len(game.bugs), i18n.Plural(game.total, "bug"))
+		return nil
+	}
+	earned, today, err := awardGameLogic(cfg.Scoring.BugHuntLogic, cfg.Scoring.GameLogicPerDay)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s🐛 %s caught all %s in %s!%s", colorGreen, state.DisplayName(), i18n.Plural(game.total, "bug"), took, colorReset)
+	if earned > 0 {
+		fmt.Printf(" +%d logic shards\n", earned)
+	} else {
+		fmt.Println()
+	}
+	if today >= cfg.Scoring.GameLogicPerDay {
+		fmt.Printf("%sThat's all %d shards games can earn today. Play on for fun, or come back tomorrow.%s\n", colorDim, cfg.Scoring.GameLogicPerDay, colorReset)
+	}
+	if err := store.AddJournalEntry("bughunt", fmt.Sprintf("Keeper and I chased down %s in %s. Not one got away.", i18n.Plural(game.total, "bug"), took)); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+	return nil
+}
+
+// awardGameLogic adds a game's shards to the pet, as many as today's cap
+// leaves room for. It returns what was earned and the day's total after.
+func awardGameLogic(shards, perDay int) (earned, today int, err error) {
+	history, err := store.LoadHistory()
+	if err != nil {
+		return 0, 0, err
+	}
+	rec := history.Day(time.Now().Format(store.DayLayout))
+	earned = min(shards, max(perDay-rec.GameLogic, 0))
+	if earned == 0 {
+		return 0, rec.GameLogic, nil
+	}
+	rec.GameLogic += earned
+	if err := store.SaveHistory(history); err != nil {
+		return 0, 0, err
+	}
+	// The game took a while; start from the pet as it is now.
+	state, err := store.Load()
+	if err != nil {
+		return 0, 0, err
+	}
+	state.Logic += earned
+	if err := store.Save(state); err != nil {
+		return 0, 0, err
+	}
+	return earned, rec.GameLogic, nil
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const changelogPRQuery = `query($q: String!) {
+  search(query: $q, type: ISSUE, first: 100) {
+    nodes { ... on PullRequest { number title author { login } } }
+  }
+}`
+
+// changelogSections are the draft's sections in order, each with its
+// heading and the conventional commit types it collects. Anything else
+// lands in the last one.
+var changelogSections = []struct {
+	Key, Icon, Heading string
+}{
+	{"feat", "✨", "Features"},
+	{"fix", "🐛", "Fixes"},
+	{"docs", "📚", "Documentation"},
+	{"other", "🧹", "Other changes"},
+}
+
+// prReference finds the pull request a squash or merge commit came from.
+var prReference = regexp.MustCompile(`\(#(\d+)\)$|^Merge pull request #(\d+)`)
+
+// changelogEntry is one line of the draft: a merged pull request, or a
+// commit that didn't come through one.
+type changelogEntry struct {
+	Kind string
+	Text string
+	// Ref is "#12 @octocat" for a pull request or a short SHA for a commit.
+	Ref string
+}
+
+// changelogDraft is a CHANGELOG section for everything since a tag.
+type changelogDraft struct {
+	Repo    string
+	Since   string
+	Intro   string
+	Entries []changelogEntry
+	PRs     int
+	Commits int
+}
+
+type compareCommit struct {
+	SHA     string `json:"sha"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Commit struct {
+		Message   string `json:"message"`
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
+
+type changelogPR struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// runChangelog drafts a CHANGELOG section from the pull requests merged and
+// commits made since a tag, with the Bard telling the story up top.
+func runChangelog(args []string) error {
+	fs := newFlagSet("changelog")
+	repoFlag := fs.String("repo", "", "owner/repo (default: the current repository)")
+	since := fs.String("since", "", "tag or ref the draft starts after (default: the latest release)")
+	out := fs.String("out", "", "write the draft to a file instead of stdout")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	ctx := context.Background()
+	repo := *repoFlag
+	if repo == "" {
+		owner, name, err := currentRepo(ctx)
+		if err != nil {
+			return fmt.Errorf("%w (or pass --repo owner/repo)", err)
+		}
+		repo = owner + "/" + name
+	} else if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" {
+		return usageErrorf("--repo must be owner/repo, not %q", repo)
+	}
+
+	stop := startSpinner("Gathering everything since the last release…")
+	draft, err := fetchChangelog(ctx, repo, *since)
+	stop()
+	if err != nil {
+		return err
+	}
+	state, _ := store.Load()
+	draft.Intro = bardIntro(state, draft)
+	text := draft.markdown()
+	if *out == "" {
+		fmt.Print(text)
+		return nil
+	}
+	if err := os.WriteFile(*out, []byte(text), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Changelog draft written to %s%s\n", colorGreen, *out, colorReset)
+	return nil
+}
+
+// fetchChangelog collects what reached repo's default branch after since,
+// or after the latest release when since is empty. GitHub compares at most
+// 250 commits, so a very long gap lists only the oldest of them.
+func fetchChangelog(ctx context.Context, repo, since string) (changelogDraft, error) {
+	if since == "" {
+		var release struct {
+			TagName string `json:"tag_name"`
+		}
+		if err := gh.Get(ctx, fmt.Sprintf("repos/%s/releases/latest", repo), &release); err != nil || release.TagName == "" {
+			return changelogDraft{}, fmt.Errorf("%s has no release to start from; pass --since with a tag", repo)
+		}
+		since = release.TagName
+	}
+	var info struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := gh.Get(ctx, "repos/"+repo, &info); err != nil {
+		return changelogDraft{}, err
+	}
+	var compare struct {
+		BaseCommit compareCommit   `json:"base_commit"`
+		Commits    []compareCommit `json:"commits"`
+	}
+	endpoint := fmt.Sprintf("repos/%s/compare/%s...%s", repo, url.PathEscape(since), url.PathEscape(info.DefaultBranch))
+	if err := gh.Get(ctx, endpoint, &compare); err != nil {
+		return changelogDraft{}, fmt.Errorf("cannot compare %s with %s: %w", since, info.DefaultBranch, err)
+	}
+
+	var data struct {
+		Search struct {
+			Nodes []changelogPR `json:"nodes"`
+		} `json:"search"`
+	}
+	after := compare.BaseCommit.Commit.Committer.Date.UTC().Format(time.RFC3339)
+	q := fmt.Sprintf("repo:%s is:pr is:merged base:%s merged:>%s sort:created-asc", repo, info.DefaultBranch, after)
+	if err := gh.GraphQL(ctx, changelogPRQuery, map[string]any{"q": q}, &data); err != nil {
+		return changelogDraft{}, err
+	}
+	return buildChangelog(repo, since, data.Search.Nodes, compare.Commits), nil
+}
+
+// buildChangelog sorts pull requests and the commits that didn't come
+// through one into entries. Merge commits are left out.
+func buildChangelog(repo, since string, prs []changelogPR, commits []compareCommit) changelogDraft {
+	d := changelogDraft{Repo: repo, Since: since}
+	merged := map[string]bool{}
+	for _, pr := range prs {
+		if pr.Number == 0 {
+			continue
+		}
+		ref := fmt.Sprintf("#%d", pr.Number)
+		merged[ref] = true
+		if pr.Author != nil && pr.Author.Login != "" {
+			ref += " @" + pr.Author.Login
+		}
+		kind, text := changeKind(pr.Title)
+		d.Entries = append(d.Entries, changelogEntry{Kind: kind, Text: text, Ref: ref})
+		d.PRs++
+	}
+	for _, c := range commits {
+		subject, _, _ := strings.Cut(c.Commit.Message, "\n")
+		if len(c.Parents) > 1 || subject == "" {
+			continue
+		}
+		if m := prReference.FindStringSubmatch(subject); m != nil && merged["#"+m[1]+m[2]] {
+			continue
+		}
+		kind, text := changeKind(subject)
+		d.Entries = append(d.Entries, changelogEntry{Kind: kind, Text: text, Ref: c.SHA[:min(7, len(c.SHA))]})
+		d.Commits++
+	}
+	return d
+}
+
+// changeKind sorts a commit subject or pull request title into a changelog
+// section and drops its conventional prefix. Titles without one are sorted
+// by their wording.
+func changeKind(title string) (kind, text string) {
+	if prefix := conventionalPrefix.FindStringSubmatch(title); prefix != nil {
+		text = strings.TrimPrefix(title, prefix[0])
+		if scope := strings.Trim(prefix[2], "()"); scope != "" {
+			text = "**" + scope + ":** " + text
+		}
+		switch prefix[1] {
+		case "feat", "fix", "docs":
+			return prefix[1], text
+		case "perf":
+			return "fix", text
+		}
+		return "other", text
+	}
+	lower := strings.ToLower(title)
+	switch {
+	case strings.Contains(lower, "fix") || strings.Contains(lower, "bug"):
+		kind = "fix"
+	case strings.Contains(lower, "doc") || strings.Contains(lower, "readme"):
+		kind = "docs"
+	case strings.HasPrefix(lower, "add") || strings.HasPrefix(lower, "support") || strings.HasPrefix(lower, "introduce"):
+		kind = "feat"
+	default:
+		kind = "other"
+	}
+	return kind, title
+}
+
+// bardIntro is the draft's opening paragraph, told by the Bard whatever the
+// pet's current form. Professional mode keeps it to the facts.
+func bardIntro(state store.PetState, d changelogDraft) string {
+	counts := map[string]int{}
+	for _, e := range d.Entries {
+		counts[e.Kind]++
+	}
+	if professional() {
+		return fmt.Sprintf("This release includes %s and %s since %s.", i18n.Plural(d.PRs, "merged pull request"), i18n.Plural(d.Commits, "direct commit"), d.Since)
+	}
+	if len(d.Entries) == 0 {
+		return fmt.Sprintf("🎻 The Bard tunes the lute, but since %s the halls of %s have been quiet. No tale to tell, yet.", d.Since, d.Repo)
+	}
+	parts := []string{fmt.Sprintf("🎻 Gather round, for the Bard sings of %s since %s!", d.Repo, d.Since)}
+	if d.PRs > 0 {
+		parts = append(parts, fmt.Sprintf("%s found their way home", i18n.Plural(d.PRs, "pull request")))
+		if d.Commits > 0 {
+			parts[1] += fmt.Sprintf(", and %s marched in on their own.", i18n.Plural(d.Commits, "commit"))
+		} else {
+			parts[1] += "."
+		}
+	} else {
+		parts = append(parts, fmt.Sprintf("%s marched in on their own.", i18n.Plural(d.Commits, "commit")))
+	}
+	switch {
+	case counts["feat"] > 0 && counts["fix"] > 0:
+		parts = append(parts, fmt.Sprintf("New wonders were forged (%d) and old foes were vanquished (%d).", counts["feat"], counts["fix"]))
+	case counts["feat"] > 0:
+		parts = append(parts, fmt.Sprintf("New wonders were forged (%d), each shinier than the last.", counts["feat"]))
+	case counts["fix"] > 0:
+		parts = append(parts, fmt.Sprintf("Old foes were vanquished (%d), and the realm is steadier for it.", counts["fix"]))
+	}
+	if counts["docs"] > 0 {
+		parts = append(parts, "And the scrolls were rewritten, so travelers after us won't lose their way.")
+	}
+	parts = append(parts, fmt.Sprintf("Sung by %s, who was there for every verse.", state.DisplayName()))
+	return strings.Join(parts, " ")
+}
+
+// markdown renders the draft as a CHANGELOG section.
+func (d changelogDraft) markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Unreleased (since %s)\n\n%s\n", d.Since, d.Intro)
+	for _, section := range changelogSections {
+		var lines []string
+		for _, e := range d.Entries {
+			if e.Kind == section.Key {
+				lines = append(lines, fmt.Sprintf("- %s (%s)", e.Text, e.Ref))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		heading := section.Heading
+		if !professional() {
+			heading = section.Icon + " " + heading
+		}
+		fmt.Fprintf(&sb, "\n### %s\n\n%s\n", heading, strings.Join(lines, "\n"))
+	}
+	return sb.String()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/store"
+	"golang.org/x/sync/errgroup"
+)
+
+// Feed checks CI on the repos you pushed to most recently, up to maxCIRepos,
+// and never withholds more than maxCIDebuff mood for red builds.
+const (
+	maxCIRepos  = 5
+	maxCIDebuff = 20
+)
+
+// ciRepos is the repositories you pushed to, most recent first.
+func ciRepos(events []Event) []string {
+	var repos []string
+	for _, event := range events {
+		if event.Type != "PushEvent" || event.Repo.Name == "" || containsString(repos, event.Repo.Name) {
+			continue
+		}
+		repos = append(repos, event.Repo.Name)
+		if len(repos) == maxCIRepos {
+			break
+		}
+	}
+	return repos
+}
+
+// ciWeather fetches the latest completed workflow run on each branch you
+// triggered. Repos without Actions runs, or that fail to load, are left out.
+func ciWeather(ctx context.Context, login string, events []Event) []store.RepoWeather {
+	repos := ciRepos(events)
+	found := make([]*store.RepoWeather, len(repos))
+	var g errgroup.Group
+	g.SetLimit(gh.Concurrency)
+	for i, repo := range repos {
+		g.Go(func() error {
+			found[i] = repoWeather(ctx, login, repo)
+			return nil
+		})
+	}
+	g.Wait()
+
+	var weather []store.RepoWeather
+	for _, w := range found {
+		if w != nil {
+			weather = append(weather, *w)
+		}
+	}
+	return weather
+}
+
+// repoWeather is one repository's CI weather, or nil when it has none.
+func repoWeather(ctx context.Context, login, repo string) *store.RepoWeather {
+	var resp struct {
+		Runs []struct {
+			Branch     string `json:"head_branch"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"workflow_runs"`
+	}
+	if gh.Get(ctx, fmt.Sprintf("repos/%s/actions/runs?actor=%s&per_page=50", repo, login), &resp) != nil {
+		return nil
+	}
+	runs := resp.Runs
+	w := store.RepoWeather{Repo: repo}
+	seen := map[string]bool{}
+	// Runs come newest first, so the first completed run per branch wins.
+	for _, run := range runs {
+		if run.Status != "completed" || seen[run.Branch] {
+			continue
+		}
+		seen[run.Branch] = true
+		switch run.Conclusion {
+		case "failure", "timed_out", "startup_failure":
+			w.Red = append(w.Red, run.Branch)
+		case "success":
+			w.Passing = append(w.Passing, run.Branch)
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	return &w
+}
+
+// applyCIWeather replaces the pet's CI weather. The mood withheld for the
+// previous red builds is given back, then withheld again for the current
+// ones, so the debuff only lasts while a build stays red. It returns how many
+// previously red branches are now passing.
+func applyCIWeather(state *store.PetState, weather []store.RepoWeather, scoring ScoringConfig) int {
+	wasRed := map[string]bool{}
+	for _, w := range state.CI {
+		for _, branch := range w.Red {
+			wasRed[w.Repo+"@"+branch] = true
+		}
+	}
+	fixed, red := 0, 0
+	for _, w := range weather {
+		red += len(w.Red)
+		for _, branch := range w.Passing {
+			if wasRed[w.Repo+"@"+branch] {
+				fixed++
+			}
+		}
+	}
+
+	state.Mood = min(100, state.Mood+state.CIDebuff)
+	state.CIDebuff = min(min(red*scoring.RedBuildMood, maxCIDebuff), state.Mood)
+	state.Mood = min(100, state.Mood-state.CIDebuff+fixed*scoring.FirefighterMood)
+	state.CI = weather
+	return fixed
+}
+
+// ciWeatherLines is the status screen's forecast, one line per repo.
+func ciWeatherLines(weather []store.RepoWeather) []string {
+	var lines []string
+	for _, w := range weather {
+		line := w.Icon() + "  " + w.Repo
+		if len(w.Red) > 0 {
+			line += " (" + strings.Join(w.Red, ", ") + " failing)"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
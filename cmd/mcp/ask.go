@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/config"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AskAnswer is pet_ask's structured result. Answer is the pet speaking; the
+// other fields are the numbers behind it so agents don't have to parse prose.
+type AskAnswer struct {
+	Intent    string    `json:"intent" jsonschema:"enum=week,enum=focus,enum=streak,enum=mood,enum=evolution,enum=overview" jsonschema_description:"What the question was understood to be about"`
+	Answer    string    `json:"answer" jsonschema_description:"The pet's in-character reply"`
+	Pet       string    `json:"pet" jsonschema_description:"The pet's name"`
+	Evolution string    `json:"evolution"`
+	Mood      int       `json:"mood" jsonschema_description:"Current mood, 0-100"`
+	Streak    int       `json:"streak" jsonschema_description:"Consecutive active days ending today or yesterday"`
+	Week      WeekStats `json:"week" jsonschema_description:"Activity over the last 7 days compared with the 7 before"`
+	Focus     []string  `json:"focus,omitempty" jsonschema_description:"Suggested next steps, most valuable first"`
+	Wellness  []string  `json:"wellness,omitempty" jsonschema_description:"Wellness concerns, if any"`
+}
+
+type WeekStats struct {
+	Commits       int `json:"commits"`
+	MergedPRs     int `json:"merged_prs"`
+	Reviews       int `json:"reviews"`
+	DocComments   int `json:"doc_comments"`
+	Issues        int `json:"issues"`
+	Total         int `json:"total"`
+	PreviousTotal int `json:"previous_total"`
+	// TrendPercent is nil when last week was empty and no ratio exists.
+	TrendPercent *int `json:"trend_percent,omitempty"`
+}
+
+// askIntents maps keywords to the intent they signal, checked in order.
+var askIntents = []struct {
+	intent   string
+	keywords []string
+}{
+	{"focus", []string{"focus", "should", "next", "improve", "work on", "advice", "recommend"}},
+	{"streak", []string{"streak", "in a row", "consecutive"}},
+	{"evolution", []string{"evolve", "evolution", "become", "grow"}},
+	{"mood", []string{"mood", "feel", "happy", "sad", "how are you"}},
+	{"week", []string{"week", "lately", "recent", "how was", "how did"}},
+}
+
+func classifyQuestion(question string) string {
+	q := strings.ToLower(question)
+	for _, candidate := range askIntents {
+		for _, keyword := range candidate.keywords {
+			if strings.Contains(q, keyword) {
+				return candidate.intent
+			}
+		}
+	}
+	return "overview"
+}
+
+func weekStats(history store.History, now time.Time) WeekStats {
+	y, m, d := now.Date()
+	end := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, 0, -7)
+	var w WeekStats
+	for _, day := range history.Between(start, end) {
+		w.Commits += day.Commits
+		w.MergedPRs += day.MergedPRs
+		w.Reviews += day.Reviews
+		w.DocComments += day.DocComments
+		w.Issues += day.Issues
+		w.Total += day.Total()
+	}
+	for _, day := range history.Between(start.AddDate(0, 0, -7), start) {
+		w.PreviousTotal += day.Total()
+	}
+	if w.PreviousTotal > 0 {
+		pct := (w.Total - w.PreviousTotal) * 100 / w.PreviousTotal
+		w.TrendPercent = &pct
+	}
+	return w
+}
+
+// focusAdvice ranks what would help the pet most, using the configured
+// weights so teams that reward reviews hear about reviews first.
+func focusAdvice(state store.PetState, scoring ScoringConfig) []string {
+	a := state.Activity
+	var advice []string
+	if a.Reviews == 0 {
+		advice = append(advice, fmt.Sprintf("Review a teammate's pull request (+%d kindness each).", scoring.ReviewKindness))
+	}
+	if a.TestCommits == 0 {
+		advice = append(advice, fmt.Sprintf("Add or fix a test (+%d logic shard per test commit).", scoring.TestLogic))
+	}
+	if a.Commits > 5 && a.MergedPRs == 0 {
+		advice = append(advice, fmt.Sprintf("Land a pull request; merges are worth +%d mood.", scoring.MergedPRMood))
+	}
+	if a.DocComments == 0 {
+		advice = append(advice, "Leave a helpful comment or doc update; I love a good story.")
+	}
+	if a.IssuesClosed == 0 && a.IssuesOpened > 0 {
+		advice = append(advice, fmt.Sprintf("Close one of the issues you opened (+%d kindness).", scoring.IssueClosedKindness))
+	}
+	if len(advice) == 0 {
+		advice = append(advice, "Keep doing what you're doing, and take a break when you need one.")
+	}
+	return advice
+}
+
+func answerQuestion(question string, state store.PetState, history store.History, cfg config.Settings, now time.Time) AskAnswer {
+	streak := store.CurrentStreak(history, now)
+	ans := AskAnswer{
+		Intent:    classifyQuestion(question),
+		Pet:       state.DisplayName(),
+		Evolution: state.Evolution,
+		Mood:      state.Mood,
+		Streak:    streak,
+		Week:      weekStats(history, now),
+		Wellness:  pet.WellnessConcerns(state.Activity, streak, cfg.Wellness),
+	}
+	if ans.Evolution == "" {
+		ans.Evolution = "Lonely"
+	}
+	w := ans.Week
+	voice := state.Signature() + " " + state.DisplayName() + ": "
+
+	switch ans.Intent {
+	case "week":
+		switch {
+		case w.Total == 0:
+			ans.Answer = voice + "It was a quiet week. I napped a lot and kept your seat warm."
+		case w.TrendPercent == nil:
+			ans.Answer = voice + fmt.Sprintf("You came back with %d events after a quiet stretch: %d commits, %d merged PRs, %d reviews. I missed you!", w.Total, w.Commits, w.MergedPRs, w.Reviews)
+		case *w.TrendPercent >= 0:
+			ans.Answer = voice + fmt.Sprintf("A lively week! %d events (%d commits, %d merged PRs, %d reviews), up %d%% on the week before.", w.Total, w.Commits, w.MergedPRs, w.Reviews, *w.TrendPercent)
+		default:
+			ans.Answer = voice + fmt.Sprintf("A gentler week: %d events (%d commits, %d merged PRs, %d reviews), down %d%%. Slow weeks count too.", w.Total, w.Commits, w.MergedPRs, w.Reviews, -*w.TrendPercent)
+		}
+	case "focus":
+		ans.Focus = focusAdvice(state, cfg.Scoring)
+		ans.Answer = voice + "If you're asking me, " + lowerFirst(ans.Focus[0])
+	case "streak":
+		if streak == 0 {
+			ans.Answer = voice + "No streak right now. One commit today starts a new one!"
+		} else {
+			ans.Answer = voice + fmt.Sprintf("We're on a %d-day streak. Remember that rest days keep streaks healthy.", streak)
+		}
+	case "mood":
+		ans.Answer = voice + fmt.Sprintf("I'm feeling %s (%d/100).", strings.ToLower(moodDescriptor(state.Mood)), state.Mood)
+	case "evolution":
+		ans.Answer = voice + fmt.Sprintf("I'm a %s right now, shaped by this week's activity. Different work nudges me toward a different form.", ans.Evolution)
+	default:
+		ans.Answer = voice + fmt.Sprintf("I'm a %s feeling %s, with %d events this week and a %d-day streak.", ans.Evolution, strings.ToLower(moodDescriptor(state.Mood)), w.Total, streak)
+	}
+	if len(ans.Wellness) > 0 && ans.Intent != "focus" {
+		ans.Answer += " " + ans.Wellness[0]
+	}
+	return ans
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func handleAsk(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	question, err := req.RequireString("question")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	state, _ := store.Load()
+	history, _ := store.LoadHistory()
+	cfg, err := config.Load()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to load config: %v", err)), nil
+	}
+	ans := answerQuestion(question, state, history, cfg, time.Now())
+	return mcp.NewToolResultStructured(ans, ans.Answer), nil
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/config"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxChecklists is how many pull requests' checklists the pet remembers
+// rewarding; older ones could earn kindness again, but only from a review
+// long finished.
+const maxChecklists = 50
+
+// prInfo is the part of a pull request the checklist reads.
+type prInfo struct {
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// prFile is one file a pull request changes. The files endpoint lists up
+// to 100 per page, which is all the checklist reads.
+type prFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Patch     string `json:"patch"`
+}
+
+// checklistVoices is how each evolution opens a review.
+var checklistVoices = map[string]string{
+	"Pioneer":   "New ground! Let's walk it before anyone builds on it.",
+	"Guardian":  "Nothing passes the gate unchecked. Here's what I'd look at.",
+	"Bard":      "Every change tells a story; let's make sure this one reads well.",
+	"Void":      "Less is more. Let's see what this change really needs.",
+	"Sentinel":  "Tests first, then trust. Here's my watch list.",
+	"Curator":   "A tidy change is a kind change. Let's sort this one out.",
+	"Lonely":    "I'd love some company on this review. Here's where to start.",
+	"Companion": "Here's what I'd check, side by side with you.",
+}
+
+// checklistFocus is the item each evolution puts first when the diff calls
+// for it.
+var checklistFocus = map[string]string{
+	"Pioneer":  "new-files",
+	"Guardian": "breaking",
+	"Bard":     "docs",
+	"Void":     "size",
+	"Sentinel": "tests",
+	"Curator":  "size",
+	"Lonely":   "kindness",
+}
+
+// conventionalBang matches a title like "feat(api)!: drop v1".
+var conventionalBang = regexp.MustCompile(`^\w+(\([^)]*\))?!:`)
+
+// exportedDecl matches a line declaring public API in Go, JS/TS, or Java.
+var exportedDecl = regexp.MustCompile(`^\s*(func (\([^)]*\) )?[A-Z]\w*|type [A-Z]\w*|export |public )`)
+
+func handlePRChecklist(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	number := req.GetInt("pr", 0)
+	if number < 1 {
+		return mcp.NewToolResultError("pr must be a pull request number"), nil
+	}
+	repo := strings.TrimSpace(req.GetString("repo", ""))
+	if repo == "" {
+		var ok bool
+		if repo, ok = originRepo(ctx); !ok {
+			return mcp.NewToolResultError("not in a GitHub repository; pass repo as owner/name"), nil
+		}
+	}
+	if strings.Count(repo, "/") != 1 {
+		return mcp.NewToolResultError(fmt.Sprintf("repo %q should be owner/name", repo)), nil
+	}
+	var pr prInfo
+	if err := gh.Get(ctx, fmt.Sprintf("repos/%s/pulls/%d", repo, number), &pr); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch %s#%d: %v", repo, number, gh.ExplainAccess(err, repo))), nil
+	}
+	var files []prFile
+	if err := gh.Get(ctx, fmt.Sprintf("repos/%s/pulls/%d/files?per_page=100", repo, number), &files); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch %s#%d's files: %v", repo, number, err)), nil
+	}
+
+	state, _ := store.Load()
+	if state.Evolution == "" {
+		state.Evolution = "Lonely"
+	}
+	result := buildChecklist(state.Evolution, pr, files)
+	result.Repo, result.Number = repo, number
+
+	if completed := req.GetStringSlice("completed", nil); len(completed) > 0 {
+		cfg, err := config.Load()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load config: %v", err)), nil
+		}
+		if err := tickChecklist(&result, completed); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		key := fmt.Sprintf("%s#%d", repo, number)
+		if !slices.ContainsFunc(state.Checklists, func(c string) bool { return strings.EqualFold(c, key) }) {
+			result.KindnessEarned = len(result.Completed) * cfg.Scoring.ChecklistKindness
+			state.Kindness += result.KindnessEarned
+			state.Checklists = append(state.Checklists, key)
+			if len(state.Checklists) > maxChecklists {
+				state.Checklists = state.Checklists[len(state.Checklists)-maxChecklists:]
+			}
+			if err := store.Save(state); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to save state: %v", err)), nil
+			}
+			if err := store.AddJournalEntry("mcp", fmt.Sprintf("Keeper worked through %s of the review checklist for %s. Careful reviews are kind ones.", i18n.Plural(len(result.Completed), "item"), key)); err != nil {
+				logging.Logger.Debug("journal", "err", err)
+			}
+		} else {
+			result.AlreadyCounted = true
+		}
+	}
+	result.Pet = petSnapshot(state)
+	return mcp.NewToolResultStructured(result, renderChecklist(state, result)), nil
+}
+
+// originRepo is owner/name of the origin remote, when it's on GitHub.
+func originRepo(ctx context.Context) (string, bool) {
+	out, err := gh.GitOutput(ctx, "remote", "get-url", "origin")
+	if err != nil {
+		return "", false
+	}
+	url := strings.TrimSuffix(strings.TrimSpace(string(out)), ".git")
+	for _, prefix := range []string{"https://github.com/", "git@github.com:", "ssh://git@github.com/"} {
+		if rest, ok := strings.CutPrefix(url, prefix); ok && strings.Count(rest, "/") == 1 {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+// buildChecklist reads the diff for what a reviewer should look at, in the
+// order evolution would check it.
+func buildChecklist(evolution string, pr prInfo, files []prFile) ChecklistResult {
+	r := ChecklistResult{
+		Title:      pr.Title,
+		Author:     pr.User.Login,
+		URL:        pr.HTMLURL,
+		Persona:    evolution,
+		Insertions: pr.Additions,
+		Deletions:  pr.Deletions,
+		Files:      len(files),
+	}
+	r.Intro = checklistVoices[evolution]
+	if r.Intro == "" {
+		r.Intro = checklistVoices["Companion"]
+	}
+	c := activity.StagedChange{Insert: pr.Additions, Delete: pr.Deletions}
+	var code, added, build, ci []string
+	for _, f := range files {
+		c.Files = append(c.Files, f.Filename)
+		switch {
+		case activity.IsTestPath(f.Filename):
+			r.TestsTouched = true
+		case activity.IsDocPath(f.Filename):
+			r.DocsTouched = true
+		case strings.HasPrefix(f.Filename, ".github/"):
+			ci = append(ci, f.Filename)
+		case activity.IsBuildPath(f.Filename):
+			build = append(build, f.Filename)
+		default:
+			code = append(code, f.Filename)
+		}
+		if f.Status == "added" {
+			c.Added = append(c.Added, f.Filename)
+			added = append(added, f.Filename)
+		}
+	}
+	if len(c.Files) > 0 {
+		r.Type = activity.InferChangeType(c)
+	}
+	r.Breaking = breakingSignals(pr, files)
+
+	item := func(id, check, reason string) {
+		r.Items = append(r.Items, ChecklistItem{ID: id, Check: check, Reason: reason})
+	}
+	switch {
+	case len(code) > 0 && !r.TestsTouched:
+		item("tests", "Ask for a test that covers the change, or agree why it doesn't need one", fmt.Sprintf("%s changed and no tests did", i18n.Plural(len(code), "source file")))
+	case r.TestsTouched:
+		item("tests", "Check the tests would fail without the change and assert behavior, not implementation", "test files changed")
+	}
+	switch {
+	case len(added) > 0 && !r.DocsTouched:
+		item("docs", "Check whether the README, docs, or doc comments need to mention this", "it adds "+activity.DescribeFiles(added)+" and no docs changed")
+	case r.DocsTouched:
+		item("docs", "Read the docs change as a newcomer would: is it accurate and easy to follow?", "documentation changed")
+	}
+	if len(r.Breaking) > 0 {
+		item("breaking", "Confirm the breaking change is intended, called out in the description, and versioned", i18n.JoinNames(r.Breaking))
+	}
+	if len(added) > 0 {
+		item("new-files", "Check the new files are named and placed where the project keeps such things", "it adds "+activity.DescribeFiles(added))
+	}
+	if len(build) > 0 {
+		item("deps", "Check dependency versions, licenses, and that the lockfile matches", activity.DescribeFiles(build)+" changed")
+	}
+	if len(ci) > 0 {
+		item("ci", "Check workflow permissions, secrets, and triggers", activity.DescribeFiles(ci)+" changed")
+	}
+	if lines := pr.Additions + pr.Deletions; lines >= activity.LargeCommitLines {
+		item("size", "Ask whether it could land as smaller pull requests", fmt.Sprintf("%d lines across %s", lines, i18n.Plural(len(files), "file")))
+	}
+	item("intent", "Check the description says why, and the diff does only that", "every pull request")
+	item("kindness", "Leave at least one specific, kind comment on something done well", "every review")
+
+	// The persona's favorite goes first; the rest keep their order.
+	if focus := checklistFocus[evolution]; focus != "" {
+		sort.SliceStable(r.Items, func(i, j int) bool { return r.Items[i].ID == focus && r.Items[j].ID != focus })
+	}
+	return r
+}
+
+// breakingSignals lists what hints the pull request may break its callers.
+func breakingSignals(pr prInfo, files []prFile) []string {
+	var signals []string
+	if conventionalBang.MatchString(pr.Title) {
+		signals = append(signals, "the title is marked with !")
+	}
+	if strings.Contains(pr.Body, "BREAKING CHANGE") {
+		signals = append(signals, "the description mentions BREAKING CHANGE")
+	}
+	for _, l := range pr.Labels {
+		if strings.Contains(strings.ToLower(l.Name), "breaking") {
+			signals = append(signals, fmt.Sprintf("it's labeled %q", l.Name))
+		}
+	}
+	for _, f := range files {
+		if activity.IsTestPath(f.Filename) || activity.IsDocPath(f.Filename) {
+			continue
+		}
+		if f.Status == "removed" {
+			signals = append(signals, "it deletes "+path.Base(f.Filename))
+			continue
+		}
+		if n := changedExports(f.Patch); n > 0 {
+			signals = append(signals, fmt.Sprintf("it removes or changes %s in %s", i18n.Plural(n, "exported declaration"), path.Base(f.Filename)))
+		}
+	}
+	return signals
+}
+
+// changedExports counts public declarations a patch removes without adding
+// the same line back.
+func changedExports(patch string) int {
+	added := map[string]bool{}
+	var removed []string
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			added[strings.TrimSpace(line[1:])] = true
+		case strings.HasPrefix(line, "-") && exportedDecl.MatchString(line[1:]):
+			removed = append(removed, strings.TrimSpace(line[1:]))
+		}
+	}
+	n := 0
+	for _, line := range removed {
+		if !added[line] {
+			n++
+		}
+	}
+	return n
+}
+
+// tickChecklist marks the items the agent reported done. An id that isn't on
+// the list is an error, so a typo doesn't pass silently.
+func tickChecklist(r *ChecklistResult, completed []string) error {
+	for _, id := range completed {
+		id = strings.TrimSpace(id)
+		found := false
+		for i := range r.Items {
+			if r.Items[i].ID == id {
+				found = true
+				if !r.Items[i].Done {
+					r.Items[i].Done = true
+					r.Completed = append(r.Completed, id)
+				}
+			}
+		}
+		if !found {
+			ids := make([]string, len(r.Items))
+			for i, item := range r.Items {
+				ids[i] = item.ID
+			}
+			return fmt.Errorf("%q isn't on this checklist; its items are %s", id, strings.Join(ids, ", "))
+		}
+	}
+	return nil
+}
+
+// renderChecklist is the checklist as Markdown, in the pet's voice.
+func renderChecklist(state store.PetState, r ChecklistResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s's review checklist for %s#%d", state.Signature(), state.DisplayName(), r.Repo, r.Number)
+	if r.Title != "" {
+		fmt.Fprintf(&sb, ", %q", r.Title)
+	}
+	if r.Author != "" {
+		fmt.Fprintf(&sb, " by @%s", r.Author)
+	}
+	fmt.Fprintf(&sb, "\n\n%s\n\n", r.Intro)
+	for _, item := range r.Items {
+		box := " "
+		if item.Done {
+			box = "x"
+		}
+		fmt.Fprintf(&sb, "- [%s] %s (`%s`: %s)\n", box, item.Check, item.ID, item.Reason)
+	}
+	switch {
+	case r.AlreadyCounted:
+		sb.WriteString("\nThis pull request's checklist has already earned kindness.")
+	case r.KindnessEarned > 0:
+		fmt.Fprintf(&sb, "\n+%d kindness for %s. Thank you for reviewing with care!", r.KindnessEarned, i18n.Plural(len(r.Completed), "item"))
+	case len(r.Completed) == 0:
+		sb.WriteString("\nOnce you've checked items, call pet_pr_checklist again with their ids in completed to earn kindness.")
+	}
+	return sb.String()
+}
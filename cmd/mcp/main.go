@@ -5,220 +5,378 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/config"
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/sync/errgroup"
 )
 
-// --- Data types (shared with main.go) ---
-
-type PetState struct {
-	Version   int             `json:"version"`
-	LastSync  string          `json:"last_sync"`
-	Mood      int             `json:"mood"`
-	Kindness  int             `json:"kindness"`
-	Logic     int             `json:"logic_shards"`
-	Evolution string          `json:"evolution"`
-	Activity  ActivitySummary `json:"activity"`
-}
-
-type ActivitySummary struct {
-	Commits         int `json:"commits"`
-	MergedPRs       int `json:"merged_prs"`
-	Reviews         int `json:"reviews"`
-	DocComments     int `json:"doc_comments"`
-	RefactorCommits int `json:"refactor_commits"`
-	NewRepos        int `json:"new_repos"`
-	LargeCommits    int `json:"large_commits"`
-	Thoughts        int `json:"thought_fragments"`
-	FixCommits      int `json:"fix_commits"`
-	DocCommits      int `json:"doc_commits"`
-}
-
-type Event struct {
-	Type      string          `json:"type"`
-	CreatedAt time.Time       `json:"created_at"`
-	Payload   json.RawMessage `json:"payload"`
-}
-
-type PushPayload struct {
-	Size    int `json:"size"`
-	Commits []struct {
-		Message string `json:"message"`
-	} `json:"commits"`
-}
-
-type PullRequestPayload struct {
-	PullRequest struct {
-		Merged bool `json:"merged"`
-	} `json:"pull_request"`
-}
-
-type CreatePayload struct {
-	RefType string `json:"ref_type"`
-}
-
-const configFileName = "gh-pet.json"
-
 func main() {
 	rand.Seed(time.Now().UnixNano())
+	verbose := flag.Bool("verbose", false, "log tool calls and gh api requests to stderr")
+	flag.Parse()
+	logging.Setup(*verbose)
+	cfg, _ := config.Load()
+	cfg.Apply()
+	store.Command = "mcp"
 
 	s := server.NewMCPServer(
 		"gitpet",
 		"0.3.0",
 		server.WithToolCapabilities(true),
 	)
+	if cfg.MCPSampling {
+		s.EnableSampling()
+	}
 
 	// pet_status tool
 	statusTool := mcp.NewTool("pet_status",
 		mcp.WithDescription("Show GitPet's current status: evolution, mood, kindness, logic shards, and recent activity summary."),
+		mcp.WithBoolean("absolute",
+			mcp.Description("Show the last sync as an exact local time instead of relative, like \"2h ago\" (default: false)"),
+		),
+		mcp.WithOutputSchema[StatusResult](),
 	)
-	s.AddTool(statusTool, handleStatus)
+	s.AddTool(statusTool, logged("pet_status", handleStatus))
 
 	// pet_feed tool
 	feedTool := mcp.NewTool("pet_feed",
 		mcp.WithDescription("Feed GitPet by syncing your recent GitHub activity (commits, PRs, reviews) from the last 7 days. Updates mood, evolution, and stats."),
+		mcp.WithOutputSchema[FeedResult](),
 	)
-	s.AddTool(feedTool, handleFeed)
+	s.AddTool(feedTool, logged("pet_feed", handleFeed))
 
 	// pet_suggest tool
 	suggestTool := mcp.NewTool("pet_suggest",
-		mcp.WithDescription("Get creative git commit message suggestions from GitPet based on its current personality and mood."),
+		mcp.WithDescription("Get creative git commit message suggestions from GitPet based on its current personality and mood. When changes are staged, the first suggestions name the files and scope being changed."),
 		mcp.WithNumber("count",
 			mcp.Description("Number of suggestions to generate (default: 5)"),
 		),
+		mcp.WithString("type",
+			mcp.Description("Conventional commit type to use for staged-change suggestions, e.g. feat, fix, docs (default: inferred from the diff)"),
+		),
+		mcp.WithOutputSchema[SuggestResult](),
 	)
-	s.AddTool(suggestTool, handleSuggest)
+	s.AddTool(suggestTool, logged("pet_suggest", handleSuggest))
+
+	// pet_ask tool
+	askTool := mcp.NewTool("pet_ask",
+		mcp.WithDescription("Ask GitPet a question in natural language, such as \"how was my week?\" or \"what should I focus on?\". Returns an in-character answer plus the stats behind it."),
+		mcp.WithString("question",
+			mcp.Required(),
+			mcp.Description("The question for the pet"),
+		),
+		mcp.WithOutputSchema[AskAnswer](),
+	)
+	s.AddTool(askTool, logged("pet_ask", handleAsk))
+
+	// pet_explain tool
+	explainTool := mcp.NewTool("pet_explain",
+		mcp.WithDescription("Have GitPet explain a commit in the current repository in plain language, in its own voice. The diff is read locally."),
+		mcp.WithString("sha",
+			mcp.Required(),
+			mcp.Description("The commit to explain: a SHA or any revision git understands, such as HEAD~1"),
+		),
+		mcp.WithOutputSchema[CommitExplanation](),
+	)
+	s.AddTool(explainTool, logged("pet_explain", handleExplain))
+
+	// pet_pr_checklist tool
+	checklistTool := mcp.NewTool("pet_pr_checklist",
+		mcp.WithDescription("Get a review checklist for a pull request, tailored to its diff (tests, docs, breaking changes, dependencies, size) and ordered by GitPet's personality. Call again with the ids you checked in completed to earn Kindness, once per pull request."),
+		mcp.WithNumber("pr",
+			mcp.Required(),
+			mcp.Description("The pull request number"),
+		),
+		mcp.WithString("repo",
+			mcp.Description("The repository as owner/name (default: the origin remote of the current repository)"),
+		),
+		mcp.WithArray("completed",
+			mcp.Description("Ids of checklist items the review has covered, to report back for Kindness"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithOutputSchema[ChecklistResult](),
+	)
+	s.AddTool(checklistTool, logged("pet_pr_checklist", handlePRChecklist))
 
+	logging.Logger.Debug("serving", "transport", "stdio")
 	if err := server.ServeStdio(s); err != nil {
 		fmt.Fprintf(os.Stderr, "gitpet mcp server error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func handleStatus(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	state, _ := loadState()
-	if state.Evolution == "" {
-		state.Evolution = "Lonely"
+// logged wraps a tool handler to log its duration and outcome, and to count
+// the call when telemetry is on.
+func logged(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, req)
+		logging.Logger.Debug("tool call", "tool", name, "duration", time.Since(start).Round(time.Millisecond),
+			"is_error", result != nil && result.IsError, "err", err)
+		if cfg, cfgErr := config.Load(); cfgErr == nil {
+			store.CountUsage(cfg.Telemetry, "mcp:"+name)
+		}
+		return result, err
 	}
-	text := renderStatus(state)
-	return mcp.NewToolResultText(text), nil
 }
 
-func handleFeed(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	state, _ := loadState()
-
-	login, err := ghLogin()
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get GitHub login: %v", err)), nil
+func handleStatus(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	state, _ := store.Load()
+	if state.Evolution == "" {
+		state.Evolution = "Lonely"
 	}
-
-	events, err := fetchEvents(login)
+	cfg, err := config.Load()
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch events: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to load config: %v", err)), nil
 	}
+	history, _ := store.LoadHistory()
+	concerns := pet.WellnessConcerns(state.Activity, store.CurrentStreak(history, time.Now()), cfg.Wellness)
+	text := renderStatus(state, concerns, req.GetBool("absolute", false))
+	return mcp.NewToolResultStructured(statusResult(state, concerns), text), nil
+}
 
-	summary := summarize(events)
-	thoughts := localThoughtFragments()
-	summary.Thoughts = thoughts
-
-	activityTotal := summary.Commits + summary.MergedPRs + summary.Reviews + summary.DocComments + summary.RefactorCommits + summary.NewRepos
-	state.Logic += summary.Commits + summary.MergedPRs*3
-	state.Kindness += summary.Reviews * 2
-	if activityTotal == 0 {
-		state.Mood = maxInt(0, state.Mood-1)
-	} else {
-		state.Mood = minInt(100, state.Mood+summary.Commits+summary.MergedPRs*5+summary.Reviews+summary.DocComments)
+func handleFeed(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	state, _ := store.Load()
+	if state.Departed != "" {
+		return mcp.NewToolResultError(fmt.Sprintf("%s drifted into the Void on %s. Run gh pet hatch in a terminal for a new egg.", state.DisplayName(), state.Departed)), nil
 	}
-	if thoughts > 0 {
-		state.Mood = minInt(100, state.Mood+1)
-	}
-
-	state.Evolution = evolutionFor(summary)
+	before := state
+	cfg, err := config.Load()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to load config: %v", err)), nil
+	}
+
+	// GitHub and the local repository are read concurrently, so a feed
+	// takes about as long as its slowest call rather than all of them.
+	var (
+		login     string
+		events    []Event
+		languages map[string]int
+		thoughts  int
+		tests     int
+		private   ActivitySummary
+		privErr   error
+		depth     ReviewDepth
+		lines     int
+		large     int
+		newcomers int
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		if login, err = ghLogin(gctx); err != nil {
+			return fmt.Errorf("Failed to get GitHub login: %w", err)
+		}
+		if events, err = fetchEvents(gctx, login); err != nil {
+			return fmt.Errorf("Failed to fetch events: %w", err)
+		}
+		events = cfg.RepoFilter().Events(cfg.Bots.HumanEvents(events))
+		newcomers = activity.FirstTimerHelps(events, login)
+		languages = activity.LanguageBreakdown(gctx, events)
+		depth = activity.ReviewDepth(gctx, login, events, time.Duration(cfg.Scoring.QuickReviewHours)*time.Hour)
+		lines, large = activity.CommitStats(gctx, events)
+		if cfg.PrivateActivity && !cfg.PublicOnly {
+			private, privErr = activity.PrivateActivity(gctx, login, pet.SummaryCutoff(time.Now()), activity.EventRepos(events), cfg.RepoFilter())
+		}
+		return nil
+	})
+	g.Go(func() error {
+		thoughts = localThoughtFragments(gctx)
+		return nil
+	})
+	g.Go(func() error {
+		tests = activity.LocalTestFragments(gctx)
+		return nil
+	})
+	if state.AccountCreated == "" {
+		g.Go(func() error {
+			state.AccountCreated = store.AccountCreated(gctx)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return mcp.NewToolResultError(gh.ExplainAccess(err, "pet_feed").Error()), nil
+	}
+
+	summary := discountCommits(cfg.Scoring, events, summarize(events))
+	summary = pet.DiscountReviews(events, summary, state.CreditedReviews, pet.SummaryCutoff(time.Now()))
+	summary.AddPrivate(private)
+	summary.ReviewDepth = depth
+	summary.LinesChanged, summary.LargeCommits = lines, large
+	summary.FirstTimers = newcomers
+	summary.Languages = languages
+	summary.Thoughts = thoughts + state.PendingThoughts
+	state.PendingThoughts = 0
+	summary.TestCommits += tests
+	scoring := cfg.Scoring.WithAbility(state.Evolution, time.Now())
+	if cfg.Wellness.IsRestDay(time.Now()) || state.AwayUntil >= time.Now().Format(store.DayLayout) {
+		scoring.IdleMoodDecay = 0
+	}
+	applyActivity(scoring, &state, summary)
+
+	state.Evolution = pet.EvolutionFor(summary)
 	state.Activity = summary
 	state.LastSync = time.Now().UTC().Format(time.RFC3339)
+	state.Login = login
 	state.Version = 1
+	unlocked := store.UnlockAchievements(&state)
+	store.RecordHistory(events, state.Mood)
+	unlocked = append(unlocked, store.AwardEventBadges(&state, time.Now())...)
 
-	if err := saveState(state); err != nil {
+	if err := store.Save(state); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to save state: %v", err)), nil
 	}
+	store.AppendJournal(sampledDiary(ctx, cfg, state, store.NewJournalEntry("feed", before, state, unlocked, "", summary.CoAuthors)))
+	why := store.ExplainFeed(scoring, before, state, summary, 0)
+	store.RecordExplanation(why)
+	var warnings []string
 
 	var sb strings.Builder
-	sb.WriteString("🍖 Fed GitPet with fresh activity!\n\n")
+	praise := sampledPraise(ctx, cfg, before, state, unlocked)
+	sb.WriteString(fmt.Sprintf("🍖 Fed %s with fresh activity!\n", state.DisplayName()))
+	sb.WriteString("💬 " + praise + "\n\n")
 	sb.WriteString(fmt.Sprintf("Commits: %d | Merged PRs: %d | Reviews: %d | Docs/Comments: %d\n", summary.Commits, summary.MergedPRs, summary.Reviews, summary.DocComments))
+	if summary.Private > 0 {
+		sb.WriteString(fmt.Sprintf("🔒 %d contribution(s) from private repos included.\n", summary.Private))
+	}
+	if privErr != nil {
+		sb.WriteString(fmt.Sprintf("⚠️ %v\n", activity.PrivateError(privErr)))
+		warnings = append(warnings, activity.PrivateError(privErr).Error())
+	}
+	if summary.IgnoredCommits > 0 {
+		sb.WriteString(fmt.Sprintf("%d commit(s) earned nothing: repeats, throwaway branches, or past the hourly allowance.\n", summary.IgnoredCommits))
+	}
 	if summary.MergedPRs > 0 {
 		sb.WriteString("🎆 Fireworks! PRs merged!\n")
 	}
-	sb.WriteString(fmt.Sprintf("Mood: %d | Kindness: %d | Logic Shards: %d\n", state.Mood, state.Kindness, state.Logic))
+	sb.WriteString(fmt.Sprintf("Mood: %d | Kindness: %d | Logic Shards: %d | Mentor: %d\n", state.Mood, state.Kindness, state.Logic, state.Mentor))
+	if d := summary.ReviewDepth; d.Approvals+d.ChangeRequests+d.Comments > 0 {
+		sb.WriteString(fmt.Sprintf("🧭 Reviews: %d approved, %d asked for changes, %d comment(s) left.\n", d.Approvals, d.ChangeRequests, d.Comments))
+	}
+	if summary.DuetCommits > 0 {
+		sb.WriteString(fmt.Sprintf("🎶 Paired with %s on %d commit(s).\n", i18n.JoinNames(summary.CoAuthors), summary.DuetCommits))
+	}
 	sb.WriteString(fmt.Sprintf("Evolution: %s\n", state.Evolution))
+	for _, name := range unlocked {
+		sb.WriteString(fmt.Sprintf("🏆 Achievement unlocked: %s\n", name))
+	}
 	sb.WriteString("\n" + renderArt(state))
 
-	return mcp.NewToolResultText(sb.String()), nil
+	result := feedResult(before, state, unlocked, why, warnings)
+	result.Message = praise
+	return mcp.NewToolResultStructured(result, sb.String()), nil
 }
 
-func handleSuggest(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	state, _ := loadState()
+func handleSuggest(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	state, _ := store.Load()
 	personality := state.Evolution
 	if personality == "" || personality == "Lonely" {
 		personality = "Companion"
 	}
 
 	count := 5
+	commitType := ""
 	if args := req.GetArguments(); args != nil {
 		if c, ok := args["count"].(float64); ok && c > 0 {
 			count = int(c)
 		}
+		if t, ok := args["type"].(string); ok {
+			commitType = strings.TrimSpace(t)
+		}
+	}
+
+	mood := moodDescriptor(state.Mood)
+	cfg, _ := config.Load()
+	suggestions := sampledSuggestions(ctx, cfg, state, personality, commitType, generateSuggestions(ctx, personality, commitType, count), count)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s (%s, Mood: %s) suggests:\n\n", state.Signature(), state.DisplayName(), personality, mood))
+	for i, msg := range suggestions {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, msg))
 	}
+	return mcp.NewToolResultStructured(SuggestResult{Personality: personality, Mood: mood, Suggestions: suggestions}, sb.String()), nil
+}
 
-	suggestions := generateSuggestions(personality, moodDescriptor(state.Mood), count)
-	return mcp.NewToolResultText(suggestions), nil
+func handleExplain(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sha, err := req.RequireString("sha")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	c, err := activity.LoadCommit(ctx, strings.TrimSpace(sha))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	state, _ := store.Load()
+	cfg, _ := config.Load()
+	text := sampledCommit(ctx, cfg, state, c)
+	return mcp.NewToolResultStructured(CommitExplanation{
+		SHA:         c.SHA,
+		Subject:     c.Subject,
+		Author:      c.Author,
+		Type:        c.Change.Type,
+		Files:       c.Change.Files,
+		Insertions:  c.Change.Insert,
+		Deletions:   c.Change.Delete,
+		Explanation: text,
+	}, fmt.Sprintf("%s %s explains %s:\n\n%s", state.Signature(), state.DisplayName(), c.Short, text)), nil
 }
 
 // --- Core Logic ---
 
-func ghLogin() (string, error) {
-	out, err := exec.Command("gh", "api", "user", "--jq", ".login").Output()
-	if err != nil {
-		return "", fmt.Errorf("gh api user failed: %w", err)
+func ghLogin(ctx context.Context) (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := gh.Get(ctx, "user", &user); err != nil {
+		return "", err
 	}
-	login := strings.TrimSpace(string(out))
+	login := user.Login
 	if login == "" {
 		return "", errors.New("unable to determine GitHub login")
 	}
+	if err := dirs.CheckAccount(login); err != nil {
+		return "", err
+	}
 	return login, nil
 }
 
-func fetchEvents(login string) ([]Event, error) {
-	out, err := exec.Command("gh", "api", fmt.Sprintf("users/%s/events", login)).Output()
-	if err != nil {
-		// Fallback to direct HTTP if gh CLI not available
-		return fetchEventsHTTP(login)
-	}
+func fetchEvents(ctx context.Context, login string) ([]Event, error) {
 	var events []Event
-	if err := json.Unmarshal(out, &events); err != nil {
-		return nil, fmt.Errorf("unable to parse events: %w", err)
+	if err := gh.Get(ctx, fmt.Sprintf("users/%s/events", login), &events); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Fallback to anonymous HTTP if neither a token nor gh is available
+		return fetchEventsHTTP(ctx, login)
 	}
 	return events, nil
 }
 
-func fetchEventsHTTP(login string) ([]Event, error) {
+func fetchEventsHTTP(ctx context.Context, login string) ([]Event, error) {
 	url := fmt.Sprintf("https://api.github.com/users/%s/events", login)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", "gitpet-mcp-server")
 
-	client := http.Client{Timeout: 10 * time.Second}
+	client := http.Client{Timeout: time.Duration(gh.Timeouts.GitHubSeconds) * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -232,125 +390,88 @@ func fetchEventsHTTP(login string) ([]Event, error) {
 	return events, nil
 }
 
-func localThoughtFragments() int {
-	if exec.Command("git", "rev-parse", "--is-inside-work-tree").Run() != nil {
+func localThoughtFragments(ctx context.Context) int {
+	if !gh.InWorkTree(ctx) {
 		return 0
 	}
-	status, _ := exec.Command("git", "status", "--porcelain").Output()
-	diff, _ := exec.Command("git", "diff", "--stat").Output()
+	status, _ := gh.GitOutput(ctx, "status", "--porcelain")
+	diff, _ := gh.GitOutput(ctx, "diff", "--stat")
 	if len(bytes.TrimSpace(status)) > 0 || len(bytes.TrimSpace(diff)) > 0 {
 		return 1
 	}
 	return 0
 }
 
-func summarize(events []Event) ActivitySummary {
-	cutoff := time.Now().Add(-7 * 24 * time.Hour)
-	summary := ActivitySummary{}
-	for _, event := range events {
-		if event.CreatedAt.Before(cutoff) {
-			continue
-		}
-		switch event.Type {
-		case "PushEvent":
-			var payload PushPayload
-			if json.Unmarshal(event.Payload, &payload) == nil {
-				summary.Commits += len(payload.Commits)
-				if payload.Size >= 10 {
-					summary.LargeCommits++
-				}
-				for _, commit := range payload.Commits {
-					classifyCommit(commit.Message, &summary)
-				}
-			}
-		case "PullRequestEvent":
-			var payload PullRequestPayload
-			if json.Unmarshal(event.Payload, &payload) == nil && payload.PullRequest.Merged {
-				summary.MergedPRs++
-			}
-		case "PullRequestReviewEvent":
-			summary.Reviews++
-		case "PullRequestReviewCommentEvent":
-			summary.Reviews++
-			summary.DocComments++
-		case "IssueCommentEvent":
-			summary.DocComments++
-		case "CreateEvent":
-			var payload CreatePayload
-			if json.Unmarshal(event.Payload, &payload) == nil && payload.RefType == "repository" {
-				summary.NewRepos++
-			}
-		}
-	}
-	return summary
-}
+// --- Rendering ---
 
-func classifyCommit(message string, summary *ActivitySummary) {
-	lower := strings.ToLower(message)
-	if strings.Contains(lower, "fix") || strings.Contains(lower, "bug") {
-		summary.FixCommits++
+func renderStatus(state store.PetState, concerns []string, absolute bool) string {
+	tone := activityTone(state.DisplayName(), state.Activity)
+	art := renderArt(state)
+	lines := []string{
+		fmt.Sprintf("%s %s Status", state.Signature(), state.Introduction()),
+		fmt.Sprintf("Evolution: %s", state.Evolution),
+		fmt.Sprintf("Mood: %d | Kindness: %d | Logic Shards: %d", state.Mood, state.Kindness, state.Logic),
+		fmt.Sprintf("Last Sync: %s", i18n.DisplayTime(state.LastSync, absolute)),
+		fmt.Sprintf("Activity (7d): Commits %d, Merged PRs %d, Reviews %d, Docs/Comments %d, Tests %d", state.Activity.Commits, state.Activity.MergedPRs, state.Activity.Reviews, state.Activity.DocComments, state.Activity.TestCommits),
 	}
-	if strings.Contains(lower, "doc") || strings.Contains(lower, "readme") || strings.Contains(lower, "comment") {
-		summary.DocCommits++
+	if pet.IssueTriage(state.Activity)+state.Activity.IssueComments > 0 {
+		lines = append(lines, fmt.Sprintf("Issues (7d): %d opened, %d closed, %d labeled, %d comments", state.Activity.IssuesOpened, state.Activity.IssuesClosed, state.Activity.IssuesLabeled, state.Activity.IssueComments))
 	}
-	if strings.Contains(lower, "refactor") || strings.Contains(lower, "cleanup") || strings.Contains(lower, "remove") || strings.Contains(lower, "delete") {
-		summary.RefactorCommits++
+	if langs := activity.LanguageLine(state.Activity.Languages); langs != "" {
+		lines = append(lines, fmt.Sprintf("Languages (7d): %s", langs))
 	}
-}
-
-func evolutionFor(summary ActivitySummary) string {
-	if summary.Commits+summary.MergedPRs+summary.Reviews+summary.DocComments+summary.RefactorCommits+summary.NewRepos == 0 {
-		return "Lonely"
+	if len(state.Achievements) > 0 {
+		lines = append(lines, fmt.Sprintf("Achievements: %s", strings.Join(state.Achievements, ", ")))
 	}
-	pioneer := summary.Commits + summary.NewRepos*2
-	guardian := summary.Reviews*2 + summary.MergedPRs*2 + summary.FixCommits
-	bard := summary.DocComments*2 + summary.DocCommits
-	voidScore := summary.RefactorCommits * 2
-	best := "Pioneer"
-	bestScore := pioneer
-	if guardian > bestScore {
-		best = "Guardian"
-		bestScore = guardian
+	if len(state.Badges) > 0 {
+		lines = append(lines, fmt.Sprintf("Limited badges: %s", strings.Join(state.Badges, ", ")))
 	}
-	if bard > bestScore {
-		best = "Bard"
-		bestScore = bard
+	if len(state.Companions) > 0 {
+		var names []string
+		for _, c := range state.Companions {
+			names = append(names, fmt.Sprintf("%s %s (fork %s)", c.Sprite, c.DisplayName(), c.Fork))
+		}
+		lines = append(lines, fmt.Sprintf("Companions: %s", strings.Join(names, ", ")))
 	}
-	if voidScore > bestScore {
-		best = "Void"
+	for _, event := range store.ActiveEvents(time.Now()) {
+		lines = append(lines, fmt.Sprintf("Event: %s %s until %s, quest: %s", event.Icon, event.Name, event.End.AddDate(0, 0, -1).Format("Jan 2"), event.Quest))
 	}
-	return best
-}
-
-// --- Rendering ---
-
-func renderStatus(state PetState) string {
-	tone := activityTone(state.Activity)
-	art := renderArt(state)
-	lines := []string{
-		"🐾 GitPet Status",
-		fmt.Sprintf("Evolution: %s", state.Evolution),
-		fmt.Sprintf("Mood: %d | Kindness: %d | Logic Shards: %d", state.Mood, state.Kindness, state.Logic),
-		fmt.Sprintf("Last Sync: %s", displayTime(state.LastSync)),
-		fmt.Sprintf("Activity (7d): Commits %d, Merged PRs %d, Reviews %d, Docs/Comments %d", state.Activity.Commits, state.Activity.MergedPRs, state.Activity.Reviews, state.Activity.DocComments),
-		tone,
-		art,
+	if len(concerns) > 0 {
+		lines = append(lines, "Wellness: "+strings.Join(concerns, " "))
+	} else {
+		lines = append(lines, "Wellness: 💚 Balanced rhythm.")
 	}
+	lines = append(lines, tone, art)
 	return strings.Join(lines, "\n")
 }
 
-func renderArt(state PetState) string {
+func renderArt(state store.PetState) string {
 	art := artFor(state.Evolution)
 	special := ""
-	if state.Evolution == "Pioneer" && rand.Intn(5) == 0 {
-		special = "\n🗝️  Found a tiny treasure chest!"
+	if pet.FoundTreasure(state.Evolution) {
+		special = "\n" + i18n.Tr("🗝️  Found a tiny treasure chest!")
 	}
 	if state.Evolution == "Guardian" {
-		special = "\n🛡️  Shielding your logs."
+		special = "\n" + i18n.Tr("🛡️  Shielding your logs.")
 	}
 	if state.Evolution == "Bard" {
 		special = fmt.Sprintf("\n📜 %s", dailyProverb())
 	}
+	if state.Evolution == "Sentinel" {
+		special = "\n" + i18n.Tr("🧪 Every test is a watchtower.")
+	}
+	if state.Evolution == "Curator" {
+		special = "\n" + i18n.Tr("🗂️  Tending the issue garden.")
+	}
+	lang := activity.DominantLanguage(state.Activity.Languages)
+	if accessory := activity.LanguageAccessory(lang); accessory != "" {
+		art = "  " + i18n.Tr(accessory) + "\n" + art
+	}
+	if proverb := activity.LanguageProverb(lang); proverb != "" {
+		special += fmt.Sprintf("\n💬 %s", i18n.Tr(proverb))
+	}
+	art, special = store.ApplyBehavior(art, special, store.SleepingArt(), state, time.Now())
+	art += store.CompanionTrail(state.Companions)
 	return art + special
 }
 
@@ -385,6 +506,25 @@ func artFor(evolution string) string {
 			"  ╰┬───┬╯\n" +
 			"   │   │\n" +
 			"   ╰─♪─╯"
+	case "Sentinel":
+		return "" +
+			"   ┌─🧪─┐\n" +
+			"   ╭───╮\n" +
+			"  (◎_◎ )\n" +
+			"  ╭┤ ✓ ├╮\n" +
+			"  │╰───╯│\n" +
+			"  ╰┬───┬╯\n" +
+			"   │   │\n" +
+			"   ╰───╯"
+	case "Curator":
+		return "" +
+			"   ╭─🏷─╮\n" +
+			"  (◔ ◡ ◔)\n" +
+			"  ╭┤ ☰ ├╮  🗂️\n" +
+			"  │╰───╯│\n" +
+			"  ╰┬───┬╯\n" +
+			"   │   │\n" +
+			"   ╰───╯"
 	case "Void":
 		return "" +
 			"    · · ·\n" +
@@ -415,17 +555,17 @@ func artFor(evolution string) string {
 	}
 }
 
-func activityTone(summary ActivitySummary) string {
-	total := summary.Commits + summary.MergedPRs + summary.Reviews + summary.DocComments + summary.NewRepos + summary.RefactorCommits
+func activityTone(name string, summary ActivitySummary) string {
+	total := pet.Activity(summary)
 	switch {
 	case total >= 20:
-		return "🔥 Intensity: blazing. GitPet is thriving in the Cache."
+		return "🔥 " + i18n.Tr("Intensity: blazing. %s is thriving in the Cache.", name)
 	case total >= 8:
-		return "✨ Intensity: steady. GitPet hums with creative heat."
+		return "✨ " + i18n.Tr("Intensity: steady. %s hums with creative heat.", name)
 	case total >= 1:
-		return "🌱 Intensity: gentle. GitPet feels acknowledged."
+		return "🌱 " + i18n.Tr("Intensity: gentle. %s feels acknowledged.", name)
 	default:
-		return "💤 Intensity: quiet. GitPet grows a little lonely."
+		return "💤 " + i18n.Tr("Intensity: quiet. %s grows a little lonely.", name)
 	}
 }
 
@@ -451,17 +591,12 @@ func dailyProverb() string {
 		"Bugs fear patient eyes.",
 	}
 	today := time.Now().YearDay()
-	return proverbs[today%len(proverbs)]
+	return i18n.Tr(proverbs[today%len(proverbs)])
 }
 
-func displayTime(ts string) string {
-	if ts == "" {
-		return "Never"
-	}
-	return ts
-}
-
-func generateSuggestions(personality, mood string, count int) string {
+// generateSuggestions returns up to count commit messages in personality's
+// voice, starting with any that describe the staged change.
+func generateSuggestions(ctx context.Context, personality, commitType string, count int) []string {
 	templates := map[string][]string{
 		"Pioneer": {
 			"🗺️ feat: chart unknown territory in %s",
@@ -499,6 +634,24 @@ func generateSuggestions(personality, mood string, count int) string {
 			"⚫ refactor: simplify until nothing remains but clarity",
 			"🔮 refactor: reshape the formless into structure",
 		},
+		"Sentinel": {
+			"🧪 test: post a sentinel at the module's edge",
+			"✅ test: prove the happy path stays happy",
+			"🔬 test: catch the regression before it hatches",
+			"🧱 test: build a wall of table-driven cases",
+			"🚨 test: sound the alarm on the flaky edge case",
+			"📏 test: measure twice, assert once",
+			"🛰️ test: watch over the integration seams",
+		},
+		"Curator": {
+			"🗂️ chore: label the wandering issues",
+			"🏷️ chore: bring order to the backlog",
+			"🧹 fix: close the loop on a long-lost report",
+			"📋 docs: add a triage guide to the issue templates",
+			"🔖 chore: tag the good first issues for newcomers",
+			"🪴 chore: prune stale issues from the garden",
+			"📬 fix: answer the issue that waited patiently",
+		},
 		"Companion": {
 			"💡 feat: breathe life into the first feature",
 			"🌱 feat: plant the seed of something new",
@@ -512,58 +665,14 @@ func generateSuggestions(personality, mood string, count int) string {
 	if !ok {
 		msgs = templates["Companion"]
 	}
-
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("🐾 GitPet (%s, Mood: %s) suggests:\n\n", personality, mood))
-	for i := 0; i < count && i < len(msgs); i++ {
-		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, msgs[i]))
-	}
-	return sb.String()
-}
-
-// --- State persistence ---
-
-func loadState() (PetState, error) {
-	path, err := configPath()
-	if err != nil {
-		return PetState{}, err
-	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return PetState{Mood: 5, Evolution: "Lonely"}, nil
-		}
-		return PetState{}, err
-	}
-	var state PetState
-	if err := json.Unmarshal(data, &state); err != nil {
-		return PetState{}, err
+	if change, ok := activity.ReadStagedChange(ctx); ok {
+		msgs = append(activity.ContextSuggestions(personality, change, commitType, count), msgs...)
 	}
-	return state, nil
-}
 
-func saveState(state PetState) error {
-	path, err := configPath()
-	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0o600)
+	return msgs[:minInt(count, len(msgs))]
 }
 
-func configPath() (string, error) {
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(configDir, "gh", configFileName), nil
-}
+// --- State persistence ---
 
 func minInt(a, b int) int {
 	if a < b {
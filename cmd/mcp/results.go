@@ -0,0 +1,168 @@
+package main
+
+import (
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// PetSnapshot is the pet as every tool's structured result reports it.
+type PetSnapshot struct {
+	Name         string   `json:"name" jsonschema_description:"The pet's name"`
+	Evolution    string   `json:"evolution" jsonschema:"enum=Lonely,enum=Pioneer,enum=Guardian,enum=Bard,enum=Void,enum=Sentinel,enum=Curator"`
+	Mood         int      `json:"mood" jsonschema_description:"Current mood, 0-100"`
+	Kindness     int      `json:"kindness"`
+	Logic        int      `json:"logic_shards"`
+	Mentor       int      `json:"mentor"`
+	Achievements []string `json:"achievements,omitempty"`
+	Badges       []string `json:"badges,omitempty" jsonschema_description:"Limited-time badges from seasonal events"`
+	LastSync     string   `json:"last_sync,omitempty" jsonschema_description:"When the pet was last fed, in RFC 3339"`
+}
+
+// ActivityCounts is the last 7 days of activity behind the pet's stats.
+type ActivityCounts struct {
+	Commits       int            `json:"commits"`
+	MergedPRs     int            `json:"merged_prs"`
+	Reviews       int            `json:"reviews"`
+	DocComments   int            `json:"doc_comments"`
+	TestCommits   int            `json:"test_commits"`
+	IssuesOpened  int            `json:"issues_opened"`
+	IssuesClosed  int            `json:"issues_closed"`
+	IssueComments int            `json:"issue_comments"`
+	DuetCommits   int            `json:"duet_commits" jsonschema_description:"Commits with Co-authored-by trailers"`
+	Ignored       int            `json:"ignored_commits" jsonschema_description:"Commits that earned nothing: repeats, throwaway branches, or past the hourly allowance"`
+	Languages     map[string]int `json:"languages,omitempty"`
+}
+
+// StatusResult is pet_status's structured result.
+type StatusResult struct {
+	Pet      PetSnapshot    `json:"pet"`
+	Activity ActivityCounts `json:"activity"`
+	Wellness []string       `json:"wellness,omitempty" jsonschema_description:"Wellness concerns, if any"`
+}
+
+// FeedResult is pet_feed's structured result. Deltas are how much each
+// stat moved, and Contributions what moved them.
+type FeedResult struct {
+	Pet PetSnapshot `json:"pet"`
+	// Message is the pet's reaction, written by the client's model when
+	// sampling is on.
+	Message           string         `json:"message"`
+	PreviousEvolution string         `json:"previous_evolution"`
+	Evolved           bool           `json:"evolved" jsonschema_description:"Whether the pet took a new form in this feed"`
+	Deltas            StatDeltas     `json:"deltas"`
+	Unlocked          []string       `json:"unlocked,omitempty" jsonschema_description:"Achievements and badges earned in this feed"`
+	Activity          ActivityCounts `json:"activity"`
+	Contributions     []Contribution `json:"contributions,omitempty" jsonschema_description:"What added or took away each point; caps get a line of their own"`
+	Warnings          []string       `json:"warnings,omitempty"`
+}
+
+type StatDeltas struct {
+	Mood     int `json:"mood"`
+	Kindness int `json:"kindness"`
+	Logic    int `json:"logic_shards"`
+	Mentor   int `json:"mentor"`
+}
+
+// SuggestResult is pet_suggest's structured result.
+type SuggestResult struct {
+	Personality string   `json:"personality" jsonschema_description:"The evolution whose voice the suggestions take"`
+	Mood        string   `json:"mood"`
+	Suggestions []string `json:"suggestions" jsonschema_description:"Commit messages, best first; those naming staged files come first"`
+}
+
+// CommitExplanation is pet_explain's structured result.
+type CommitExplanation struct {
+	SHA         string   `json:"sha"`
+	Subject     string   `json:"subject"`
+	Author      string   `json:"author"`
+	Type        string   `json:"type,omitempty" jsonschema_description:"Conventional commit type inferred from the files, e.g. feat, fix, docs"`
+	Files       []string `json:"files,omitempty"`
+	Insertions  int      `json:"insertions"`
+	Deletions   int      `json:"deletions"`
+	Explanation string   `json:"explanation" jsonschema_description:"The pet's plain-language account of the commit"`
+}
+
+// ChecklistItem is one thing to check in a pull request review.
+type ChecklistItem struct {
+	ID     string `json:"id" jsonschema_description:"Pass this back in completed once the item is checked"`
+	Check  string `json:"check"`
+	Reason string `json:"reason" jsonschema_description:"What in the pull request put this item on the list"`
+	Done   bool   `json:"done"`
+}
+
+// ChecklistResult is pet_pr_checklist's structured result.
+type ChecklistResult struct {
+	Repo         string          `json:"repo"`
+	Number       int             `json:"number"`
+	Title        string          `json:"title"`
+	Author       string          `json:"author"`
+	URL          string          `json:"url"`
+	Persona      string          `json:"persona" jsonschema_description:"The evolution whose voice and priorities shape the checklist"`
+	Intro        string          `json:"intro"`
+	Type         string          `json:"type,omitempty" jsonschema_description:"Conventional commit type inferred from the files, e.g. feat, fix, docs"`
+	Files        int             `json:"files"`
+	Insertions   int             `json:"insertions"`
+	Deletions    int             `json:"deletions"`
+	TestsTouched bool            `json:"tests_touched"`
+	DocsTouched  bool            `json:"docs_touched"`
+	Breaking     []string        `json:"breaking,omitempty" jsonschema_description:"Signs the change may break its callers"`
+	Items        []ChecklistItem `json:"items"`
+	// Completed, KindnessEarned, and AlreadyCounted answer a call that
+	// reported items done.
+	Completed      []string    `json:"completed,omitempty"`
+	KindnessEarned int         `json:"kindness_earned" jsonschema_description:"Kindness granted for the completed items; each pull request earns once"`
+	AlreadyCounted bool        `json:"already_counted,omitempty"`
+	Pet            PetSnapshot `json:"pet"`
+}
+
+func petSnapshot(s store.PetState) PetSnapshot {
+	return PetSnapshot{
+		Name:         s.DisplayName(),
+		Evolution:    s.Evolution,
+		Mood:         s.Mood,
+		Kindness:     s.Kindness,
+		Logic:        s.Logic,
+		Mentor:       s.Mentor,
+		Achievements: s.Achievements,
+		Badges:       s.Badges,
+		LastSync:     s.LastSync,
+	}
+}
+
+func activityCounts(s ActivitySummary) ActivityCounts {
+	return ActivityCounts{
+		Commits:       s.Commits,
+		MergedPRs:     s.MergedPRs,
+		Reviews:       s.Reviews,
+		DocComments:   s.DocComments,
+		TestCommits:   s.TestCommits,
+		IssuesOpened:  s.IssuesOpened,
+		IssuesClosed:  s.IssuesClosed,
+		IssueComments: s.IssueComments,
+		DuetCommits:   s.DuetCommits,
+		Ignored:       s.IgnoredCommits,
+		Languages:     s.Languages,
+	}
+}
+
+func feedResult(before, after store.PetState, unlocked []string, why store.Explanation, warnings []string) FeedResult {
+	return FeedResult{
+		Pet:               petSnapshot(after),
+		PreviousEvolution: before.Evolution,
+		Evolved:           store.Evolved(before, after),
+		Deltas: StatDeltas{
+			Mood:     after.Mood - before.Mood,
+			Kindness: after.Kindness - before.Kindness,
+			Logic:    after.Logic - before.Logic,
+			Mentor:   after.Mentor - before.Mentor,
+		},
+		Unlocked:      unlocked,
+		Activity:      activityCounts(after.Activity),
+		Contributions: why.Contributions,
+		Warnings:      warnings,
+	}
+}
+
+// statusResult is what pet_status reports alongside its card.
+func statusResult(state store.PetState, concerns []string) StatusResult {
+	return StatusResult{Pet: petSnapshot(state), Activity: activityCounts(state.Activity), Wellness: concerns}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/config"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// samplingTimeout bounds one request to the client's model. Clients may ask
+// the Keeper to approve each request, so it's generous.
+const samplingTimeout = 30 * time.Second
+
+// maxSampledRunes keeps a runaway reply out of the journal and tool output.
+const maxSampledRunes = 600
+
+// canSample reports whether the pet may ask the connected client's model to
+// write for it: the Keeper turned mcp_sampling on and the client said it
+// can take sampling requests.
+func canSample(ctx context.Context, cfg config.Settings) bool {
+	if !cfg.MCPSampling || server.ServerFromContext(ctx) == nil {
+		return false
+	}
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	return ok && session.GetClientCapabilities().Sampling != nil
+}
+
+// petVoice asks the client's model to write task in the pet's voice, and
+// returns fallback when it can't or won't.
+func petVoice(ctx context.Context, cfg config.Settings, state store.PetState, task, fallback string) string {
+	if !canSample(ctx, cfg) {
+		return fallback
+	}
+	ctx, cancel := context.WithTimeout(ctx, samplingTimeout)
+	defer cancel()
+	req := mcp.CreateMessageRequest{CreateMessageParams: mcp.CreateMessageParams{
+		Messages:     []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent(task)}},
+		SystemPrompt: personaPrompt(state),
+		MaxTokens:    300,
+		Temperature:  0.9,
+	}}
+	result, err := server.ServerFromContext(ctx).RequestSampling(ctx, req)
+	if err != nil {
+		logging.Logger.Debug("sampling", "err", err)
+		return fallback
+	}
+	var text string
+	switch c := result.Content.(type) {
+	case mcp.TextContent:
+		text = c.Text
+	case *mcp.TextContent:
+		text = c.Text
+	}
+	text = strings.Trim(strings.TrimSpace(text), `"`)
+	if text == "" {
+		return fallback
+	}
+	if r := []rune(text); len(r) > maxSampledRunes {
+		text = string(r[:maxSampledRunes]) + "…"
+	}
+	logging.Logger.Debug("sampling", "model", result.Model)
+	return text
+}
+
+// personaPrompt tells the model who it's speaking as.
+func personaPrompt(state store.PetState) string {
+	evolution := state.Evolution
+	if evolution == "" {
+		evolution = "Lonely"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "You are %s, a small terminal pet that lives in a developer's GitHub activity. ", state.DisplayName())
+	fmt.Fprintf(&sb, "Your form is %s and your mood is %s (%d/100). ", evolution, moodDescriptor(state.Mood), state.Mood)
+	sb.WriteString("You call the developer your Keeper. Speak in the first person, warmly and briefly, with at most one emoji. ")
+	sb.WriteString("Never invent activity beyond what you're told, and never use Markdown headings or lists unless asked. ")
+	if i18n.Locale != "en" {
+		fmt.Fprintf(&sb, "Write in the language with the tag %s.", i18n.Locale)
+	}
+	return sb.String()
+}
+
+// weekFacts is the activity a sampled line may mention.
+func weekFacts(s ActivitySummary) string {
+	return fmt.Sprintf("This week the Keeper pushed %d commits, merged %d pull requests, gave %d reviews, wrote %d docs or comments, and touched tests in %d commits.",
+		s.Commits, s.MergedPRs, s.Reviews, s.DocComments, s.TestCommits)
+}
+
+// sampledPraise is the pet's reaction to a feed.
+func sampledPraise(ctx context.Context, cfg config.Settings, before, after store.PetState, unlocked []string) string {
+	task := weekFacts(after.Activity) + fmt.Sprintf(" Your mood went from %d to %d.", before.Mood, after.Mood)
+	if store.Evolved(before, after) {
+		task += fmt.Sprintf(" You just evolved from %s into %s.", before.Evolution, after.Evolution)
+	}
+	if len(unlocked) > 0 {
+		task += " You earned " + strings.Join(unlocked, ", ") + "."
+	}
+	task += " React to being fed in one or two short sentences."
+	return petVoice(ctx, cfg, after, task, activityTone(after.DisplayName(), after.Activity))
+}
+
+// sampledDiary rewrites a journal page in the pet's own words, keeping
+// entry's facts.
+func sampledDiary(ctx context.Context, cfg config.Settings, state store.PetState, entry store.JournalEntry) store.JournalEntry {
+	task := "Here is today's diary entry: " + entry.Text +
+		" Rewrite it as your own diary entry in two or three sentences, keeping every fact and adding none. Reply with the entry only."
+	entry.Text = petVoice(ctx, cfg, state, task, entry.Text)
+	return entry
+}
+
+// sampledCommit explains c in the pet's words, keeping to what the commit
+// shows.
+func sampledCommit(ctx context.Context, cfg config.Settings, state store.PetState, c activity.CommitInfo) string {
+	task := c.Facts() + "\n\nExplain this commit to your Keeper in plain language, in two to four short sentences. Say what changed and why it matters, without inventing details."
+	return petVoice(ctx, cfg, state, task, activity.NarrateCommit(state, c))
+}
+
+// sampledSuggestions asks for count commit messages, for the staged change
+// if there is one, topping up from templates when the model gives fewer.
+func sampledSuggestions(ctx context.Context, cfg config.Settings, state store.PetState, personality, commitType string, templates []string, count int) []string {
+	if !canSample(ctx, cfg) {
+		return templates
+	}
+	task := fmt.Sprintf("Suggest %d git commit messages in the voice of a %s", count, personality)
+	if change, ok := activity.ReadStagedChange(ctx); ok {
+		task += fmt.Sprintf(" for this staged change: files %s, +%d -%d lines.", strings.Join(change.Files, ", "), change.Insert, change.Delete)
+	} else {
+		task += " for the Keeper's next commit."
+	}
+	if commitType != "" {
+		task += fmt.Sprintf(" Use the Conventional Commits type %q.", commitType)
+	} else {
+		task += " Use Conventional Commits."
+	}
+	task += " Each starts with one emoji. Reply with one message per line and nothing else."
+	reply := petVoice(ctx, cfg, state, task, "")
+	var msgs []string
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*0123456789.)"))
+		if line != "" && len(msgs) < count {
+			msgs = append(msgs, line)
+		}
+	}
+	return append(msgs, templates...)[:minInt(count, len(msgs)+len(templates))]
+}
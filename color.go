@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Terminal color depths, in bits per pixel as terminals advertise them.
+const (
+	depthNone      = 0
+	depthBasic     = 8
+	depth256       = 256
+	depthTrueColor = 24
+)
+
+// colorDepth inspects the environment to decide how rich output can be.
+// NO_COLOR (https://no-color.org) always wins.
+func colorDepth() int {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return depthNone
+	}
+	if forced := os.Getenv("GITPET_COLOR"); forced != "" {
+		switch forced {
+		case "none":
+			return depthNone
+		case "basic":
+			return depthBasic
+		case "256":
+			return depth256
+		case "truecolor":
+			return depthTrueColor
+		}
+	}
+	colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return depthTrueColor
+	}
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "dumb":
+		return depthNone
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "direct"):
+		return depthTrueColor
+	case strings.Contains(term, "256color"):
+		return depth256
+	}
+	if runtime.GOOS == "windows" && os.Getenv("WT_SESSION") != "" {
+		// Windows Terminal handles 24-bit color; legacy conhost only the basics.
+		return depthTrueColor
+	}
+	return depthBasic
+}
+
+type rgb struct{ r, g, b uint8 }
+
+func parseHex(s string) (rgb, bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return rgb{}, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return rgb{}, false
+	}
+	return rgb{uint8(v >> 16), uint8(v >> 8), uint8(v)}, true
+}
+
+func (c rgb) lerp(to rgb, t float64) rgb {
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*t + 0.5) }
+	return rgb{mix(c.r, to.r), mix(c.g, to.g), mix(c.b, to.b)}
+}
+
+// ansi renders the color as a foreground escape for the given depth,
+// approximating on terminals that can't show it exactly.
+func (c rgb) ansi(depth int) string {
+	switch depth {
+	case depthNone:
+		return ""
+	case depthTrueColor:
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.r, c.g, c.b)
+	case depth256:
+		cube := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+		return fmt.Sprintf("\x1b[38;5;%dm", 16+36*cube(c.r)+6*cube(c.g)+cube(c.b))
+	default:
+		return c.basic()
+	}
+}
+
+// basic maps the color onto the nearest of the eight standard ANSI colors.
+func (c rgb) basic() string {
+	on := func(v uint8) int {
+		if v >= 128 {
+			return 1
+		}
+		return 0
+	}
+	index := on(c.r) | on(c.g)<<1 | on(c.b)<<2
+	if index == 0 {
+		return "\x1b[90m"
+	}
+	return fmt.Sprintf("\x1b[%dm", 30+index)
+}
+
+// evolutionHex is the rich palette used when the terminal can do better than
+// the basic eight colors.
+var evolutionHex = map[string]string{
+	"Pioneer":  "#f2b134",
+	"Guardian": "#4a90e2",
+	"Bard":     "#c86dd7",
+	"Void":     "#8a8f98",
+	"Sentinel": "#2ec4b6",
+	"Curator":  "#6abf69",
+}
+
+// Mood bars fade from moodLow to moodHigh across their length.
+const (
+	moodLowHex  = "#e05252"
+	moodMidHex  = "#f2c94c"
+	moodHighHex = "#5cc66a"
+)
+
+func gradientAt(t float64) rgb {
+	low, _ := parseHex(moodLowHex)
+	mid, _ := parseHex(moodMidHex)
+	high, _ := parseHex(moodHighHex)
+	if t < 0.5 {
+		return low.lerp(mid, t*2)
+	}
+	return mid.lerp(high, (t-0.5)*2)
+}
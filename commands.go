@@ -0,0 +1,408 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Exit codes: 1 when a command fails, 2 when it was invoked wrongly.
+const (
+	exitError = 1
+	exitUsage = 2
+)
+
+// command is one gh pet subcommand. Commands with flags parse them with
+// newFlagSet, which answers --help; for the rest the router does.
+type command struct {
+	Name    string
+	Aliases []string
+	// Usage is what follows the name in the synopsis, e.g. "[--watch 5m]".
+	Usage      string
+	Summary    string
+	Run        func(args []string) error
+	Sub        []*command
+	Completion commandSpec
+}
+
+// usageError is a mistake in how a command was invoked. shown is set when
+// the flag package has already printed it along with the command's help.
+type usageError struct {
+	err   error
+	shown bool
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+func usageErrorf(format string, args ...any) error {
+	return usageError{err: fmt.Errorf(format, args...)}
+}
+
+// commands is filled in by init, since the help command refers back to it.
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{Name: "feed", Usage: "[--repo owner/repo]... [--login user]", Summary: "Sync recent GitHub activity and update pet stats", Run: runFeed,
+			Completion: commandSpec{Flags: []string{"--repo=", "--login="}}},
+		{Name: "status", Aliases: []string{"st"}, Usage: "[--absolute] [--graphics auto|ascii|pixel] [--login user]", Summary: "Render the current pet state", Run: runStatus,
+			Completion: commandSpec{Flags: []string{"--absolute", "--graphics=", "--login="}, FlagValues: map[string][]string{"--graphics": {"auto", "ascii", "pixel"}}}},
+		{Name: "stats", Usage: "[--weeks 4] [--months 3]", Summary: "Weekly/monthly rollups, trends, and busiest day from history", Run: runStats,
+			Completion: commandSpec{Flags: []string{"--weeks=", "--months="}}},
+		{Name: "compare", Usage: "[week|month]", Summary: "This week or month so far beside the same stretch of the last one, with the pet's take", Run: runCompare,
+			Completion: commandSpec{Args: fixedArgs(comparePeriods...)}},
+		{Name: "report", Usage: "--week [--format markdown|html] [--out file]", Summary: "Weekly digest for yourself or a retro", Run: runReport,
+			Completion: commandSpec{
+				Flags:      []string{"--week", "--format=", "--out="},
+				FlagValues: map[string][]string{"--format": {"markdown", "html"}},
+			}},
+		{Name: "journal", Aliases: []string{"diary"}, Usage: "[--since date] [--until date] [--last N] [--export file]", Summary: "Read the pet's diary", Run: runJournal,
+			Completion: commandSpec{Flags: []string{"--since=", "--until=", "--last=", "--export="}}},
+		{Name: "why", Usage: "[--last N] [--json]", Summary: "Which activity moved each stat in the last feed, and why the pet evolved", Run: runWhy,
+			Completion: commandSpec{Flags: []string{"--last=", "--json"}}},
+		{Name: "undo", Usage: "[--force]", Summary: "Roll back the last change to your pet, such as an accidental feed", Run: runUndo,
+			Completion: commandSpec{Flags: []string{"--force"}}},
+		{Name: "changes", Usage: "[--last N]", Summary: "What each recent command changed about your pet", Run: runChanges,
+			Completion: commandSpec{Flags: []string{"--last="}}},
+		{Name: "compact", Usage: "[--keep-days N] [--dry-run]", Summary: "Roll old history into monthly totals and trim the journal and undo log", Run: runCompact,
+			Completion: commandSpec{Flags: []string{"--keep-days=", "--dry-run"}}},
+		{Name: "backfill", Usage: "[--git [--since YYYY-MM-DD]] [--dry-run]", Summary: "Rebuild past history from GitHub's events archive, and git log with --git, crediting the weeks before your pet", Run: runBackfill,
+			Completion: commandSpec{Flags: []string{"--git", "--since=", "--dry-run"}}},
+		{Name: "archive", Usage: "--from YYYY-MM-DD [--to YYYY-MM-DD] [--jobs 4] [--max-disk 1GB] | --clean", Summary: "Import history older than 90 days from GH Archive's hourly dumps; resumes where it stopped", Run: runArchive,
+			Completion: commandSpec{Flags: []string{"--from=", "--to=", "--jobs=", "--max-disk=", "--clean"}}},
+		{Name: "simulate", Aliases: []string{"sim"}, Usage: "[--commits N] [--reviews N] [--merged-prs N]… [--file summary.json] [--fresh] [--json]", Summary: "Preview how a made-up week would score, evolve, and look, without saving", Run: runSimulate,
+			Completion: commandSpec{Flags: simulateFlags()}},
+		{Name: "story", Usage: "[--week N | --all] [--export file]", Summary: "A short chapter of the pet's saga for each week", Run: runStory,
+			Completion: commandSpec{Flags: []string{"--week=", "--all", "--export="}}},
+		{Name: "snapshot", Usage: "[--format text|svg|png|inline] [--output file]", Summary: "A framed picture of your pet to share, with a ready-made post", Run: runSnapshot,
+			Completion: commandSpec{Flags: []string{"--format=", "--output="}, FlagValues: map[string][]string{"--format": {"text", "svg", "png", "inline"}}}},
+		{Name: "morning", Usage: "[--once] [--offline]", Summary: "A short briefing to start the day: mood, yesterday, quests, reviews, and issues", Run: runMorning,
+			Completion: commandSpec{Flags: []string{"--once", "--offline"}}},
+		{Name: "standup", Usage: "[--hours 24] [--format text|slack] [--offline]", Summary: "A yesterday / today / blockers draft from your activity and local branches", Run: runStandup,
+			Completion: commandSpec{
+				Flags:      []string{"--hours=", "--format=", "--offline"},
+				FlagValues: map[string][]string{"--format": {"text", "slack"}},
+			}},
+		{Name: "heatmap", Usage: "[--user login] [--weeks 52]", Summary: "Your contribution calendar as a heatmap, with the pet on this week", Run: runHeatmap,
+			Completion: commandSpec{Flags: []string{"--user=", "--weeks="}}},
+		{Name: "events", Summary: "Seasonal events running now, their quests, and limited badges", Run: noArgs(runEvents)},
+		{Name: "companions", Aliases: []string{"companion"}, Summary: "Little sprites hatched from forks that follow your pet", Sub: []*command{
+			{Name: "list", Summary: "List companions and the forks they hatched from", Run: noArgs(runCompanionsList)},
+			{Name: "name", Usage: "<number|fork> <name>", Summary: "Name a companion", Run: runCompanionsName,
+				Completion: commandSpec{Args: companionArgs}},
+		}},
+		{Name: "plugins", Aliases: []string{"plugin"}, Summary: "Executables that feed the pet custom activity and react to feeds", Sub: []*command{
+			{Name: "list", Summary: "List installed plugins and the hooks they handle", Run: noArgs(runPluginsList)},
+		}},
+		{Name: "abilities", Summary: "Each evolution's perk, and which one your pet has now", Run: noArgs(runAbilities)},
+		{Name: "play", Summary: "Terminal mini-games with the pet that earn a few logic shards a day", Sub: []*command{
+			{Name: "bughunt", Summary: "Chase down the bugs from this week's fixes before time runs out", Run: noArgs(runBugHunt)},
+		}},
+		{Name: "wip", Aliases: []string{"stash-guard"}, Summary: "Old stashes, unpushed branches, and uncommitted changes across your repos", Run: noArgs(runWIP)},
+		{Name: "name", Usage: "<name> [--pronouns p] [--emoji e] | --reset", Summary: "Name your pet", Run: runName,
+			Completion: commandSpec{Flags: []string{"--pronouns=", "--emoji=", "--reset"}}},
+		{Name: "skin", Summary: "Install and choose community art packs", Sub: []*command{
+			{Name: "list", Summary: "List installed skins", Run: noArgs(runSkinList)},
+			{Name: "install", Usage: "<path|url>", Summary: "Install a skin from a YAML file or URL", Run: func(args []string) error {
+				if len(args) != 1 {
+					return usageErrorf("usage: gh pet skin install <path|url>")
+				}
+				return runSkinInstall(args[0])
+			}},
+			{Name: "use", Usage: "<name|default> [evolution]", Summary: "Use a skin for every evolution, or just one", Run: func(args []string) error {
+				if len(args) < 1 || len(args) > 2 {
+					return usageErrorf("usage: gh pet skin use <name|default> [evolution]")
+				}
+				evolution := "*"
+				if len(args) == 2 {
+					evolution = args[1]
+				}
+				return runSkinUse(args[0], evolution)
+			}, Completion: commandSpec{Args: func(n int, _ []string) []string {
+				switch n {
+				case 0:
+					names, _ := installedSkins()
+					return append(names, "default")
+				case 1:
+					return evolutionNames
+				}
+				return nil
+			}}},
+		}},
+		{Name: "config", Summary: "Read and change preferences such as the pet's language", Sub: []*command{
+			{Name: "get", Usage: "<key>", Summary: "Print a setting: language, sounds, prompt-branch, private-activity, theme, or border", Run: runConfigGet,
+				Completion: commandSpec{Args: fixedArgs(configKeyNames()...)}},
+			{Name: "set", Usage: "<key> <value>", Summary: "Change a setting, e.g. gh pet config set language ja", Run: runConfigSet,
+				Completion: commandSpec{Args: configArgs}},
+		}},
+		{Name: "suggest", Usage: "[--count 5] [--type feat|fix|docs] [--local] [--write [--pick n]]", Summary: "Commit message ideas from Copilot, or the pet itself", Run: runSuggest,
+			Completion: commandSpec{
+				Flags:      []string{"--count=", "--type=", "--write", "--pick=", "--local"},
+				FlagValues: map[string][]string{"--type": {"feat", "fix", "docs", "refactor", "test", "chore"}},
+			}},
+		{Name: "export", Usage: "--ics [--out gitpet.ics]", Summary: "Export achievements, evolutions, and streak milestones as a calendar", Run: runExport,
+			Completion: commandSpec{Flags: []string{"--ics", "--out="}}},
+		{Name: "changelog", Usage: "[--repo owner/repo] [--since v1.2] [--out file]", Summary: "Draft a CHANGELOG section from merged PRs and commits, introduced by the Bard", Run: runChangelog,
+			Completion: commandSpec{Flags: []string{"--repo=", "--since=", "--out="}}},
+		{Name: "pr-draft", Usage: "[--base main] [--create [--draft]]", Summary: "Draft a pull request title and description from the branch's commits, in the pet's voice", Run: runPRDraft,
+			Completion: commandSpec{Flags: []string{"--base=", "--create", "--draft"}}},
+		{Name: "explain", Usage: "[sha] [--copilot]", Summary: "The pet explains what a commit did, in plain words", Run: runExplain,
+			Completion: commandSpec{Flags: []string{"--copilot"}}},
+		{Name: "review", Usage: "<number|url> [--approve | --comment text | --request-changes text]", Summary: "Summarize a pull request; reviewing earns Kindness", Run: runReview,
+			Completion: commandSpec{Flags: []string{"--approve", "--comment=", "--request-changes="}}},
+		{Name: "maintain", Usage: "[--watch 5m]", Summary: "Turn new issues, review requests, and red CI on your repos into requests for help", Run: runMaintain,
+			Completion: commandSpec{Flags: []string{"--watch="}}},
+		{Name: "adopt", Usage: "[owner/repo] [--name name | --release]", Summary: "Adopt a repo pet, or check on every adopted one", Run: runAdopt,
+			Completion: commandSpec{Flags: []string{"--name=", "--release"}, Args: adoptedRepoArgs}},
+		{Name: "duel", Usage: "<username> [--fast] [--seed n]", Summary: "Battle another user's shadow pet; nothing is saved", Run: runDuel,
+			Completion: commandSpec{Flags: []string{"--fast", "--seed="}}},
+		{Name: "peek", Usage: "<username>", Summary: "See the shadow pet any public GitHub user would have; nothing is saved", Run: runPeek},
+		{Name: "hatch", Usage: "[--defaults] [--egg …] [--name …] [--theme …] [--install-hook] [--install-prompt]", Summary: "Meet your pet: choose an egg, name it, and give it its first feed", Run: runHatch,
+			Completion: commandSpec{Flags: []string{"--defaults", "--egg=", "--name=", "--theme=", "--install-hook", "--install-prompt"}, FlagValues: map[string][]string{"--egg": eggNames()}}},
+		{Name: "mode", Usage: "[playful|professional]", Summary: "Tone output down to a neutral summary for screen sharing, or back", Run: runMode,
+			Completion: commandSpec{Args: fixedArgs(modes...)}},
+		{Name: "away", Usage: "[--until YYYY-MM-DD | --end]", Summary: "Leave the pet on holiday: no mood decay or streak loss until you're back", Run: runAway,
+			Completion: commandSpec{Flags: []string{"--until=", "--end"}}},
+		{Name: "graveyard", Summary: "Pets that drifted into the Void, with their final stats and story", Run: noArgs(runGraveyard)},
+		{Name: "goal", Aliases: []string{"goals"}, Summary: "Weekly targets, such as 20 commits, tracked against history", Sub: []*command{
+			{Name: "list", Summary: "Each goal's progress this day, week, or month", Run: noArgs(runGoalList)},
+			{Name: "set", Usage: "<metric> <target> [--per day|week|month]", Summary: "Set a goal, replacing any for the same metric and period", Run: runGoalSet,
+				Completion: commandSpec{Args: goalArgs, Flags: []string{"--per="}, FlagValues: map[string][]string{"--per": goalPeriods}}},
+			{Name: "remove", Usage: "<metric> [--per day|week|month]", Summary: "Remove a goal", Run: runGoalRemove,
+				Completion: commandSpec{Args: goalArgs, Flags: []string{"--per="}, FlagValues: map[string][]string{"--per": goalPeriods}}},
+		}},
+		{Name: "pomodoro", Aliases: []string{"pomo"}, Usage: "[--length 25m] [--task …]", Summary: "A focus session the pet watches; finishing earns mood and logic shards", Run: runPomodoro,
+			Completion: commandSpec{Flags: []string{"--length=", "--task="}}},
+		{Name: "focus", Usage: "[--pomodoro 25m] [--idle 5m] [repo...]", Summary: "Watch saves for thought fragments; pomodoros earn mood", Run: runFocus,
+			Completion: commandSpec{Flags: []string{"--pomodoro=", "--idle="}}},
+		{Name: "sync", Usage: "[push|pull|key] [--key key]", Summary: "Share one pet across machines through an encrypted secret gist", Run: runSync,
+			Completion: commandSpec{Flags: []string{"--key="}, Args: fixedArgs("push", "pull", "key")}},
+		{Name: "serve", Usage: "[--addr 127.0.0.1:7878] [--token token]", Summary: "Serve the pet over a local HTTP API", Run: runServe,
+			Completion: commandSpec{Flags: []string{"--addr=", "--token="}}},
+		{Name: "install-hook", Usage: "[--hook post-commit|commit-msg|pre-commit] [--shell sh|powershell|cmd] [--strict]", Summary: "Install a git hook in this repository", Run: runInstallHook,
+			Completion: commandSpec{
+				Flags: []string{"--shell=", "--hook=", "--strict"},
+				FlagValues: map[string][]string{
+					"--shell": {"sh", "powershell", "cmd"},
+					"--hook":  {"post-commit", "commit-msg", "pre-commit"},
+				},
+			}},
+		{Name: "install-prompt", Summary: "Add the pet to your bash, zsh, or PowerShell prompt", Run: noArgs(runInstallPrompt)},
+		// The hook and prompt entry points ignore stray arguments: they must
+		// never be the reason a commit or a prompt fails.
+		{Name: "post-commit", Usage: "[--background]", Summary: "Run by the post-commit hook", Run: runPostCommit,
+			Completion: commandSpec{Flags: []string{"--background"}}},
+		{Name: "pre-commit", Usage: "[--strict]", Summary: "Run by the pre-commit hook", Run: runPreCommit,
+			Completion: commandSpec{Flags: []string{"--strict"}}},
+		{Name: "commit-msg", Usage: "<file> [--strict]", Summary: "Run by the commit-msg hook", Run: runCommitMsg,
+			Completion: commandSpec{Flags: []string{"--strict"}}},
+		{Name: "prompt", Usage: "[--raycast | --alfred]", Summary: "Print the one-line pet for your shell prompt, or for Raycast or Alfred", Run: runPromptCommand,
+			Completion: commandSpec{Flags: []string{"--raycast", "--alfred"}}},
+		{Name: "telemetry", Usage: "on|off|show|reset", Summary: "Opt in to a local, anonymous count of which commands you run", Run: runTelemetry,
+			Completion: commandSpec{Args: fixedArgs("on", "off", "show", "reset")}},
+		{Name: "completion", Usage: "bash|zsh|fish|powershell", Summary: "Print a shell completion script", Run: runCompletion,
+			Completion: commandSpec{Args: fixedArgs("bash", "zsh", "fish", "powershell")}},
+		{Name: "uninstall", Usage: "[--purge] [--yes]", Summary: "Remove prompt and hooks; --purge also deletes pet data", Run: runUninstall,
+			Completion: commandSpec{Flags: []string{"--purge", "--yes"}}},
+		{Name: "help", Usage: "[command]", Summary: "Show help for gh pet or one of its commands", Run: runHelp,
+			Completion: commandSpec{Args: func(n int, _ []string) []string {
+				if n != 0 {
+					return nil
+				}
+				var names []string
+				for _, c := range commands {
+					names = append(names, c.Name)
+				}
+				return names
+			}}},
+	}
+}
+
+// noArgs adapts a command that takes no arguments.
+func noArgs(run func() error) func([]string) error {
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageErrorf("unexpected argument %q", args[0])
+		}
+		return run()
+	}
+}
+
+// newFlagSet makes a flag set whose --help shows the command's synopsis and
+// summary above its flags.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		if c := findCommand(commands, name); c != nil {
+			printCommandHelp(fs.Output(), name, c, fs)
+		}
+	}
+	return fs
+}
+
+// parseFlags parses args into fs, marking mistakes as usage errors.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return usageError{err: err, shown: true}
+	}
+	return err
+}
+
+// dispatch finds and runs the command named by args[0] among cmds, and
+// returns its full name, e.g. "skin use", for logging.
+func dispatch(cmds []*command, parent string, args []string) (string, error) {
+	if len(args) == 0 {
+		printCommandList(os.Stderr, parent, cmds)
+		return parent, usageError{err: fmt.Errorf("missing command"), shown: true}
+	}
+	c := findCommand(cmds, args[0])
+	if c == nil {
+		return parent, unknownCommand(cmds, parent, args[0])
+	}
+	path := strings.TrimSpace(parent + " " + c.Name)
+	rest := args[1:]
+	if len(c.Sub) > 0 {
+		if len(rest) > 0 && isHelpFlag(rest[0]) {
+			printCommandHelp(os.Stdout, path, c, nil)
+			return path, nil
+		}
+		return dispatch(c.Sub, path, rest)
+	}
+	if len(c.Completion.Flags) == 0 && len(rest) > 0 && isHelpFlag(rest[0]) {
+		printCommandHelp(os.Stdout, path, c, nil)
+		return path, nil
+	}
+	store.Command = path
+	err := c.Run(rest)
+	if errors.Is(err, flag.ErrHelp) {
+		return path, nil
+	}
+	return path, err
+}
+
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "--help" || arg == "-help"
+}
+
+func findCommand(cmds []*command, name string) *command {
+	for _, c := range cmds {
+		if c.Name == name || containsString(c.Aliases, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// unknownCommand suggests the closest command names for a typo.
+func unknownCommand(cmds []*command, parent, name string) error {
+	var close []string
+	for _, c := range cmds {
+		for _, candidate := range append([]string{c.Name}, c.Aliases...) {
+			if editDistance(name, candidate) <= 2 || len(name) > 1 && strings.HasPrefix(candidate, name) {
+				close = append(close, c.Name)
+				break
+			}
+		}
+	}
+	where := strings.TrimSpace("gh pet " + parent)
+	if len(close) == 0 {
+		return usageErrorf("unknown command %q for %s; run 'gh pet help' for a list", name, where)
+	}
+	return usageErrorf("unknown command %q for %s; did you mean %s?", name, where, strings.Join(close, " or "))
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func printCommandList(w io.Writer, parent string, cmds []*command) {
+	if parent == "" {
+		fmt.Fprintln(w, "GitPet (gh extension)")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Usage: gh pet <command> [flags]")
+	} else {
+		fmt.Fprintf(w, "Usage: gh pet %s <command>\n", parent)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range cmds {
+		fmt.Fprintf(w, "  %-16s %s\n", c.Name, c.Summary)
+	}
+	fmt.Fprintln(w)
+	if parent == "" {
+		fmt.Fprintln(w, "Add --verbose to any command for debug logs.")
+	}
+	fmt.Fprintf(w, "Run 'gh pet help %s' for more about a command.\n", strings.TrimSpace(parent+" <command>"))
+}
+
+func printCommandHelp(w io.Writer, path string, c *command, fs *flag.FlagSet) {
+	if len(c.Sub) > 0 {
+		fmt.Fprintf(w, "%s\n\n", c.Summary)
+		printCommandList(w, path, c.Sub)
+		return
+	}
+	fmt.Fprintf(w, "Usage: gh pet %s\n\n%s\n", strings.TrimSpace(path+" "+c.Usage), c.Summary)
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(w, "\nAliases: %s\n", strings.Join(c.Aliases, ", "))
+	}
+	if fs != nil {
+		fmt.Fprintln(w, "\nFlags:")
+		fs.PrintDefaults()
+	}
+}
+
+// helpCommand is what to run for help with the command at path.
+func helpCommand(path string) string {
+	if path == "" || path == "help" {
+		return "gh pet help"
+	}
+	return "gh pet help " + path
+}
+
+// runHelp shows the command list, or one command's help. Commands with flags
+// print their own, flag descriptions included.
+func runHelp(args []string) error {
+	cmds, path := commands, ""
+	for len(args) > 0 {
+		c := findCommand(cmds, args[0])
+		if c == nil {
+			return unknownCommand(cmds, path, args[0])
+		}
+		path = strings.TrimSpace(path + " " + c.Name)
+		args = args[1:]
+		if len(c.Sub) == 0 || len(args) == 0 {
+			if len(c.Completion.Flags) > 0 {
+				return c.Run([]string{"--help"})
+			}
+			printCommandHelp(os.Stdout, path, c, nil)
+			return nil
+		}
+		cmds = c.Sub
+	}
+	printCommandList(os.Stdout, "", commands)
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const maxSubjectLength = 72
+
+var (
+	conventionalPrefix = regexp.MustCompile(`^(feat|fix|docs|style|refactor|perf|test|build|ci|chore|revert)(\([\w./-]+\))?!?: `)
+	issueReference     = regexp.MustCompile(`(#\d+|\b[A-Z][A-Z0-9]+-\d+\b)`)
+	wipMessage         = regexp.MustCompile(`(?i)^(wip|tmp|temp|todo)\b|^\.+$`)
+)
+
+// messageGrade is how a commit message fared against each check, with the
+// pet's notes on whatever it missed.
+type messageGrade struct {
+	Score int
+	Max   int
+	Notes []string
+}
+
+func (g messageGrade) great() bool {
+	return g.Score == g.Max
+}
+
+func gradeMessage(msg string) messageGrade {
+	subject, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+	subject = strings.TrimSpace(subject)
+	g := messageGrade{Max: 4}
+
+	if n := len([]rune(subject)); n >= 10 && n <= maxSubjectLength {
+		g.Score++
+	} else if n < 10 {
+		g.Notes = append(g.Notes, "That subject is so short I can't tell what changed.")
+	} else {
+		g.Notes = append(g.Notes, fmt.Sprintf("The subject runs %d characters; I lose the thread past %d.", n, maxSubjectLength))
+	}
+
+	if isImperative(conventionalPrefix.ReplaceAllString(subject, "")) {
+		g.Score++
+	} else {
+		g.Notes = append(g.Notes, `Try the imperative: "add parser", not "added parser".`)
+	}
+
+	if conventionalPrefix.MatchString(subject) {
+		g.Score++
+	} else {
+		g.Notes = append(g.Notes, "A prefix like feat: or fix: helps me sort my treasures.")
+	}
+
+	if issueReference.MatchString(msg) {
+		g.Score++
+	} else {
+		g.Notes = append(g.Notes, "Mention an issue (#123) so I can follow the trail.")
+	}
+	return g
+}
+
+// isImperative is a cheap check that the first word is a bare verb rather
+// than past tense, a gerund, or a third-person form.
+func isImperative(subject string) bool {
+	word, _, _ := strings.Cut(strings.ToLower(subject), " ")
+	word = strings.Trim(word, ".,:;!")
+	if word == "" {
+		return false
+	}
+	for _, suffix := range []string{"ed", "ing"} {
+		if strings.HasSuffix(word, suffix) && len(word) > len(suffix)+2 {
+			return false
+		}
+	}
+	switch word {
+	case "fixes", "adds", "updates", "removes", "changes", "makes", "moves", "uses":
+		return false
+	}
+	return true
+}
+
+// commitMessageBody strips git's comment lines and everything below the
+// scissors line, leaving what will actually be committed.
+func commitMessageBody(raw string) string {
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func runCommitMsg(args []string) error {
+	fs := newFlagSet("commit-msg")
+	strict := fs.Bool("strict", false, "reject empty and wip messages")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("usage: gh pet commit-msg [--strict] <message-file>")
+	}
+	raw, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	msg := commitMessageBody(string(raw))
+	state, _ := store.Load()
+	name := state.DisplayName()
+
+	if msg == "" || wipMessage.MatchString(msg) {
+		if *strict {
+			return fmt.Errorf("%s won't let this one through: write a real message before committing", name)
+		}
+		fmt.Printf("%s%s %s hopes you'll reword this before pushing.%s\n", colorDim, state.Signature(), name, colorReset)
+		return nil
+	}
+	// Git writes these itself; grading them would only nag.
+	if strings.HasPrefix(msg, "Merge ") || strings.HasPrefix(msg, "fixup! ") || strings.HasPrefix(msg, "squash! ") {
+		return nil
+	}
+
+	grade := gradeMessage(msg)
+	stars := strings.Repeat("★", grade.Score) + strings.Repeat("☆", grade.Max-grade.Score)
+	fmt.Printf("%s %s rates this message %s%s%s\n", state.Signature(), name, colorYellow, stars, colorReset)
+	for _, note := range grade.Notes {
+		fmt.Printf("  %s%s%s\n", colorDim, note, colorReset)
+	}
+	if !grade.great() {
+		return nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: using default scoring:", err)
+	}
+	state.Logic += cfg.Scoring.GreatMessageLogic
+	if err := store.Save(state); err != nil {
+		return err
+	}
+	fmt.Printf("  %s✨ A message worth keeping! +%d logic shards%s\n", colorGreen, cfg.Scoring.GreatMessageLogic, colorReset)
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// runCompact applies retention to the history, journal, and undo log now,
+// rather than on their next save, and reports what it saved.
+func runCompact(args []string) error {
+	fs := newFlagSet("compact")
+	keepDays := fs.Int("keep-days", store.Retention.HistoryDays, "days of history to keep one by one before rolling them into months; 0 keeps all")
+	dryRun := fs.Bool("dry-run", false, "show what would be compacted without changing anything")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *keepDays < 0 || *keepDays > 0 && *keepDays < store.MinHistoryDays {
+		return usageErrorf("--keep-days must be 0 or at least %d", store.MinHistoryDays)
+	}
+	// The saves below compact again, so they must use the same window.
+	store.Retention.HistoryDays = *keepDays
+
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	journal, err := store.LoadJournal()
+	if err != nil {
+		return err
+	}
+	changes, err := store.LoadChanges()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	historyBefore := jsonSize(history)
+	rolled := history.Compact(store.Retention.HistoryDays, now)
+	journalBefore := jsonSize(journal)
+	dropped := journal.Prune(store.Retention, now)
+	changesBefore, snapshots := jsonSize(changes), len(changes)
+	changes = store.PruneChanges(changes, store.Retention, now)
+
+	if rolled == 0 && dropped == 0 && len(changes) == snapshots {
+		fmt.Println("Nothing to compact; everything is within retention.")
+		return nil
+	}
+	if !*dryRun {
+		if rolled > 0 {
+			if err := store.SaveHistory(history); err != nil {
+				return err
+			}
+		}
+		if dropped > 0 {
+			if err := store.SaveJournal(journal); err != nil {
+				return err
+			}
+		}
+		if len(changes) < snapshots {
+			if err := store.SaveChanges(changes); err != nil {
+				return err
+			}
+		}
+	}
+
+	verb := "Compacted"
+	if *dryRun {
+		verb = "Would compact"
+	}
+	fmt.Printf("\n%s🗜️  %s your pet's records%s\n\n", colorBold, verb, colorReset)
+	row := func(name, what string, before, after int) {
+		fmt.Printf("  %-9s %-40s %s%s → %s%s\n", name, what, colorDim, byteSize(before), byteSize(after), colorReset)
+	}
+	row("History", fmt.Sprintf("%s rolled into monthly totals", i18n.Plural(rolled, "day")), historyBefore, jsonSize(history))
+	row("Journal", fmt.Sprintf("%s dropped", i18n.Plural(dropped, "page")), journalBefore, jsonSize(journal))
+	row("Undo log", fmt.Sprintf("%s dropped", i18n.Plural(snapshots-len(changes), "snapshot")), changesBefore, jsonSize(changes))
+	if *dryRun {
+		fmt.Printf("\n%sDry run; nothing was changed.%s\n", colorDim, colorReset)
+	}
+	return nil
+}
+
+// jsonSize is how many bytes v takes in its file.
+func jsonSize(v any) int {
+	data, _ := json.MarshalIndent(v, "", "  ")
+	return len(data)
+}
+
+// byteSize writes n bytes for people, such as "12.4 KB".
+func byteSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+type ForkPayload struct {
+	Forkee struct {
+		FullName string `json:"full_name"`
+	} `json:"forkee"`
+}
+
+var companionSprites = []string{"·ᴗ·", "°ᴥ°", "ᵔᴥᵔ", "•ω•", "˘ᵕ˘", "ºᴗº", "•ᴥ•", "^ᴗ^"}
+
+// hatchCompanions adds a companion for every fork in events that involves
+// login's repos and isn't followed yet, and returns the new ones.
+func hatchCompanions(state *store.PetState, login string, events []Event) []store.Companion {
+	var hatched []store.Companion
+	for _, event := range events {
+		if event.Type != "ForkEvent" || len(state.Companions) >= store.MaxCompanions {
+			continue
+		}
+		var payload ForkPayload
+		if json.Unmarshal(event.Payload, &payload) != nil || payload.Forkee.FullName == "" {
+			continue
+		}
+		fork, source := payload.Forkee.FullName, event.Repo.Name
+		if !ownedBy(fork, login) && !ownedBy(source, login) || hasCompanion(*state, fork) {
+			continue
+		}
+		h := fnv.New32a()
+		h.Write([]byte(fork))
+		c := store.Companion{
+			Fork:   fork,
+			Source: source,
+			Sprite: companionSprites[h.Sum32()%uint32(len(companionSprites))],
+			Born:   event.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		state.Companions = append(state.Companions, c)
+		hatched = append(hatched, c)
+	}
+	return hatched
+}
+
+func ownedBy(repo, login string) bool {
+	owner, _, _ := strings.Cut(repo, "/")
+	return strings.EqualFold(owner, login)
+}
+
+func hasCompanion(state store.PetState, fork string) bool {
+	for _, c := range state.Companions {
+		if strings.EqualFold(c.Fork, fork) {
+			return true
+		}
+	}
+	return false
+}
+
+// ghForkEvents returns recent forks of login's repos by other people, which
+// only show up in the events login receives.
+func ghForkEvents(ctx context.Context, login string) []Event {
+	var received []Event
+	if err := gh.Get(ctx, fmt.Sprintf("users/%s/received_events", login), &received); err != nil {
+		logging.Logger.Debug("no received events", "err", err)
+		return nil
+	}
+	var forks []Event
+	for _, event := range received {
+		if event.Type == "ForkEvent" && ownedBy(event.Repo.Name, login) {
+			forks = append(forks, event)
+		}
+	}
+	return forks
+}
+
+func runCompanionsList() error {
+	state, _ := store.Load()
+	if len(state.Companions) == 0 {
+		fmt.Println("No companions yet. One hatches whenever you fork a repo, or someone forks yours.")
+		return nil
+	}
+	fmt.Printf("\n%s%s %s's companions%s\n\n", colorBold, state.Signature(), state.DisplayName(), colorReset)
+	for i, c := range state.Companions {
+		born := ""
+		if t, err := time.Parse(time.RFC3339, c.Born); err == nil {
+			born = ", hatched " + t.Local().Format("Jan 2, 2006")
+		}
+		fmt.Printf("%d. %s %s%s%s  %s%s from %s%s%s\n", i+1, c.Sprite, colorBold, c.DisplayName(), colorReset,
+			colorDim, c.Fork, c.Source, born, colorReset)
+	}
+	if cfg, err := loadConfig(); err == nil && cfg.Scoring.CompanionLogic > 0 {
+		fmt.Printf("\nEach companion brings %s per feed, for up to %d companions.\n",
+			i18n.Plural(cfg.Scoring.CompanionLogic, "Logic Shard"), store.BonusCompanions)
+	}
+	return nil
+}
+
+func runCompanionsName(args []string) error {
+	if len(args) != 2 {
+		return usageErrorf("usage: gh pet companions name <number|fork> <name>")
+	}
+	if err := validateName(args[1]); err != nil {
+		return err
+	}
+	state, _ := store.Load()
+	i, err := findCompanion(state.Companions, args[0])
+	if err != nil {
+		return err
+	}
+	state.Companions[i].Name = args[1]
+	if err := store.Save(state); err != nil {
+		return err
+	}
+	c := state.Companions[i]
+	fmt.Printf("%s✓ %s %s is now called %s%s\n", colorGreen, c.Sprite, c.Fork, c.Name, colorReset)
+	return nil
+}
+
+// findCompanion finds a companion by its number in the list, its fork, or
+// its current name.
+func findCompanion(companions []store.Companion, key string) (int, error) {
+	if n, err := strconv.Atoi(key); err == nil {
+		if n < 1 || n > len(companions) {
+			return 0, fmt.Errorf("there is no companion %d (1–%d)", n, len(companions))
+		}
+		return n - 1, nil
+	}
+	for i, c := range companions {
+		if strings.EqualFold(c.Fork, key) || strings.EqualFold(c.DisplayName(), key) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no companion %q; see gh pet companions list", key)
+}
+
+// companionArgs offers companions by their fork.
+func companionArgs(n int, _ []string) []string {
+	if n != 0 {
+		return nil
+	}
+	state, _ := store.Load()
+	var forks []string
+	for _, c := range state.Companions {
+		forks = append(forks, c.Fork)
+	}
+	return forks
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+var comparePeriods = []string{"week", "month"}
+
+// comparison is one period so far beside the same stretch of the one before.
+type comparison struct {
+	Per        string
+	From, To   time.Time // this period, up to the end of today
+	PrevFrom   time.Time
+	PrevTo     time.Time
+	Cur, Prev  rollup
+	CurDays    []store.DayRecord
+	PrevDays   []store.DayRecord
+	CurActive  int
+	PrevActive int
+}
+
+func runCompare(args []string) error {
+	per := "week"
+	if len(args) > 1 {
+		return usageErrorf("usage: gh pet compare [week|month]")
+	}
+	if len(args) == 1 {
+		per = args[0]
+		if !containsString(comparePeriods, per) {
+			return usageErrorf("unknown period %q; choose week or month", per)
+		}
+	}
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	if len(history.Days) == 0 {
+		fmt.Println("No history yet. Run `gh pet feed` to start recording.")
+		return nil
+	}
+	state, _ := store.Load()
+	fmt.Print(renderComparison(compareHistory(history, per, time.Now()), state, loadTheme()))
+	return nil
+}
+
+// compareHistory sets this period so far against the same number of days at
+// the start of the previous one, so a Wednesday isn't judged against a whole
+// week. A month compares against the previous month's first days, cut short
+// when that month was shorter.
+func compareHistory(history store.History, per string, now time.Time) comparison {
+	c := comparison{Per: per, From: periodStart(per, now)}
+	c.To = periodStart("day", now).AddDate(0, 0, 1)
+	elapsed := int(c.To.Sub(c.From).Hours()/24 + 0.5)
+	if per == "month" {
+		c.PrevFrom = c.From.AddDate(0, -1, 0)
+	} else {
+		c.PrevFrom = c.From.AddDate(0, 0, -7)
+	}
+	c.PrevTo = c.PrevFrom.AddDate(0, 0, elapsed)
+	if c.PrevTo.After(c.From) {
+		c.PrevTo = c.From
+	}
+	c.CurDays = history.Between(c.From, c.To)
+	c.PrevDays = history.Between(c.PrevFrom, c.PrevTo)
+	for _, d := range c.CurDays {
+		c.Cur.add(d)
+		if d.Total() > 0 {
+			c.CurActive++
+		}
+	}
+	for _, d := range c.PrevDays {
+		c.Prev.add(d)
+		if d.Total() > 0 {
+			c.PrevActive++
+		}
+	}
+	return c
+}
+
+func renderComparison(c comparison, state store.PetState, theme Theme) string {
+	color := theme.accent(state.Evolution)
+	this, last := "This "+c.Per, "Last "+c.Per
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n%s%s📊 %s vs %s%s\n", theme.Bold, color, this, strings.ToLower(last), theme.Reset))
+	sb.WriteString(fmt.Sprintf("%s   %s vs %s%s\n\n", theme.Dim, spanLabel(c.From, c.To), spanLabel(c.PrevFrom, c.PrevTo), theme.Reset))
+
+	sb.WriteString(fmt.Sprintf("  %-12s %12s %12s\n", "", this, last))
+	rows := []struct {
+		label     string
+		cur, prev int
+	}{
+		{"Commits", c.Cur.Commits, c.Prev.Commits},
+		{"Merged PRs", c.Cur.MergedPRs, c.Prev.MergedPRs},
+		{"Reviews", c.Cur.Reviews, c.Prev.Reviews},
+		{"Docs", c.Cur.DocComments, c.Prev.DocComments},
+		{"Issues", c.Cur.Issues, c.Prev.Issues},
+		{"Active days", c.CurActive, c.PrevActive},
+	}
+	for _, row := range rows {
+		sb.WriteString(fmt.Sprintf("  %-12s %12d %12d  %s\n", row.label, row.cur, row.prev, delta(row.cur, row.prev, theme)))
+	}
+	curMood, prevMood := averageMood(c.CurDays), averageMood(c.PrevDays)
+	sb.WriteString(fmt.Sprintf("  %-12s %12d %12d  %s\n", "Mood (avg)", curMood, prevMood, delta(curMood, prevMood, theme)))
+	sb.WriteString(fmt.Sprintf("    %s%-10s%s %s%s%s\n", theme.Dim, strings.ToLower(this), theme.Reset, color, moodSparkline(c.CurDays), theme.Reset))
+	sb.WriteString(fmt.Sprintf("    %s%-10s%s %s\n", theme.Dim, strings.ToLower(last), theme.Reset, moodSparkline(c.PrevDays)))
+
+	sb.WriteString("\n")
+	for _, line := range c.commentary() {
+		sb.WriteString(fmt.Sprintf("  %s %s%s%s\n", moodFace(state.Mood), theme.Dim, line, theme.Reset))
+	}
+	return sb.String()
+}
+
+// commentary is the pet's read on whether the Keeper is trending up or down.
+func (c comparison) commentary() []string {
+	cur, prev := c.Cur.total(), c.Prev.total()
+	var lines []string
+	switch {
+	case cur == 0 && prev == 0:
+		lines = append(lines, fmt.Sprintf("A quiet %s, and the last one was too. I'm still here when you're ready.", c.Per))
+	case prev == 0:
+		lines = append(lines, fmt.Sprintf("You're back! %s after a silent stretch last %s.", i18n.Plural(cur, "event"), c.Per))
+	case cur*10 >= prev*12:
+		lines = append(lines, fmt.Sprintf("Trending up: %d events against %d by this point last %s. I can feel it!", cur, prev, c.Per))
+	case cur*10 <= prev*8:
+		lines = append(lines, fmt.Sprintf("Trending down: %d events against %d by this point last %s. Slower is fine, as long as it's on purpose.", cur, prev, c.Per))
+	default:
+		lines = append(lines, fmt.Sprintf("Holding steady, about the same as this point last %s.", c.Per))
+	}
+	if c.Cur.Reviews > c.Prev.Reviews && c.Cur.Commits < c.Prev.Commits {
+		lines = append(lines, "Fewer commits, but more reviews. Helping others counts too.")
+	}
+	if curMood, prevMood := averageMood(c.CurDays), averageMood(c.PrevDays); len(c.CurDays) > 0 && len(c.PrevDays) > 0 {
+		switch {
+		case curMood >= prevMood+10:
+			lines = append(lines, fmt.Sprintf("My mood is up, %d on average against %d.", curMood, prevMood))
+		case curMood <= prevMood-10:
+			lines = append(lines, fmt.Sprintf("My mood has slipped, %d on average against %d.", curMood, prevMood))
+		}
+	}
+	return lines
+}
+
+func averageMood(days []store.DayRecord) int {
+	if len(days) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, d := range days {
+		sum += d.Mood
+	}
+	return sum / len(days)
+}
+
+// delta is a compact arrow for a change, green up and red down.
+func delta(cur, prev int, theme Theme) string {
+	switch {
+	case cur > prev:
+		return fmt.Sprintf("%s↑ +%d%s", theme.Good, cur-prev, theme.Reset)
+	case cur < prev:
+		return fmt.Sprintf("%s↓ −%d%s", theme.Bad, prev-cur, theme.Reset)
+	default:
+		return theme.Dim + "→" + theme.Reset
+	}
+}
+
+// spanLabel shows a half-open span of days, such as "Oct 12 – Oct 15".
+func spanLabel(from, to time.Time) string {
+	last := to.AddDate(0, 0, -1)
+	if !last.After(from) {
+		return from.Format("Jan 2")
+	}
+	return from.Format("Jan 2") + " – " + last.Format("Jan 2")
+}
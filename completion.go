@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// commandSpec is what shell completion knows about a command. Flags ending
+// in "=" take a value; FlagValues lists the choices for those that have a
+// fixed set, and Args offers candidates for the positional argument at
+// index n given the positionals typed so far.
+type commandSpec struct {
+	Flags      []string
+	FlagValues map[string][]string
+	Args       func(n int, positionals []string) []string
+}
+
+func fixedArgs(values ...string) func(int, []string) []string {
+	return func(n int, _ []string) []string {
+		if n == 0 {
+			return values
+		}
+		return nil
+	}
+}
+
+var evolutionNames = []string{"Pioneer", "Guardian", "Bard", "Sentinel", "Curator", "Void", "Lonely"}
+
+// adoptedRepoArgs offers the repos with adopted pets.
+func adoptedRepoArgs(n int, _ []string) []string {
+	if n != 0 {
+		return nil
+	}
+	adopted, _ := loadAdopted()
+	var repos []string
+	for _, pet := range adopted.Pets {
+		repos = append(repos, pet.Repo)
+	}
+	return repos
+}
+
+// completeWords returns the candidates for the last of words, the arguments
+// after `gh pet` with the one under the cursor last.
+func completeWords(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	// PowerShell 5 can't pass an empty argument, so its script sends "".
+	if cur == `""` {
+		cur = ""
+	}
+	// Walk down to the command being completed, through any subcommands;
+	// words is then left holding that command's arguments.
+	cmds := commands
+	var leaf *command
+	for leaf == nil {
+		if len(words) == 1 {
+			var names []string
+			for _, c := range cmds {
+				names = append(names, c.Name)
+			}
+			return withPrefix(names, cur)
+		}
+		c := findCommand(cmds, words[0])
+		if c == nil {
+			return nil
+		}
+		words = words[1:]
+		if len(c.Sub) == 0 {
+			leaf = c
+		} else {
+			cmds = c.Sub
+		}
+	}
+
+	spec := leaf.Completion
+	takesValue := map[string]bool{}
+	var flags []string
+	for _, f := range spec.Flags {
+		name, value := strings.CutSuffix(f, "=")
+		takesValue[name] = value
+		flags = append(flags, name)
+	}
+	if len(words) > 1 && takesValue[words[len(words)-2]] {
+		return withPrefix(spec.FlagValues[words[len(words)-2]], cur)
+	}
+	if strings.HasPrefix(cur, "-") {
+		return withPrefix(flags, cur)
+	}
+	if spec.Args == nil {
+		return nil
+	}
+	var positionals []string
+	for i := 0; i < len(words)-1; i++ {
+		switch w := words[i]; {
+		case takesValue[w]:
+			i++
+		case !strings.HasPrefix(w, "-"):
+			positionals = append(positionals, w)
+		}
+	}
+	return withPrefix(spec.Args(len(positionals), positionals), cur)
+}
+
+func withPrefix(candidates []string, prefix string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func runComplete(args []string) {
+	for _, c := range completeWords(args) {
+		fmt.Println(c)
+	}
+}
+
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return usageErrorf("usage: gh pet completion bash|zsh|fish|powershell")
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown shell %q (bash, zsh, fish, powershell)", args[0])
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// completionScripts hook `gh pet` into each shell's completion and hand every
+// other gh command back to gh's own completion. Candidates come from the
+// hidden `gh pet __complete` command, so skin and repo pet names stay current.
+var completionScripts = map[string]string{
+	"bash": `# gh pet completion for bash. Load it after gh's own completion:
+#   eval "$(gh pet completion bash)"
+__gh_pet_complete() {
+  local IFS=$'\n'
+  COMPREPLY=($(gh pet __complete "${COMP_WORDS[@]:2:COMP_CWORD-1}" 2>/dev/null))
+}
+__gh_pet_wrap() {
+  if [[ ${COMP_WORDS[1]} == pet && $COMP_CWORD -ge 2 ]]; then
+    __gh_pet_complete
+  elif declare -F __start_gh >/dev/null; then
+    __start_gh "$@"
+  fi
+}
+complete -o default -F __gh_pet_wrap gh
+`,
+	"zsh": `# gh pet completion for zsh. Load it after compinit:
+#   eval "$(gh pet completion zsh)"
+__gh_pet_wrap() {
+  if [[ ${words[2]} == pet && $CURRENT -ge 3 ]]; then
+    local -a candidates
+    candidates=(${(f)"$(gh pet __complete "${(@)words[3,CURRENT]}" 2>/dev/null)"})
+    compadd -a candidates
+  elif (( $+functions[_gh] )); then
+    _gh "$@"
+  fi
+}
+compdef __gh_pet_wrap gh
+`,
+	"fish": `# gh pet completion for fish:
+#   gh pet completion fish > ~/.config/fish/completions/gh-pet.fish
+complete -c gh -n '__fish_seen_subcommand_from pet' -f -a '(gh pet __complete (commandline -opc | tail -n +3) (commandline -ct))'
+`,
+	"powershell": `# gh pet completion for PowerShell. Add this to your $PROFILE:
+#   gh pet completion powershell | Out-String | Invoke-Expression
+# gh's own completer, if it was loaded first, keeps handling everything else.
+$__ghCompleter = ${__ghCompleterBlock}
+Register-ArgumentCompleter -Native -CommandName gh -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })
+    if ($words.Count -lt 2 -or $words[1] -ne 'pet') {
+        if ($__ghCompleter) { & $__ghCompleter $wordToComplete $commandAst $cursorPosition }
+        return
+    }
+    $rest = @($words | Select-Object -Skip 2)
+    if ($wordToComplete -eq '') { $rest += '""' }
+    gh pet __complete @rest 2>$null | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`,
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/config"
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+// Config holds user preferences: the settings the MCP server shares, and
+// the CLI's own. It's only rewritten by commands that change a preference,
+// such as `gh pet skin use`, or by install-hook recording the repo it
+// touched.
+type Config struct {
+	config.Settings
+	// Notifications toggles desktop popups and the terminal bell.
+	Notifications NotificationsConfig `json:"notifications"`
+	// Sounds plays short sounds on milestones when enabled.
+	Sounds SoundsConfig `json:"sounds"`
+	// Prompt tunes gh pet prompt.
+	Prompt PromptConfig `json:"prompt"`
+	// WIP sets when old stashes, unpushed branches, and uncommitted changes
+	// earn a reminder.
+	WIP WIPConfig `json:"wip"`
+	// Hooks runs the Keeper's commands on evolutions, achievements, and
+	// drops in mood.
+	Hooks HooksConfig `json:"hooks"`
+	// Maintainer configures gh pet maintain.
+	Maintainer MaintainerConfig `json:"maintainer"`
+	// Skins maps an evolution name, or "*" for all of them, to an installed
+	// skin name.
+	Skins map[string]string `json:"skins,omitempty"`
+	// Theme names a built-in theme or one defined under Themes; Border is
+	// rounded, ascii, or double.
+	Theme  string               `json:"theme"`
+	Border string               `json:"border"`
+	Themes map[string]ThemeSpec `json:"themes,omitempty"`
+	// Repos lists the repositories install-hook has written a hook into, so
+	// uninstall can clean them all up.
+	Repos []string `json:"repos,omitempty"`
+	// Goals are the Keeper's targets, set with gh pet goal set.
+	Goals []Goal `json:"goals,omitempty"`
+	// VoidDays is how long mood may stay at 0 before the pet drifts into
+	// the Void, unless Immortal; see graveyard.go.
+	VoidDays int  `json:"void_days"`
+	Immortal bool `json:"immortal,omitempty"`
+	// Mode is playful, the default, or professional; see mode.go.
+	Mode string `json:"mode,omitempty"`
+	// AsyncHook makes the post-commit hook sync in the background; see
+	// background.go.
+	AsyncHook bool `json:"async_hook,omitempty"`
+	// Presence shows the pet on Discord during focus and pomodoro
+	// sessions; see presence.go.
+	Presence PresenceConfig `json:"presence"`
+	// ServeOrigins are the web origins, e.g. "https://example.com", whose
+	// pages may call gh pet serve from a browser; see serve.go.
+	ServeOrigins []string `json:"serve_origins,omitempty"`
+	// onlyRepos limits a single feed to some repos, from gh pet feed
+	// --repo. It's never saved.
+	onlyRepos []string
+}
+
+func (c Config) repoFilter() activity.RepoFilter {
+	filter := c.RepoFilter()
+	filter.Only = c.onlyRepos
+	return filter
+}
+
+func defaultConfig() Config {
+	return Config{Settings: config.Default(), Notifications: defaultNotifications(), Sounds: defaultSounds(), WIP: defaultWIP(), Maintainer: defaultMaintainer(), Presence: defaultPresence(), VoidDays: 14, Theme: "default", Border: "rounded"}
+}
+
+// loadConfig reads the user's config on top of the defaults, so any field
+// left out of the file keeps its default value.
+func loadConfig() (Config, error) {
+	cfg := defaultConfig()
+	if err := config.Read(&cfg); err != nil {
+		return defaultConfig(), err
+	}
+	if err := cfg.Settings.Validate(); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := cfg.Notifications.validate(); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := cfg.Sounds.validate(); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := cfg.WIP.validate(); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := cfg.Maintainer.validate(); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := cfg.Hooks.validate(); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := cfg.Presence.validate(); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := cfg.validateTheme(); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := validateGoals(cfg.Goals); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if err := validateMode(cfg.Mode); err != nil {
+		return defaultConfig(), fmt.Errorf("invalid %s: %w", config.FileName, err)
+	}
+	if cfg.VoidDays < 1 {
+		return defaultConfig(), fmt.Errorf("invalid %s: void_days must be at least 1", config.FileName)
+	}
+	return cfg, nil
+}
+
+func saveConfig(cfg Config) error {
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// configKey is a preference gh pet config can read and change.
+type configKey struct {
+	get    func(Config) string
+	set    func(*Config, string) error
+	values func(Config) []string
+}
+
+var configKeys = map[string]configKey{
+	"language": {
+		get: func(c Config) string {
+			if c.Language == "" {
+				return "auto"
+			}
+			return c.Language
+		},
+		set: func(c *Config, value string) error {
+			if value == "auto" {
+				value = ""
+			}
+			language, err := i18n.ValidateLanguage(value)
+			c.Language = language
+			return err
+		},
+		values: func(Config) []string { return append([]string{"auto"}, i18n.Locales...) },
+	},
+	"login": {
+		get: func(c Config) string {
+			if c.Login == "" {
+				return "auto"
+			}
+			return c.Login
+		},
+		set: func(c *Config, value string) error {
+			value = strings.TrimPrefix(value, "@")
+			if value == "auto" {
+				value = ""
+			}
+			c.Login = value
+			return dirs.ValidateLogin(value)
+		},
+		values: func(Config) []string {
+			_, users := dirs.GHAccounts()
+			return append([]string{"auto"}, users...)
+		},
+	},
+	"week-start": {
+		get: func(c Config) string {
+			if c.WeekStart == "" {
+				return "auto"
+			}
+			return c.WeekStart
+		},
+		set: func(c *Config, value string) error {
+			if value == "auto" {
+				value = ""
+			}
+			c.WeekStart = strings.ToLower(value)
+			return pet.ValidateWeeks(c.WeekStart, c.Weeks)
+		},
+		values: func(Config) []string {
+			return []string{"auto", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+		},
+	},
+	"weeks": {
+		get: func(c Config) string {
+			if c.Weeks == "" {
+				return pet.WeeksRolling
+			}
+			return c.Weeks
+		},
+		set: func(c *Config, value string) error {
+			c.Weeks = value
+			return pet.ValidateWeeks(c.WeekStart, value)
+		},
+		values: func(Config) []string { return []string{pet.WeeksRolling, pet.WeeksCalendar} },
+	},
+	"quiet-hours": {
+		get: func(c Config) string { return c.Notifications.QuietHours.String() },
+		set: func(c *Config, value string) error {
+			if value == "off" {
+				c.Notifications.QuietHours.Start, c.Notifications.QuietHours.End = "", ""
+				return nil
+			}
+			start, end, ok := strings.Cut(value, "-")
+			if !ok {
+				return fmt.Errorf("quiet-hours must be off or a window like 22:00-07:00")
+			}
+			c.Notifications.QuietHours.Start, c.Notifications.QuietHours.End = strings.TrimSpace(start), strings.TrimSpace(end)
+			return c.Notifications.QuietHours.validate()
+		},
+		values: func(Config) []string { return []string{"off", "22:00-07:00"} },
+	},
+	"sounds": {
+		get: func(c Config) string {
+			if c.Sounds.Enabled {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *Config, value string) error {
+			switch value {
+			case "on", "off":
+				c.Sounds.Enabled = value == "on"
+				return nil
+			}
+			return fmt.Errorf("sounds must be on or off")
+		},
+		values: func(Config) []string { return []string{"on", "off"} },
+	},
+	"private-activity": {
+		get: func(c Config) string {
+			if c.PrivateActivity {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *Config, value string) error {
+			switch value {
+			case "on", "off":
+				c.PrivateActivity = value == "on"
+				return nil
+			}
+			return fmt.Errorf("private-activity must be on or off")
+		},
+		values: func(Config) []string { return []string{"on", "off"} },
+	},
+	"public-only": {
+		get: func(c Config) string {
+			if c.PublicOnly {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *Config, value string) error {
+			switch value {
+			case "on", "off":
+				c.PublicOnly = value == "on"
+				return nil
+			}
+			return fmt.Errorf("public-only must be on or off")
+		},
+		values: func(Config) []string { return []string{"on", "off"} },
+	},
+	"mcp-sampling": {
+		get: func(c Config) string {
+			if c.MCPSampling {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *Config, value string) error {
+			switch value {
+			case "on", "off":
+				c.MCPSampling = value == "on"
+				return nil
+			}
+			return fmt.Errorf("mcp-sampling must be on or off")
+		},
+		values: func(Config) []string { return []string{"on", "off"} },
+	},
+	"async-hook": {
+		get: func(c Config) string {
+			if c.AsyncHook {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *Config, value string) error {
+			switch value {
+			case "on", "off":
+				c.AsyncHook = value == "on"
+				return nil
+			}
+			return fmt.Errorf("async-hook must be on or off")
+		},
+		values: func(Config) []string { return []string{"on", "off"} },
+	},
+	"presence": {
+		get: func(c Config) string {
+			if c.Presence.Enabled {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *Config, value string) error {
+			switch value {
+			case "on", "off":
+				c.Presence.Enabled = value == "on"
+				return c.Presence.validate()
+			}
+			return fmt.Errorf("presence must be on or off")
+		},
+		values: func(Config) []string { return []string{"on", "off"} },
+	},
+	"immortal": {
+		get: func(c Config) string {
+			if c.Immortal {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *Config, value string) error {
+			switch value {
+			case "on", "off":
+				c.Immortal = value == "on"
+				return nil
+			}
+			return fmt.Errorf("immortal must be on or off")
+		},
+		values: func(Config) []string { return []string{"on", "off"} },
+	},
+	"mode": {
+		get: func(c Config) string {
+			if c.Mode == "" {
+				return modePlayful
+			}
+			return c.Mode
+		},
+		set: func(c *Config, value string) error {
+			c.Mode = value
+			return validateMode(value)
+		},
+		values: func(Config) []string { return modes },
+	},
+	"prompt-branch": {
+		get: func(c Config) string {
+			if c.Prompt.Branch {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *Config, value string) error {
+			switch value {
+			case "on", "off":
+				c.Prompt.Branch = value == "on"
+				return nil
+			}
+			return fmt.Errorf("prompt-branch must be on or off")
+		},
+		values: func(Config) []string { return []string{"on", "off"} },
+	},
+	"theme": {
+		get: func(c Config) string { return c.Theme },
+		set: func(c *Config, value string) error {
+			c.Theme = value
+			return c.validateTheme()
+		},
+		values: func(c Config) []string {
+			var names []string
+			for name := range builtinThemes() {
+				names = append(names, name)
+			}
+			for name := range c.Themes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return names
+		},
+	},
+	"border": {
+		get: func(c Config) string { return c.Border },
+		set: func(c *Config, value string) error {
+			c.Border = value
+			return c.validateTheme()
+		},
+		values: func(Config) []string { return []string{"rounded", "ascii", "double"} },
+	},
+}
+
+func configKeyNames() []string {
+	names := make([]string, 0, len(configKeys))
+	for name := range configKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupConfigKey(name string) (configKey, error) {
+	key, ok := configKeys[name]
+	if !ok {
+		return configKey{}, usageErrorf("unknown setting %q (%s)", name, strings.Join(configKeyNames(), ", "))
+	}
+	return key, nil
+}
+
+func runConfigGet(args []string) error {
+	if len(args) != 1 {
+		return usageErrorf("usage: gh pet config get <key>")
+	}
+	key, err := lookupConfigKey(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	fmt.Println(key.get(cfg))
+	return nil
+}
+
+func runConfigSet(args []string) error {
+	if len(args) != 2 {
+		return usageErrorf("usage: gh pet config set <key> <value>")
+	}
+	key, err := lookupConfigKey(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if err := key.set(&cfg, args[1]); err != nil {
+		return err
+	}
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ %s set to %s%s\n", colorGreen, args[0], key.get(cfg), colorReset)
+	return nil
+}
+
+// configArgs completes a setting name, then its values.
+func configArgs(n int, positionals []string) []string {
+	switch {
+	case n == 0:
+		return configKeyNames()
+	case n == 1:
+		if key, ok := configKeys[positionals[0]]; ok {
+			cfg, _ := loadConfig()
+			return key.values(cfg)
+		}
+	}
+	return nil
+}
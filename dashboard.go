@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// dashboardDays is how many days the dashboard's charts reach back.
+const dashboardDays = 30
+
+// htmlRenderer draws the pet as HTML for the dashboard gh pet serve hosts at
+// /. Status is a whole page; the other views are fragments to put in one.
+type htmlRenderer struct {
+	history  store.History
+	absolute bool
+	now      time.Time
+}
+
+// Status is the dashboard: the animated pet, its stats and badges, the
+// wellness concerns, and charts of the last month's activity and mood.
+func (h htmlRenderer) Status(state store.PetState, concerns []string) string {
+	e := html.EscapeString
+	var sb strings.Builder
+	sb.WriteString(h.Card(state))
+	sb.WriteString(fmt.Sprintf("<p class=\"prompt\">%s</p>\n", h.Prompt(state, nil)))
+
+	sb.WriteString("<section>\n<h2>Stats</h2>\n<table>\n")
+	row := func(label, value string) {
+		sb.WriteString(fmt.Sprintf("<tr><th>%s</th><td>%s</td></tr>\n", e(i18n.Tr(label)), e(value)))
+	}
+	row("Mood", fmt.Sprintf("%d/100, %s", state.Mood, moodDescriptor(state.Mood)))
+	row("Kindness", fmt.Sprint(state.Kindness))
+	row("Shards", fmt.Sprint(state.Logic))
+	if state.Mentor > 0 {
+		row("Mentor", fmt.Sprint(state.Mentor))
+	}
+	if streak := store.CurrentStreak(h.history, h.now); streak > 0 {
+		row("Streak", i18n.Plural(streak, "day"))
+	}
+	row("Synced", i18n.DisplayTime(state.LastSync, h.absolute))
+	if badges := strings.TrimSpace(store.AchievementBadges(state) + " " + store.EventBadges(state)); badges != "" {
+		row("Badges", badges)
+	}
+	sb.WriteString("</table>\n</section>\n")
+
+	a := state.Activity
+	sb.WriteString("<section>\n<h2>Last 7 days</h2>\n<table>\n")
+	for _, r := range []struct {
+		label string
+		n     int
+	}{
+		{"Commits", a.Commits},
+		{"Merged PRs", a.MergedPRs},
+		{"Reviews", a.Reviews},
+		{"Docs/Comments", a.DocComments},
+		{"Tests", a.TestCommits},
+		{"Issues", pet.IssueTriage(a)},
+	} {
+		sb.WriteString(fmt.Sprintf("<tr><th>%s</th><td>%d</td></tr>\n", e(r.label), r.n))
+	}
+	sb.WriteString("</table>\n")
+	if langs := activity.LanguageLine(a.Languages); langs != "" {
+		sb.WriteString(fmt.Sprintf("<p>%s: %s</p>\n", e(i18n.Tr("Langs")), e(langs)))
+	}
+	sb.WriteString("</section>\n")
+
+	sb.WriteString(fmt.Sprintf("<section>\n<h2>%s</h2>\n", e(i18n.Tr("Wellness"))))
+	if len(concerns) == 0 {
+		concerns = []string{i18n.Tr("💚 Balanced rhythm. Keep it gentle.")}
+	}
+	sb.WriteString("<ul>\n")
+	for _, concern := range concerns {
+		sb.WriteString(fmt.Sprintf("<li>%s</li>\n", e(concern)))
+	}
+	sb.WriteString("</ul>\n</section>\n")
+
+	days := h.recentDays()
+	sb.WriteString(fmt.Sprintf("<section>\n<h2>Activity, last %d days</h2>\n%s</section>\n", dashboardDays, activityChart(days)))
+	sb.WriteString(fmt.Sprintf("<section>\n<h2>Mood, last %d days</h2>\n%s</section>\n", dashboardDays, moodChart(days)))
+	return htmlPage(state.Signature()+" "+state.DisplayName(), sb.String(), true)
+}
+
+// PostCommit is the card for a new commit, with the mood it earned.
+func (h htmlRenderer) PostCommit(state store.PetState, commitMsg string, moodGain int) string {
+	e := html.EscapeString
+	var sb strings.Builder
+	sb.WriteString("<section class=\"post-commit\">\n")
+	sb.WriteString(h.Card(state))
+	sb.WriteString(fmt.Sprintf("<p>%s %s <span class=\"gain\">+%d</span></p>\n", e(moodFace(state.Mood)), e(randomPraise()), moodGain))
+	if commitMsg != "" {
+		sb.WriteString(fmt.Sprintf("<p>📝 <code>%s</code></p>\n", e(commitMsg)))
+	}
+	sb.WriteString("</section>\n")
+	return sb.String()
+}
+
+func (h htmlRenderer) Prompt(state store.PetState, branch *branchState) string {
+	return html.EscapeString(promptLine(state, branch, h.now))
+}
+
+// Card is the pet's sprite, bobbing while it's awake, with its name, mood
+// bar, and evolution in the evolution's color.
+func (h htmlRenderer) Card(state store.PetState) string {
+	e := html.EscapeString
+	evolution := orDefault(state.Evolution, "Lonely")
+	color, ok := evolutionHex[evolution]
+	if !ok {
+		color = evolutionHex["Void"]
+	}
+	pose := "awake"
+	if store.IsAsleep(h.now) || state.Away(h.now) {
+		pose = "asleep"
+	}
+	mood := min(max(state.Mood, 0), 100)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<figure class=\"card\" style=\"--accent: %s\">\n", color))
+	sb.WriteString(fmt.Sprintf("<img class=\"pet %s\" src=\"data:image/png;base64,%s\" alt=\"%s\" width=\"96\" height=\"96\">\n",
+		pose, base64.StdEncoding.EncodeToString(spriteFor(state, h.now)), e(state.DisplayName()+" the "+evolution)))
+	sb.WriteString(fmt.Sprintf("<figcaption>\n<h1>%s %s</h1>\n", e(state.Signature()), e(state.DisplayName())))
+	sb.WriteString(fmt.Sprintf("<p>%s · %s</p>\n", e(i18n.Tr(evolution)), e(moodFace(state.Mood))))
+	sb.WriteString(fmt.Sprintf("<div class=\"bar\" title=\"%s %d/100\"><div style=\"width: %d%%\"></div></div>\n", e(i18n.Tr("Mood")), mood, mood))
+	if state.Away(h.now) {
+		sb.WriteString(fmt.Sprintf("<p>%s</p>\n", e(awayLine(state))))
+	}
+	sb.WriteString("</figcaption>\n</figure>\n")
+	return sb.String()
+}
+
+// recentDays is one record for each of the last dashboardDays days, oldest
+// first, with days the history has nothing for left at zero.
+func (h htmlRenderer) recentDays() []store.DayRecord {
+	today := time.Date(h.now.Year(), h.now.Month(), h.now.Day(), 0, 0, 0, 0, time.Local)
+	first := today.AddDate(0, 0, 1-dashboardDays)
+	recorded := map[string]store.DayRecord{}
+	for _, d := range h.history.Between(first, today.AddDate(0, 0, 1)) {
+		recorded[d.Date] = d
+	}
+	days := make([]store.DayRecord, dashboardDays)
+	for i := range days {
+		date := first.AddDate(0, 0, i).Format(store.DayLayout)
+		days[i] = recorded[date]
+		days[i].Date = date
+	}
+	return days
+}
+
+const (
+	chartWidth  = 600
+	chartHeight = 120
+)
+
+// activityChart draws a bar for each day's activity.
+func activityChart(days []store.DayRecord) string {
+	peak := 1
+	for _, d := range days {
+		peak = max(peak, d.Total())
+	}
+	step := float64(chartWidth) / float64(max(len(days), 1))
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<svg class=\"chart\" viewBox=\"0 0 %d %d\" role=\"img\" aria-label=\"Daily activity\">\n", chartWidth, chartHeight))
+	for i, d := range days {
+		height := float64(d.Total()) / float64(peak) * (chartHeight - 4)
+		sb.WriteString(fmt.Sprintf("<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\"><title>%s: %s</title></rect>\n",
+			float64(i)*step+1, chartHeight-height, step-2, height, d.Date, i18n.Plural(d.Total(), "contribution")))
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// moodChart draws the mood recorded each day as a line, skipping days with
+// no mood recorded.
+func moodChart(days []store.DayRecord) string {
+	step := float64(chartWidth) / float64(max(len(days), 1))
+	var points []string
+	for i, d := range days {
+		if d.Mood == 0 {
+			continue
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", float64(i)*step+step/2, chartHeight-2-float64(d.Mood)/100*(chartHeight-4)))
+	}
+	if len(points) == 0 {
+		return "<p>No mood recorded yet.</p>\n"
+	}
+	return fmt.Sprintf("<svg class=\"chart\" viewBox=\"0 0 %d %d\" role=\"img\" aria-label=\"Daily mood\">\n<polyline points=\"%s\"/>\n</svg>\n",
+		chartWidth, chartHeight, strings.Join(points, " "))
+}
+
+// dashboardStyle is the dashboard's stylesheet: a dark page like the SVG
+// card, with the pet bobbing while it's awake and breathing while asleep.
+const dashboardStyle = `
+body { background: #1e1e24; color: #c8c8d0; font-family: sans-serif; max-width: 640px; margin: 2em auto; padding: 0 1em; }
+h1 { margin: 0; font-size: 1.4em; color: var(--accent); }
+h2 { font-size: 1em; border-bottom: 1px solid #3a3a44; padding-bottom: .3em; }
+table { border-collapse: collapse; }
+th { text-align: left; font-weight: normal; color: #8a8f98; padding-right: 2em; }
+.card { --accent: #8a8f98; display: flex; gap: 1.5em; align-items: center; margin: 0; padding: 1em; border: 2px solid var(--accent); border-radius: 10px; }
+.card figcaption p { margin: .3em 0; }
+.pet { image-rendering: pixelated; }
+.pet.awake { animation: bob 1.2s ease-in-out infinite; }
+.pet.asleep { animation: breathe 4s ease-in-out infinite; opacity: .8; }
+@keyframes bob { 50% { transform: translateY(-6px); } }
+@keyframes breathe { 50% { transform: scale(1.04); } }
+.bar { width: 200px; height: 10px; border-radius: 5px; background: #3a3a44; }
+.bar div { height: 100%; border-radius: 5px; background: linear-gradient(to right, ` + moodLowHex + `, ` + moodMidHex + `, ` + moodHighHex + `); }
+.prompt { font-family: monospace; }
+.chart { width: 100%; height: auto; }
+.chart rect { fill: #4a90e2; }
+.chart polyline { fill: none; stroke: ` + moodHighHex + `; stroke-width: 2; }
+`
+
+// htmlPage wraps body in a page. With refresh, the page reloads every
+// minute, so a dashboard left open keeps up with feeds.
+func htmlPage(title, body string, refresh bool) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\">")
+	sb.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">")
+	if refresh {
+		sb.WriteString("<meta http-equiv=\"refresh\" content=\"60\">")
+	}
+	sb.WriteString(fmt.Sprintf("<title>%s</title>\n<style>%s</style></head><body>\n", html.EscapeString(title), dashboardStyle))
+	sb.WriteString(body)
+	sb.WriteString("</body></html>\n")
+	return sb.String()
+}
+
+// handleDashboard serves the dashboard page.
+func handleDashboard(w http.ResponseWriter, r *http.Request) {
+	state, _ := store.Load()
+	history, _ := store.LoadHistory()
+	cfg, err := loadConfig()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	render := htmlRenderer{history: history, now: now}
+	concerns := pet.WellnessConcerns(state.Activity, store.CurrentStreak(history, now), cfg.Wellness)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, render.Status(state, concerns))
+}
+
+// handleCard serves the card alone as a page, for an iframe.
+func handleCard(w http.ResponseWriter, r *http.Request) {
+	state, _ := store.Load()
+	render := htmlRenderer{now: time.Now()}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, htmlPage(state.DisplayName(), render.Card(state), true))
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package main
+
+import "os/exec"
+
+// detach is a no-op where processes can't be moved to a new session; the
+// background sync simply shares the hook's.
+func detach(cmd *exec.Cmd) {}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachedProcess starts a process without a console of its own.
+const detachedProcess = 0x00000008
+
+// detach starts cmd outside the terminal's console and process group, so it
+// outlives the hook.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Discord's local RPC speaks in frames: an opcode, a length, and JSON.
+const (
+	discordOpHandshake = 0
+	discordOpFrame     = 1
+	discordOpClose     = 2
+	// discordTimeout bounds each exchange, so a hung client can't stall a
+	// pomodoro.
+	discordTimeout = 5 * time.Second
+)
+
+// discordActivity is what Rich Presence shows under the Keeper's name.
+type discordActivity struct {
+	Details    string             `json:"details,omitempty"`
+	State      string             `json:"state,omitempty"`
+	Timestamps *discordTimestamps `json:"timestamps,omitempty"`
+}
+
+type discordTimestamps struct {
+	Start int64 `json:"start,omitempty"`
+	End   int64 `json:"end,omitempty"`
+}
+
+// discordIPC is a connection to the Discord desktop client.
+type discordIPC struct {
+	conn io.ReadWriteCloser
+}
+
+// dialDiscord connects to the running Discord client as the application
+// clientID. It fails when Discord isn't running.
+func dialDiscord(clientID string) (*discordIPC, error) {
+	conn, err := openDiscordPipe()
+	if err != nil {
+		return nil, err
+	}
+	d := &discordIPC{conn: conn}
+	if _, err := d.exchange(discordOpHandshake, map[string]any{"v": 1, "client_id": clientID}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("discord handshake: %w", err)
+	}
+	return d, nil
+}
+
+// setActivity replaces the Keeper's status; nil clears it.
+func (d *discordIPC) setActivity(activity *discordActivity) error {
+	nonce := make([]byte, 8)
+	rand.Read(nonce)
+	reply, err := d.exchange(discordOpFrame, map[string]any{
+		"cmd":   "SET_ACTIVITY",
+		"args":  map[string]any{"pid": os.Getpid(), "activity": activity},
+		"nonce": hex.EncodeToString(nonce),
+	})
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Evt  string `json:"evt"`
+		Data struct {
+			Message string `json:"message"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(reply, &resp) == nil && resp.Evt == "ERROR" {
+		return fmt.Errorf("discord: %s", resp.Data.Message)
+	}
+	return nil
+}
+
+func (d *discordIPC) Close() error {
+	d.write(discordOpClose, map[string]any{})
+	return d.conn.Close()
+}
+
+// exchange sends one frame and returns the payload of the reply.
+func (d *discordIPC) exchange(op uint32, payload any) ([]byte, error) {
+	if deadline, ok := d.conn.(interface{ SetDeadline(time.Time) error }); ok {
+		deadline.SetDeadline(time.Now().Add(discordTimeout))
+	}
+	if err := d.write(op, payload); err != nil {
+		return nil, err
+	}
+	var header [8]byte
+	if _, err := io.ReadFull(d.conn, header[:]); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(header[:4]) == discordOpClose {
+		return nil, errors.New("discord closed the connection")
+	}
+	reply := make([]byte, binary.LittleEndian.Uint32(header[4:]))
+	if _, err := io.ReadFull(d.conn, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (d *discordIPC) write(op uint32, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 8, 8+len(body))
+	binary.LittleEndian.PutUint32(frame[:4], op)
+	binary.LittleEndian.PutUint32(frame[4:], uint32(len(body)))
+	_, err = d.conn.Write(append(frame, body...))
+	return err
+}
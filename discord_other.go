@@ -0,0 +1,36 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// openDiscordPipe connects to the socket of the first Discord client that
+// answers, including the Flatpak and Snap builds, which keep theirs in a
+// subdirectory of the runtime directory.
+func openDiscordPipe() (io.ReadWriteCloser, error) {
+	var dirs []string
+	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if dir := os.Getenv(env); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	dirs = append(dirs, "/tmp")
+	for _, dir := range dirs {
+		for _, sub := range []string{"", "app/com.discordapp.Discord", "snap.discord"} {
+			for i := 0; i < 10; i++ {
+				conn, err := net.Dial("unix", filepath.Join(dir, sub, fmt.Sprintf("discord-ipc-%d", i)))
+				if err == nil {
+					return conn, nil
+				}
+			}
+		}
+	}
+	return nil, errors.New("discord is not running")
+}
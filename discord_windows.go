@@ -0,0 +1,22 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// openDiscordPipe opens the named pipe of the first Discord client that
+// answers; a second client or a PTB build takes the next number.
+func openDiscordPipe() (io.ReadWriteCloser, error) {
+	for i := 0; i < 10; i++ {
+		pipe, err := os.OpenFile(fmt.Sprintf(`\\.\pipe\discord-ipc-%d`, i), os.O_RDWR, 0)
+		if err == nil {
+			return pipe, nil
+		}
+	}
+	return nil, errors.New("discord is not running")
+}
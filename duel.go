@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const (
+	duelHP        = 100
+	duelMaxRounds = 12
+	duelColumn    = 22
+)
+
+// duelMoves gives each evolution its signature attack.
+var duelMoves = map[string][]string{
+	"Pioneer":  {"swings a pickaxe", "deploys to production", "charts a shortcut"},
+	"Guardian": {"raises a shield", "requests changes", "blocks the merge"},
+	"Bard":     {"sings a README ballad", "writes a stinging doc comment", "recites the changelog"},
+	"Void":     {"deletes 400 lines", "refactors reality", "stares into the diff"},
+	"Sentinel": {"fires a unit test", "asserts dominance", "runs the whole suite"},
+	"Curator":  {"labels the opponent wontfix", "closes a duplicate", "files an issue about it"},
+	"Lonely":   {"sighs dramatically", "naps aggressively", "waits for a commit"},
+}
+
+// duelist is one side of a duel. The opponent's is a shadow pet built from
+// public events alone; nothing about it is stored.
+type duelist struct {
+	Name      string
+	Emoji     string
+	Evolution string
+	Week      ActivitySummary
+	Power     int
+	HP        int
+}
+
+func newDuelist(name, emoji string, week ActivitySummary, scoring ScoringConfig) *duelist {
+	return &duelist{
+		Name:      name,
+		Emoji:     emoji,
+		Evolution: pet.EvolutionFor(week),
+		Week:      week,
+		// Everyone gets a base power so a quiet week still has a fighting chance.
+		Power: 5 + scoring.LogicFor(week) + scoring.KindnessFor(week) + scoring.MoodGainFor(week),
+		HP:    duelHP,
+	}
+}
+
+func (d *duelist) move(rng *rand.Rand) string {
+	moves, ok := duelMoves[d.Evolution]
+	if !ok {
+		moves = duelMoves["Pioneer"]
+	}
+	return moves[rng.Intn(len(moves))]
+}
+
+func runDuel(args []string) error {
+	fs := newFlagSet("duel")
+	fast := fs.Bool("fast", false, "skip the dramatic pauses")
+	seed := fs.Int64("seed", 0, "replay a duel with this seed")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	// Allow the username before or after the flags.
+	if fs.NArg() == 0 {
+		return usageErrorf("usage: gh pet duel <username> [--fast] [--seed n]")
+	}
+	opponent := strings.TrimPrefix(fs.Arg(0), "@")
+	if err := parseFlags(fs, fs.Args()[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	login, err := ghLogin(ctx)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(login, opponent) {
+		return fmt.Errorf("%s can't duel its own reflection; pick another username", opponent)
+	}
+	mine, err := ghEvents(ctx, login)
+	if err != nil {
+		return err
+	}
+	theirs, err := ghEvents(ctx, opponent)
+	if err != nil {
+		return fmt.Errorf("cannot fetch @%s's public events: %w", opponent, err)
+	}
+
+	mine, theirs = cfg.Bots.HumanEvents(mine), cfg.Bots.HumanEvents(theirs)
+
+	state, _ := store.Load()
+	me := newDuelist(state.DisplayName(), state.Signature(), discountCommits(cfg.Scoring, mine, summarize(mine)), cfg.Scoring)
+	them := newDuelist("@"+opponent+"'s shadow", "👤", discountCommits(cfg.Scoring, theirs, summarize(theirs)), cfg.Scoring)
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+	pause := func() {
+		if !*fast {
+			time.Sleep(600 * time.Millisecond)
+		}
+	}
+
+	theme := loadTheme()
+	fmt.Printf("\n%s⚔️  %s %s vs %s %s%s\n\n", theme.Bold, me.Emoji, me.Name, them.Emoji, them.Name, theme.Reset)
+	fmt.Println(sideBySide(skinnedArt(me.Evolution), skinnedArt(them.Evolution)))
+	fmt.Println(sideBySide(me.Evolution, them.Evolution))
+	fmt.Println()
+	for _, row := range duelTale(me.Week, them.Week) {
+		fmt.Printf("%s  %s%s\n", theme.Dim, row, theme.Reset)
+	}
+	fmt.Println()
+	pause()
+
+	for round := 1; round <= duelMaxRounds && me.HP > 0 && them.HP > 0; round++ {
+		attacker, defender := me, them
+		// The busier week lands more blows, but never all of them.
+		if rng.Intn(me.Power+them.Power) >= me.Power {
+			attacker, defender = them, me
+		}
+		damage := 8 + rng.Intn(15)
+		crit := rng.Intn(10) == 0
+		if crit {
+			damage *= 2
+		}
+		defender.HP = max(defender.HP-damage, 0)
+		line := fmt.Sprintf("Round %d: %s %s %s!", round, attacker.Emoji, attacker.Name, attacker.move(rng))
+		if crit {
+			line += " Critical hit!"
+		}
+		fmt.Printf("%s %s−%d%s\n", line, theme.Bad, damage, theme.Reset)
+		fmt.Printf("   %s %s  %s %s\n", hpBar(me.HP), me.Emoji, hpBar(them.HP), them.Emoji)
+		pause()
+	}
+
+	fmt.Println()
+	switch {
+	case me.HP == them.HP:
+		fmt.Printf("%s🤝 A draw! Both pets share a snack.%s\n", theme.Bold, theme.Reset)
+	case me.HP > them.HP:
+		fmt.Printf("%s🏆 %s wins! %s bows politely and wanders off to write some code.%s\n", theme.Good, me.Name, them.Name, theme.Reset)
+	default:
+		fmt.Printf("%s🏆 %s wins! %s dusts itself off and asks for a rematch next week.%s\n", theme.Warn, them.Name, me.Name, theme.Reset)
+	}
+	fmt.Printf("%sJust for fun: no stats were changed. Replay with --seed %d%s\n", theme.Dim, *seed, theme.Reset)
+	return nil
+}
+
+// duelTale lines up the weekly stats that decide who lands more blows.
+func duelTale(me, them ActivitySummary) []string {
+	rows := []struct {
+		label string
+		a, b  int
+	}{
+		{"Commits", me.Commits, them.Commits},
+		{"Merged PRs", me.MergedPRs, them.MergedPRs},
+		{"Reviews", me.Reviews, them.Reviews},
+		{"Docs", me.DocComments, them.DocComments},
+		{"Issues", pet.IssueTriage(me), pet.IssueTriage(them)},
+	}
+	out := make([]string, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, fmt.Sprintf("%-11s %4d  vs  %-4d", r.label, r.a, r.b))
+	}
+	return out
+}
+
+func hpBar(hp int) string {
+	filled := (hp + 9) / 10
+	return fmt.Sprintf("[%s%s] %3d", strings.Repeat("█", filled), strings.Repeat("░", 10-filled), hp)
+}
+
+// sideBySide prints two blocks of text in columns, padding by display width
+// so emoji in the art don't push the right column around.
+func sideBySide(left, right string) string {
+	a := strings.Split(left, "\n")
+	b := strings.Split(right, "\n")
+	var sb strings.Builder
+	for i := 0; i < max(len(a), len(b)); i++ {
+		l, r := "", ""
+		if i < len(a) {
+			l = a[i]
+		}
+		if i < len(b) {
+			r = b[i]
+		}
+		sb.WriteString(strings.TrimRight(l+strings.Repeat(" ", max(duelColumn-displayWidth(l), 1))+r, " "))
+		if i < max(len(a), len(b))-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
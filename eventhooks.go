@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// HooksConfig runs the Keeper's own shell commands when something happens
+// to the pet, such as turning a smart light red when mood drops. Each
+// command gets the event as GITPET_* environment variables and as JSON on
+// stdin; see eventHookInput.
+type HooksConfig struct {
+	OnEvolution   string     `json:"on_evolution,omitempty"`
+	OnAchievement string     `json:"on_achievement,omitempty"`
+	OnMoodBelow   []MoodHook `json:"on_mood_below,omitempty"`
+}
+
+// MoodHook runs Run once mood falls below Mood, and not again until it has
+// been back up.
+type MoodHook struct {
+	Mood int    `json:"mood"`
+	Run  string `json:"run"`
+}
+
+func (h HooksConfig) validate() error {
+	for _, m := range h.OnMoodBelow {
+		if m.Mood < 1 || m.Mood > 100 {
+			return fmt.Errorf("hooks.on_mood_below mood must be between 1 and 100")
+		}
+		if m.Run == "" {
+			return fmt.Errorf("hooks.on_mood_below for mood %d has nothing to run", m.Mood)
+		}
+	}
+	return nil
+}
+
+// eventHookTimeout is how long one hook command may run before it's killed.
+const eventHookTimeout = 10 * time.Second
+
+// eventHookInput is what a hook command gets on stdin.
+type eventHookInput struct {
+	// Event is evolution, achievement, or mood_below.
+	Event       string     `json:"event"`
+	Pet         petSummary `json:"pet"`
+	Previous    petSummary `json:"previous"`
+	Achievement string     `json:"achievement,omitempty"`
+	Threshold   int        `json:"threshold,omitempty"`
+}
+
+// runEventHooks runs the configured commands for what changed between two
+// saves of the pet, one after another. A failing command is reported on
+// stderr and doesn't stop the rest.
+func runEventHooks(cfg HooksConfig, before, after store.PetState, unlocked []string) {
+	base := eventHookInput{Pet: summarizePet(after), Previous: summarizePet(before)}
+	if cfg.OnEvolution != "" && store.Evolved(before, after) {
+		in := base
+		in.Event = "evolution"
+		runEventHook(cfg.OnEvolution, in)
+	}
+	if cfg.OnAchievement != "" {
+		for _, achievement := range unlocked {
+			in := base
+			in.Event, in.Achievement = "achievement", achievement
+			runEventHook(cfg.OnAchievement, in)
+		}
+	}
+	for _, m := range cfg.OnMoodBelow {
+		if before.Mood >= m.Mood && after.Mood < m.Mood {
+			in := base
+			in.Event, in.Threshold = "mood_below", m.Mood
+			runEventHook(m.Run, in)
+		}
+	}
+}
+
+func runEventHook(command string, in eventHookInput) {
+	input, err := json.Marshal(in)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), eventHookTimeout)
+	defer cancel()
+	cmd := shellCommand(ctx, command)
+	cmd.WaitDelay = gh.KillGrace
+	cmd.Stdin = bytes.NewReader(input)
+	// Hooks talk on stderr, so they don't end up in output that's piped on.
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	cmd.Env = append(os.Environ(),
+		"GITPET_EVENT="+in.Event,
+		"GITPET_NAME="+in.Pet.Name,
+		"GITPET_EVOLUTION="+in.Pet.Evolution,
+		"GITPET_PREVIOUS_EVOLUTION="+in.Previous.Evolution,
+		"GITPET_MOOD="+strconv.Itoa(in.Pet.Mood),
+		"GITPET_PREVIOUS_MOOD="+strconv.Itoa(in.Previous.Mood),
+		"GITPET_ACHIEVEMENT="+in.Achievement,
+		"GITPET_THRESHOLD="+strconv.Itoa(in.Threshold),
+	)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("still running after %s", eventHookTimeout)
+		}
+		fmt.Fprintf(os.Stderr, "GitPet: %s hook failed: %v\n", in.Event, err)
+	}
+}
+
+// shellCommand runs command through the platform's shell, so hooks can use
+// pipes and quoting as typed.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+func runEvents() error {
+	state, _ := store.Load()
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	active := store.ActiveEvents(now)
+
+	fmt.Printf("\n%s📅 Seasonal events%s\n\n", colorBold, colorReset)
+	if len(active) == 0 {
+		fmt.Println("Nothing is running right now.")
+	}
+	running := map[string]bool{}
+	for _, w := range active {
+		running[w.Name] = true
+		daysLeft := int(w.End.Sub(now).Hours()/24) + 1
+		fmt.Printf("%s %s%s%s until %s (%s left)\n", w.Icon, colorBold, w.Name, colorReset,
+			w.End.AddDate(0, 0, -1).Format("Jan 2"), i18n.Plural(daysLeft, "day"))
+		if store.HasBadge(state, w.Badge()) {
+			fmt.Printf("   %s✓ %s: %s badge earned%s\n", colorGreen, w.Quest, w.Badge(), colorReset)
+		} else {
+			fmt.Printf("   Quest: %s (%d/%d) for the %s badge\n", w.Quest, min(w.QuestProgress(history), w.Target), w.Target, w.Badge())
+		}
+		fmt.Printf("   %sYour pet wears: %s%s\n", colorDim, i18n.Tr(w.Overlay), colorReset)
+	}
+
+	var upcoming []store.EventWindow
+	for _, e := range store.SeasonalEvents {
+		if !running[e.Name] {
+			upcoming = append(upcoming, e.NextWindow(now))
+		}
+	}
+	sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].Start.Before(upcoming[j].Start) })
+	if len(upcoming) > 0 {
+		fmt.Printf("\n%sComing up%s\n", colorBold, colorReset)
+	}
+	for _, w := range upcoming {
+		fmt.Printf("%s %-15s %s – %s  %s%s%s\n", w.Icon, w.Name, w.Start.Format("Jan 2"), w.End.AddDate(0, 0, -1).Format("Jan 2"),
+			colorDim, w.Quest, colorReset)
+	}
+
+	if len(state.Badges) > 0 {
+		fmt.Printf("\n%sLimited badges%s\n", colorBold, colorReset)
+		for _, badge := range state.Badges {
+			fmt.Printf("%s %s\n", store.BadgeIcon(badge), badge)
+		}
+	}
+	return nil
+}
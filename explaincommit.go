@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// runExplain tells the Keeper what a commit did, in the pet's words.
+func runExplain(args []string) error {
+	fs := newFlagSet("explain")
+	copilot := fs.Bool("copilot", false, "have Copilot write the explanation")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return usageErrorf("usage: gh pet explain [sha] [--copilot]")
+	}
+	rev := "HEAD"
+	if fs.NArg() == 1 {
+		rev = fs.Arg(0)
+	}
+	ctx := context.Background()
+	c, err := activity.LoadCommit(ctx, rev)
+	if err != nil {
+		return err
+	}
+	state, _ := store.Load()
+
+	if *copilot {
+		if !copilotAvailable(ctx) {
+			return fmt.Errorf("gh copilot is not installed; run gh extension install github/gh-copilot, or drop --copilot")
+		}
+		prompt := fmt.Sprintf("You are %s, a %s GitPet feeling %s. Explain this commit to your Keeper in plain language, in a few friendly sentences. %s", state.Introduction(), state.Evolution, moodDescriptor(state.Mood), c.Facts())
+		cmd := exec.Command("gh", "copilot", "explain", prompt)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	if professional() {
+		fmt.Printf("%s %s (%s): %s, +%d/-%d lines.\n", c.Short, c.Subject, c.Author, i18n.Plural(len(c.Change.Files), "file"), c.Change.Insert, c.Change.Delete)
+		return nil
+	}
+	fmt.Printf("%s %s explains %s%s%s:\n\n", state.Signature(), state.DisplayName(), colorBold, c.Short, colorReset)
+	fmt.Println(activity.NarrateCommit(state, c))
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// streakMilestones are the streak lengths worth a calendar entry.
+var streakMilestones = []int{7, 14, 30, 50, 100, 200, 365, 500, 1000}
+
+// celebration is one all-day calendar entry.
+type celebration struct {
+	Date string
+	Kind string
+	// Key names what's celebrated, such as the achievement, so the event
+	// keeps its UID when the pet is renamed.
+	Key     string
+	Summary string
+	Detail  string
+}
+
+// runExport writes the pet's milestones in a format other apps read. The
+// only format so far is iCalendar.
+func runExport(args []string) error {
+	fs := newFlagSet("export")
+	ics := fs.Bool("ics", false, "an iCalendar file of achievements, evolutions, and streak milestones")
+	out := fs.String("out", "", "write to a file instead of stdout")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if !*ics {
+		return usageErrorf("choose a format: gh pet export --ics")
+	}
+	state, _ := store.Load()
+	journal, err := store.LoadJournal()
+	if err != nil {
+		return err
+	}
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	events := celebrations(state, journal, history)
+	text := icsCalendar(state, events, time.Now())
+	if *out == "" {
+		fmt.Print(text)
+		return nil
+	}
+	if err := os.WriteFile(*out, []byte(text), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Exported %s to %s. Import it into Google or Apple Calendar.%s\n", colorGreen, i18n.Plural(len(events), "celebration"), *out, colorReset)
+	return nil
+}
+
+// celebrations are the days the journal marks an evolution or achievement,
+// and the days history shows a streak reaching a milestone, oldest first.
+func celebrations(state store.PetState, journal store.Journal, history store.History) []celebration {
+	name := state.DisplayName()
+	var out []celebration
+	for _, e := range journal.Entries {
+		date := e.Time.Local().Format(store.DayLayout)
+		if e.Evolved != "" {
+			out = append(out, celebration{Date: date, Kind: "evolution", Key: e.Evolved, Summary: fmt.Sprintf("✨ %s evolved into a %s", name, e.Evolved), Detail: e.Text})
+		}
+		for _, a := range e.Unlocked {
+			out = append(out, celebration{Date: date, Kind: "achievement", Key: a, Summary: fmt.Sprintf("%s earned %s", name, a), Detail: e.Text})
+		}
+	}
+
+	// Walk the days in order, counting each run of active days. Holidays
+	// pause a run rather than end it.
+	streak, previous := 0, time.Time{}
+	var streaks []celebration
+	for _, d := range history.Days {
+		if d.Total() == 0 {
+			if !history.AwayOn(d.Date) {
+				streak = 0
+			}
+			continue
+		}
+		day := d.Day()
+		if streak > 0 && history.AwayBetween(previous, day) {
+			streak++
+		} else {
+			streak = 1
+		}
+		previous = day
+		for _, m := range streakMilestones {
+			if streak == m {
+				streaks = append(streaks, celebration{Date: d.Date, Kind: "streak", Key: fmt.Sprint(m), Summary: fmt.Sprintf("🔥 %d-day streak with %s", m, name),
+					Detail: fmt.Sprintf("%d days in a row of commits, reviews, or docs.", m)})
+			}
+		}
+	}
+	// Journal entries and history are each in date order; merge them.
+	merged := make([]celebration, 0, len(out)+len(streaks))
+	i, j := 0, 0
+	for i < len(out) || j < len(streaks) {
+		if j == len(streaks) || i < len(out) && out[i].Date <= streaks[j].Date {
+			merged = append(merged, out[i])
+			i++
+		} else {
+			merged = append(merged, streaks[j])
+			j++
+		}
+	}
+	return merged
+}
+
+// icsCalendar renders the celebrations as an iCalendar (RFC 5545) file of
+// all-day events.
+func icsCalendar(state store.PetState, events []celebration, now time.Time) string {
+	var sb strings.Builder
+	line := func(s string) { sb.WriteString(icsFold(s)) }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//GitPet//gh-pet//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + icsEscape(state.DisplayName()+"'s celebrations"))
+	stamp := now.UTC().Format("20060102T150405Z")
+	seen := map[string]bool{}
+	for _, e := range events {
+		day, err := time.ParseInLocation(store.DayLayout, e.Date, time.Local)
+		uid := fmt.Sprintf("%s-%s-%s@gitpet", strings.ReplaceAll(e.Date, "-", ""), e.Kind, icsSlug(e.Key))
+		if err != nil || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		line("BEGIN:VEVENT")
+		line("UID:" + uid)
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + icsEscape(e.Summary))
+		if e.Detail != "" {
+			line("DESCRIPTION:" + icsEscape(e.Detail))
+		}
+		line("CATEGORIES:GitPet")
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return sb.String()
+}
+
+// icsEscape escapes text for an iCalendar property value.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icsFold ends a content line with CRLF, folding it so no physical line is
+// longer than 75 bytes, without splitting a UTF-8 character.
+func icsFold(s string) string {
+	var sb strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		sb.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// The leading space of a continuation line counts toward its 75.
+		limit = 74
+	}
+	sb.WriteString(s + "\r\n")
+	return sb.String()
+}
+
+// icsSlug is the ASCII letters and digits of key, lowercased, for a UID.
+func icsSlug(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, key)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Editors write a file several times per save, so each file yields at most
+// one thought fragment per cooldown.
+const fragmentCooldown = 30 * time.Second
+
+// focusIgnoredDirs are never watched: they change without anyone thinking.
+var focusIgnoredDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "target": true,
+	"dist": true, "build": true, "__pycache__": true,
+}
+
+// focusSession is one stretch of saves with no pause longer than --idle.
+type focusSession struct {
+	Start     time.Time
+	Last      time.Time
+	Fragments int
+	Files     map[string]bool
+	Announced int
+}
+
+func (s *focusSession) pomodoros(length time.Duration) int {
+	return int(s.Last.Sub(s.Start) / length)
+}
+
+func runFocus(args []string) error {
+	flags := newFlagSet("focus")
+	pomodoro := flags.Duration("pomodoro", 25*time.Minute, "length of one pomodoro")
+	idle := flags.Duration("idle", 5*time.Minute, "a pause this long ends the session")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *pomodoro <= 0 || *idle <= 0 {
+		return fmt.Errorf("--pomodoro and --idle must be positive")
+	}
+	repos := flags.Args()
+	if len(repos) == 0 {
+		repos = hookRepos()
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no repositories to watch; run inside a repo or pass paths")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot start file watcher: %w", err)
+	}
+	defer watcher.Close()
+	for _, repo := range repos {
+		if err := watchTree(watcher, repo); err != nil {
+			return err
+		}
+	}
+
+	state, _ := store.Load()
+	fmt.Printf("👀 %s is watching %s. Save files to gather thought fragments; Ctrl+C to stop.\n", state.DisplayName(), i18n.Plural(len(repos), "repo"))
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer startPresence(cfg.Presence, presenceSession{Kind: "focus", Start: time.Now()})()
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	var session *focusSession
+	lastFragment := map[string]time.Time{}
+	finish := func() {
+		if session != nil {
+			if err := endFocusSession(cfg, session, *pomodoro); err != nil {
+				fmt.Fprintln(os.Stderr, "GitPet: could not save focus session:", err)
+			}
+			session = nil
+		}
+	}
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				finish()
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = watchTree(watcher, event.Name)
+					continue
+				}
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) || isScratchFile(event.Name) {
+				continue
+			}
+			now := time.Now()
+			if now.Sub(lastFragment[event.Name]) < fragmentCooldown {
+				continue
+			}
+			lastFragment[event.Name] = now
+			if session != nil && now.Sub(session.Last) > *idle {
+				finish()
+			}
+			if session == nil {
+				session = &focusSession{Start: now, Files: map[string]bool{}}
+				fmt.Printf("🎯 Focus session started at %s.\n", now.Format("15:04"))
+			}
+			session.Last = now
+			session.Fragments++
+			session.Files[event.Name] = true
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				finish()
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, "GitPet: file watcher:", err)
+		case now := <-ticker.C:
+			if session == nil {
+				continue
+			}
+			if now.Sub(session.Last) > *idle {
+				finish()
+				continue
+			}
+			if n := session.pomodoros(*pomodoro); n > session.Announced {
+				session.Announced = n
+				msg := fmt.Sprintf("Pomodoro %d done with %s. Stretch for five minutes?", n, i18n.Plural(session.Fragments, "thought fragment"))
+				fmt.Printf("🍅 %s\n", msg)
+				notify(cfg.Notifications, state.Signature()+" "+state.DisplayName(), msg)
+			}
+		case <-stop:
+			finish()
+			return nil
+		}
+	}
+}
+
+// watchTree adds root and every directory below it, skipping hidden and
+// generated ones. fsnotify does not recurse by itself.
+func watchTree(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (focusIgnoredDirs[name] || strings.HasPrefix(name, ".")) {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("cannot watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// isScratchFile reports editor swap, backup, and lock files.
+func isScratchFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, ".") || strings.HasPrefix(base, "#") ||
+		strings.HasSuffix(base, "~") || strings.HasSuffix(base, ".swp") ||
+		strings.HasSuffix(base, ".swx") || strings.HasSuffix(base, ".tmp")
+}
+
+// endFocusSession banks the session's fragments for the next feed, rewards
+// each completed pomodoro with mood, and prints a summary.
+func endFocusSession(cfg Config, s *focusSession, pomodoro time.Duration) error {
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	minutes := int(s.Last.Sub(s.Start).Minutes())
+	pomodoros := s.pomodoros(pomodoro)
+	reward := pomodoros * cfg.Scoring.FocusMood
+	state.PendingThoughts += s.Fragments
+	state.Mood = min(100, state.Mood+reward)
+	if err := store.Save(state); err != nil {
+		return err
+	}
+	if pomodoros > 0 {
+		if err := store.RecordFocus(pomodoros, minutes); err != nil {
+			fmt.Fprintln(os.Stderr, "GitPet: could not record focus session:", err)
+		}
+	}
+
+	work := fmt.Sprintf("%s across %s", i18n.Plural(s.Fragments, "thought fragment"), i18n.Plural(len(s.Files), "file"))
+	if pomodoros == 0 {
+		fmt.Printf("☕ %s of focus, %s. Not a full pomodoro yet.\n", i18n.Plural(minutes, "minute"), work)
+		return nil
+	}
+	fmt.Printf("🍅 Focus session: %s, %s, %s. +%d mood\n", i18n.Plural(minutes, "minute"), i18n.Plural(pomodoros, "pomodoro"), work, reward)
+	line := fmt.Sprintf("Keeper focused for %s (%s) and I collected %s. My head is full of ideas.", i18n.Plural(minutes, "minute"), i18n.Plural(pomodoros, "pomodoro"), i18n.Plural(s.Fragments, "thought fragment"))
+	if err := store.AddJournalEntry("focus", line); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+	return nil
+}
@@ -0,0 +1,589 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// GH Archive (gharchive.org) keeps every public GitHub event since 2011 in
+// one gzipped dump per hour, far past the 90 days the events API serves.
+// gh pet archive streams those dumps, keeps the Keeper's own events, and
+// folds them into history. Each dump is over 100 MB, so a year is a long
+// download; progress is saved after every hour, and running the command
+// again picks up where it stopped.
+
+const (
+	gharchiveURL          = "https://data.gharchive.org/"
+	archiveProgressFile   = "gh-pet-archive.json"
+	defaultArchiveJobs    = 4
+	defaultArchiveMaxDisk = "1GB"
+)
+
+// gharchiveClient has no overall timeout, since a dump takes minutes; a
+// stalled one is cut off by its context instead.
+var gharchiveClient = &http.Client{}
+
+// archiveProgress is what earlier runs of gh pet archive have read: which
+// hours are done, and the day records built from them so far. Hours are
+// UTC, named as GH Archive names its dumps, e.g. "2025-01-02-15".
+type archiveProgress struct {
+	Login string                     `json:"login"`
+	Done  map[string]bool            `json:"done"`
+	Days  map[string]store.DayRecord `json:"days"`
+	// Missing are hours GH Archive has no dump for; there are a few.
+	Missing int `json:"missing,omitempty"`
+}
+
+func runArchive(args []string) error {
+	fs := newFlagSet("archive")
+	from := fs.String("from", "", "first day to import, as YYYY-MM-DD")
+	to := fs.String("to", "", "last day to import, as YYYY-MM-DD (default: yesterday)")
+	jobs := fs.Int("jobs", defaultArchiveJobs, "how many hourly dumps to download at once")
+	maxDisk := fs.String("max-disk", defaultArchiveMaxDisk, "disk space for keeping downloaded dumps, so a rerun needn't fetch them again; 0 keeps none")
+	clean := fs.Bool("clean", false, "delete the kept dumps and the saved progress")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *clean {
+		return cleanArchive()
+	}
+	limit, err := parseByteSize(*maxDisk)
+	if err != nil {
+		return usageErrorf("--max-disk: %v", err)
+	}
+	if *jobs < 1 || *jobs > 16 {
+		return usageErrorf("--jobs must be between 1 and 16")
+	}
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1).Format(store.DayLayout)
+	if *from == "" {
+		return usageErrorf("usage: gh pet archive --from YYYY-MM-DD [--to YYYY-MM-DD]")
+	}
+	if *to == "" {
+		*to = yesterday
+	}
+	first, err := time.ParseInLocation(store.DayLayout, *from, time.Local)
+	if err != nil {
+		return usageErrorf("--from must be a date like %s", now.AddDate(-1, 0, 0).Format(store.DayLayout))
+	}
+	last, err := time.ParseInLocation(store.DayLayout, *to, time.Local)
+	if err != nil {
+		return usageErrorf("--to must be a date like %s", yesterday)
+	}
+	if last.Before(first) || *to > yesterday {
+		return usageErrorf("--to must be no earlier than --from and no later than %s", yesterday)
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	login, err := ghLogin(ctx)
+	if err != nil {
+		return err
+	}
+	progress, err := loadArchiveProgress(login)
+	if err != nil {
+		return err
+	}
+	cache, err := archiveCacheDir()
+	if err != nil {
+		return err
+	}
+
+	// Local days start and end at different UTC hours, so the range takes
+	// every hour touching them.
+	var hours []string
+	for t := first.UTC().Truncate(time.Hour); t.Before(last.AddDate(0, 0, 1)); t = t.Add(time.Hour) {
+		if name := archiveHour(t); !progress.Done[name] {
+			hours = append(hours, name)
+		}
+	}
+	if len(hours) == 0 {
+		fmt.Println("Every hour in that range was imported already.")
+	}
+
+	imp := &archiveImport{
+		login:    login,
+		filter:   cfg.repoFilter(),
+		progress: &progress,
+		cache:    cache,
+		limit:    limit,
+		total:    len(hours),
+	}
+	imp.cached.Store(dirSize(cache))
+	stopUI := imp.showProgress()
+	runErr := imp.run(ctx, hours, *jobs)
+	stopUI()
+
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	filled := mergeArchive(&history, progress.Days, first, last)
+	if filled > 0 {
+		if err := store.SaveHistory(history); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\n%s🗄️  GH Archive import for @%s%s\n\n", colorBold, login, colorReset)
+	fmt.Printf("  %-9s %d of %s read, %s downloaded\n", "Hours", imp.done.Load(), i18n.Plural(len(hours), "hour"), byteSize(int(imp.downloaded.Load())))
+	fmt.Printf("  %-9s %s found\n", "Events", i18n.Plural(int(imp.found.Load()), "event"))
+	fmt.Printf("  %-9s %s filled in between %s and %s\n", "History", i18n.Plural(filled, "day"), *from, *to)
+	if progress.Missing > 0 {
+		fmt.Printf("  %s%s GH Archive has no dump for.%s\n", colorDim, i18n.Plural(progress.Missing, "hour"), colorReset)
+	}
+	if runErr != nil {
+		if ctx.Err() != nil {
+			fmt.Printf("\n%sPaused. Run the same command to pick up where it stopped.%s\n", colorDim, colorReset)
+			return nil
+		}
+		return fmt.Errorf("%w\nProgress is saved; run the same command to pick up where it stopped", runErr)
+	}
+	return nil
+}
+
+// archiveImport is one run of gh pet archive, shared by its downloads.
+type archiveImport struct {
+	login  string
+	filter activity.RepoFilter
+	cache  string
+	limit  int64
+	total  int
+
+	mu       sync.Mutex
+	progress *archiveProgress
+
+	done, found, downloaded, cached atomic.Int64
+}
+
+// run reads hours with jobs downloads at a time. It stops at the first
+// error, or when ctx is cancelled, with every finished hour saved.
+func (imp *archiveImport) run(ctx context.Context, hours []string, jobs int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	queue := make(chan string)
+	errs := make(chan error, jobs)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for hour := range queue {
+				if err := imp.readHour(ctx, hour); err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+feed:
+	for _, hour := range hours {
+		select {
+		case queue <- hour:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// archiveAttempts is how many times a dump is tried when its download
+// fails partway.
+const archiveAttempts = 3
+
+// readHour imports one hourly dump and saves the progress.
+func (imp *archiveImport) readHour(ctx context.Context, hour string) error {
+	var days map[string]store.DayRecord
+	var found int
+	err := ctx.Err()
+	for attempt := 0; attempt < archiveAttempts && ctx.Err() == nil; attempt++ {
+		if days, found, err = imp.countHour(ctx, hour); err == nil || gh.IsNotFound(err) {
+			break
+		}
+		logging.Logger.Debug("gharchive", "hour", hour, "attempt", attempt+1, "err", err)
+	}
+	missing := gh.IsNotFound(err)
+	if missing {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	for date, rec := range days {
+		day := imp.progress.Days[date]
+		day.Date = date
+		day.Add(rec)
+		imp.progress.Days[date] = day
+	}
+	imp.progress.Done[hour] = true
+	if missing {
+		imp.progress.Missing++
+	}
+	imp.done.Add(1)
+	imp.found.Add(int64(found))
+	return saveArchiveProgress(*imp.progress)
+}
+
+// countHour counts the Keeper's events in hour's dump by local day.
+func (imp *archiveImport) countHour(ctx context.Context, hour string) (map[string]store.DayRecord, int, error) {
+	days := map[string]store.DayRecord{}
+	found := 0
+	err := imp.scanHour(ctx, hour, func(line []byte) {
+		// Most lines aren't the Keeper's; skip them before decoding.
+		if !bytes.Contains(line, []byte(`"login":"`+imp.login+`"`)) {
+			return
+		}
+		var event struct {
+			Event
+			Actor struct {
+				Login string `json:"login"`
+			} `json:"actor"`
+		}
+		if json.Unmarshal(line, &event) != nil || !strings.EqualFold(event.Actor.Login, imp.login) {
+			return
+		}
+		if len(imp.filter.Events([]Event{event.Event})) == 0 {
+			return
+		}
+		date := event.CreatedAt.Local().Format(store.DayLayout)
+		rec := days[date]
+		rec.AddCounts(pet.Summarize([]Event{event.Event}, time.Time{}))
+		days[date] = rec
+		found++
+	})
+	return days, found, err
+}
+
+// scanHour calls fn with each line of hour's dump, reading the kept copy if
+// there is one. Otherwise it downloads the dump, keeping a copy when it
+// fits in the disk limit.
+func (imp *archiveImport) scanHour(ctx context.Context, hour string, fn func([]byte)) error {
+	kept := filepath.Join(imp.cache, hour+".json.gz")
+	if f, err := os.Open(kept); err == nil {
+		defer f.Close()
+		return scanDump(f, fn)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gharchiveURL+hour+".json.gz", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "gh-pet")
+	resp, err := gharchiveClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("gharchive %s: %w", hour, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &gh.APIError{Endpoint: "gharchive " + hour, Status: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+	body := io.Reader(&countingReader{r: resp.Body, n: &imp.downloaded})
+
+	var part *os.File
+	if size := resp.ContentLength; size > 0 && imp.cached.Add(size) <= imp.limit {
+		if part, err = os.Create(kept + ".part"); err == nil {
+			body = io.TeeReader(body, part)
+		} else {
+			imp.cached.Add(-size)
+		}
+	} else if size > 0 {
+		imp.cached.Add(-size)
+	}
+	err = scanDump(body, fn)
+	if part != nil {
+		part.Close()
+		if err == nil {
+			err = os.Rename(kept+".part", kept)
+		} else {
+			os.Remove(kept + ".part")
+			imp.cached.Add(-resp.ContentLength)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("gharchive %s: %w", hour, err)
+	}
+	return nil
+}
+
+// scanDump calls fn with each line of a gzipped dump. Lines hold a whole
+// event, and some run to megabytes.
+func scanDump(r io.Reader, fn func([]byte)) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	lines := bufio.NewReader(gz)
+	for {
+		line, err := lines.ReadBytes('\n')
+		if len(line) > 0 {
+			fn(line)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// countingReader adds the bytes read through it to n.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// showProgress redraws a progress line on stderr until stop is called. Like
+// the spinner, it stays silent when stderr isn't a terminal.
+func (imp *archiveImport) showProgress() (stop func()) {
+	if info, err := os.Stderr.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 || os.Getenv("TERM") == "dumb" || imp.total == 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	start := time.Now()
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		width := 0
+		for {
+			n := int(imp.done.Load())
+			line := fmt.Sprintf("🗄️  %s %d/%d hours · %s · %s downloaded",
+				progressBar(n, imp.total, 20), n, imp.total, i18n.Plural(int(imp.found.Load()), "event"), byteSize(int(imp.downloaded.Load())))
+			if n > 0 && n < imp.total {
+				left := time.Since(start) / time.Duration(n) * time.Duration(imp.total-n)
+				line += " · " + left.Round(time.Minute).String() + " left"
+			}
+			width = max(width, displayWidth(line))
+			fmt.Fprintf(os.Stderr, "\r%-*s", width, line)
+			select {
+			case <-ticker.C:
+			case <-done:
+				fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", width))
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// progressBar draws n of total as a bar width cells wide.
+func progressBar(n, total, width int) string {
+	filled := width * n / max(total, 1)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// mergeArchive folds the imported days from first to last into history the
+// way syncs do, keeping whichever count is higher, and returns how many
+// days it filled in or raised. Months retention already rolled up are
+// raised as a whole instead.
+func mergeArchive(history *store.History, days map[string]store.DayRecord, first, last time.Time) int {
+	from, to := first.Format(store.DayLayout), last.Format(store.DayLayout)
+	months := map[string]*store.MonthRecord{}
+	filled := 0
+	for date, imported := range days {
+		if date < from || date > to || imported.Total() == 0 {
+			continue
+		}
+		if history.RolledUp(date) {
+			month := date[:len(store.MonthLayout)]
+			if months[month] == nil {
+				months[month] = &store.MonthRecord{Month: month}
+			}
+			months[month].Add(imported)
+			continue
+		}
+		rec := history.Day(date)
+		before := *rec
+		rec.Raise(imported)
+		if *rec != before {
+			filled++
+		}
+	}
+	for month, imported := range months {
+		m := history.Month(month)
+		before := *m
+		m.ActiveDays = max(m.ActiveDays, imported.ActiveDays)
+		m.Commits = max(m.Commits, imported.Commits)
+		m.MergedPRs = max(m.MergedPRs, imported.MergedPRs)
+		m.Reviews = max(m.Reviews, imported.Reviews)
+		m.DocComments = max(m.DocComments, imported.DocComments)
+		m.Issues = max(m.Issues, imported.Issues)
+		m.TestCommits = max(m.TestCommits, imported.TestCommits)
+		if *m != before {
+			filled += imported.ActiveDays
+		}
+	}
+	return filled
+}
+
+// archiveHour names the dump for the UTC hour t falls in. GH Archive
+// doesn't pad the hour: "2025-01-02-5", not "2025-01-02-05".
+func archiveHour(t time.Time) string {
+	t = t.UTC()
+	return t.Format("2006-01-02") + "-" + strconv.Itoa(t.Hour())
+}
+
+func archiveCacheDir() (string, error) {
+	base, err := dirs.Cache()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, "gharchive")
+	return dir, os.MkdirAll(dir, 0o700)
+}
+
+// dirSize adds up the sizes of the files in dir.
+func dirSize(dir string) int64 {
+	entries, _ := os.ReadDir(dir)
+	var size int64
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+func archiveProgressPath() (string, error) {
+	return dirs.DataPath(archiveProgressFile)
+}
+
+// loadArchiveProgress reads what earlier runs imported for login. Progress
+// for another login is set aside.
+func loadArchiveProgress(login string) (archiveProgress, error) {
+	fresh := archiveProgress{Login: login, Done: map[string]bool{}, Days: map[string]store.DayRecord{}}
+	path, err := archiveProgressPath()
+	if err != nil {
+		return fresh, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fresh, nil
+	}
+	if err != nil {
+		return fresh, err
+	}
+	var progress archiveProgress
+	if err := json.Unmarshal(data, &progress); err != nil || !strings.EqualFold(progress.Login, login) {
+		return fresh, nil
+	}
+	if progress.Done == nil {
+		progress.Done = map[string]bool{}
+	}
+	if progress.Days == nil {
+		progress.Days = map[string]store.DayRecord{}
+	}
+	return progress, nil
+}
+
+func saveArchiveProgress(progress archiveProgress) error {
+	path, err := archiveProgressPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// cleanArchive deletes the kept dumps and the saved progress. History the
+// imports filled in stays.
+func cleanArchive() error {
+	dir, err := archiveCacheDir()
+	if err != nil {
+		return err
+	}
+	freed := dirSize(dir)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	path, err := archiveProgressPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	fmt.Printf("%s✓ Freed %s of GH Archive dumps and cleared the import progress.%s\n", colorGreen, byteSize(int(freed)), colorReset)
+	return nil
+}
+
+// parseByteSize reads a size like "500MB" or "2GB", in the binary units
+// byteSize writes. A bare number is bytes.
+func parseByteSize(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+	text := strings.ToUpper(strings.TrimSpace(s))
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(text, unit.suffix); ok {
+			n, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil || n < 0 {
+				break
+			}
+			return int64(n * float64(unit.size)), nil
+		}
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q isn't a size like 500MB or 2GB", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+func TestArchiveHour(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2025, time.January, 2, 5, 59, 0, 0, time.UTC), "2025-01-02-5"},
+		{time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC), "2025-01-02-0"},
+		{time.Date(2025, time.January, 2, 23, 30, 0, 0, time.UTC), "2025-01-02-23"},
+		// Dumps are named in UTC, whatever the Keeper's zone.
+		{time.Date(2025, time.January, 2, 1, 0, 0, 0, time.FixedZone("EST", -5*3600)), "2025-01-02-6"},
+		{time.Date(2025, time.January, 2, 3, 0, 0, 0, time.FixedZone("JST", 9*3600)), "2025-01-01-18"},
+	}
+	for _, tt := range tests {
+		if got := archiveHour(tt.t); got != tt.want {
+			t.Errorf("archiveHour(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"500MB", 500 << 20, false},
+		{"2GB", 2 << 30, false},
+		{"1.5 gb", 3 << 29, false},
+		{"64kb", 64 << 10, false},
+		{"10B", 10, false},
+		{"4096", 4096, false},
+		{" 0 ", 0, false},
+		{"", 0, true},
+		{"-1MB", 0, true},
+		{"-5", 0, true},
+		{"lots", 0, true},
+		{"5TB", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseByteSize(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// writeDump keeps lines as hour's gzipped dump in dir, as a finished
+// download would.
+func writeDump(t *testing.T, dir, hour string, lines ...string) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(dir, hour+".json.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	for i, line := range lines {
+		if i > 0 {
+			gz.Write([]byte("\n"))
+		}
+		gz.Write([]byte(line))
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCountHour(t *testing.T) {
+	dir := t.TempDir()
+	const hour = "2025-01-02-15"
+	// The last line has no newline, as some dumps end.
+	writeDump(t, dir, hour,
+		`{"type":"PushEvent","created_at":"2025-01-02T15:04:00Z","actor":{"login":"octocat"},"repo":{"name":"octocat/app"},"public":true,"payload":{"commits":[{"sha":"a","message":"Add tests"},{"sha":"b","message":"Fix typo"}]}}`,
+		`{"type":"PushEvent","created_at":"2025-01-02T15:05:00Z","actor":{"login":"someone"},"repo":{"name":"someone/app"},"public":true,"payload":{"commits":[{"sha":"c","message":"Not ours"}]}}`,
+		`{"type":"IssuesEvent","created_at":"2025-01-02T15:06:00Z","actor":{"login":"octocat"},"repo":{"name":"octocat/app"},"public":true,"payload":{"action":"opened"}}`,
+		`{"type":"PullRequestReviewEvent","created_at":"2025-01-02T15:07:00Z","actor":{"login":"octocat"},"repo":{"name":"octocat/skip"},"public":true,"payload":{}}`,
+		`not json, but mentions "login":"octocat"`,
+		`{"type":"PullRequestReviewEvent","created_at":"2025-01-02T15:08:00Z","actor":{"login":"octocat"},"repo":{"name":"octocat/app"},"public":true,"payload":{}}`,
+	)
+
+	imp := &archiveImport{login: "octocat", cache: dir, filter: activity.RepoFilter{Ignore: []string{"octocat/skip"}}}
+	days, found, err := imp.countHour(context.Background(), hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Other people's events, ignored repos, and unreadable lines are left
+	// out.
+	if found != 3 {
+		t.Errorf("found %d events, want 3", found)
+	}
+	date := time.Date(2025, time.January, 2, 15, 4, 0, 0, time.UTC).Local().Format(store.DayLayout)
+	want := store.DayRecord{Commits: 2, TestCommits: 1, Reviews: 1, Issues: 1}
+	if got := days[date]; got != want {
+		t.Errorf("days[%s] = %+v, want %+v", date, got, want)
+	}
+}
+
+func TestMergeArchive(t *testing.T) {
+	first := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.Local)
+	last := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name       string
+		history    store.History
+		days       map[string]store.DayRecord
+		wantFilled int
+		want       store.History
+	}{
+		{
+			name:       "fills a missing day",
+			days:       map[string]store.DayRecord{"2025-03-10": {Commits: 3}},
+			wantFilled: 1,
+			want:       store.History{Days: []store.DayRecord{{Date: "2025-03-10", Commits: 3}}},
+		},
+		{
+			name:       "raises counts the feed missed",
+			history:    store.History{Days: []store.DayRecord{{Date: "2025-03-10", Commits: 5, Reviews: 1, Mood: 40}}},
+			days:       map[string]store.DayRecord{"2025-03-10": {Commits: 3, Reviews: 2}},
+			wantFilled: 1,
+			want:       store.History{Days: []store.DayRecord{{Date: "2025-03-10", Commits: 5, Reviews: 2, Mood: 40}}},
+		},
+		{
+			name:    "leaves a day that already saw more",
+			history: store.History{Days: []store.DayRecord{{Date: "2025-03-10", Commits: 5}}},
+			days:    map[string]store.DayRecord{"2025-03-10": {Commits: 3}},
+			want:    store.History{Days: []store.DayRecord{{Date: "2025-03-10", Commits: 5}}},
+		},
+		{
+			name: "skips empty days and days out of range",
+			days: map[string]store.DayRecord{"2025-03-11": {}, "2025-02-28": {Commits: 1}, "2025-04-01": {Commits: 1}},
+		},
+		{
+			name:       "raises a rolled-up month as a whole",
+			history:    store.History{Months: []store.MonthRecord{{Month: "2025-03", ActiveDays: 1, Commits: 2}}},
+			days:       map[string]store.DayRecord{"2025-03-10": {Commits: 3}, "2025-03-11": {Commits: 4}},
+			wantFilled: 2,
+			want:       store.History{Months: []store.MonthRecord{{Month: "2025-03", ActiveDays: 2, Commits: 7}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := tt.history
+			if got := mergeArchive(&history, tt.days, first, last); got != tt.wantFilled {
+				t.Errorf("filled %d days, want %d", got, tt.wantFilled)
+			}
+			if len(history.Days) != len(tt.want.Days) || len(history.Months) != len(tt.want.Months) {
+				t.Fatalf("history = %+v, want %+v", history, tt.want)
+			}
+			for i := range history.Days {
+				if history.Days[i] != tt.want.Days[i] {
+					t.Errorf("day %d = %+v, want %+v", i, history.Days[i], tt.want.Days[i])
+				}
+			}
+			for i := range history.Months {
+				if history.Months[i] != tt.want.Months[i] {
+					t.Errorf("month %d = %+v, want %+v", i, history.Months[i], tt.want.Months[i])
+				}
+			}
+		})
+	}
+}
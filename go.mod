@@ -2,7 +2,13 @@ module github.com/gitpet/gh-pet
 
 go 1.23.0
 
-require github.com/mark3labs/mcp-go v0.44.0
+require (
+	github.com/fsnotify/fsnotify v1.8.0
+	github.com/mark3labs/mcp-go v0.44.0
+	golang.org/x/sync v0.10.0
+	golang.org/x/sys v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/bahlo/generic-list-go v0.2.0 // indirect
@@ -13,5 +19,4 @@ require (
 	github.com/spf13/cast v1.7.1 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Goal is a target the Keeper sets for one metric over a day, week, or
+// month, tracked against history.
+type Goal struct {
+	Metric string `json:"metric"`
+	Target int    `json:"target"`
+	Per    string `json:"per"`
+}
+
+// goalMetric is something a goal can count, read from day records.
+type goalMetric struct {
+	Name  string
+	Label string
+	value func(d store.DayRecord) int
+}
+
+var goalMetrics = []goalMetric{
+	{"commits", "Commits", func(d store.DayRecord) int { return d.Commits }},
+	{"merged-prs", "Merged PRs", func(d store.DayRecord) int { return d.MergedPRs }},
+	{"reviews", "Reviews", func(d store.DayRecord) int { return d.Reviews }},
+	{"docs", "Docs/comments", func(d store.DayRecord) int { return d.DocComments }},
+	{"issues", "Issues", func(d store.DayRecord) int { return d.Issues }},
+	{"tests", "Test commits", func(d store.DayRecord) int { return d.TestCommits }},
+	{"pomodoros", "Pomodoros", func(d store.DayRecord) int { return d.Pomodoros }},
+	{"focus-minutes", "Focus minutes", func(d store.DayRecord) int { return d.FocusMinutes }},
+	{"active-days", "Active days", func(d store.DayRecord) int {
+		if d.Total() > 0 {
+			return 1
+		}
+		return 0
+	}},
+}
+
+var goalPeriods = []string{"day", "week", "month"}
+
+func findGoalMetric(name string) (goalMetric, bool) {
+	for _, m := range goalMetrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return goalMetric{}, false
+}
+
+func goalMetricNames() []string {
+	var names []string
+	for _, m := range goalMetrics {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+// goalArgs completes the metric, the first argument of goal set and
+// remove.
+func goalArgs(n int, _ []string) []string {
+	if n != 0 {
+		return nil
+	}
+	return goalMetricNames()
+}
+
+func validateGoals(goals []Goal) error {
+	for _, g := range goals {
+		if _, ok := findGoalMetric(g.Metric); !ok {
+			return fmt.Errorf("goals: unknown metric %q; use one of %s", g.Metric, strings.Join(goalMetricNames(), ", "))
+		}
+		if !containsString(goalPeriods, g.Per) {
+			return fmt.Errorf("goals: per must be day, week, or month, not %q", g.Per)
+		}
+		if g.Target < 1 {
+			return fmt.Errorf("goals: %s target must be at least 1", g.Metric)
+		}
+	}
+	return nil
+}
+
+// periodStart is local midnight at the start of the day, week, or month
+// holding t.
+func periodStart(per string, t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch per {
+	case "week":
+		return pet.WeekStart(t)
+	case "month":
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextPeriod(per string, start time.Time) time.Time {
+	switch per {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// progress counts the goal's metric over the period starting at start.
+func (g Goal) progress(history store.History, start time.Time) int {
+	metric, _ := findGoalMetric(g.Metric)
+	n := 0
+	for _, d := range history.Between(start, nextPeriod(g.Per, start)) {
+		n += metric.value(d)
+	}
+	return n
+}
+
+func (g Goal) label() string {
+	metric, _ := findGoalMetric(g.Metric)
+	return metric.Label
+}
+
+// goalLine draws a goal's progress in the current period as a bar.
+func goalLine(g Goal, history store.History, now time.Time) string {
+	done := g.progress(history, periodStart(g.Per, now))
+	filled := min(done, g.Target) * 10 / g.Target
+	color := colorYellow
+	if done >= g.Target {
+		color = colorGreen
+	}
+	return fmt.Sprintf("%-14s %s%s%s%s %d/%d per %s", g.label(), color, strings.Repeat("█", filled),
+		colorDim+strings.Repeat("░", 10-filled), colorReset, done, g.Target, g.Per)
+}
+
+// goalReview is how a goal went over a period that has just ended.
+type goalReview struct {
+	Goal Goal
+	Done int
+	Met  bool
+}
+
+// reviewGoals looks back on each goal whose period ended since the last
+// feed, and rewards the ones that were met. Only the most recent period is
+// reviewed, so a long absence brings one verdict per goal, not a backlog.
+func reviewGoals(cfg Config, state *store.PetState, history store.History, now time.Time) []goalReview {
+	last, err := time.ParseInLocation(store.DayLayout, state.GoalsChecked, now.Location())
+	state.GoalsChecked = now.Format(store.DayLayout)
+	if err != nil {
+		return nil
+	}
+	var reviews []goalReview
+	for _, g := range cfg.Goals {
+		current := periodStart(g.Per, now)
+		if !last.Before(current) {
+			continue
+		}
+		ended := periodStart(g.Per, current.Add(-time.Hour))
+		done := g.progress(history, ended)
+		review := goalReview{Goal: g, Done: done, Met: done >= g.Target}
+		if review.Met {
+			state.Mood = min(100, state.Mood+cfg.Scoring.GoalMood)
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews
+}
+
+// goalsMet counts the goals reviews found met.
+func goalsMet(reviews []goalReview) int {
+	met := 0
+	for _, r := range reviews {
+		if r.Met {
+			met++
+		}
+	}
+	return met
+}
+
+// message is the pet's verdict on a review, for the feed and the journal.
+func (r goalReview) message() string {
+	when := "last " + r.Goal.Per
+	if r.Goal.Per == "day" {
+		when = "yesterday"
+	}
+	what := fmt.Sprintf("%s %d/%d %s", r.Goal.label(), r.Done, r.Goal.Target, when)
+	if r.Met {
+		return fmt.Sprintf("🎯 Goal met: %s. I'm so proud of you!", what)
+	}
+	return fmt.Sprintf("🥀 Goal missed: %s. We'll get it next time.", what)
+}
+
+func runGoalSet(args []string) error {
+	fs := newFlagSet("goal set")
+	per := fs.String("per", "week", "day, week, or month")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	rest := fs.Args()
+	if len(rest) < 2 {
+		return usageErrorf("usage: gh pet goal set <metric> <target> [--per week]")
+	}
+	// Flags may also follow the metric and target, as in "set commits 20
+	// --per week".
+	if err := parseFlags(fs, rest[2:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	target, err := strconv.Atoi(rest[1])
+	if err != nil {
+		return usageErrorf("target must be a whole number, not %q", rest[1])
+	}
+	goal := Goal{Metric: rest[0], Target: target, Per: *per}
+	if err := validateGoals([]Goal{goal}); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i, g := range cfg.Goals {
+		if g.Metric == goal.Metric && g.Per == goal.Per {
+			cfg.Goals[i] = goal
+			replaced = true
+		}
+	}
+	if !replaced {
+		cfg.Goals = append(cfg.Goals, goal)
+	}
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+	history, _ := store.LoadHistory()
+	fmt.Printf("🎯 Goal set: %s, %d per %s.\n", strings.ToLower(goal.label()), goal.Target, goal.Per)
+	fmt.Println("   " + goalLine(goal, history, time.Now()))
+	return nil
+}
+
+func runGoalRemove(args []string) error {
+	fs := newFlagSet("goal remove")
+	per := fs.String("per", "", "remove only the day, week, or month goal")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return usageErrorf("usage: gh pet goal remove <metric> [--per week]")
+	}
+	metric := fs.Arg(0)
+	if err := parseFlags(fs, fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	var kept []Goal
+	for _, g := range cfg.Goals {
+		if g.Metric != metric || *per != "" && g.Per != *per {
+			kept = append(kept, g)
+		}
+	}
+	removed := len(cfg.Goals) - len(kept)
+	if removed == 0 {
+		return fmt.Errorf("no %s goal to remove; see gh pet goal list", metric)
+	}
+	cfg.Goals = kept
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s.\n", i18n.Plural(removed, "goal"))
+	return nil
+}
+
+func runGoalList() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Goals) == 0 {
+		fmt.Println("No goals yet. Try gh pet goal set commits 20 --per week")
+		return nil
+	}
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	fmt.Printf("\n%s🎯 Goals%s\n\n", colorBold, colorReset)
+	for _, g := range cfg.Goals {
+		fmt.Println("  " + goalLine(g, history, now))
+	}
+	fmt.Printf("\n%sCounts come from history, so run gh pet feed to bring them up to date.%s\n", colorDim, colorReset)
+	return nil
+}
+
+// printGoals shows each goal's progress under the status card.
+func printGoals(goals []Goal, now time.Time) {
+	if len(goals) == 0 {
+		return
+	}
+	history, err := store.LoadHistory()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not read history:", err)
+		return
+	}
+	fmt.Println("🎯 " + i18n.Tr("Goals:"))
+	for _, g := range goals {
+		fmt.Println("  " + goalLine(g, history, now))
+	}
+}
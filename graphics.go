@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// sprites holds the pixel-art pets, 24×24 PNGs named for each evolution in
+// lowercase, plus default.png for the rest and sleeping.png for the night.
+//
+//go:embed sprites/*.png
+var sprites embed.FS
+
+// A sprite covers spriteCols×spriteRows cells of the status card, which is
+// about square in most terminal fonts.
+const (
+	spriteCols = 16
+	spriteRows = 8
+)
+
+// spriteMark is a private-use character in the corner of the space kept for
+// a sprite, so overlaySprite can find it in the rendered card.
+const spriteMark = '\uE000'
+
+// Image protocols, as graphicsProtocol reports them.
+const (
+	graphicsKitty = "kitty"
+	graphicsITerm = "iterm"
+	graphicsSixel = "sixel"
+)
+
+// graphicsProtocol names the image protocol this terminal speaks, going by
+// the variables terminals set, or "" when it can't show images.
+func graphicsProtocol() string {
+	term := strings.ToLower(os.Getenv("TERM"))
+	program := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return graphicsKitty
+	case program == "iTerm.app" || os.Getenv("LC_TERMINAL") == "iTerm2" || program == "WezTerm" || program == "mintty":
+		return graphicsITerm
+	case strings.HasPrefix(term, "foot") || term == "mlterm" || strings.Contains(term, "sixel") ||
+		term == "contour" || os.Getenv("KONSOLE_VERSION") != "":
+		return graphicsSixel
+	}
+	return ""
+}
+
+// spriteGraphics picks how gh pet status draws the pet for --graphics. It
+// returns the image protocol to draw a sprite with, or "" for ASCII art:
+// auto draws sprites only on a terminal, outside tmux, and with color on;
+// pixel draws them wherever the terminal can.
+func spriteGraphics(mode string) (string, error) {
+	switch mode {
+	case "ascii":
+		return "", nil
+	case "auto":
+		if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return "", nil
+		}
+		if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TMUX") != "" {
+			return "", nil
+		}
+		return graphicsProtocol(), nil
+	case "pixel":
+		protocol := graphicsProtocol()
+		if protocol == "" {
+			fmt.Fprintln(os.Stderr, i18n.Tr("This terminal can't show images, so here's the ASCII pet. kitty, iTerm2, WezTerm, and sixel terminals such as foot can."))
+		}
+		return protocol, nil
+	}
+	return "", usageErrorf("unknown --graphics %q (auto, ascii, or pixel)", mode)
+}
+
+// spritePlaceholder is the blank space renderArt keeps for a sprite.
+func spritePlaceholder() string {
+	rows := make([]string, spriteRows)
+	for i := range rows {
+		rows[i] = strings.Repeat(" ", spriteCols)
+	}
+	rows[0] = string(spriteMark) + rows[0][1:]
+	return strings.Join(rows, "\n")
+}
+
+// spriteFor is the sprite for the pet as it is now.
+func spriteFor(state store.PetState, now time.Time) []byte {
+	name := "default"
+	if store.IsAsleep(now) {
+		name = "sleeping"
+	} else if state.Evolution != "" {
+		name = strings.ToLower(state.Evolution)
+	}
+	data, err := sprites.ReadFile("sprites/" + name + ".png")
+	if err != nil {
+		data, _ = sprites.ReadFile("sprites/default.png")
+	}
+	return data
+}
+
+// overlaySprite draws sprite over the space kept for it in text, a rendered
+// card that ends in a newline: after the card is printed, the cursor goes
+// back up to the space, draws the image, and returns. It reports false when
+// text kept no space or the space would be scrolled out of reach, and the
+// card should be drawn in ASCII instead.
+func overlaySprite(text string, sprite []byte, protocol string) (string, bool) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		at := strings.IndexRune(line, spriteMark)
+		if at < 0 {
+			continue
+		}
+		up := len(lines) - 1 - i
+		if rows := terminalRows(); rows > 0 && up >= rows {
+			return "", false
+		}
+		image, err := encodeImage(sprite, protocol, spriteCols, spriteRows)
+		if err != nil {
+			return "", false
+		}
+		col := displayWidth(line[:at])
+		lines[i] = line[:at] + " " + line[at+len(string(spriteMark)):]
+		return strings.Join(lines, "\n") + fmt.Sprintf("\x1b7\x1b[%dA\x1b[%dG%s\x1b8", up, col+1, image), true
+	}
+	return "", false
+}
+
+// encodeImage wraps a PNG in the escape sequence for protocol. With cols and
+// rows, the image is fit into that many cells and the cursor stays put, as
+// far as the protocol allows; without, it's drawn at its own size.
+func encodeImage(data []byte, protocol string, cols, rows int) (string, error) {
+	switch protocol {
+	case graphicsKitty:
+		// kitty takes the image in chunks of at most 4096 bytes. q=2 keeps
+		// it from answering on stdin.
+		encoded := base64.StdEncoding.EncodeToString(data)
+		control := "f=100,a=T,q=2"
+		if cols > 0 {
+			control += fmt.Sprintf(",c=%d,r=%d,C=1", cols, rows)
+		}
+		var sb strings.Builder
+		for i := 0; i < len(encoded); i += 4096 {
+			chunk := encoded[i:min(i+4096, len(encoded))]
+			more := 0
+			if i+4096 < len(encoded) {
+				more = 1
+			}
+			if i == 0 {
+				fmt.Fprintf(&sb, "\x1b_G%s,m=%d;%s\x1b\\", control, more, chunk)
+			} else {
+				fmt.Fprintf(&sb, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+			}
+		}
+		return sb.String(), nil
+	case graphicsITerm:
+		size := ""
+		if cols > 0 {
+			size = fmt.Sprintf(";width=%d;height=%d", cols, rows)
+		}
+		return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d%s;preserveAspectRatio=1:%s\a", len(data), size, base64.StdEncoding.EncodeToString(data)), nil
+	case graphicsSixel:
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return "", err
+		}
+		scale := 1
+		if cols > 0 {
+			cellW, cellH := cellPixels()
+			if cellW == 0 || cellH == 0 {
+				cellW, cellH = 10, 20
+			}
+			b := img.Bounds()
+			scale = max(min(cols*cellW/b.Dx(), rows*cellH/b.Dy()), 1)
+		}
+		return sixel(img, scale), nil
+	}
+	return "", fmt.Errorf("unknown image protocol %q", protocol)
+}
+
+// sixel encodes img, blown up scale times without smoothing, as a sixel
+// image with a transparent background. Pixel art has few enough colors that
+// each gets its own palette entry.
+func sixel(img image.Image, scale int) string {
+	b := img.Bounds()
+	width, height := b.Dx()*scale, b.Dy()*scale
+	at := func(x, y int) color.NRGBA {
+		return color.NRGBAModel.Convert(img.At(b.Min.X+x/scale, b.Min.Y+y/scale)).(color.NRGBA)
+	}
+
+	var sb strings.Builder
+	// P2=1 leaves unset pixels alone, so the card shows through.
+	fmt.Fprintf(&sb, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	palette := map[color.NRGBA]int{}
+	var colors []color.NRGBA
+	for y := 0; y < height; y += scale {
+		for x := 0; x < width; x += scale {
+			c := at(x, y)
+			if _, ok := palette[c]; ok || c.A < 128 || len(palette) == 256 {
+				continue
+			}
+			palette[c] = len(palette)
+			colors = append(colors, c)
+			fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", palette[c], int(c.R)*100/255, int(c.G)*100/255, int(c.B)*100/255)
+		}
+	}
+
+	for top := 0; top < height; top += 6 {
+		first := true
+		for index, c := range colors {
+			// Each color paints its pixels of the band, run-length encoded.
+			var band strings.Builder
+			run, last, used := 0, byte(0), false
+			flush := func() {
+				if run > 3 {
+					fmt.Fprintf(&band, "!%d%c", run, last)
+				} else {
+					band.WriteString(strings.Repeat(string(last), run))
+				}
+			}
+			for x := 0; x < width; x++ {
+				bits := byte(0)
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if at(x, top+dy) == c {
+						bits |= 1 << dy
+					}
+				}
+				used = used || bits != 0
+				ch := 63 + bits
+				if run > 0 && ch != last {
+					flush()
+					run = 0
+				}
+				last = ch
+				run++
+			}
+			if !used {
+				continue
+			}
+			flush()
+			if !first {
+				sb.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&sb, "#%d%s", index, band.String())
+		}
+		sb.WriteByte('-')
+	}
+	sb.WriteString("\x1b\\")
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const (
+	graveyardFileName = "gh-pet-graveyard.json"
+	// inheritedLogicPercent is the share of logic shards a new egg inherits
+	// from the pet that departed before it.
+	inheritedLogicPercent = 25
+	// graveStoryEntries is how many of the last journal pages are buried
+	// with a pet.
+	graveStoryEntries = 5
+)
+
+// Grave is a pet that drifted into the Void: who it was, its final stats,
+// and the last pages of its story.
+type Grave struct {
+	Name         string   `json:"name"`
+	Emoji        string   `json:"emoji,omitempty"`
+	Evolution    string   `json:"evolution"`
+	Generation   int      `json:"generation"`
+	Hatched      string   `json:"hatched,omitempty"`
+	Departed     string   `json:"departed"`
+	Kindness     int      `json:"kindness"`
+	Logic        int      `json:"logic_shards"`
+	Mentor       int      `json:"mentor,omitempty"`
+	Achievements []string `json:"achievements,omitempty"`
+	Badges       []string `json:"badges,omitempty"`
+	Story        []string `json:"story,omitempty"`
+}
+
+// errDeparted stops commands that would care for a pet that's gone.
+var errDeparted = fmt.Errorf("your pet drifted into the Void; run gh pet hatch for a new egg, or gh pet graveyard to remember it")
+
+// trackNeglect notes the day mood fell to 0, and forgets it once mood
+// recovers or while the Keeper is away. It reports whether the pet has now
+// been at 0 for cfg.VoidDays and isn't immortal.
+func trackNeglect(cfg Config, state *store.PetState, now time.Time) bool {
+	if state.Mood > 0 || state.Away(now) {
+		state.MoodZeroSince = ""
+		return false
+	}
+	if state.MoodZeroSince == "" {
+		state.MoodZeroSince = now.Format(store.DayLayout)
+	}
+	since, err := time.ParseInLocation(store.DayLayout, state.MoodZeroSince, now.Location())
+	return err == nil && !cfg.Immortal && now.Sub(since) >= time.Duration(cfg.VoidDays)*24*time.Hour
+}
+
+// driftIntoVoid buries state in the graveyard and marks it departed. The
+// pet stays as it was, so gh pet undo can still bring it back.
+func driftIntoVoid(state *store.PetState, now time.Time) (Grave, error) {
+	grave := Grave{
+		Name:         state.DisplayName(),
+		Emoji:        state.Emoji,
+		Evolution:    state.Evolution,
+		Generation:   state.Generation,
+		Hatched:      state.Hatched,
+		Departed:     now.Format(store.DayLayout),
+		Kindness:     state.Kindness,
+		Logic:        state.Logic,
+		Mentor:       state.Mentor,
+		Achievements: state.Achievements,
+		Badges:       state.Badges,
+	}
+	if journal, err := store.LoadJournal(); err == nil && len(journal.Entries) > 0 {
+		if grave.Hatched == "" {
+			grave.Hatched = journal.Began().Local().Format(store.DayLayout)
+		}
+		for _, e := range journal.Entries[max(0, len(journal.Entries)-graveStoryEntries):] {
+			grave.Story = append(grave.Story, e.Text)
+		}
+	}
+	graves, err := loadGraveyard()
+	if err != nil {
+		return grave, err
+	}
+	if err := saveGraveyard(append(graves, grave)); err != nil {
+		return grave, err
+	}
+	state.Departed = grave.Departed
+	line := fmt.Sprintf("After %s at zero mood, I drifted into the Void. Thank you for everything, Keeper.", i18n.Plural(int(now.Sub(parseDay(state.MoodZeroSince, now)).Hours()/24), "day"))
+	if err := store.AddJournalEntry("void", line); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+	return grave, nil
+}
+
+func parseDay(date string, now time.Time) time.Time {
+	t, err := time.ParseInLocation(store.DayLayout, date, now.Location())
+	if err != nil {
+		return now
+	}
+	return t
+}
+
+// printDeparted stands in for the status card once the pet is gone.
+func printDeparted(state store.PetState) error {
+	graves, err := loadGraveyard()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n🌌 %s drifted into the Void on %s.\n", state.DisplayName(), state.Departed)
+	if len(graves) > 0 {
+		fmt.Print(renderGrave(graves[len(graves)-1]))
+	}
+	fmt.Printf("\nRun gh pet hatch for a new egg. It inherits %d%% of the logic shards.\n", inheritedLogicPercent)
+	return nil
+}
+
+func runGraveyard() error {
+	graves, err := loadGraveyard()
+	if err != nil {
+		return err
+	}
+	if len(graves) == 0 {
+		fmt.Println("The graveyard is empty. Every pet you've had is still with you.")
+		return nil
+	}
+	fmt.Printf("\n%s🪦 The graveyard%s\n", colorBold, colorReset)
+	for i := len(graves) - 1; i >= 0; i-- {
+		fmt.Print(renderGrave(graves[i]))
+	}
+	fmt.Println()
+	return nil
+}
+
+// renderGrave is a grave's headstone: name, years, final stats, and the
+// last pages of its story.
+func renderGrave(g Grave) string {
+	var sb strings.Builder
+	lived := g.Departed
+	if g.Hatched != "" {
+		lived = g.Hatched + " – " + g.Departed
+	}
+	fmt.Fprintf(&sb, "\n%s%s%s  %s%s, generation %d · %s%s\n", colorBold, strings.TrimSpace(g.Emoji+" "+g.Name), colorReset,
+		colorDim, g.Evolution, g.Generation+1, lived, colorReset)
+	fmt.Fprintf(&sb, "   Kindness %d · Logic shards %d · Mentor %d\n", g.Kindness, g.Logic, g.Mentor)
+	if len(g.Achievements)+len(g.Badges) > 0 {
+		fmt.Fprintf(&sb, "   🏆 %s\n", strings.Join(append(append([]string{}, g.Achievements...), g.Badges...), ", "))
+	}
+	for _, line := range g.Story {
+		fmt.Fprintf(&sb, "   %s“%s”%s\n", colorDim, line, colorReset)
+	}
+	return sb.String()
+}
+
+func loadGraveyard() ([]Grave, error) {
+	path, err := graveyardPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var graves []Grave
+	if err := json.Unmarshal(data, &graves); err != nil {
+		return nil, err
+	}
+	return graves, nil
+}
+
+func saveGraveyard(graves []Grave) error {
+	path, err := graveyardPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(graves, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func graveyardPath() (string, error) {
+	return dirs.DataPath(graveyardFileName)
+}
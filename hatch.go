@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// egg is a choice of egg in the hatching wizard. Its emoji becomes the
+// pet's signature; which egg hatches has no effect on stats.
+type egg struct {
+	Name  string
+	Emoji string
+	Blurb string
+}
+
+var eggs = []egg{
+	{"speckled", "", "A plain speckled egg. Anything could hatch."},
+	{"ember", "🔥", "Warm to the touch, and a little impatient."},
+	{"tide", "🌊", "Cool and calm; it rocks gently now and then."},
+	{"moss", "🌿", "Soft and green, as if it grew here."},
+	{"star", "⭐", "It glows faintly after dark."},
+}
+
+func findEgg(name string) (egg, bool) {
+	for _, e := range eggs {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return egg{}, false
+}
+
+func eggNames() []string {
+	var names []string
+	for _, e := range eggs {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// wizard asks questions on stdin. One reader serves every question, so
+// answers piped in ahead of time aren't lost to buffering.
+type wizard struct {
+	in *bufio.Reader
+}
+
+func (w wizard) ask(question, def string) string {
+	if def != "" {
+		fmt.Printf("%s %s[%s]%s ", question, colorDim, def, colorReset)
+	} else {
+		fmt.Printf("%s ", question)
+	}
+	answer, _ := w.in.ReadString('\n')
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def
+	}
+	return answer
+}
+
+// choose lists options and returns the index picked by number or name.
+func (w wizard) choose(question string, options []string, def int) int {
+	for {
+		answer := w.ask(question, strconv.Itoa(def+1))
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1
+		}
+		for i, option := range options {
+			if strings.EqualFold(answer, option) {
+				return i
+			}
+		}
+		fmt.Printf("  Pick 1-%d.\n", len(options))
+	}
+}
+
+func (w wizard) yes(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer := strings.ToLower(w.ask(question+" ["+hint+"]", ""))
+	if answer == "" {
+		return def
+	}
+	return answer == "y" || answer == "yes"
+}
+
+// petExists reports whether a pet has been saved yet.
+func petExists() bool {
+	path, err := store.Path()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// runHatch walks a new Keeper, or one whose pet drifted into the Void,
+// through hatching an egg: which egg, its name, a theme, the hook and
+// prompt, and the first feed. --defaults answers every question for them.
+func runHatch(args []string) error {
+	fs := newFlagSet("hatch")
+	defaults := fs.Bool("defaults", false, "don't ask; take the defaults and any answers given as flags")
+	eggName := fs.String("egg", "", "which egg: "+strings.Join(eggNames(), ", "))
+	name := fs.String("name", "", "name the new pet")
+	theme := fs.String("theme", "", "color theme")
+	hook := fs.Bool("install-hook", false, "with --defaults, also install the post-commit hook in this repository")
+	prompt := fs.Bool("install-prompt", false, "with --defaults, also add the pet to your shell prompt")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	chosen := eggs[0]
+	if *eggName != "" {
+		var ok bool
+		if chosen, ok = findEgg(*eggName); !ok {
+			return usageErrorf("unknown egg %q; choose %s", *eggName, strings.Join(eggNames(), ", "))
+		}
+	}
+	if *name != "" {
+		if err := validateName(*name); err != nil {
+			return err
+		}
+	}
+	themes := configKeys["theme"].values(cfg)
+	if *theme != "" && !containsString(themes, *theme) {
+		return usageErrorf("unknown theme %q; choose %s", *theme, strings.Join(themes, ", "))
+	}
+	old, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if petExists() && old.Departed == "" {
+		return fmt.Errorf("%s has already hatched; eggs only hatch for a new Keeper or after a pet drifts into the Void", old.DisplayName())
+	}
+	_, gitErr := gh.GitOutput(context.Background(), "rev-parse", "--git-dir")
+	inRepo := gitErr == nil
+
+	if old.Departed != "" {
+		fmt.Printf("\n🌌 Where %s rested, a new egg has appeared.\n\n", old.DisplayName())
+	} else {
+		fmt.Printf("\n%s🥚 Welcome to GitPet!%s A pet that grows with your GitHub activity is about to hatch.\n\n", colorBold, colorReset)
+	}
+
+	if !*defaults {
+		w := wizard{in: bufio.NewReader(os.Stdin)}
+		if *eggName == "" {
+			var options []string
+			for i, e := range eggs {
+				fmt.Printf("  %d. %s %-9s %s%s%s\n", i+1, orDefault(e.Emoji, "🥚"), e.Name, colorDim, e.Blurb, colorReset)
+				options = append(options, e.Name)
+			}
+			chosen = eggs[w.choose("Which egg?", options, 0)]
+			fmt.Println()
+		}
+		for *name == "" {
+			answer := w.ask("What will you call it?", store.DefaultPetName)
+			if err := validateName(answer); err != nil {
+				fmt.Println("  " + err.Error())
+				continue
+			}
+			*name = answer
+		}
+		if *theme == "" {
+			fmt.Println()
+			for i, t := range themes {
+				fmt.Printf("  %d. %s\n", i+1, t)
+			}
+			*theme = themes[w.choose("Which theme?", themes, max(0, indexOf(themes, cfg.Theme)))]
+		}
+		fmt.Println()
+		*hook = inRepo && w.yes("Install the post-commit hook here, so every commit cheers the pet?", true)
+		*prompt = w.yes("Add the pet to your shell prompt?", false)
+	}
+	if *name == store.DefaultPetName {
+		*name = ""
+	}
+
+	inherited := old.Logic * inheritedLogicPercent / 100
+	if old.Departed == "" {
+		inherited = 0
+	}
+	pet := store.PetState{
+		Mood:           5,
+		Evolution:      "Lonely",
+		Logic:          inherited,
+		AccountCreated: old.AccountCreated,
+		Name:           *name,
+		Emoji:          chosen.Emoji,
+		Hatched:        time.Now().Format(store.DayLayout),
+	}
+	if old.Departed != "" {
+		pet.Generation = old.Generation + 1
+	}
+	if err := store.Save(pet); err != nil {
+		return err
+	}
+	if *theme != "" && *theme != cfg.Theme {
+		cfg.Theme = *theme
+		if err := saveConfig(cfg); err != nil {
+			return err
+		}
+	}
+	crackEgg(chosen)
+	fmt.Printf("%s%s %s hatched!%s\n", colorBold, pet.Signature(), pet.DisplayName(), colorReset)
+	if inherited > 0 {
+		fmt.Printf("   It inherited %s from %s.\n", i18n.Plural(inherited, "logic shard"), old.DisplayName())
+	}
+	line := "I hatched today. Hello, Keeper!"
+	if old.Departed != "" {
+		line = fmt.Sprintf("I hatched today, carrying %s from %s before me.", i18n.Plural(inherited, "logic shard"), old.DisplayName())
+	}
+	if err := store.AddJournalEntry("hatch", line); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+
+	if *hook {
+		if !inRepo {
+			fmt.Fprintln(os.Stderr, "GitPet: not in a git repository; skipping the hook")
+		} else if err := runInstallHook(nil); err != nil {
+			fmt.Fprintln(os.Stderr, "GitPet: could not install the hook:", err)
+		}
+	}
+	if *prompt {
+		if err := runInstallPrompt(); err != nil {
+			fmt.Fprintln(os.Stderr, "GitPet: could not install the prompt:", err)
+		}
+	}
+
+	fmt.Println()
+	stop := startSpinner("Gathering your first meal from GitHub…")
+	result, err := feedPet(context.Background(), cfg)
+	stop()
+	if err != nil {
+		fmt.Printf("%sCouldn't feed it yet (%v). Run gh pet feed when you're online.%s\n", colorDim, err, colorReset)
+	} else {
+		s := result.Summary
+		fmt.Printf("🍽  First meal: %s, %s, %s. It's a %s.\n", i18n.Plural(s.Commits, "commit"), i18n.Plural(s.MergedPRs, "merged PR"), i18n.Plural(s.Reviews, "review"), result.State.Evolution)
+	}
+	fmt.Printf("\n%sNext: gh pet status to see it, gh pet help for everything else.%s\n", colorDim, colorReset)
+	return nil
+}
+
+// crackEgg plays a short hatching animation on a terminal.
+func crackEgg(e egg) {
+	if !stdoutIsTerminal() {
+		return
+	}
+	for _, frame := range []string{"🥚", "🥚 *wobble*", "🥚 *crack*", "🐣 *CRACK*"} {
+		fmt.Printf("\r%s %s\033[K", orDefault(e.Emoji, ""), frame)
+		time.Sleep(400 * time.Millisecond)
+	}
+	fmt.Print("\r\033[K")
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
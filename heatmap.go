@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const calendarQuery = `query($login: String!) {
+  user(login: $login) {
+    contributionsCollection {
+      contributionCalendar {
+        totalContributions
+        weeks { contributionDays { date contributionCount contributionLevel } }
+      }
+    }
+  }
+}`
+
+// calendarDay is one square of GitHub's contribution calendar.
+type calendarDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"contributionCount"`
+	Level string `json:"contributionLevel"`
+}
+
+type contributionCalendar struct {
+	Total int `json:"totalContributions"`
+	Weeks []struct {
+		Days []calendarDay `json:"contributionDays"`
+	} `json:"weeks"`
+}
+
+// heatmapLevels shades the calendar's quartiles, from none to the busiest.
+var heatmapLevels = map[string]string{
+	"NONE":            "·",
+	"FIRST_QUARTILE":  "░",
+	"SECOND_QUARTILE": "▒",
+	"THIRD_QUARTILE":  "▓",
+	"FOURTH_QUARTILE": "█",
+}
+
+// heatmapGutter is the width of the weekday labels left of the grid.
+const heatmapGutter = 4
+
+func runHeatmap(args []string) error {
+	fs := newFlagSet("heatmap")
+	user := fs.String("user", "", "whose calendar to draw (default: you)")
+	weeks := fs.Int("weeks", 52, "how many weeks back to show, up to 53")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *weeks < 1 || *weeks > 53 {
+		return fmt.Errorf("--weeks must be between 1 and 53")
+	}
+	ctx := context.Background()
+	login := *user
+	if login == "" {
+		var err error
+		if login, err = ghLogin(ctx); err != nil {
+			return err
+		}
+	}
+	stop := startSpinner("Fetching your contribution calendar…")
+	calendar, err := fetchCalendar(ctx, login)
+	stop()
+	if err != nil {
+		return err
+	}
+	state, _ := store.Load()
+	fmt.Print(renderHeatmap(calendar, state, loadTheme(), *weeks, terminalWidth()))
+	return nil
+}
+
+func fetchCalendar(ctx context.Context, login string) (contributionCalendar, error) {
+	var data struct {
+		User *struct {
+			ContributionsCollection struct {
+				ContributionCalendar contributionCalendar `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+	if err := gh.GraphQL(ctx, calendarQuery, map[string]any{"login": login}, &data); err != nil {
+		return contributionCalendar{}, err
+	}
+	if data.User == nil {
+		return contributionCalendar{}, fmt.Errorf("no GitHub user %q", login)
+	}
+	return data.User.ContributionsCollection.ContributionCalendar, nil
+}
+
+// renderHeatmap draws the calendar as a grid, one column per week and one
+// row per weekday, with the pet perched above the current week. Squares are
+// two cells wide when the terminal has room, and only the latest weeks that
+// fit are shown.
+func renderHeatmap(calendar contributionCalendar, state store.PetState, theme Theme, weeks, width int) string {
+	columns := calendar.Weeks
+	if len(columns) > weeks {
+		columns = columns[len(columns)-weeks:]
+	}
+	cell := 2
+	if heatmapGutter+cell*len(columns) > width {
+		cell = 1
+		if fit := width - heatmapGutter - 2; fit > 0 && len(columns) > fit {
+			columns = columns[len(columns)-fit:]
+		}
+	}
+	color := theme.accent(state.Evolution)
+
+	var grid [7]strings.Builder
+	var months strings.Builder
+	lastMonth := time.Month(0)
+	total, best := 0, calendarDay{}
+	for w, week := range columns {
+		// A month is labeled above the first week that starts in it, when
+		// there's room after the previous label.
+		if len(week.Days) > 0 {
+			if t, err := time.Parse(store.DayLayout, week.Days[0].Date); err == nil && t.Month() != lastMonth {
+				lastMonth = t.Month()
+				if pad := w*cell - displayWidth(months.String()); w == 0 || pad > 0 {
+					months.WriteString(strings.Repeat(" ", max(0, pad)) + t.Format("Jan"))
+				}
+			}
+		}
+		var column [7]string
+		for i := range column {
+			column[i] = strings.Repeat(" ", cell)
+		}
+		for _, d := range week.Days {
+			t, err := time.Parse(store.DayLayout, d.Date)
+			if err != nil {
+				continue
+			}
+			glyph := heatmapLevels[d.Level]
+			if glyph == "" {
+				glyph = heatmapLevels["NONE"]
+			}
+			paint := color
+			if d.Count == 0 {
+				paint = theme.Dim
+			}
+			column[t.Weekday()] = paint + strings.Repeat(glyph, cell) + theme.Reset
+			total += d.Count
+			if d.Count > best.Count {
+				best = d
+			}
+		}
+		for i, square := range column {
+			grid[i].WriteString(square)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	// The pet sits on the current week, the rightmost column.
+	perch := max(0, len(columns)*cell-displayWidth(state.Signature()))
+	sb.WriteString(strings.Repeat(" ", heatmapGutter+perch) + state.Signature() + "\n")
+	sb.WriteString(strings.Repeat(" ", heatmapGutter) + theme.Dim + months.String() + theme.Reset + "\n")
+	labels := map[time.Weekday]string{time.Monday: "Mon", time.Wednesday: "Wed", time.Friday: "Fri"}
+	for i := range grid {
+		sb.WriteString(fmt.Sprintf("%s%-*s%s%s\n", theme.Dim, heatmapGutter, labels[time.Weekday(i)], theme.Reset, grid[i].String()))
+	}
+	sb.WriteString("\n")
+	legend := theme.Dim + "Less " + heatmapLevels["NONE"] + theme.Reset + " " + color + "░▒▓█" + theme.Reset + theme.Dim + " More" + theme.Reset
+	sb.WriteString(fmt.Sprintf("%s%s in %s  %s\n", strings.Repeat(" ", heatmapGutter), i18n.Plural(total, "contribution"), i18n.Plural(len(columns), "week"), legend))
+	if best.Count > 0 {
+		t, _ := time.Parse(store.DayLayout, best.Date)
+		sb.WriteString(fmt.Sprintf("%s%sBusiest day: %s, with %d%s\n", strings.Repeat(" ", heatmapGutter), theme.Dim, t.Format("Mon Jan 2"), best.Count, theme.Reset))
+	}
+	return sb.String()
+}
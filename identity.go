@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const maxNameLength = 16
+
+func runName(args []string) error {
+	fs := newFlagSet("name")
+	pronouns := fs.String("pronouns", "", "pronouns, e.g. she/her, he/him, they/them")
+	emoji := fs.String("emoji", "", "signature emoji shown in status and prompt")
+	reset := fs.Bool("reset", false, "go back to the default name, pronouns, and emoji")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	// Allow the name before or after the flags.
+	name := ""
+	if fs.NArg() > 0 {
+		name = fs.Arg(0)
+		if err := parseFlags(fs, fs.Args()[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() > 0 {
+			return usageErrorf("usage: gh pet name [name] [--pronouns p] [--emoji e]")
+		}
+	}
+
+	state, _ := store.Load()
+	if *reset {
+		state.Name, state.Pronouns, state.Emoji = "", "", ""
+	}
+	if name != "" {
+		if err := validateName(name); err != nil {
+			return err
+		}
+		state.Name = name
+	}
+	if *pronouns != "" {
+		if err := validatePronouns(*pronouns); err != nil {
+			return err
+		}
+		state.Pronouns = *pronouns
+	}
+	if *emoji != "" {
+		if err := validateEmoji(*emoji); err != nil {
+			return err
+		}
+		state.Emoji = *emoji
+	}
+
+	if !*reset && name == "" && *pronouns == "" && *emoji == "" {
+		fmt.Printf("%s %s\n", state.Signature(), state.Introduction())
+		return nil
+	}
+	if err := store.Save(state); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Say hello to %s %s%s\n", colorGreen, state.Signature(), state.Introduction(), colorReset)
+	return nil
+}
+
+func validateName(name string) error {
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxNameLength)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("name must not contain control characters")
+		}
+	}
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("name must not start or end with spaces")
+	}
+	return nil
+}
+
+func validatePronouns(pronouns string) error {
+	parts := strings.Split(pronouns, "/")
+	if len(parts) < 2 || len(pronouns) > 24 {
+		return fmt.Errorf("pronouns should look like she/her, he/him, or they/them")
+	}
+	for _, part := range parts {
+		if part == "" || strings.IndexFunc(part, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+			return fmt.Errorf("pronouns should look like she/her, he/him, or they/them")
+		}
+	}
+	return nil
+}
+
+// validateEmoji accepts a single emoji, including ones built from several
+// code points such as flags or skin-tone variants.
+func validateEmoji(emoji string) error {
+	if utf8.RuneCountInString(emoji) > 8 {
+		return fmt.Errorf("emoji must be a single emoji")
+	}
+	for _, r := range emoji {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fmt.Errorf("emoji must be a single emoji")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// maxPatchBytes keeps the diff handed to a model to a readable excerpt.
+const maxPatchBytes = 4000
+
+// CommitInfo is one local commit: who made it, what it says, and what it
+// changed.
+type CommitInfo struct {
+	SHA     string
+	Short   string
+	Author  string
+	Subject string
+	Body    string
+	Change  StagedChange
+	// Patch is the start of the diff, at most maxPatchBytes.
+	Patch string
+}
+
+// LoadCommit reads rev, a SHA or any revision git understands, from the
+// repository in the working directory.
+func LoadCommit(ctx context.Context, rev string) (CommitInfo, error) {
+	if strings.HasPrefix(rev, "-") {
+		return CommitInfo{}, fmt.Errorf("%q is not a commit", rev)
+	}
+	if _, err := gh.GitOutput(ctx, "rev-parse", "--git-dir"); err != nil {
+		return CommitInfo{}, fmt.Errorf("not in a git repository")
+	}
+	out, err := gh.GitOutput(ctx, "log", "-1", "--format=%H%x00%h%x00%an%x00%s%x00%b", rev+"^{commit}", "--")
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("%q is not a commit in this repository", rev)
+	}
+	fields := strings.SplitN(strings.TrimRight(string(out), "\n"), "\x00", 5)
+	if len(fields) < 5 {
+		return CommitInfo{}, fmt.Errorf("could not read commit %q", rev)
+	}
+	c := CommitInfo{SHA: fields[0], Short: fields[1], Author: fields[2], Subject: fields[3], Body: strings.TrimSpace(fields[4])}
+	c.Change, _ = ReadChange(ctx, "show", "--format=", c.SHA)
+	if patch, err := gh.GitOutput(ctx, "show", "--format=", "--patch", c.SHA); err == nil {
+		c.Patch = string(patch)
+		if len(c.Patch) > maxPatchBytes {
+			c.Patch = c.Patch[:maxPatchBytes] + "\n…"
+		}
+	}
+	return c, nil
+}
+
+// Facts states what the commit did, for a model to put into words.
+func (c CommitInfo) Facts() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Commit %s by %s, titled %q.", c.Short, c.Author, c.Subject)
+	if c.Body != "" {
+		fmt.Fprintf(&sb, " Its message goes on: %q.", c.Body)
+	}
+	if len(c.Change.Files) > 0 {
+		fmt.Fprintf(&sb, " It changes %s (+%d/-%d lines): %s.", i18n.Plural(len(c.Change.Files), "file"), c.Change.Insert, c.Change.Delete, strings.Join(c.Change.Files, ", "))
+	}
+	if c.Patch != "" {
+		sb.WriteString(" The diff begins:\n" + c.Patch)
+	}
+	return sb.String()
+}
+
+// CommitOpeners start a narration in each evolution's voice.
+var CommitOpeners = map[string]string{
+	"Pioneer":   "I tagged along on this expedition!",
+	"Guardian":  "I stood watch while this one went in.",
+	"Bard":      "Gather round, this commit has a story.",
+	"Void":      "Let me put it simply.",
+	"Sentinel":  "I looked this one over carefully.",
+	"Curator":   "I've filed this one away for you.",
+	"Companion": "I watched you make this one!",
+}
+
+// changeKinds says in plain words what each inferred change type means.
+var changeKinds = map[string]string{
+	"feat":     "adds something new",
+	"fix":      "adjusts code that was already there",
+	"refactor": "mostly takes code away or reshapes it",
+	"test":     "only touches tests",
+	"docs":     "only touches documentation",
+	"ci":       "only changes the CI workflows",
+	"build":    "changes how the project is built",
+}
+
+// NarrateCommit explains a commit in plain language, as the pet would tell
+// it. It needs no model; sampling or Copilot can replace it with prose.
+func NarrateCommit(state store.PetState, c CommitInfo) string {
+	opener, ok := CommitOpeners[state.Evolution]
+	if !ok {
+		opener = CommitOpeners["Companion"]
+	}
+	lines := []string{opener}
+	lines = append(lines, fmt.Sprintf("In %s, “%s”, %s", c.Short, c.Subject, c.Author))
+	ch := c.Change
+	if len(ch.Files) == 0 {
+		lines[1] += " didn't change any files. Sometimes a commit is just a marker."
+		return strings.Join(lines, " ")
+	}
+	where := ""
+	if ch.Scope != "" {
+		where = " in " + ch.Scope
+	}
+	lines[1] += fmt.Sprintf(" changed %s%s, adding %s and removing %d.", i18n.Plural(len(ch.Files), "file"), where, i18n.Plural(ch.Insert, "line"), ch.Delete)
+	if kind, ok := changeKinds[ch.Type]; ok {
+		lines = append(lines, fmt.Sprintf("It %s: %s.", kind, ch.Subject))
+	}
+	if len(ch.Added) > 0 {
+		added := strings.Join(ch.Added, ", ")
+		if len(ch.Added) > 3 {
+			added = fmt.Sprintf("%s, %s and %d more", ch.Added[0], ch.Added[1], len(ch.Added)-2)
+		}
+		lines = append(lines, "New here: "+added+".")
+	}
+	if c.Body != "" {
+		first, _, _ := strings.Cut(c.Body, "\n")
+		lines = append(lines, fmt.Sprintf("The message explains: “%s”", strings.TrimSpace(first)))
+	}
+	switch size := ch.Insert + ch.Delete; {
+	case size >= LargeCommitLines:
+		lines = append(lines, "That was a big one. I'm tired just watching.")
+	case size < 10:
+		lines = append(lines, "Small and tidy. I like that.")
+	default:
+		lines = append(lines, "A solid step forward.")
+	}
+	return strings.Join(lines, " ")
+}
@@ -0,0 +1,97 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"golang.org/x/sync/errgroup"
+)
+
+// Reading stats costs an API call per commit, so only the latest pushed
+// commits are read.
+const (
+	maxStatCommits = 30
+	// LargeCommitLines is how many added and removed lines make a commit
+	// large.
+	LargeCommitLines = 500
+)
+
+type commitStatsResponse struct {
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+}
+
+// CommitStats counts the lines the latest pushed commits added and
+// removed, and how many of them were large. Commits that fail to load count
+// for nothing.
+func CommitStats(ctx context.Context, events []pet.Event) (lines, large int) {
+	type commit struct{ repo, sha string }
+	cutoff := pet.SummaryCutoff(time.Now())
+	seen := map[string]bool{}
+	var commits []commit
+	for _, event := range events {
+		if event.Type != "PushEvent" || event.CreatedAt.Before(cutoff) {
+			continue
+		}
+		var p pet.PushPayload
+		if json.Unmarshal(event.Payload, &p) != nil {
+			continue
+		}
+		for _, c := range p.Commits {
+			if c.SHA == "" || seen[c.SHA] || len(commits) == maxStatCommits {
+				continue
+			}
+			seen[c.SHA] = true
+			commits = append(commits, commit{event.Repo.Name, c.SHA})
+		}
+	}
+
+	changed := make([]int, len(commits))
+	var g errgroup.Group
+	g.SetLimit(gh.Concurrency)
+	for i, c := range commits {
+		g.Go(func() error {
+			var resp commitStatsResponse
+			if err := gh.Get(ctx, fmt.Sprintf("repos/%s/commits/%s", c.repo, c.sha), &resp); err != nil {
+				logging.Logger.Debug("commit stats", "repo", c.repo, "sha", c.sha, "err", err)
+				return nil
+			}
+			changed[i] = resp.Stats.Additions + resp.Stats.Deletions
+			return nil
+		})
+	}
+	g.Wait()
+	for _, n := range changed {
+		lines += n
+		if n >= LargeCommitLines {
+			large++
+		}
+	}
+	return lines, large
+}
+
+var shortstatCounts = regexp.MustCompile(`(\d+) (insertion|deletion)`)
+
+// LastCommitLines counts the lines the commit just made added and removed,
+// from git's shortstat. The root commit works too, unlike diffing HEAD~1.
+func LastCommitLines(ctx context.Context) (int, bool) {
+	out, err := gh.GitOutput(ctx, "show", "--shortstat", "--format=", "HEAD")
+	if err != nil {
+		return 0, false
+	}
+	lines := 0
+	for _, m := range shortstatCounts.FindAllStringSubmatch(string(out), -1) {
+		n, _ := strconv.Atoi(m[1])
+		lines += n
+	}
+	return lines, true
+}
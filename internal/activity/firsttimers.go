@@ -0,0 +1,62 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+// isFirstTimer reports whether an author_association marks someone
+// contributing to the repository, or to GitHub, for the first time.
+func isFirstTimer(association string) bool {
+	return association == "FIRST_TIME_CONTRIBUTOR" || association == "FIRST_TIMER"
+}
+
+type firstTimerPayload struct {
+	PullRequest *firstTimerItem `json:"pull_request"`
+	Issue       *struct {
+		firstTimerItem
+		PullRequest json.RawMessage `json:"pull_request"`
+	} `json:"issue"`
+}
+
+type firstTimerItem struct {
+	Number            int    `json:"number"`
+	AuthorAssociation string `json:"author_association"`
+}
+
+// FirstTimerHelps counts the pull requests from first-time contributors to
+// login's repositories that login reviewed or commented on this week. Each
+// pull request counts once, however many times the Keeper answered it.
+func FirstTimerHelps(events []pet.Event, login string) int {
+	cutoff := pet.SummaryCutoff(time.Now())
+	helped := map[string]bool{}
+	for _, event := range events {
+		owner, _, _ := strings.Cut(event.Repo.Name, "/")
+		if event.CreatedAt.Before(cutoff) || !strings.EqualFold(owner, login) {
+			continue
+		}
+		var p firstTimerPayload
+		if json.Unmarshal(event.Payload, &p) != nil {
+			continue
+		}
+		var item *firstTimerItem
+		switch event.Type {
+		case "PullRequestReviewEvent", "PullRequestReviewCommentEvent":
+			item = p.PullRequest
+		case "IssueCommentEvent":
+			// Comments on issues themselves don't count, only on pull
+			// requests.
+			if p.Issue != nil && len(p.Issue.PullRequest) > 0 && string(p.Issue.PullRequest) != "null" {
+				item = &p.Issue.firstTimerItem
+			}
+		}
+		if item != nil && isFirstTimer(item.AuthorAssociation) {
+			helped[fmt.Sprintf("%s#%d", event.Repo.Name, item.Number)] = true
+		}
+	}
+	return len(helped)
+}
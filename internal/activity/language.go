@@ -0,0 +1,188 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"golang.org/x/sync/errgroup"
+)
+
+// languageByExt maps file extensions seen in local diffs to the language
+// names GitHub reports for repositories.
+var languageByExt = map[string]string{
+	".go":    "Go",
+	".rs":    "Rust",
+	".py":    "Python",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".rb":    "Ruby",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".swift": "Swift",
+	".c":     "C",
+	".h":     "C",
+	".cpp":   "C++",
+	".cc":    "C++",
+	".cs":    "C#",
+	".php":   "PHP",
+	".sh":    "Shell",
+	".md":    "Markdown",
+}
+
+// LanguageBreakdown weighs the languages of repositories pushed to in the
+// feed's window by commit count, then adds files touched in the local diff.
+func LanguageBreakdown(ctx context.Context, events []pet.Event) map[string]int {
+	cutoff := pet.SummaryCutoff(time.Now())
+	repoCommits := map[string]int{}
+	for _, event := range events {
+		if event.Type != "PushEvent" || event.CreatedAt.Before(cutoff) || event.Repo.Name == "" {
+			continue
+		}
+		commits := 1
+		var payload pet.PushPayload
+		if json.Unmarshal(event.Payload, &payload) == nil && len(payload.Commits) > 0 {
+			commits = len(payload.Commits)
+		}
+		repoCommits[event.Repo.Name] += commits
+	}
+
+	// Each repository is its own API call, so look them up side by side.
+	repos := make([]string, 0, len(repoCommits))
+	for repo := range repoCommits {
+		repos = append(repos, repo)
+	}
+	langs := make([]string, len(repos))
+	var g errgroup.Group
+	g.SetLimit(gh.Concurrency)
+	for i, repo := range repos {
+		g.Go(func() error {
+			langs[i] = repoLanguage(ctx, repo)
+			return nil
+		})
+	}
+	g.Wait()
+
+	languages := map[string]int{}
+	for i, lang := range langs {
+		if lang != "" {
+			languages[lang] += repoCommits[repos[i]]
+		}
+	}
+	for lang, files := range localDiffLanguages(ctx) {
+		languages[lang] += files
+	}
+	return languages
+}
+
+func repoLanguage(ctx context.Context, repo string) string {
+	var info struct {
+		Language string `json:"language"`
+	}
+	if gh.Get(ctx, "repos/"+repo, &info) != nil {
+		return ""
+	}
+	return info.Language
+}
+
+func localDiffLanguages(ctx context.Context) map[string]int {
+	languages := map[string]int{}
+	if !gh.InWorkTree(ctx) {
+		return languages
+	}
+	out, _ := gh.GitOutput(ctx, "diff", "--name-only", "HEAD")
+	for _, name := range strings.Split(string(out), "\n") {
+		if lang, ok := languageByExt[strings.ToLower(filepath.Ext(strings.TrimSpace(name)))]; ok {
+			languages[lang]++
+		}
+	}
+	return languages
+}
+
+// DominantLanguage returns the most active language, breaking ties by name
+// so the pet's accessory doesn't flicker between renders.
+func DominantLanguage(languages map[string]int) string {
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	best, bestCount := "", 0
+	for _, name := range names {
+		if languages[name] > bestCount {
+			best, bestCount = name, languages[name]
+		}
+	}
+	return best
+}
+
+func LanguageAccessory(lang string) string {
+	switch lang {
+	case "Go":
+		return "🐹 gopher hat"
+	case "Rust":
+		return "🦀 crab buddy"
+	case "Python":
+		return "🐍 snake scarf"
+	case "JavaScript", "TypeScript":
+		return "⚡ lightning pin"
+	case "Ruby":
+		return "💎 ruby brooch"
+	case "Java", "Kotlin":
+		return "☕ coffee mug"
+	case "Swift":
+		return "🐦 swift feather"
+	case "C", "C++":
+		return "⚙️  gear monocle"
+	case "Shell":
+		return "🐚 seashell"
+	case "Markdown":
+		return "🪶 quill"
+	default:
+		return ""
+	}
+}
+
+func LanguageProverb(lang string) string {
+	switch lang {
+	case "Go":
+		return "Clear is better than clever."
+	case "Rust":
+		return "The borrow checker is a friend in disguise."
+	case "Python":
+		return "Readability counts."
+	case "JavaScript", "TypeScript":
+		return "Types are promises kept."
+	case "Ruby":
+		return "Optimize for programmer happiness."
+	default:
+		return ""
+	}
+}
+
+func LanguageLine(languages map[string]int) string {
+	if len(languages) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if languages[names[i]] != languages[names[j]] {
+			return languages[names[i]] > languages[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > 3 {
+		names = names[:3]
+	}
+	return strings.Join(names, " · ")
+}
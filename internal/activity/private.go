@@ -0,0 +1,168 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+// privateScopes are what a classic token needs for private activity: repo
+// for private contributions, read:org for org repos, and notifications.
+const privateScopes = "repo,read:org,notifications"
+
+const privateQuery = `query($login: String!, $from: DateTime!) {
+  user(login: $login) {
+    contributionsCollection(from: $from) {
+      restrictedContributionsCount
+      commitContributionsByRepository(maxRepositories: 100) {
+        repository { nameWithOwner isPrivate }
+        contributions { totalCount }
+      }
+      pullRequestContributionsByRepository(maxRepositories: 100) {
+        repository { nameWithOwner isPrivate }
+        contributions(first: 100) { nodes { pullRequest { merged } } }
+      }
+      pullRequestReviewContributionsByRepository(maxRepositories: 100) {
+        repository { nameWithOwner isPrivate }
+        contributions { totalCount }
+      }
+      issueContributionsByRepository(maxRepositories: 100) {
+        repository { nameWithOwner isPrivate }
+        contributions { totalCount }
+      }
+    }
+  }
+}`
+
+type contributionRepo struct {
+	NameWithOwner string `json:"nameWithOwner"`
+	IsPrivate     bool   `json:"isPrivate"`
+}
+
+type repoContributions struct {
+	Repository    contributionRepo `json:"repository"`
+	Contributions struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"contributions"`
+}
+
+type notificationThread struct {
+	Reason     string `json:"reason"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		Private  bool   `json:"private"`
+	} `json:"repository"`
+}
+
+// PrivateActivity counts work in private repos that the events feed left
+// out: commits, merged pull requests, reviews, and issues from the
+// contributions API, and conversations joined from notifications. Repos in
+// seen were already counted from events and are skipped, as are repos
+// filter leaves out. Contributions GitHub won't itemize count as commits,
+// unless filter is limited to certain repos.
+func PrivateActivity(ctx context.Context, login string, since time.Time, seen map[string]bool, filter RepoFilter) (pet.ActivitySummary, error) {
+	var data struct {
+		User struct {
+			ContributionsCollection struct {
+				RestrictedContributionsCount         int                 `json:"restrictedContributionsCount"`
+				CommitContributionsByRepository      []repoContributions `json:"commitContributionsByRepository"`
+				PullRequestContributionsByRepository []struct {
+					Repository    contributionRepo `json:"repository"`
+					Contributions struct {
+						Nodes []struct {
+							PullRequest struct {
+								Merged bool `json:"merged"`
+							} `json:"pullRequest"`
+						} `json:"nodes"`
+					} `json:"contributions"`
+				} `json:"pullRequestContributionsByRepository"`
+				PullRequestReviewContributionsByRepository []repoContributions `json:"pullRequestReviewContributionsByRepository"`
+				IssueContributionsByRepository             []repoContributions `json:"issueContributionsByRepository"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+	vars := map[string]any{"login": login, "from": since.UTC().Format(time.RFC3339)}
+	if err := gh.GraphQL(ctx, privateQuery, vars, &data); err != nil {
+		return pet.ActivitySummary{}, err
+	}
+	unseen := func(repo contributionRepo) bool {
+		return repo.IsPrivate && !seen[strings.ToLower(repo.NameWithOwner)] && filter.keeps(repo.NameWithOwner)
+	}
+
+	c := data.User.ContributionsCollection
+	var summary pet.ActivitySummary
+	if len(filter.Only) == 0 {
+		summary.Commits = c.RestrictedContributionsCount
+	}
+	for _, r := range c.CommitContributionsByRepository {
+		if unseen(r.Repository) {
+			summary.Commits += r.Contributions.TotalCount
+		}
+	}
+	for _, r := range c.PullRequestContributionsByRepository {
+		if !unseen(r.Repository) {
+			continue
+		}
+		for _, node := range r.Contributions.Nodes {
+			if node.PullRequest.Merged {
+				summary.MergedPRs++
+			}
+		}
+	}
+	for _, r := range c.PullRequestReviewContributionsByRepository {
+		if unseen(r.Repository) {
+			summary.Reviews += r.Contributions.TotalCount
+		}
+	}
+	for _, r := range c.IssueContributionsByRepository {
+		if unseen(r.Repository) {
+			summary.IssuesOpened += r.Contributions.TotalCount
+		}
+	}
+
+	var threads []notificationThread
+	endpoint := "notifications?all=true&participating=true&per_page=100&since=" + since.UTC().Format(time.RFC3339)
+	if err := gh.Get(ctx, endpoint, &threads); err != nil {
+		return summary, err
+	}
+	for _, t := range threads {
+		if t.Reason == "comment" && t.Repository.Private && !seen[strings.ToLower(t.Repository.FullName)] && filter.keeps(t.Repository.FullName) {
+			summary.IssueComments++
+			summary.DocComments++
+		}
+	}
+	return summary, nil
+}
+
+// EventRepos are the repos events already speak for, lowercased.
+func EventRepos(events []pet.Event) map[string]bool {
+	repos := map[string]bool{}
+	for _, event := range events {
+		repos[strings.ToLower(event.Repo.Name)] = true
+	}
+	return repos
+}
+
+// PrivateError explains a failed private read. When GitHub refused the token,
+// it says which scopes to add: the one GitHub named, or else all of them.
+func PrivateError(err error) error {
+	// GitHub hides what a token can't see behind a 404, too.
+	var access *gh.AccessError
+	if !errors.As(err, &access) && !gh.IsNotFound(err) {
+		return fmt.Errorf("could not read private activity: %w", err)
+	}
+	if access != nil && (access.BadToken() || access.SSO != "" || len(access.Missing) > 0) {
+		return fmt.Errorf("could not read private activity: %w", gh.ExplainAccess(err, "private activity"))
+	}
+	return fmt.Errorf(`could not read private activity: %w
+Private activity needs a token that can see your private repos and notifications.
+With gh, add the scopes:    gh auth refresh --scopes %s
+With GH_TOKEN, use a classic token with the same scopes; fine-grained tokens can't read notifications.
+Organizations may also need to approve the token under SSO.
+To count public activity only:    gh pet config set private-activity off`, err, privateScopes)
+}
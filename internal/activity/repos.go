@@ -0,0 +1,85 @@
+// Package activity gathers what the Keeper did beyond the events feed:
+// private work, review depth, tests, languages, and the commits behind
+// them.
+package activity
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+// RepoFilter narrows the activity that feeds the pet to some repositories,
+// or away from others. Patterns are owner/repo and may use *, as in
+// "my-company/*"; case is ignored, as on GitHub.
+type RepoFilter struct {
+	// Only, when set, keeps just the repos matching one of its patterns.
+	Only []string
+	// Ignore drops the repos matching one of its patterns.
+	Ignore []string
+	// PublicOnly drops activity in private repos.
+	PublicOnly bool
+}
+
+func (f RepoFilter) active() bool {
+	return len(f.Only)+len(f.Ignore) > 0 || f.PublicOnly
+}
+
+// keeps reports whether activity in the repo named owner/repo counts.
+func (f RepoFilter) keeps(name string) bool {
+	if len(f.Only) > 0 && !matchesAnyRepo(f.Only, name) {
+		return false
+	}
+	return !matchesAnyRepo(f.Ignore, name)
+}
+
+func (f RepoFilter) Events(events []pet.Event) []pet.Event {
+	if !f.active() {
+		return events
+	}
+	var kept []pet.Event
+	for _, event := range events {
+		if f.keeps(event.Repo.Name) && (event.Public || !f.PublicOnly) {
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
+
+func matchesAnyRepo(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRepoPatterns checks each pattern names an owner and a repo.
+func ValidateRepoPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		owner, repo, ok := strings.Cut(pattern, "/")
+		if _, err := path.Match(pattern, ""); err != nil || !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return fmt.Errorf("%q is not an owner/repo pattern", pattern)
+		}
+	}
+	return nil
+}
+
+// RepoList collects a repeatable --repo flag; commas also separate repos.
+type RepoList []string
+
+func (l *RepoList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *RepoList) Set(value string) error {
+	for _, repo := range strings.Split(value, ",") {
+		if repo = strings.TrimSpace(repo); repo != "" {
+			*l = append(*l, repo)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"golang.org/x/sync/errgroup"
+)
+
+// Looking deeper costs API calls, so only the latest reviews are read, and
+// a review can't earn more than a handful of comments' worth.
+const (
+	maxDepthReviews      = 20
+	maxCommentsPerReview = 5
+)
+
+type reviewPayload struct {
+	Review struct {
+		ID          int64     `json:"id"`
+		State       string    `json:"state"`
+		Body        string    `json:"body"`
+		SubmittedAt time.Time `json:"submitted_at"`
+	} `json:"review"`
+	PullRequest struct {
+		Number int `json:"number"`
+	} `json:"pull_request"`
+}
+
+type timelineEvent struct {
+	Event             string    `json:"event"`
+	CreatedAt         time.Time `json:"created_at"`
+	RequestedReviewer struct {
+		Login string `json:"login"`
+	} `json:"requested_reviewer"`
+}
+
+// ReviewDepth reads the Keeper's latest reviews from the pull request review
+// APIs: the comments each left, whether it approved or asked for changes,
+// and, where a review was requested, how long the request waited. Reviews
+// that fail to load count for their state only.
+func ReviewDepth(ctx context.Context, login string, events []pet.Event, quick time.Duration) pet.ReviewDepth {
+	type review struct {
+		repo    string
+		payload reviewPayload
+	}
+	cutoff := pet.SummaryCutoff(time.Now())
+	var reviews []review
+	for _, event := range events {
+		if event.Type != "PullRequestReviewEvent" || event.CreatedAt.Before(cutoff) {
+			continue
+		}
+		var p reviewPayload
+		if json.Unmarshal(event.Payload, &p) != nil || p.Review.ID == 0 {
+			continue
+		}
+		if p.Review.SubmittedAt.IsZero() {
+			p.Review.SubmittedAt = event.CreatedAt
+		}
+		reviews = append(reviews, review{event.Repo.Name, p})
+		if len(reviews) == maxDepthReviews {
+			break
+		}
+	}
+
+	comments := make([]int, len(reviews))
+	// Only the first review of a pull request answers its request; events
+	// come newest first, so the last one seen is the first submitted.
+	first := map[string]review{}
+	for _, r := range reviews {
+		first[fmt.Sprintf("%s#%d", r.repo, r.payload.PullRequest.Number)] = r
+	}
+	var (
+		mu    sync.Mutex
+		waits []time.Duration
+	)
+	var g errgroup.Group
+	g.SetLimit(gh.Concurrency)
+	for i, r := range reviews {
+		g.Go(func() error {
+			var list []json.RawMessage
+			endpoint := fmt.Sprintf("repos/%s/pulls/%d/reviews/%d/comments?per_page=100", r.repo, r.payload.PullRequest.Number, r.payload.Review.ID)
+			if err := gh.Get(ctx, endpoint, &list); err != nil {
+				logging.Logger.Debug("review comments", "repo", r.repo, "err", err)
+			}
+			comments[i] = min(len(list), maxCommentsPerReview)
+			return nil
+		})
+	}
+	for _, r := range first {
+		g.Go(func() error {
+			if wait, ok := requestWait(ctx, login, r.repo, r.payload.PullRequest.Number, r.payload.Review.SubmittedAt); ok {
+				mu.Lock()
+				waits = append(waits, wait)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	var depth pet.ReviewDepth
+	for i, r := range reviews {
+		depth.Comments += comments[i]
+		switch strings.ToLower(r.payload.Review.State) {
+		case "approved":
+			depth.Approvals++
+			if comments[i] == 0 && strings.TrimSpace(r.payload.Review.Body) == "" {
+				depth.SilentApprovals++
+			}
+		case "changes_requested":
+			depth.ChangeRequests++
+		}
+	}
+	sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+	for _, wait := range waits {
+		if wait <= quick {
+			depth.Quick++
+		}
+	}
+	depth.Requested = len(waits)
+	if len(waits) > 0 {
+		depth.LatencyMinutes = int(waits[len(waits)/2].Minutes())
+	}
+	return depth
+}
+
+// requestWait is how long the latest request for login's review of a pull
+// request waited before the review at submitted. It reports false when the
+// review wasn't requested.
+func requestWait(ctx context.Context, login, repo string, number int, submitted time.Time) (time.Duration, bool) {
+	var timeline []timelineEvent
+	if err := gh.Get(ctx, fmt.Sprintf("repos/%s/issues/%d/timeline?per_page=100", repo, number), &timeline); err != nil {
+		logging.Logger.Debug("review timeline", "repo", repo, "err", err)
+		return 0, false
+	}
+	var requested time.Time
+	for _, e := range timeline {
+		if e.Event == "review_requested" && strings.EqualFold(e.RequestedReviewer.Login, login) && e.CreatedAt.Before(submitted) {
+			requested = e.CreatedAt
+		}
+	}
+	if requested.IsZero() {
+		return 0, false
+	}
+	return submitted.Sub(requested), true
+}
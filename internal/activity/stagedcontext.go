@@ -0,0 +1,176 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+)
+
+// StagedChange summarizes what is about to be committed, so suggestions can
+// talk about real files instead of generic adventures.
+type StagedChange struct {
+	Files   []string
+	Added   []string
+	Insert  int
+	Delete  int
+	Type    string
+	Scope   string
+	Subject string
+}
+
+// ReadStagedChange inspects the index of the current repository. ok is false
+// outside a repository or when nothing is staged.
+func ReadStagedChange(ctx context.Context) (StagedChange, bool) {
+	return ReadChange(ctx, "diff", "--cached")
+}
+
+// ReadChange summarizes the diff a git command prints, such as "diff
+// --cached" or "show --format= <sha>". ok is false when it touches no files.
+func ReadChange(ctx context.Context, diff ...string) (StagedChange, bool) {
+	numstat, err := gh.GitOutput(ctx, append(diff, "--numstat")...)
+	if err != nil {
+		return StagedChange{}, false
+	}
+	status, _ := gh.GitOutput(ctx, append(diff, "--name-status")...)
+	var c StagedChange
+	for _, row := range strings.Split(strings.TrimSpace(string(numstat)), "\n") {
+		fields := strings.Fields(row)
+		if len(fields) < 3 {
+			continue
+		}
+		insert, _ := strconv.Atoi(fields[0])
+		del, _ := strconv.Atoi(fields[1])
+		c.Insert += insert
+		c.Delete += del
+		c.Files = append(c.Files, fields[len(fields)-1])
+	}
+	for _, row := range strings.Split(strings.TrimSpace(string(status)), "\n") {
+		if fields := strings.Fields(row); len(fields) >= 2 && fields[0] == "A" {
+			c.Added = append(c.Added, fields[1])
+		}
+	}
+	if len(c.Files) == 0 {
+		return StagedChange{}, false
+	}
+	c.Type = InferChangeType(c)
+	c.Scope = inferScope(c.Files)
+	c.Subject = DescribeFiles(c.Files)
+	return c, true
+}
+
+func InferChangeType(c StagedChange) string {
+	all := func(match func(string) bool) bool {
+		for _, f := range c.Files {
+			if !match(f) {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case all(IsTestPath):
+		return "test"
+	case all(IsDocPath):
+		return "docs"
+	case all(func(f string) bool { return strings.HasPrefix(f, ".github/") }):
+		return "ci"
+	case all(IsBuildPath):
+		return "build"
+	case len(c.Added) > 0:
+		return "feat"
+	case c.Delete > c.Insert*2:
+		return "refactor"
+	default:
+		return "fix"
+	}
+}
+
+func IsDocPath(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".rst" || ext == ".txt" || strings.HasPrefix(name, "docs/")
+}
+
+func IsBuildPath(name string) bool {
+	switch path.Base(name) {
+	case "go.mod", "go.sum", "package.json", "package-lock.json", "Makefile", "Dockerfile", "Cargo.toml", "Cargo.lock":
+		return true
+	}
+	return false
+}
+
+// inferScope is the deepest directory every file shares, or the file's own
+// name for a single-file change.
+func inferScope(files []string) string {
+	if len(files) == 1 {
+		base := path.Base(files[0])
+		return strings.TrimSuffix(base, path.Ext(base))
+	}
+	common := strings.Split(path.Dir(files[0]), "/")
+	for _, f := range files[1:] {
+		parts := strings.Split(path.Dir(f), "/")
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(common) == 0 || common[0] == "." {
+		return ""
+	}
+	return common[len(common)-1]
+}
+
+func DescribeFiles(files []string) string {
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, path.Base(f))
+	}
+	switch len(names) {
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + " and " + names[1]
+	default:
+		return fmt.Sprintf("%s and %d more files", names[0], len(names)-1)
+	}
+}
+
+// personaVerbs gives each evolution its own way of describing a change.
+var personaVerbs = map[string][]string{
+	"Pioneer":   {"explore", "chart a path through", "break ground in"},
+	"Guardian":  {"harden", "guard", "shore up"},
+	"Bard":      {"clarify", "tell the story of", "polish"},
+	"Void":      {"simplify", "trim", "distill"},
+	"Sentinel":  {"cover", "verify", "watch over"},
+	"Curator":   {"tidy", "organize", "tend"},
+	"Companion": {"update", "improve", "touch up"},
+}
+
+// ContextSuggestions writes messages about the staged change in the
+// personality's voice. commitType overrides the inferred type when set.
+func ContextSuggestions(personality string, c StagedChange, commitType string, count int) []string {
+	kind := c.Type
+	if commitType != "" {
+		kind = commitType
+	}
+	prefix := kind
+	if c.Scope != "" {
+		prefix = fmt.Sprintf("%s(%s)", kind, c.Scope)
+	}
+	verbs, ok := personaVerbs[personality]
+	if !ok {
+		verbs = personaVerbs["Companion"]
+	}
+	var out []string
+	for _, verb := range verbs {
+		out = append(out, fmt.Sprintf("%s: %s %s", prefix, verb, c.Subject))
+	}
+	if len(out) > count {
+		out = out[:count]
+	}
+	return out
+}
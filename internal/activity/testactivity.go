@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+)
+
+// IsTestPath reports whether a changed file looks like a test across the
+// common Go, JS/TS, Python, Ruby, and Rust layouts.
+func IsTestPath(name string) bool {
+	name = filepath.ToSlash(strings.TrimSpace(name))
+	if name == "" {
+		return false
+	}
+	base := strings.ToLower(filepath.Base(name))
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.Contains(base, ".test."),
+		strings.Contains(base, ".spec."),
+		strings.HasSuffix(base, "_spec.rb"),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+		strings.HasSuffix(base, "_test.py"):
+		return true
+	}
+	for _, dir := range strings.Split(strings.ToLower(filepath.Dir(name)), "/") {
+		if dir == "test" || dir == "tests" || dir == "__tests__" || dir == "spec" {
+			return true
+		}
+	}
+	return false
+}
+
+// LastCommitTouchesTests checks the files changed by HEAD in the local repo.
+func LastCommitTouchesTests(ctx context.Context) bool {
+	out, err := gh.GitOutput(ctx, "diff-tree", "--no-commit-id", "--name-only", "-r", "HEAD")
+	if err != nil {
+		return false
+	}
+	for _, name := range strings.Split(string(out), "\n") {
+		if IsTestPath(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// LocalTestFragments counts uncommitted test files in the working tree.
+func LocalTestFragments(ctx context.Context) int {
+	if !gh.InWorkTree(ctx) {
+		return 0
+	}
+	out, _ := gh.GitOutput(ctx, "diff", "--name-only", "HEAD")
+	count := 0
+	for _, name := range strings.Split(string(out), "\n") {
+		if IsTestPath(name) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,28 @@
+package activity
+
+import "testing"
+
+func TestIsTestPath(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"scoring_test.go", true},
+		{"src/app.test.ts", true},
+		{"src/app.spec.js", true},
+		{"spec/models/user_spec.rb", true},
+		{"tests/test_api.py", true},
+		{"pkg/api_test.py", true},
+		{"web/__tests__/App.jsx", true},
+		{"test/fixtures/data.json", true},
+		{"latest.go", false},
+		{"internal/attestation/sign.go", false},
+		{"docs/testing-guide.md", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsTestPath(tt.name); got != tt.want {
+			t.Errorf("IsTestPath(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
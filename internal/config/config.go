@@ -0,0 +1,156 @@
+// Package config holds the preferences both the gh extension and the MCP
+// server read from gh-pet-config.json.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// FileName is the config file in the config directory.
+const FileName = "gh-pet-config.json"
+
+// Settings are the preferences that shape the pet wherever it's fed. The
+// CLI's Config embeds them next to its own display and hook settings. They
+// live next to the pet state but GitPet only rewrites them from commands
+// that change a preference, so hand edits are safe.
+type Settings struct {
+	Scoring  pet.ScoringConfig  `json:"scoring"`
+	Wellness pet.WellnessConfig `json:"wellness"`
+	// PrivateActivity adds work in private repos from the contributions API
+	// and notifications, which needs extra token scopes; see
+	// internal/activity/private.go.
+	PrivateActivity bool `json:"private_activity,omitempty"`
+	// PublicOnly keeps the pet to public activity, so GitPet never needs a
+	// token scope; it overrides PrivateActivity. See internal/gh/access.go.
+	PublicOnly bool `json:"public_only,omitempty"`
+	// MCPSampling lets the MCP server ask the client's model to write the
+	// pet's praise, diary, and suggestions; see cmd/mcp/sampling.go.
+	MCPSampling bool `json:"mcp_sampling,omitempty"`
+	// Bots lists automation whose activity should still count.
+	Bots pet.BotConfig `json:"bots"`
+	// Timeouts bounds each gh and git call.
+	Timeouts gh.TimeoutsConfig `json:"timeouts"`
+	// Retention bounds the history, journal, and undo log kept on disk;
+	// see internal/store/retention.go.
+	Retention store.RetentionConfig `json:"retention"`
+	// Telemetry opts in to the local usage counter; see
+	// internal/store/usage.go.
+	Telemetry bool `json:"telemetry,omitempty"`
+	// Login pins the GitHub account whose pet GitPet looks after; empty
+	// follows gh's active account. See internal/dirs/account.go.
+	Login string `json:"login,omitempty"`
+	// Language is the pet's language, e.g. "ja"; empty follows the locale.
+	Language string `json:"language,omitempty"`
+	// WeekStart names the day weeks start on, e.g. "sunday"; empty follows
+	// the locale. Weeks is rolling, the default, for feeds that count the
+	// last seven days, or calendar to count only this week; see
+	// internal/pet/weeks.go.
+	WeekStart string `json:"week_start,omitempty"`
+	Weeks     string `json:"weeks,omitempty"`
+	// IgnoreRepos are owner/repo patterns whose activity never feeds the
+	// pet; see internal/activity/repos.go.
+	IgnoreRepos []string `json:"ignore_repos,omitempty"`
+}
+
+// Default is the settings before the Keeper changes any.
+func Default() Settings {
+	return Settings{Scoring: pet.DefaultScoring(), Wellness: pet.DefaultWellness(), Timeouts: gh.DefaultTimeouts(), Retention: store.DefaultRetention()}
+}
+
+// Validate reports the first setting out of range, and normalizes Language.
+func (s *Settings) Validate() error {
+	if err := s.Scoring.Validate(); err != nil {
+		return err
+	}
+	if err := s.Wellness.Validate(); err != nil {
+		return err
+	}
+	if err := s.Timeouts.Validate(); err != nil {
+		return err
+	}
+	if err := s.Retention.Validate(); err != nil {
+		return err
+	}
+	language, err := i18n.ValidateLanguage(s.Language)
+	if err != nil {
+		return err
+	}
+	s.Language = language
+	if err := pet.ValidateWeeks(s.WeekStart, s.Weeks); err != nil {
+		return err
+	}
+	if err := dirs.ValidateLogin(s.Login); err != nil {
+		return err
+	}
+	if err := activity.ValidateRepoPatterns(s.IgnoreRepos); err != nil {
+		return fmt.Errorf("ignore_repos: %w", err)
+	}
+	return nil
+}
+
+// RepoFilter is the repos whose activity may feed the pet.
+func (s Settings) RepoFilter() activity.RepoFilter {
+	return activity.RepoFilter{Ignore: s.IgnoreRepos, PublicOnly: s.PublicOnly}
+}
+
+// Apply sets the timeouts, retention, account, language, and weeks the
+// rest of GitPet reads, once at startup.
+func (s Settings) Apply() {
+	gh.Timeouts = s.Timeouts
+	store.Retention = s.Retention
+	dirs.Account = s.Login
+	i18n.Locale = i18n.DetectLocale(s.Language)
+	pet.SetWeeks(s.WeekStart, s.Weeks)
+}
+
+// Load reads the settings on top of the defaults, so any field left out
+// of the file keeps its default value.
+func Load() (Settings, error) {
+	s := Default()
+	if err := Read(&s); err != nil {
+		return Default(), err
+	}
+	if err := s.Validate(); err != nil {
+		return Default(), fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	return s, nil
+}
+
+// Read decodes the config file into v, leaving v alone when there's no
+// file yet. The CLI reads its whole Config this way.
+func Read(v any) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	return nil
+}
+
+// Path is the config file.
+func Path() (string, error) {
+	dir, err := dirs.Config()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
@@ -0,0 +1,172 @@
+package dirs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// A Keeper signed in to gh with several accounts, say personal and work,
+// keeps one pet per account. The pet in the data directory belongs to the
+// first account that fed it; every other account's pet and records live
+// under accounts/<login> there.
+
+// Account is the login pinned by --login or the config's login. When it's
+// "", GitPet follows the account gh auth switch made active.
+var Account string
+
+// loginPattern is what GitHub allows in a login: letters, digits, and
+// single hyphens between them, up to 39 characters.
+var loginPattern = regexp.MustCompile(`^[A-Za-z0-9](?:-?[A-Za-z0-9]){0,38}$`)
+
+func ValidateLogin(login string) error {
+	if login != "" && !loginPattern.MatchString(login) {
+		return fmt.Errorf("login %q isn't a GitHub username", login)
+	}
+	return nil
+}
+
+// PinAccount pins login for this run, over the config's, as --login does.
+func PinAccount(login string) error {
+	login = strings.TrimPrefix(login, "@")
+	if err := ValidateLogin(login); err != nil {
+		return err
+	}
+	if login != "" {
+		Account = login
+	}
+	return nil
+}
+
+// petAccount is whose pet this run looks after: the pinned account, else
+// gh's active one, else "" when there's no telling, as with GH_TOKEN.
+func petAccount() string {
+	if Account != "" {
+		return Account
+	}
+	if os.Getenv("GH_TOKEN") != "" || os.Getenv("GITHUB_TOKEN") != "" {
+		return ""
+	}
+	active, _ := GHAccounts()
+	return active
+}
+
+// CheckAccount makes sure GitHub answered as the pinned account, so one
+// account's work never feeds another's pet.
+func CheckAccount(login string) error {
+	if Account == "" || strings.EqualFold(login, Account) {
+		return nil
+	}
+	if os.Getenv("GH_TOKEN") != "" || os.Getenv("GITHUB_TOKEN") != "" {
+		return fmt.Errorf("the token in GH_TOKEN or GITHUB_TOKEN belongs to @%s, not @%s; unset it to use gh's login for @%s", login, Account, Account)
+	}
+	return fmt.Errorf("gh is signed in as @%s, not @%s; run gh auth login to add @%s, or gh auth switch --user %s", login, Account, Account, Account)
+}
+
+// Pet is the directory holding petAccount's pet and records.
+func Pet() (string, error) {
+	dir, err := Data()
+	if err != nil {
+		return "", err
+	}
+	login := petAccount()
+	if login == "" {
+		return dir, nil
+	}
+	if owner := petOwner(dir); owner == "" || strings.EqualFold(owner, login) {
+		return dir, nil
+	}
+	return filepath.Join(dir, "accounts", strings.ToLower(login)), nil
+}
+
+// Accounts holds the pets of every account but the first.
+func Accounts() (string, error) {
+	dir, err := Data()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "accounts"), nil
+}
+
+// petOwner is the login that last fed the pet in dir, or "" for a pet from
+// before accounts were recorded, or no pet at all; such a pet goes to
+// whichever account feeds it first.
+func petOwner(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, StateFileName))
+	if os.IsNotExist(err) {
+		// It may not have moved over from beside the preferences yet.
+		if legacyDir, err := Config(); err == nil {
+			data, _ = os.ReadFile(filepath.Join(legacyDir, StateFileName))
+		}
+	}
+	var state struct {
+		Login string `json:"login"`
+	}
+	json.Unmarshal(data, &state)
+	return state.Login
+}
+
+// GHConfigDir is where gh keeps its own config, hosts.yml among it.
+func GHConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	if dir := os.Getenv("AppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "GitHub CLI")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "gh")
+}
+
+// GHAccounts reads gh's hosts.yml for the github.com account gh auth
+// switch made active and every account signed in there, without running
+// gh. Both are empty when gh is pointed at another host or not signed in.
+func GHAccounts() (active string, users []string) {
+	if host := os.Getenv("GH_HOST"); host != "" && host != "github.com" {
+		return "", nil
+	}
+	data, err := os.ReadFile(filepath.Join(GHConfigDir(), "hosts.yml"))
+	if err != nil {
+		return "", nil
+	}
+	// hosts.yml looks like this, and only needs reading by indentation:
+	//
+	//	github.com:
+	//	    users:
+	//	        octocat:
+	//	    user: octocat
+	var host string
+	var hostIndent, usersIndent int
+	inUsers := false
+	for _, line := range strings.Split(string(data), "\n") {
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		key, value, _ := strings.Cut(text, ":")
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch {
+		case indent == 0:
+			host, hostIndent, inUsers = key, 0, false
+		case host != "github.com":
+		case hostIndent == 0 || indent == hostIndent:
+			hostIndent, inUsers = indent, key == "users"
+			usersIndent = 0
+			if key == "user" {
+				active = value
+			}
+		case inUsers && (usersIndent == 0 || indent == usersIndent):
+			usersIndent = indent
+			users = append(users, key)
+		}
+	}
+	return active, users
+}
@@ -0,0 +1,90 @@
+// Package dirs finds where GitPet keeps preferences and records, and
+// which GitHub account's pet they belong to.
+package dirs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// GitPet keeps preferences (the settings file, skins, and plugins) in gh's
+// config directory, and the pet and its records in the data directory.
+// GITPET_CONFIG_DIR puts both in one directory of its own, so tests and
+// sandboxes never touch the real pet, and GITPET_STATE_FILE moves the pet
+// state file alone. Each GitHub account has a pet of its own; see
+// account.go.
+
+// Config is where preferences live: $GITPET_CONFIG_DIR, else gh's
+// directory in the user config dir.
+func Config() (string, error) {
+	if dir := os.Getenv("GITPET_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "gh"), nil
+}
+
+// Data is where the pet and its records live: $GITPET_CONFIG_DIR, else
+// $XDG_DATA_HOME/gh-pet when that's set, else beside the preferences.
+func Data() (string, error) {
+	if dir := os.Getenv("GITPET_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	// The XDG spec says a relative path is invalid and should be ignored.
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "gh-pet"), nil
+	}
+	return Config()
+}
+
+// Cache is where GitPet keeps what it can fetch or build again, such as
+// ETags and sounds: cache in $GITPET_CONFIG_DIR, else gh-pet in the user
+// cache dir.
+func Cache() (string, error) {
+	if dir := os.Getenv("GITPET_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "cache"), nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "gh-pet"), nil
+}
+
+// DataPath is the file name in the directory of this account's pet. A
+// file kept beside the preferences from before XDG_DATA_HOME was set is
+// moved over the first time it's needed; if it can't be moved, it's used
+// where it is.
+func DataPath(name string) (string, error) {
+	dir, err := Pet()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	if base, err := Data(); err != nil || base != dir {
+		return path, nil
+	}
+	legacyDir, err := Config()
+	if err != nil || legacyDir == dir {
+		return path, nil
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return path, nil
+	}
+	legacy := filepath.Join(legacyDir, name)
+	if _, err := os.Stat(legacy); err != nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return legacy, nil
+	}
+	if err := os.Rename(legacy, path); err != nil {
+		return legacy, nil
+	}
+	return path, nil
+}
+
+const StateFileName = "gh-pet.json"
@@ -0,0 +1,171 @@
+package gh
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GitHub refusing a token is the one failure the Keeper can always fix, so
+// refusals are told apart from other errors as they come back, whether from
+// the API or from gh api, and explained with what to change.
+
+// AccessError is GitHub refusing what a token asked for: for want of a
+// scope or fine-grained permission, because an org wants the token
+// authorized for single sign-on, or because the token itself is bad.
+type AccessError struct {
+	*APIError
+	// Missing are the classic token scopes that would do, any one of them,
+	// when GitHub said.
+	Missing []string
+	// Permission is what a fine-grained token needs, e.g. "contents=read".
+	Permission string
+	// SSO is the page that authorizes the token for the org.
+	SSO string
+}
+
+func (e *AccessError) Unwrap() error { return e.APIError }
+
+// BadToken reports whether GitHub refused the token outright.
+func (e *AccessError) BadToken() bool {
+	return e.Status == http.StatusUnauthorized
+}
+
+var (
+	// GraphQL says "requires one of the following scopes: ['read:org']".
+	requiredScopesPattern = regexp.MustCompile(`following scopes: \[([^\]]*)\]`)
+	// gh adds `This API operation needs the "admin:org" scope.`
+	neededScopePattern = regexp.MustCompile(`needs the "([^"]+)" scope`)
+	// gh ends its errors with the status, as in "Not Found (HTTP 404)".
+	httpStatusPattern = regexp.MustCompile(`\(HTTP (\d{3})\)`)
+)
+
+// responseAccess classifies a failed REST response from its status and the
+// headers GitHub sends with refusals. It returns nil for anything else.
+func responseAccess(apiErr *APIError, header http.Header) *AccessError {
+	sso := ssoURL(header.Get("X-GitHub-SSO"))
+	if apiErr.Status != http.StatusUnauthorized && apiErr.Status != http.StatusForbidden && sso == "" {
+		return nil
+	}
+	return &AccessError{
+		APIError:   apiErr,
+		Missing:    missingScopes(splitScopes(header.Get("X-Accepted-OAuth-Scopes")), splitScopes(header.Get("X-OAuth-Scopes"))),
+		Permission: header.Get("X-Accepted-GitHub-Permissions"),
+		SSO:        sso,
+	}
+}
+
+// graphQLAccess classifies a GraphQL error by its type, returning nil
+// unless it's a refusal.
+func graphQLAccess(apiErr *APIError, errType string) *AccessError {
+	if errType != "INSUFFICIENT_SCOPES" && errType != "FORBIDDEN" {
+		return nil
+	}
+	return &AccessError{APIError: apiErr, Missing: scopesIn(apiErr.Message)}
+}
+
+// ghAPIError turns what gh api printed when it failed into an APIError, or
+// an AccessError for a refusal, so both ways of calling GitHub fail alike.
+func ghAPIError(endpoint, stderr string) error {
+	message := strings.TrimPrefix(strings.TrimSpace(stderr), "gh: ")
+	message, _, _ = strings.Cut(message, "\n")
+	apiErr := &APIError{Endpoint: endpoint, Message: message}
+	if m := httpStatusPattern.FindStringSubmatch(stderr); m != nil {
+		apiErr.Status, _ = strconv.Atoi(m[1])
+		apiErr.Message = strings.TrimSpace(strings.TrimSuffix(message, m[0]))
+	}
+	missing := scopesIn(stderr)
+	lower := strings.ToLower(stderr)
+	refused := apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusForbidden || len(missing) > 0
+	if !refused || strings.Contains(lower, "rate limit") {
+		return apiErr
+	}
+	access := &AccessError{APIError: apiErr, Missing: missing}
+	if i := strings.Index(lower, "saml"); i >= 0 {
+		if j := strings.Index(stderr[i:], "https://"); j >= 0 {
+			access.SSO = strings.Fields(stderr[i+j:])[0]
+		}
+	}
+	return access
+}
+
+// scopesIn finds the scopes GraphQL or gh said were needed in message.
+func scopesIn(message string) []string {
+	if m := requiredScopesPattern.FindStringSubmatch(message); m != nil {
+		return splitScopes(strings.NewReplacer("'", "", `"`, "").Replace(m[1]))
+	}
+	var scopes []string
+	for _, m := range neededScopePattern.FindAllStringSubmatch(message, -1) {
+		scopes = append(scopes, m[1])
+	}
+	return scopes
+}
+
+func splitScopes(list string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(list, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// missingScopes is the accepted scopes, unless the token already has one of
+// them, in which case a scope isn't what's missing.
+func missingScopes(accepted, granted []string) []string {
+	for _, scope := range accepted {
+		for _, have := range granted {
+			if scope == have {
+				return nil
+			}
+		}
+	}
+	return accepted
+}
+
+// ssoURL picks the authorization page out of an X-GitHub-SSO header, which
+// reads "required; url=https://…".
+func ssoURL(header string) string {
+	for _, part := range strings.Split(header, ";") {
+		if url, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+			return url
+		}
+	}
+	return ""
+}
+
+// ExplainAccess says what to do when GitHub refused the token for what,
+// such as "private activity". Other errors come back as they are.
+func ExplainAccess(err error, what string) error {
+	var access *AccessError
+	if !errors.As(err, &access) {
+		return err
+	}
+	var b strings.Builder
+	switch {
+	case access.BadToken():
+		fmt.Fprintf(&b, "GitHub didn't accept your token for %s.\n", what)
+		b.WriteString("With gh, sign in again:    gh auth login\n")
+		b.WriteString("With GH_TOKEN, check that it hasn't expired or been revoked.")
+		return fmt.Errorf("%w\n%s", err, b.String())
+	case access.SSO != "":
+		fmt.Fprintf(&b, "For %s, your token needs authorizing for the organization's single sign-on.\n", what)
+		fmt.Fprintf(&b, "Authorize it here:    %s\n", access.SSO)
+	case len(access.Missing) > 0:
+		fmt.Fprintf(&b, "Your token needs the %s scope for %s.\n", strings.Join(access.Missing, " or "), what)
+		fmt.Fprintf(&b, "With gh, add it:    gh auth refresh --scopes %s\n", access.Missing[0])
+		b.WriteString("With GH_TOKEN, use a token that has it.\n")
+	case access.Permission != "":
+		fmt.Fprintf(&b, "Your fine-grained token needs the %s permission for %s.\n", access.Permission, what)
+		b.WriteString("Edit the token under Settings → Developer settings → Fine-grained tokens.\n")
+	default:
+		fmt.Fprintf(&b, "GitHub refused your token for %s.\n", what)
+		b.WriteString("A fine-grained token may lack the repository or permission, or an organization may restrict it.\n")
+	}
+	b.WriteString("To keep to public activity, which needs no scopes:    gh pet config set public-only on")
+	return fmt.Errorf("%w\n%s", err, b.String())
+}
@@ -0,0 +1,67 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/logging"
+)
+
+// Concurrency caps how many gh processes run at once when a command fans
+// out over several repositories.
+const Concurrency = 4
+
+// API runs `gh api args...` under the configured GitHub timeout and logs
+// the endpoint, how long it took, and gh's own error output when it fails.
+// That output becomes the error, as an APIError where gh gave a status.
+func API(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(Timeouts.GitHubSeconds)*time.Second)
+	defer cancel()
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, "gh", append([]string{"api"}, args...)...)
+	cmd.WaitDelay = KillGrace
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		err = fmt.Errorf("gh api %s: %w", args[0], ctx.Err())
+	}
+	attrs := []any{"endpoint", args[0], "duration", time.Since(start).Round(time.Millisecond), "bytes", len(out)}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			attrs = append(attrs, "stderr", strings.TrimSpace(string(exitErr.Stderr)))
+			logging.Logger.Debug("gh api failed", append(attrs, "err", err)...)
+			return out, ghAPIError(args[0], string(exitErr.Stderr))
+		}
+		logging.Logger.Debug("gh api failed", append(attrs, "err", err)...)
+		return out, err
+	}
+	logging.Logger.Debug("gh api", attrs...)
+	return out, nil
+}
+
+// LogRateLimit records how much of the REST and GraphQL budget is left. It
+// costs an extra call, so it only runs with debug output on.
+func LogRateLimit(ctx context.Context) {
+	if !logging.DebugEnabled() {
+		return
+	}
+	var limits struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if Get(ctx, "rate_limit", &limits) != nil {
+		return
+	}
+	for _, name := range []string{"core", "graphql"} {
+		if r, ok := limits.Resources[name]; ok {
+			logging.Logger.Debug("rate limit", "resource", name, "remaining", r.Remaining, "limit", r.Limit, "reset", time.Unix(r.Reset, 0).Format(time.TimeOnly))
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// Package gh talks to GitHub through the REST and GraphQL APIs, and to gh
+// and git, with the timeouts the Keeper configured.
+package gh
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/logging"
+)
+
+const githubAPIURL = "https://api.github.com/"
+
+// githubAttempts is how many times a request is tried when GitHub answers
+// with a server error or the connection drops.
+const githubAttempts = 3
+
+// githubClient is shared so repeated calls reuse one connection.
+var githubClient = &http.Client{}
+
+// APIError is a GitHub response outside 2xx, or a GraphQL error.
+type APIError struct {
+	Endpoint string
+	Status   int
+	Message  string
+}
+
+func (e *APIError) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("github %s: %s", e.Endpoint, e.Message)
+	}
+	return fmt.Sprintf("github %s: %d %s", e.Endpoint, e.Status, e.Message)
+}
+
+// RateLimitError means the request budget ran out until Reset.
+type RateLimitError struct {
+	APIError
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("github %s: rate limit exceeded until %s", e.Endpoint, e.Reset.Local().Format(time.TimeOnly))
+}
+
+// IsNotFound reports whether err is GitHub saying 404.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+var tokenCache struct {
+	sync.Mutex
+	token string
+	found bool
+}
+
+// Token finds a token in GH_TOKEN, GITHUB_TOKEN, or `gh auth token`,
+// asking gh for the pinned account's token when there is one. It returns "" when none is available or gh is pointed at another host; the
+// caller then falls back to running `gh api`, which knows how to handle both.
+func Token(ctx context.Context) string {
+	if host := os.Getenv("GH_HOST"); host != "" && host != "github.com" {
+		return ""
+	}
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	if tokenCache.found {
+		return tokenCache.token
+	}
+	token := os.Getenv("GH_TOKEN")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		ctx, cancel := context.WithTimeout(ctx, time.Duration(Timeouts.GitHubSeconds)*time.Second)
+		defer cancel()
+		args := []string{"auth", "token", "--hostname", "github.com"}
+		if dirs.Account != "" {
+			args = append(args, "--user", dirs.Account)
+		}
+		cmd := exec.CommandContext(ctx, "gh", args...)
+		cmd.WaitDelay = KillGrace
+		if out, err := cmd.Output(); err == nil {
+			token = strings.TrimSpace(string(out))
+		} else {
+			logging.Logger.Debug("no gh token, using gh api", "err", err)
+		}
+	}
+	tokenCache.token, tokenCache.found = token, true
+	return token
+}
+
+// Get fetches a REST endpoint, e.g. "users/octocat/events", and
+// decodes the JSON response into v.
+func Get(ctx context.Context, endpoint string, v any) error {
+	var out []byte
+	var err error
+	if token := Token(ctx); token != "" {
+		out, err = githubDo(ctx, token, http.MethodGet, endpoint, nil)
+	} else {
+		out, err = API(ctx, endpoint)
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("github %s: unable to parse response: %w", endpoint, err)
+	}
+	return nil
+}
+
+// GraphQL runs query with vars and decodes the response's data into v.
+func GraphQL(ctx context.Context, query string, vars map[string]any, v any) error {
+	var out []byte
+	var err error
+	if token := Token(ctx); token != "" {
+		body, _ := json.Marshal(map[string]any{"query": query, "variables": vars})
+		out, err = githubDo(ctx, token, http.MethodPost, "graphql", body)
+	} else {
+		args := []string{"graphql", "-f", "query=" + query}
+		for name, value := range vars {
+			if s, ok := value.(string); ok {
+				args = append(args, "-f", name+"="+s)
+			} else {
+				args = append(args, "-F", fmt.Sprintf("%s=%v", name, value))
+			}
+		}
+		out, err = API(ctx, args...)
+	}
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return fmt.Errorf("github graphql: unable to parse response: %w", err)
+	}
+	if len(resp.Errors) > 0 && (len(resp.Data) == 0 || string(resp.Data) == "null") {
+		apiErr := &APIError{Endpoint: "graphql", Message: resp.Errors[0].Message}
+		if access := graphQLAccess(apiErr, resp.Errors[0].Type); access != nil {
+			return access
+		}
+		return apiErr
+	}
+	if err := json.Unmarshal(resp.Data, v); err != nil {
+		return fmt.Errorf("github graphql: unable to parse data: %w", err)
+	}
+	return nil
+}
+
+// errNoToken is returned by calls that can't fall back to gh api.
+var errNoToken = errors.New("no GitHub token; run gh auth login or set GH_TOKEN")
+
+// Send sends payload as JSON with method, e.g. POST or PATCH, and
+// decodes the response into v when v isn't nil. Unlike reads it needs a
+// token, since the gh api fallback can't be handed a request body.
+func Send(ctx context.Context, method, endpoint string, payload, v any) error {
+	token := Token(ctx)
+	if token == "" {
+		return errNoToken
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	out, err := githubDo(ctx, token, method, endpoint, body)
+	if err != nil || v == nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("github %s: unable to parse response: %w", endpoint, err)
+	}
+	return nil
+}
+
+// githubDo sends one request, retrying server errors and dropped connections
+// with backoff. GETs are conditional: a cached ETag turns an unchanged
+// response into a 304, which GitHub doesn't count against the rate limit.
+func githubDo(ctx context.Context, token, method, endpoint string, body []byte) ([]byte, error) {
+	var cached etagEntry
+	if method == http.MethodGet {
+		cached = loadETag(endpoint)
+	}
+	var lastErr error
+	for attempt := 0; attempt < githubAttempts; attempt++ {
+		if attempt > 0 {
+			wait := time.Duration(250<<attempt) * time.Millisecond
+			var apiErr *APIError
+			if errors.As(lastErr, &apiErr) && apiErr.Status == http.StatusTooManyRequests && wait < retryAfter {
+				wait = retryAfter
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		out, status, header, err := githubRequest(ctx, token, method, endpoint, body, cached.ETag)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("github %s: %w", endpoint, ctx.Err())
+			}
+			lastErr = fmt.Errorf("github %s: %w", endpoint, err)
+			continue
+		case status == http.StatusNotModified:
+			return cached.Body, nil
+		case status >= 200 && status < 300:
+			if etag := header.Get("ETag"); method == http.MethodGet && etag != "" {
+				saveETag(endpoint, etagEntry{ETag: etag, Body: out})
+			}
+			return out, nil
+		}
+
+		lastErr = githubError(endpoint, status, header, out)
+		var limited *RateLimitError
+		if errors.As(lastErr, &limited) || status < 500 && status != http.StatusTooManyRequests {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+// retryAfter is the least a 429 waits before trying again.
+const retryAfter = 2 * time.Second
+
+func githubRequest(ctx context.Context, token, method, endpoint string, body []byte, etag string) ([]byte, int, http.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(Timeouts.GitHubSeconds)*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, githubAPIURL+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "gh-pet")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	start := time.Now()
+	resp, err := githubClient.Do(req)
+	if err != nil {
+		logging.Logger.Debug("github request failed", "method", method, "endpoint", endpoint, "err", err)
+		return nil, 0, nil, err
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	logging.Logger.Debug("github request", "method", method, "endpoint", endpoint, "status", resp.StatusCode,
+		"duration", time.Since(start).Round(time.Millisecond), "bytes", len(out),
+		"remaining", resp.Header.Get("X-RateLimit-Remaining"))
+	return out, resp.StatusCode, resp.Header, err
+}
+
+// githubError turns a failed response into an APIError, a RateLimitError
+// when the budget is spent, or an AccessError when the token was refused.
+func githubError(endpoint string, status int, header http.Header, body []byte) error {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &payload)
+	if payload.Message == "" {
+		payload.Message = http.StatusText(status)
+	}
+	apiErr := APIError{Endpoint: endpoint, Status: status, Message: payload.Message}
+	if (status == http.StatusForbidden || status == http.StatusTooManyRequests) && header.Get("X-RateLimit-Remaining") == "0" {
+		reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+		return &RateLimitError{APIError: apiErr, Reset: time.Unix(reset, 0)}
+	}
+	if access := responseAccess(&apiErr, header); access != nil {
+		return access
+	}
+	return &apiErr
+}
+
+// etagEntry is a cached GET response and the ETag it came with.
+type etagEntry struct {
+	ETag string          `json:"etag"`
+	Body json.RawMessage `json:"body"`
+}
+
+func etagPath(endpoint string) (string, error) {
+	dir, err := dirs.Cache()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(endpoint))
+	return filepath.Join(dir, "etags", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+func loadETag(endpoint string) etagEntry {
+	var entry etagEntry
+	path, err := etagPath(endpoint)
+	if err != nil {
+		return entry
+	}
+	if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &entry) != nil {
+		return etagEntry{}
+	}
+	return entry
+}
+
+func saveETag(endpoint string, entry etagEntry) {
+	path, err := etagPath(endpoint)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err == nil {
+		os.WriteFile(path, data, 0o600)
+	}
+}
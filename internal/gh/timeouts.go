@@ -0,0 +1,51 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// TimeoutsConfig bounds every call to gh and git, so a stalled network or a
+// huge repository can't hang a hook, a prompt, or an MCP tool call.
+type TimeoutsConfig struct {
+	GitHubSeconds int `json:"github_seconds"`
+	GitSeconds    int `json:"git_seconds"`
+}
+
+func DefaultTimeouts() TimeoutsConfig {
+	return TimeoutsConfig{GitHubSeconds: 20, GitSeconds: 5}
+}
+
+func (t TimeoutsConfig) Validate() error {
+	if t.GitHubSeconds < 1 || t.GitHubSeconds > 300 {
+		return fmt.Errorf("timeouts.github_seconds must be between 1 and 300")
+	}
+	if t.GitSeconds < 1 || t.GitSeconds > 300 {
+		return fmt.Errorf("timeouts.git_seconds must be between 1 and 300")
+	}
+	return nil
+}
+
+// KillGrace is how long a timed-out command's output pipes may stay open
+// after it is killed, in case it left children holding them.
+const KillGrace = time.Second
+
+// Timeouts is set from the config at startup; until then the defaults apply.
+var Timeouts = DefaultTimeouts()
+
+// GitOutput runs git with the configured timeout and returns its stdout.
+func GitOutput(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(Timeouts.GitSeconds)*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.WaitDelay = KillGrace
+	return cmd.Output()
+}
+
+// InWorkTree reports whether the current directory is inside a git work tree.
+func InWorkTree(ctx context.Context) bool {
+	_, err := GitOutput(ctx, "rev-parse", "--is-inside-work-tree")
+	return err == nil
+}
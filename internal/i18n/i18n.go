@@ -0,0 +1,393 @@
+// Package i18n renders pet-facing text in the Keeper's language, and
+// formats times and lists the way a sentence would.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Locales are the languages the pet can speak; "en" is the source language
+// and needs no catalog.
+var Locales = []string{"en", "zh-TW", "ja", "es"}
+
+// Locale is the language pet-facing text is rendered in, set at startup
+// from the config or the environment.
+var Locale = "en"
+
+// DetectLocale returns configured when it is set, and otherwise the first
+// supported language named by LC_ALL, LC_MESSAGES, or LANG.
+func DetectLocale(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return matchLocale(value)
+		}
+	}
+	return "en"
+}
+
+// matchLocale maps a POSIX locale such as "ja_JP.UTF-8" or a tag such as
+// "zh-Hant-TW" to a supported language, falling back to English.
+func matchLocale(tag string) string {
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	switch {
+	case tag == "zh-tw" || tag == "zh-hk" || tag == "zh-mo" || strings.HasPrefix(tag, "zh-hant"):
+		return "zh-TW"
+	case tag == "ja" || strings.HasPrefix(tag, "ja-"):
+		return "ja"
+	case tag == "es" || strings.HasPrefix(tag, "es-"):
+		return "es"
+	}
+	return "en"
+}
+
+// ValidateLanguage accepts "" for automatic detection or a supported
+// language, and returns it in its canonical spelling.
+func ValidateLanguage(language string) (string, error) {
+	if language == "" {
+		return "", nil
+	}
+	for _, l := range Locales {
+		if strings.EqualFold(language, l) {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported language %q (%s)", language, strings.Join(Locales, ", "))
+}
+
+// Tr translates msg into the current locale and formats it with args. The
+// English text is the key, so a message missing from a catalog still reads
+// fine.
+func Tr(msg string, args ...any) string {
+	if translated, ok := catalogs[Locale][msg]; ok {
+		msg = translated
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+var catalogs = map[string]map[string]string{
+	"zh-TW": {
+		// Status labels.
+		"%s Status":          "%s 狀態",
+		"Pronouns":           "代名詞",
+		"Evolution":          "進化",
+		"Mood":               "心情",
+		"Kindness":           "善意",
+		"Shards":             "碎片",
+		"Mentor":             "導師",
+		"Synced":             "同步",
+		"Never":              "從未",
+		"Langs":              "語言",
+		"Badges":             "徽章",
+		"Ability":            "能力",
+		"Abilities":          "能力",
+		"Wellness":           "身心狀態",
+		"Requests for help:": "求助清單：",
+		"Forgotten work:":    "被遺忘的工作：",
+		"Goals:":             "目標：",
+		"📦 %s: a stash from %s ago, %q. Still need it?":      "📦 %s：%s 前的 stash %q，還需要嗎？",
+		"🌱 %s: %s hasn't been pushed for %s.":                "🌱 %s：%s 已經 %s 沒有推送了。",
+		"✏️  %s: changes left uncommitted for %s.":           "✏️  %s：有變更已經 %s 沒有提交。",
+		"…and %d more. See gh pet wip.":                      "……還有 %d 項，請看 gh pet wip。",
+		"Issues: %d opened %d closed %d labeled %d comments": "Issue：開啟 %d 關閉 %d 標籤 %d 留言 %d",
+		"Reviews: %d approved %d changes %d comments":        "審查：核准 %d 要求修改 %d 留言 %d",
+		"answered in %s":                     "%s 內回應",
+		"💚 Balanced rhythm. Keep it gentle.": "💚 節奏平衡，保持溫和。",
+
+		// Times.
+		"just now": "剛剛",
+		"%s ago":   "%s前",
+
+		// Evolutions.
+		"Pioneer":  "開拓者",
+		"Guardian": "守護者",
+		"Bard":     "吟遊詩人",
+		"Sentinel": "哨兵",
+		"Curator":  "策展人",
+		"Void":     "虛空",
+		"Lonely":   "寂寞",
+
+		// Tones and evolution flavor.
+		"Intensity: blazing. %s is thriving in the Cache.": "強度：熾烈。%s 在快取裡茁壯成長。",
+		"Intensity: steady. %s hums with creative heat.":   "強度：穩定。%s 散發著創作的熱度。",
+		"Intensity: gentle. %s feels acknowledged.":        "強度：溫和。%s 感受到你的重視。",
+		"Intensity: quiet. %s grows a little lonely.":      "強度：安靜。%s 有點寂寞。",
+		"🗝️  Found a tiny treasure chest!":                 "🗝️  找到一個小寶箱！",
+		"🛡️  Shielding your logs.":                         "🛡️  守護著你的日誌。",
+		"🧪 Every test is a watchtower.":                    "🧪 每個測試都是一座瞭望塔。",
+		"🗂️  Tending the issue garden.":                    "🗂️  照料 issue 花園中。",
+
+		// Praise.
+		"Nice commit! 🔥":    "漂亮的提交！🔥",
+		"You're on fire! 💪": "你火力全開！💪",
+		"Keep it up! ✨":     "繼續保持！✨",
+		"Great work! 🌟":     "做得好！🌟",
+		"Awesome sauce! 🎉":  "太讚了！🎉",
+		"You rock! 🤘":       "你超強！🤘",
+		"Legendary! ⚡":      "傳奇級！⚡",
+		"Brilliant! 💎":      "太聰明了！💎",
+		"Ship it! 🚀":        "出貨吧！🚀",
+		"Code warrior! ⚔️":  "程式戰士！⚔️",
+		"Well done! 🏆":      "幹得好！🏆",
+		"Commit hero! 🦸":    "提交英雄！🦸",
+
+		// Proverbs.
+		"Small diffs travel far.":                     "小小的 diff 走得最遠。",
+		"Tests are lanterns in the fog.":              "測試是霧中的燈籠。",
+		"Readability is a form of kindness.":          "可讀性是一種溫柔。",
+		"Rename first, refactor second.":              "先改名，再重構。",
+		"Bugs fear patient eyes.":                     "Bug 害怕耐心的眼睛。",
+		"Clear is better than clever.":                "清楚勝過聰明。",
+		"The borrow checker is a friend in disguise.": "借用檢查器是偽裝的朋友。",
+		"Readability counts.":                         "可讀性很重要。",
+		"Types are promises kept.":                    "型別是信守的承諾。",
+		"Optimize for programmer happiness.":          "為程式設計師的快樂而最佳化。",
+
+		// Accessories.
+		"🐹 gopher hat":     "🐹 地鼠帽",
+		"🦀 crab buddy":     "🦀 螃蟹夥伴",
+		"🐍 snake scarf":    "🐍 蛇紋圍巾",
+		"⚡ lightning pin":  "⚡ 閃電別針",
+		"💎 ruby brooch":    "💎 紅寶石胸針",
+		"☕ coffee mug":     "☕ 咖啡杯",
+		"🐦 swift feather":  "🐦 雨燕羽毛",
+		"⚙️  gear monocle": "⚙️  齒輪單片眼鏡",
+		"🐚 seashell":       "🐚 貝殼",
+		"🪶 quill":          "🪶 羽毛筆",
+
+		// Time of day and seasons.
+		"🎉🎊 Happy GitHub anniversary! 🎊🎉": "🎉🎊 GitHub 週年快樂！🎊🎉",
+		"🎃 pumpkin hat":                         "🎃 南瓜帽",
+		"🧣 cozy scarf":                          "🧣 暖暖圍巾",
+		"👕 Hacktoberfest tee":                   "👕 Hacktoberfest T 恤",
+		"⭐ advent star":                         "⭐ 聖誕降臨星",
+		"🥳 party hat":                           "🥳 派對帽",
+		"💤 Sleeping. Dreaming of green builds.": "💤 睡覺中，夢見綠色的建置。",
+		"☀️  Bright-eyed and ready to ship!":    "☀️  精神飽滿，準備出貨！",
+
+		// Wellness.
+		"🌙 %d late-night pushes this week. Sleep is a feature too.":           "🌙 本週有 %d 次深夜推送。睡眠也是一項功能。",
+		"📅 All your work landed on the weekend. Maybe borrow a weekday back?": "📅 你的工作全落在週末。要不要借回一個平日？",
+		"🔥 %d days in a row without a break. Rest days keep streaks healthy.": "🔥 已連續 %d 天沒有休息。休息日讓連續紀錄更健康。",
+		"🌙 It's late. I'll keep watch over the code; go get some rest.":       "🌙 很晚了。程式碼我來看守，去休息吧。",
+	},
+	"ja": {
+		// Status labels.
+		"%s Status":          "%s のステータス",
+		"Pronouns":           "代名詞",
+		"Evolution":          "進化",
+		"Mood":               "気分",
+		"Kindness":           "優しさ",
+		"Shards":             "欠片",
+		"Mentor":             "メンター",
+		"Synced":             "同期",
+		"Never":              "なし",
+		"Langs":              "言語",
+		"Badges":             "バッジ",
+		"Ability":            "アビリティ",
+		"Abilities":          "アビリティ",
+		"Wellness":           "ウェルネス",
+		"Requests for help:": "助けを求める声:",
+		"Forgotten work:":    "忘れられた作業:",
+		"Goals:":             "目標:",
+		"📦 %s: a stash from %s ago, %q. Still need it?":      "📦 %s: %s 前の stash %q、まだ必要？",
+		"🌱 %s: %s hasn't been pushed for %s.":                "🌱 %s: %s は %s プッシュされていないよ。",
+		"✏️  %s: changes left uncommitted for %s.":           "✏️  %s: 変更が %s コミットされていないよ。",
+		"…and %d more. See gh pet wip.":                      "…ほかに %d 件。gh pet wip を見てね。",
+		"Issues: %d opened %d closed %d labeled %d comments": "Issue: 作成 %d 完了 %d ラベル %d コメント %d",
+		"Reviews: %d approved %d changes %d comments":        "レビュー: 承認 %d 修正依頼 %d コメント %d",
+		"answered in %s":                     "%s で返答",
+		"💚 Balanced rhythm. Keep it gentle.": "💚 いいリズム。無理せずにね。",
+
+		// Times.
+		"just now": "たった今",
+		"%s ago":   "%s前",
+
+		// Evolutions.
+		"Pioneer":  "開拓者",
+		"Guardian": "守護者",
+		"Bard":     "吟遊詩人",
+		"Sentinel": "番人",
+		"Curator":  "キュレーター",
+		"Void":     "虚無",
+		"Lonely":   "さみしがり",
+
+		// Tones and evolution flavor.
+		"Intensity: blazing. %s is thriving in the Cache.": "強度: 燃え盛る。%s はキャッシュの中で元気いっぱい。",
+		"Intensity: steady. %s hums with creative heat.":   "強度: 安定。%s は創造の熱を帯びている。",
+		"Intensity: gentle. %s feels acknowledged.":        "強度: 穏やか。%s は見守られていると感じている。",
+		"Intensity: quiet. %s grows a little lonely.":      "強度: 静か。%s は少し寂しそう。",
+		"🗝️  Found a tiny treasure chest!":                 "🗝️  小さな宝箱を見つけた！",
+		"🛡️  Shielding your logs.":                         "🛡️  ログを守っているよ。",
+		"🧪 Every test is a watchtower.":                    "🧪 テストはどれも見張り塔。",
+		"🗂️  Tending the issue garden.":                    "🗂️  Issue の庭を手入れ中。",
+
+		// Praise.
+		"Nice commit! 🔥":    "ナイスコミット！🔥",
+		"You're on fire! 💪": "絶好調だね！💪",
+		"Keep it up! ✨":     "その調子！✨",
+		"Great work! 🌟":     "いい仕事！🌟",
+		"Awesome sauce! 🎉":  "最高！🎉",
+		"You rock! 🤘":       "さすが！🤘",
+		"Legendary! ⚡":      "伝説級！⚡",
+		"Brilliant! 💎":      "お見事！💎",
+		"Ship it! 🚀":        "リリースだ！🚀",
+		"Code warrior! ⚔️":  "コードの戦士！⚔️",
+		"Well done! 🏆":      "よくやった！🏆",
+		"Commit hero! 🦸":    "コミットの英雄！🦸",
+
+		// Proverbs.
+		"Small diffs travel far.":                     "小さな差分は遠くまで届く。",
+		"Tests are lanterns in the fog.":              "テストは霧の中の灯り。",
+		"Readability is a form of kindness.":          "読みやすさは優しさのひとつ。",
+		"Rename first, refactor second.":              "まず名前を変え、次にリファクタリング。",
+		"Bugs fear patient eyes.":                     "バグは辛抱強い目を恐れる。",
+		"Clear is better than clever.":                "賢さより明快さ。",
+		"The borrow checker is a friend in disguise.": "借用チェッカーは姿を変えた友だち。",
+		"Readability counts.":                         "読みやすさは大切。",
+		"Types are promises kept.":                    "型は守られた約束。",
+		"Optimize for programmer happiness.":          "プログラマーの幸せを最適化しよう。",
+
+		// Accessories.
+		"🐹 gopher hat":     "🐹 ゴーファーの帽子",
+		"🦀 crab buddy":     "🦀 カニの相棒",
+		"🐍 snake scarf":    "🐍 ヘビのマフラー",
+		"⚡ lightning pin":  "⚡ 稲妻のピン",
+		"💎 ruby brooch":    "💎 ルビーのブローチ",
+		"☕ coffee mug":     "☕ コーヒーマグ",
+		"🐦 swift feather":  "🐦 アマツバメの羽",
+		"⚙️  gear monocle": "⚙️  歯車のモノクル",
+		"🐚 seashell":       "🐚 貝殻",
+		"🪶 quill":          "🪶 羽ペン",
+
+		// Time of day and seasons.
+		"🎉🎊 Happy GitHub anniversary! 🎊🎉": "🎉🎊 GitHub 記念日おめでとう！🎊🎉",
+		"🎃 pumpkin hat":                         "🎃 かぼちゃの帽子",
+		"🧣 cozy scarf":                          "🧣 ぬくぬくマフラー",
+		"👕 Hacktoberfest tee":                   "👕 Hacktoberfest Tシャツ",
+		"⭐ advent star":                         "⭐ アドベントの星",
+		"🥳 party hat":                           "🥳 パーティー帽",
+		"💤 Sleeping. Dreaming of green builds.": "💤 おやすみ中。緑のビルドの夢を見ている。",
+		"☀️  Bright-eyed and ready to ship!":    "☀️  元気いっぱい、出荷の準備万端！",
+
+		// Wellness.
+		"🌙 %d late-night pushes this week. Sleep is a feature too.":           "🌙 今週は深夜のプッシュが %d 回。睡眠も大事な機能だよ。",
+		"📅 All your work landed on the weekend. Maybe borrow a weekday back?": "📅 作業がすべて週末に集中しているよ。平日を一日取り戻してみては？",
+		"🔥 %d days in a row without a break. Rest days keep streaks healthy.": "🔥 %d 日連続で休みなし。休息日があってこそ記録は続くよ。",
+		"🌙 It's late. I'll keep watch over the code; go get some rest.":       "🌙 もう遅いよ。コードは見張っておくから、休んでね。",
+	},
+	"es": {
+		// Status labels.
+		"%s Status":          "Estado de %s",
+		"Pronouns":           "Pronombres",
+		"Evolution":          "Evolución",
+		"Mood":               "Ánimo",
+		"Kindness":           "Bondad",
+		"Shards":             "Cristales",
+		"Mentor":             "Mentor",
+		"Synced":             "Sincronía",
+		"Never":              "Nunca",
+		"Langs":              "Lenguajes",
+		"Badges":             "Insignias",
+		"Ability":            "Habilidad",
+		"Abilities":          "Habilidades",
+		"Wellness":           "Bienestar",
+		"Requests for help:": "Pedidos de ayuda:",
+		"Forgotten work:":    "Trabajo olvidado:",
+		"Goals:":             "Metas:",
+		"📦 %s: a stash from %s ago, %q. Still need it?":      "📦 %s: un stash de hace %s, %q. ¿Aún lo necesitas?",
+		"🌱 %s: %s hasn't been pushed for %s.":                "🌱 %s: %s lleva %s sin enviarse.",
+		"✏️  %s: changes left uncommitted for %s.":           "✏️  %s: hay cambios sin confirmar desde hace %s.",
+		"…and %d more. See gh pet wip.":                      "…y %d más. Mira gh pet wip.",
+		"Issues: %d opened %d closed %d labeled %d comments": "Issues: %d abiertos %d cerrados %d etiquetados %d comentarios",
+		"Reviews: %d approved %d changes %d comments":        "Revisiones: %d aprobadas %d con cambios %d comentarios",
+		"answered in %s":                     "respondidas en %s",
+		"💚 Balanced rhythm. Keep it gentle.": "💚 Ritmo equilibrado. Con calma.",
+
+		// Times.
+		"just now": "justo ahora",
+		"%s ago":   "hace %s",
+
+		// Evolutions.
+		"Pioneer":  "Pionero",
+		"Guardian": "Guardián",
+		"Bard":     "Bardo",
+		"Sentinel": "Centinela",
+		"Curator":  "Curador",
+		"Void":     "Vacío",
+		"Lonely":   "Solitario",
+
+		// Tones and evolution flavor.
+		"Intensity: blazing. %s is thriving in the Cache.": "Intensidad: ardiente. %s prospera en la Caché.",
+		"Intensity: steady. %s hums with creative heat.":   "Intensidad: constante. %s vibra con calor creativo.",
+		"Intensity: gentle. %s feels acknowledged.":        "Intensidad: suave. %s siente tu reconocimiento.",
+		"Intensity: quiet. %s grows a little lonely.":      "Intensidad: tranquila. A %s le falta compañía.",
+		"🗝️  Found a tiny treasure chest!":                 "🗝️  ¡Encontré un pequeño cofre del tesoro!",
+		"🛡️  Shielding your logs.":                         "🛡️  Protegiendo tus logs.",
+		"🧪 Every test is a watchtower.":                    "🧪 Cada prueba es una atalaya.",
+		"🗂️  Tending the issue garden.":                    "🗂️  Cuidando el jardín de issues.",
+
+		// Praise.
+		"Nice commit! 🔥":    "¡Buen commit! 🔥",
+		"You're on fire! 💪": "¡Estás que ardes! 💪",
+		"Keep it up! ✨":     "¡Sigue así! ✨",
+		"Great work! 🌟":     "¡Gran trabajo! 🌟",
+		"Awesome sauce! 🎉":  "¡Genial! 🎉",
+		"You rock! 🤘":       "¡Eres lo máximo! 🤘",
+		"Legendary! ⚡":      "¡Legendario! ⚡",
+		"Brilliant! 💎":      "¡Brillante! 💎",
+		"Ship it! 🚀":        "¡A producción! 🚀",
+		"Code warrior! ⚔️":  "¡Guerrero del código! ⚔️",
+		"Well done! 🏆":      "¡Bien hecho! 🏆",
+		"Commit hero! 🦸":    "¡Héroe del commit! 🦸",
+
+		// Proverbs.
+		"Small diffs travel far.":                     "Los diffs pequeños llegan lejos.",
+		"Tests are lanterns in the fog.":              "Las pruebas son faroles en la niebla.",
+		"Readability is a form of kindness.":          "La legibilidad es una forma de amabilidad.",
+		"Rename first, refactor second.":              "Primero renombra, luego refactoriza.",
+		"Bugs fear patient eyes.":                     "Los bugs temen a los ojos pacientes.",
+		"Clear is better than clever.":                "Claro es mejor que ingenioso.",
+		"The borrow checker is a friend in disguise.": "El borrow checker es un amigo disfrazado.",
+		"Readability counts.":                         "La legibilidad cuenta.",
+		"Types are promises kept.":                    "Los tipos son promesas cumplidas.",
+		"Optimize for programmer happiness.":          "Optimiza para la felicidad del programador.",
+
+		// Accessories.
+		"🐹 gopher hat":     "🐹 gorro de gopher",
+		"🦀 crab buddy":     "🦀 amigo cangrejo",
+		"🐍 snake scarf":    "🐍 bufanda de serpiente",
+		"⚡ lightning pin":  "⚡ pin de rayo",
+		"💎 ruby brooch":    "💎 broche de rubí",
+		"☕ coffee mug":     "☕ taza de café",
+		"🐦 swift feather":  "🐦 pluma de vencejo",
+		"⚙️  gear monocle": "⚙️  monóculo de engranajes",
+		"🐚 seashell":       "🐚 caracola",
+		"🪶 quill":          "🪶 pluma de escribir",
+
+		// Time of day and seasons.
+		"🎉🎊 Happy GitHub anniversary! 🎊🎉": "🎉🎊 ¡Feliz aniversario en GitHub! 🎊🎉",
+		"🎃 pumpkin hat":                         "🎃 gorro de calabaza",
+		"🧣 cozy scarf":                          "🧣 bufanda abrigadora",
+		"👕 Hacktoberfest tee":                   "👕 camiseta de Hacktoberfest",
+		"⭐ advent star":                         "⭐ estrella de Adviento",
+		"🥳 party hat":                           "🥳 gorro de fiesta",
+		"💤 Sleeping. Dreaming of green builds.": "💤 Durmiendo. Soñando con builds en verde.",
+		"☀️  Bright-eyed and ready to ship!":    "☀️  ¡Bien despierto y listo para publicar!",
+
+		// Wellness.
+		"🌙 %d late-night pushes this week. Sleep is a feature too.":           "🌙 %d pushes de madrugada esta semana. Dormir también es una funcionalidad.",
+		"📅 All your work landed on the weekend. Maybe borrow a weekday back?": "📅 Todo tu trabajo cayó en fin de semana. ¿Y si recuperas un día entre semana?",
+		"🔥 %d days in a row without a break. Rest days keep streaks healthy.": "🔥 %d días seguidos sin descanso. Descansar mantiene sana la racha.",
+		"🌙 It's late. I'll keep watch over the code; go get some rest.":       "🌙 Es tarde. Yo vigilo el código; ve a descansar.",
+	},
+}
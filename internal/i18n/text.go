@@ -0,0 +1,28 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JoinNames lists names the way a sentence would: "Ana", "Ana and Bo", or
+// "Ana, Bo, and Cy".
+func JoinNames(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + " and " + names[1]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", and " + names[len(names)-1]
+}
+
+// Plural is n of noun, spelled out when there is one: "one day", "3 days".
+func Plural(n int, noun string) string {
+	if n == 1 {
+		return "one " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,50 @@
+package i18n
+
+import (
+	"fmt"
+	"time"
+)
+
+// relativeCutoff is how far back times read as "3d ago"; older ones show
+// the date.
+const relativeCutoff = 30 * 24 * time.Hour
+
+// DisplayTime renders an RFC 3339 timestamp for people: relative to now,
+// like "2h ago", or with absolute set, as the exact time in the local zone.
+func DisplayTime(ts string, absolute bool) string {
+	if ts == "" {
+		return Tr("Never")
+	}
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	if absolute {
+		return t.Local().Format("2006-01-02 15:04 MST")
+	}
+	return RelativeTime(t, time.Now())
+}
+
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return Tr("just now")
+	case d < relativeCutoff:
+		return Tr("%s ago", ShortAge(d))
+	default:
+		return t.Local().Format("2006-01-02")
+	}
+}
+
+// ShortAge formats a duration in its largest whole unit: 12m, 3h, or 5d.
+func ShortAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
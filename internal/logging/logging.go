@@ -0,0 +1,24 @@
+// Package logging holds GitPet's debug logger.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Logger is silent unless --verbose or GITPET_DEBUG turns on debug output. It
+// always writes to stderr, since the MCP server's stdout belongs to the
+// protocol.
+var Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func Setup(verbose bool) {
+	if verbose || os.Getenv("GITPET_DEBUG") != "" {
+		Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+}
+
+func DebugEnabled() bool {
+	return Logger.Enabled(context.Background(), slog.LevelDebug)
+}
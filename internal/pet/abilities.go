@@ -0,0 +1,115 @@
+package pet
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Ability is an evolution's perk. The commands it touches read it from
+// Abilities rather than checking for the evolution, so a perk is changed, or
+// given to another evolution, by editing its entry.
+type Ability struct {
+	Evolution   string
+	Name        string
+	Description string
+	// ReviewKindness multiplies the kindness reviews earn.
+	ReviewKindness float64
+	// MoodDecay multiplies the mood a quiet feed costs.
+	MoodDecay float64
+	// DropRate multiplies the chance of finding treasure.
+	DropRate float64
+	// ExtraProverbs are proverbs shared each day beyond the usual.
+	ExtraProverbs int
+	// Songbook are commit messages gh pet suggest offers first.
+	Songbook []string
+}
+
+// Multipliers left at zero change nothing.
+var Abilities = []Ability{
+	{
+		Evolution:      "Guardian",
+		Name:           "Shield of Kindness",
+		Description:    "Reviews earn double kindness.",
+		ReviewKindness: 2,
+	},
+	{
+		Evolution:     "Bard",
+		Name:          "Second Verse",
+		Description:   "One more proverb a day, and gh pet suggest sings from the Bard's songbook.",
+		ExtraProverbs: 1,
+		Songbook: []string{
+			"🎼 feat: add a new movement to the symphony",
+			"🎻 fix: retune the string that played off-key",
+			"🥁 test: keep time with a steady beat of assertions",
+			"🎤 refactor: rework the chorus so it scans",
+			"📯 chore: herald the dependency bump",
+			"🪕 docs: set the usage notes to a folk tune",
+		},
+	},
+	{
+		Evolution:   "Pioneer",
+		Name:        "Treasure Sense",
+		Description: "Finds treasure twice as often.",
+		DropRate:    2,
+	},
+	{
+		Evolution:   "Void",
+		Name:        "Stillness",
+		Description: "Quiet weeks cost half as much mood.",
+		MoodDecay:   0.5,
+	},
+}
+
+// AbilityFor is evolution's ability, if it has one.
+func AbilityFor(evolution string) (Ability, bool) {
+	for _, a := range Abilities {
+		if a.Evolution == evolution {
+			return a, true
+		}
+	}
+	return Ability{}, false
+}
+
+// ScaleWeight multiplies a scoring weight by factor on day. A fraction of a
+// point is spread evenly over the days, so halving a weight of 1 costs a
+// point every other day rather than never, and the same day always scores
+// the same.
+func ScaleWeight(weight int, factor float64, day time.Time) int {
+	if factor == 0 {
+		return weight
+	}
+	scaled := float64(weight) * factor
+	whole := math.Floor(scaled)
+	fraction := scaled - whole
+	n := float64(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+	if math.Floor((n+1)*fraction) > math.Floor(n*fraction) {
+		whole++
+	}
+	return int(whole)
+}
+
+// WithAbility is the scoring on day for a pet of evolution, with its
+// ability's multipliers applied.
+func (c ScoringConfig) WithAbility(evolution string, day time.Time) ScoringConfig {
+	a, ok := AbilityFor(evolution)
+	if !ok {
+		return c
+	}
+	c.ReviewKindness = ScaleWeight(c.ReviewKindness, a.ReviewKindness, day)
+	c.IdleMoodDecay = ScaleWeight(c.IdleMoodDecay, a.MoodDecay, day)
+	return c
+}
+
+// treasureChance is how often a pet with a drop rate of 1 would find
+// treasure when it's drawn. Pets without a drop rate never do.
+const treasureChance = 0.2
+
+// FoundTreasure rolls for treasure, at the evolution's drop rate.
+func FoundTreasure(evolution string) bool {
+	a, ok := AbilityFor(evolution)
+	if !ok || a.DropRate == 0 {
+		return false
+	}
+	return rand.Float64() < treasureChance*a.DropRate
+}
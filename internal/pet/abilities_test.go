@@ -0,0 +1,67 @@
+package pet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScaleWeight(t *testing.T) {
+	day := time.Date(2026, 10, 18, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		weight int
+		factor float64
+		days   int
+		want   int
+	}{
+		{weight: 3, factor: 0, days: 1, want: 3},
+		{weight: 3, factor: 2, days: 1, want: 6},
+		{weight: 1, factor: 0.5, days: 10, want: 5},
+		{weight: 3, factor: 0.5, days: 10, want: 15},
+		{weight: 1, factor: 0.25, days: 8, want: 2},
+	}
+	for _, tt := range tests {
+		total := 0
+		for i := range tt.days {
+			total += ScaleWeight(tt.weight, tt.factor, day.AddDate(0, 0, i))
+		}
+		if total != tt.want {
+			t.Errorf("ScaleWeight(%d, %v) over %d days = %d, want %d", tt.weight, tt.factor, tt.days, total, tt.want)
+		}
+	}
+
+	// The same day scores the same, whatever the time.
+	for hour := range 24 {
+		at := time.Date(2026, 10, 18, hour, 30, 0, 0, time.Local)
+		if got, want := ScaleWeight(1, 0.5, at), ScaleWeight(1, 0.5, day); got != want {
+			t.Fatalf("at %d:30 got %d, at 15:00 %d", hour, got, want)
+		}
+	}
+}
+
+func TestWithAbility(t *testing.T) {
+	day := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	base := DefaultScoring()
+	if got := base.WithAbility("Guardian", day); got.ReviewKindness != 2*base.ReviewKindness || got.IdleMoodDecay != base.IdleMoodDecay {
+		t.Errorf("Guardian: review kindness %d, idle decay %d; want %d, %d", got.ReviewKindness, got.IdleMoodDecay, 2*base.ReviewKindness, base.IdleMoodDecay)
+	}
+	if got := base.WithAbility("Lonely", day); got.ReviewKindness != base.ReviewKindness || got.IdleMoodDecay != base.IdleMoodDecay {
+		t.Errorf("Lonely: scoring changed to %+v", got)
+	}
+	decay := 0
+	for i := range 30 {
+		decay += base.WithAbility("Void", day.AddDate(0, 0, i)).IdleMoodDecay
+	}
+	if want := 15 * base.IdleMoodDecay; decay != want {
+		t.Errorf("Void: idle decay over 30 days = %d, want %d", decay, want)
+	}
+}
+
+func TestOnlyDropRatesFindTreasure(t *testing.T) {
+	for _, evolution := range []string{"Lonely", "Guardian", "Bard", "Void"} {
+		for range 200 {
+			if FoundTreasure(evolution) {
+				t.Fatalf("%s found treasure without a drop rate", evolution)
+			}
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package pet
+
+import (
+	"encoding/json"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ActivitySummary counts what the Keeper did over a week.
+type ActivitySummary struct {
+	Commits            int            `json:"commits"`
+	MergedPRs          int            `json:"merged_prs"`
+	Reviews            int            `json:"reviews"`
+	DocComments        int            `json:"doc_comments"`
+	RefactorCommits    int            `json:"refactor_commits"`
+	NewRepos           int            `json:"new_repos"`
+	LargeCommits       int            `json:"large_commits"`
+	Thoughts           int            `json:"thought_fragments"`
+	FixCommits         int            `json:"fix_commits"`
+	DocCommits         int            `json:"doc_commits"`
+	TestCommits        int            `json:"test_commits"`
+	IssuesOpened       int            `json:"issues_opened"`
+	IssuesClosed       int            `json:"issues_closed"`
+	IssuesLabeled      int            `json:"issues_labeled"`
+	IssueComments      int            `json:"issue_comments"`
+	Discussions        int            `json:"discussions"`
+	DiscussionComments int            `json:"discussion_comments"`
+	Sponsorships       int            `json:"sponsorships"`
+	LateNightPushes    int            `json:"late_night_pushes"`
+	WeekendEvents      int            `json:"weekend_events"`
+	WeekdayEvents      int            `json:"weekday_events"`
+	FixedBuilds        int            `json:"fixed_builds,omitempty"`
+	Languages          map[string]int `json:"languages,omitempty"`
+	// IgnoredCommits were pushed but earned nothing; see DiscountCommits.
+	IgnoredCommits int `json:"ignored_commits,omitempty"`
+	// Private is how much of the activity came from private repos the
+	// events feed doesn't show; see AddPrivate.
+	Private int `json:"private,omitempty"`
+	// ReviewDepth is what the Keeper's reviews said and how quickly
+	// requested ones came, which the events feed doesn't say; the CLI
+	// asks GitHub for it.
+	ReviewDepth ReviewDepth `json:"review_depth"`
+	// DuetCommits carry Co-authored-by trailers, and CoAuthors are who the
+	// Keeper wrote them with.
+	DuetCommits int      `json:"duet_commits,omitempty"`
+	CoAuthors   []string `json:"co_authors,omitempty"`
+	// LinesChanged adds up the lines the latest commits added and removed,
+	// and LargeCommits counts the large ones; the CLI looks them up.
+	LinesChanged int `json:"lines_changed,omitempty"`
+	// FirstTimers are pull requests from first-time contributors to the
+	// Keeper's repos that the Keeper reviewed or commented on.
+	FirstTimers int `json:"first_timers,omitempty"`
+}
+
+// ReviewDepth is how the Keeper reviewed, beyond how often: what each review
+// said, and how long requested reviews waited.
+type ReviewDepth struct {
+	// Comments are inline comments left in reviews, a few at most from
+	// each.
+	Comments       int `json:"comments,omitempty"`
+	Approvals      int `json:"approvals,omitempty"`
+	ChangeRequests int `json:"change_requests,omitempty"`
+	// SilentApprovals are approvals with neither a comment nor a word in the
+	// review body.
+	SilentApprovals int `json:"silent_approvals,omitempty"`
+	// Requested counts reviews the Keeper was asked for, Quick those answered
+	// within the quick_review_hours setting, and LatencyMinutes the median
+	// wait.
+	Requested      int `json:"requested,omitempty"`
+	Quick          int `json:"quick,omitempty"`
+	LatencyMinutes int `json:"latency_minutes,omitempty"`
+}
+
+// SummaryWindow is how far back a rolling week reaches.
+const SummaryWindow = 7 * 24 * time.Hour
+
+// Summarize counts what events since cutoff did.
+func Summarize(events []Event, cutoff time.Time) ActivitySummary {
+	summary := ActivitySummary{}
+	for _, event := range events {
+		if event.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if IsWeekend(event.CreatedAt) {
+			summary.WeekendEvents++
+		} else {
+			summary.WeekdayEvents++
+		}
+		switch event.Type {
+		case "PushEvent":
+			if IsLateNight(event.CreatedAt) {
+				summary.LateNightPushes++
+			}
+			var payload PushPayload
+			if json.Unmarshal(event.Payload, &payload) == nil {
+				summary.Commits += len(payload.Commits)
+				for _, commit := range payload.Commits {
+					ClassifyCommit(commit.Message, &summary)
+				}
+			}
+		case "PullRequestEvent":
+			var payload PullRequestPayload
+			if json.Unmarshal(event.Payload, &payload) == nil && payload.PullRequest.Merged {
+				summary.MergedPRs++
+			}
+		case "PullRequestReviewEvent":
+			summary.Reviews++
+		case "PullRequestReviewCommentEvent":
+			summary.Reviews++
+			summary.DocComments++
+		case "IssueCommentEvent":
+			summary.DocComments++
+			summary.IssueComments++
+		case "IssuesEvent":
+			var payload IssuesPayload
+			if json.Unmarshal(event.Payload, &payload) == nil {
+				switch payload.Action {
+				case "opened", "reopened":
+					summary.IssuesOpened++
+				case "closed":
+					summary.IssuesClosed++
+				case "labeled":
+					summary.IssuesLabeled++
+				}
+			}
+		case "DiscussionEvent":
+			summary.Discussions++
+		case "DiscussionCommentEvent":
+			summary.DiscussionComments++
+		case "SponsorshipEvent":
+			var payload SponsorshipPayload
+			if json.Unmarshal(event.Payload, &payload) == nil && payload.Action == "created" {
+				summary.Sponsorships++
+			}
+		case "CreateEvent":
+			var payload CreatePayload
+			if json.Unmarshal(event.Payload, &payload) == nil && payload.RefType == "repository" {
+				summary.NewRepos++
+			}
+		}
+	}
+	return summary
+}
+
+// ClassifyCommit counts what kind of work a commit message describes.
+func ClassifyCommit(message string, summary *ActivitySummary) {
+	lower := strings.ToLower(message)
+	if strings.Contains(lower, "fix") || strings.Contains(lower, "bug") {
+		summary.FixCommits++
+	}
+	if strings.Contains(lower, "doc") || strings.Contains(lower, "readme") || strings.Contains(lower, "comment") {
+		summary.DocCommits++
+	}
+	if strings.Contains(lower, "refactor") || strings.Contains(lower, "cleanup") || strings.Contains(lower, "remove") || strings.Contains(lower, "delete") {
+		summary.RefactorCommits++
+	}
+	if IsTestMessage(message) {
+		summary.TestCommits++
+	}
+	summary.AddCoAuthors(CoAuthors(message))
+}
+
+// IssueTriage counts issue housekeeping; comments are already credited as
+// DocComments so they are left out to avoid double-counting activity.
+func IssueTriage(summary ActivitySummary) int {
+	return summary.IssuesOpened + summary.IssuesClosed + summary.IssuesLabeled
+}
+
+// CommunityWork counts non-code contributions that still nourish the pet.
+func CommunityWork(summary ActivitySummary) int {
+	return summary.Discussions + summary.DiscussionComments + summary.Sponsorships
+}
+
+// testMessagePattern matches test words whole, so "latest", "inspect", and
+// "respect" aren't mistaken for them.
+var testMessagePattern = regexp.MustCompile(`(?i)\b(test(s|ed|ing)?|specs?|coverage)\b`)
+
+// IsTestMessage reports whether a commit message says it's about tests.
+func IsTestMessage(message string) bool {
+	return testMessagePattern.MatchString(message)
+}
+
+// maxCoAuthors caps the names a summary remembers, so a busy mob-programming
+// week doesn't bloat the state file.
+const maxCoAuthors = 10
+
+// CoAuthors are the people a commit message credits in Co-authored-by
+// trailers, by name, leaving out bots such as dependabot[bot].
+func CoAuthors(message string) []string {
+	var names []string
+	for _, line := range strings.Split(message, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "co-authored-by") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		name := value
+		if addr, err := mail.ParseAddress(value); err == nil {
+			name = addr.Name
+			if name == "" {
+				name, _, _ = strings.Cut(addr.Address, "@")
+			}
+		} else if at := strings.Index(value, "<"); at > 0 {
+			name = strings.TrimSpace(value[:at])
+		}
+		if name == "" || IsBot(name) || containsFold(names, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// AddCoAuthors counts a commit written with names as a duet and remembers
+// who joined in.
+func (s *ActivitySummary) AddCoAuthors(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	s.DuetCommits++
+	for _, name := range names {
+		if len(s.CoAuthors) < maxCoAuthors && !containsFold(s.CoAuthors, name) {
+			s.CoAuthors = append(s.CoAuthors, name)
+		}
+	}
+}
+
+// knownBots are automation accounts that don't always carry the "[bot]"
+// suffix, and the names CI commits under.
+var knownBots = []string{
+	"dependabot", "dependabot-preview", "renovate", "renovate-bot", "greenkeeper", "snyk-bot",
+	"github-actions", "github action", "github actions", "github-merge-queue", "mergify",
+	"pre-commit-ci", "imgbot", "allcontributors", "semantic-release-bot", "codecov",
+}
+
+// IsBot reports whether name, a login or a commit author, is automation:
+// one of knownBots or anything ending in "[bot]".
+func IsBot(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if strings.HasSuffix(name, "[bot]") {
+		return true
+	}
+	for _, bot := range knownBots {
+		if name == bot {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Negative reports whether any count a plugin may add is below zero.
+func (s ActivitySummary) Negative() bool {
+	for _, n := range []int{s.Commits, s.MergedPRs, s.Reviews, s.DocComments, s.RefactorCommits, s.NewRepos, s.Thoughts,
+		s.FixCommits, s.DocCommits, s.TestCommits, s.IssuesOpened, s.IssuesClosed, s.IssuesLabeled, s.IssueComments,
+		s.Discussions, s.DiscussionComments, s.Sponsorships} {
+		if n < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AddPlugin folds a plugin's counts into summary. The rest of its fields,
+// such as languages or the time of day, are GitHub's to say.
+func (s *ActivitySummary) AddPlugin(p ActivitySummary) {
+	s.Commits += p.Commits
+	s.MergedPRs += p.MergedPRs
+	s.Reviews += p.Reviews
+	s.DocComments += p.DocComments
+	s.RefactorCommits += p.RefactorCommits
+	s.NewRepos += p.NewRepos
+	s.Thoughts += p.Thoughts
+	s.FixCommits += p.FixCommits
+	s.DocCommits += p.DocCommits
+	s.TestCommits += p.TestCommits
+	s.IssuesOpened += p.IssuesOpened
+	s.IssuesClosed += p.IssuesClosed
+	s.IssuesLabeled += p.IssuesLabeled
+	s.IssueComments += p.IssueComments
+	s.Discussions += p.Discussions
+	s.DiscussionComments += p.DiscussionComments
+	s.Sponsorships += p.Sponsorships
+}
+
+// AddPrivate folds private activity into summary and notes how much of it
+// there was.
+func (s *ActivitySummary) AddPrivate(p ActivitySummary) {
+	s.Commits += p.Commits
+	s.MergedPRs += p.MergedPRs
+	s.Reviews += p.Reviews
+	s.IssuesOpened += p.IssuesOpened
+	s.IssueComments += p.IssueComments
+	s.DocComments += p.DocComments
+	s.Private += p.Commits + p.MergedPRs + p.Reviews + p.IssuesOpened + p.IssueComments
+}
+
+// Pushes between lateNightStart and lateNightEnd (local time) count as
+// late-night work.
+const (
+	lateNightStart = 23
+	lateNightEnd   = 5
+)
+
+func IsLateNight(t time.Time) bool {
+	hour := t.Local().Hour()
+	return hour >= lateNightStart || hour < lateNightEnd
+}
+
+// IsWeekend reports whether t falls on a Saturday or Sunday, local time.
+func IsWeekend(t time.Time) bool {
+	wd := t.Local().Weekday()
+	return wd == time.Saturday || wd == time.Sunday
+}
@@ -0,0 +1,57 @@
+package pet
+
+import "testing"
+
+func TestIsTestMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"test: cover the parser", true},
+		{"Add tests for login", true},
+		{"Fix flaky test", true},
+		{"Raise coverage to 80%", true},
+		{"Update the spec for retries", true},
+		{"Tested against Go 1.23", true},
+		{"feat(testing): run in parallel", true},
+		{"Bump to the latest release", false},
+		{"Verify build attestation", false},
+		{"Inspect headers before sending", false},
+		{"Handle special characters", false},
+		{"Respect the rate limit", false},
+		{"Contest the protest", false},
+	}
+	for _, tt := range tests {
+		if got := IsTestMessage(tt.message); got != tt.want {
+			t.Errorf("IsTestMessage(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestCoAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"none", "Fix the parser", nil},
+		{"one", "Fix the parser\n\nCo-authored-by: Ana Lima <ana@example.com>", []string{"Ana Lima"}},
+		{"any case", "Pair on it\n\nco-authored-by: Bo <bo@example.com>", []string{"Bo"}},
+		{"email only", "Pair on it\n\nCo-authored-by: <cy@example.com>", []string{"cy"}},
+		{"repeated", "Pair on it\n\nCo-authored-by: Bo <bo@example.com>\nCo-Authored-By: bo <bo@example.org>", []string{"Bo"}},
+		{"bots left out", "Bump deps\n\nCo-authored-by: dependabot[bot] <support@github.com>\nCo-authored-by: renovate <bot@renovateapp.com>", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CoAuthors(tt.message)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CoAuthors() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("CoAuthors()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
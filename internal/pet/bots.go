@@ -0,0 +1,122 @@
+package pet
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// BotConfig lists automation that should count as the Keeper's own work.
+type BotConfig struct {
+	// Allow names bot accounts or commit authors, such as "my-release-bot",
+	// to keep; "[bot]" may be left off.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// ciEmails are the addresses CI tools author commits as.
+var ciEmails = []string{"action@github.com", "actions@github.com", "noreply@renovatebot.com"}
+
+// mergeQueueRef is where GitHub's merge queue builds its merge commits.
+const mergeQueueRef = "refs/heads/gh-readonly-queue/"
+
+type botCommit struct {
+	Author struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"author"`
+}
+
+type botPullRequest struct {
+	PullRequest struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+}
+
+// isBot reports whether name, a login or a commit author, is automation
+// that isn't allowed.
+func (b BotConfig) isBot(name string) bool {
+	return !b.isAllowed(name) && IsBot(name)
+}
+
+func (b BotConfig) isAllowed(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), "[bot]")
+	for _, allowed := range b.Allow {
+		if strings.TrimSuffix(strings.ToLower(allowed), "[bot]") == name {
+			return true
+		}
+	}
+	return false
+}
+
+// botAuthored reports whether a pushed commit was written by automation,
+// going by its author's name and email.
+func (b BotConfig) botAuthored(c botCommit) bool {
+	if b.isAllowed(c.Author.Name) {
+		return false
+	}
+	email := strings.ToLower(c.Author.Email)
+	for _, ci := range ciEmails {
+		if email == ci {
+			return true
+		}
+	}
+	// Bot noreply addresses read like 49699333+dependabot[bot]@users.noreply.github.com.
+	local, _, _ := strings.Cut(email, "@")
+	if _, login, ok := strings.Cut(local, "+"); ok {
+		local = login
+	}
+	return b.isBot(c.Author.Name) || strings.HasSuffix(local, "[bot]") && b.isBot(local)
+}
+
+// HumanEvents drops what automation did from events, so stats and evolution
+// follow human work: pull requests opened by bots, such as Dependabot's, the
+// merge queue's pushes, and commits authored by bots or CI. Pushes keep their
+// other commits.
+func (b BotConfig) HumanEvents(events []Event) []Event {
+	var kept []Event
+	for _, event := range events {
+		switch event.Type {
+		case "PullRequestEvent":
+			// Reviewing a bot's pull request is still the Keeper's work, so
+			// only merging one is dropped.
+			var payload botPullRequest
+			if json.Unmarshal(event.Payload, &payload) == nil && b.isBot(payload.PullRequest.User.Login) {
+				continue
+			}
+		case "PushEvent":
+			var payload map[string]json.RawMessage
+			if json.Unmarshal(event.Payload, &payload) != nil {
+				break
+			}
+			var ref string
+			json.Unmarshal(payload["ref"], &ref)
+			if strings.HasPrefix(ref, mergeQueueRef) {
+				continue
+			}
+			var commits []json.RawMessage
+			if json.Unmarshal(payload["commits"], &commits) != nil {
+				break
+			}
+			human := commits[:0:0]
+			for _, raw := range commits {
+				var c botCommit
+				if json.Unmarshal(raw, &c) == nil && b.botAuthored(c) {
+					continue
+				}
+				human = append(human, raw)
+			}
+			if len(human) == len(commits) {
+				break
+			}
+			if len(human) == 0 {
+				continue
+			}
+			payload["commits"], _ = json.Marshal(human)
+			payload["size"], _ = json.Marshal(len(human))
+			event.Payload, _ = json.Marshal(payload)
+		}
+		kept = append(kept, event)
+	}
+	return kept
+}
@@ -0,0 +1,32 @@
+package pet
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHumanEvents(t *testing.T) {
+	events := []Event{
+		{Type: "PushEvent", Payload: json.RawMessage(`{"ref":"refs/heads/main","commits":[
+			{"sha":"a","message":"fix parser","author":{"name":"Keeper","email":"keeper@example.com"}},
+			{"sha":"b","message":"bump deps","author":{"name":"dependabot[bot]","email":"49699333+dependabot[bot]@users.noreply.github.com"}},
+			{"sha":"c","message":"release","author":{"name":"my-release-bot","email":"action@github.com"}}]}`)},
+		{Type: "PushEvent", Payload: json.RawMessage(`{"ref":"refs/heads/gh-readonly-queue/main/pr-1","commits":[{"sha":"d","message":"merge"}]}`)},
+		{Type: "PullRequestEvent", Payload: json.RawMessage(`{"action":"closed","pull_request":{"merged":true,"user":{"login":"renovate[bot]"}}}`)},
+		{Type: "PullRequestReviewEvent", Payload: json.RawMessage(`{}`)},
+	}
+
+	kept := BotConfig{}.HumanEvents(events)
+	if len(kept) != 2 {
+		t.Fatalf("kept %d events, want the push and the review", len(kept))
+	}
+	var push PushPayload
+	if err := json.Unmarshal(kept[0].Payload, &push); err != nil || len(push.Commits) != 1 || push.Commits[0].SHA != "a" {
+		t.Errorf("push kept commits %+v, want only the Keeper's", push.Commits)
+	}
+
+	allowed := BotConfig{Allow: []string{"my-release-bot"}}.HumanEvents(events)
+	if err := json.Unmarshal(allowed[0].Payload, &push); err != nil || len(push.Commits) != 2 {
+		t.Errorf("with my-release-bot allowed, push kept %+v", push.Commits)
+	}
+}
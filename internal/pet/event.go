@@ -0,0 +1,67 @@
+package pet
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is one entry of a GitHub events feed.
+type Event struct {
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Repo      EventRepo       `json:"repo"`
+	Payload   json.RawMessage `json:"payload"`
+	// Public is false for events in private repos.
+	Public bool `json:"public"`
+}
+
+type EventRepo struct {
+	Name string `json:"name"`
+}
+
+type PushPayload struct {
+	Ref     string `json:"ref"`
+	Size    int    `json:"size"`
+	Commits []struct {
+		SHA     string `json:"sha"`
+		Message string `json:"message"`
+	} `json:"commits"`
+	// Before is the branch's head before the push. The events API names
+	// the new head Head; webhooks name it After and set Forced.
+	Before string `json:"before"`
+	Head   string `json:"head"`
+	After  string `json:"after"`
+	Forced bool   `json:"forced"`
+}
+
+// NewHead is the branch's head after the push.
+func (p PushPayload) NewHead() string {
+	if p.Head != "" {
+		return p.Head
+	}
+	return p.After
+}
+
+type PullRequestPayload struct {
+	PullRequest struct {
+		Merged bool `json:"merged"`
+	} `json:"pull_request"`
+}
+
+type PullRequestReviewPayload struct {
+	Review struct {
+		ID int64 `json:"id"`
+	} `json:"review"`
+}
+
+type CreatePayload struct {
+	RefType string `json:"ref_type"`
+}
+
+type IssuesPayload struct {
+	Action string `json:"action"`
+}
+
+type SponsorshipPayload struct {
+	Action string `json:"action"`
+}
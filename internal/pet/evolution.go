@@ -0,0 +1,54 @@
+package pet
+
+// Activity counts everything in a summary that feeds the pet; a week where
+// it's zero leaves the pet Lonely.
+func Activity(s ActivitySummary) int {
+	return s.Commits + s.MergedPRs + s.Reviews + s.DocComments + s.RefactorCommits + s.NewRepos + IssueTriage(s) + CommunityWork(s)
+}
+
+// EvolutionScore is how strongly a week leans toward one evolution, and what
+// it was made of.
+type EvolutionScore struct {
+	Evolution string         `json:"evolution"`
+	Score     int            `json:"score"`
+	Parts     []Contribution `json:"parts,omitempty"`
+}
+
+// EvolutionScores is how strongly a week's activity leans toward each
+// evolution, in tie-break order.
+func EvolutionScores(s ActivitySummary) []EvolutionScore {
+	score := func(evolution string, parts ...Contribution) EvolutionScore {
+		var kept []Contribution
+		for _, p := range parts {
+			if p.Points != 0 {
+				kept = append(kept, p)
+			}
+		}
+		return EvolutionScore{Evolution: evolution, Score: Total(parts), Parts: kept}
+	}
+	return []EvolutionScore{
+		score("Pioneer", Part("", "commits", s.Commits, 1), Part("", "new repos", s.NewRepos, 2)),
+		score("Guardian", Part("", "reviews", s.Reviews, 2), Part("", "merged pull requests", s.MergedPRs, 2), Part("", "fix commits", s.FixCommits, 1)),
+		score("Bard", Part("", "docs and comments", s.DocComments, 2), Part("", "doc commits", s.DocCommits, 1)),
+		score("Void", Part("", "refactor commits", s.RefactorCommits, 2)),
+		score("Sentinel", Part("", "test commits", s.TestCommits, 3)),
+		score("Curator", Part("", "opened issues", s.IssuesOpened, 1), Part("", "closed issues", s.IssuesClosed, 2),
+			Part("", "labeled issues", s.IssuesLabeled, 2), Part("", "issue comments", s.IssueComments, 1)),
+	}
+}
+
+// EvolutionFor is the form a week's activity gives the pet: the highest of
+// EvolutionScores, ties going to the one listed first, or Lonely when there
+// was no activity at all.
+func EvolutionFor(s ActivitySummary) string {
+	if Activity(s) == 0 {
+		return "Lonely"
+	}
+	best := EvolutionScore{Evolution: "Pioneer", Score: -1}
+	for _, e := range EvolutionScores(s) {
+		if e.Score > best.Score {
+			best = e
+		}
+	}
+	return best.Evolution
+}
@@ -0,0 +1,24 @@
+package pet
+
+import "testing"
+
+func TestEvolutionFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary ActivitySummary
+		want    string
+	}{
+		{name: "quiet week", want: "Lonely"},
+		{name: "community work only", summary: ActivitySummary{Discussions: 2}, want: "Pioneer"},
+		{name: "commits", summary: ActivitySummary{Commits: 5, NewRepos: 1}, want: "Pioneer"},
+		{name: "reviews", summary: ActivitySummary{Commits: 3, Reviews: 2}, want: "Guardian"},
+		{name: "tests", summary: ActivitySummary{Commits: 3, TestCommits: 2}, want: "Sentinel"},
+		{name: "issues", summary: ActivitySummary{IssuesClosed: 2, IssuesLabeled: 1}, want: "Curator"},
+		{name: "tie goes first", summary: ActivitySummary{Commits: 2, Reviews: 1}, want: "Pioneer"},
+	}
+	for _, tt := range tests {
+		if got := EvolutionFor(tt.summary); got != tt.want {
+			t.Errorf("%s: EvolutionFor = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
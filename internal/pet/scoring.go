@@ -0,0 +1,402 @@
+// Package pet turns GitHub activity into a pet's stats. The gh pet CLI,
+// the MCP server, and the Vercel handler all score with it, so a week of
+// work earns the same wherever the pet is fed.
+package pet
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ScoringConfig holds the weights that turn activity into pet stats. Teams
+// can override any of them under "scoring" in the config file to reward the
+// behavior they care about.
+type ScoringConfig struct {
+	CommitLogic   int `json:"commit_logic"`
+	MergedPRLogic int `json:"merged_pr_logic"`
+	TestLogic     int `json:"test_logic"`
+	// GreatMessageLogic is the bonus for a commit message that passes every
+	// check in the commit-msg hook.
+	GreatMessageLogic int `json:"great_message_logic"`
+
+	ReviewKindness       int `json:"review_kindness"`
+	IssueClosedKindness  int `json:"issue_closed_kindness"`
+	IssueCommentKindness int `json:"issue_comment_kindness"`
+	CommunityKindness    int `json:"community_kindness"`
+	SponsorshipKindness  int `json:"sponsorship_kindness"`
+	// DuetKindness is earned per commit with a Co-authored-by trailer.
+	DuetKindness int `json:"duet_kindness"`
+
+	CommitMood     int `json:"commit_mood"`
+	MergedPRMood   int `json:"merged_pr_mood"`
+	ReviewMood     int `json:"review_mood"`
+	DocCommentMood int `json:"doc_comment_mood"`
+	IssueMood      int `json:"issue_mood"`
+	ThoughtMood    int `json:"thought_mood"`
+	PostCommitMood int `json:"post_commit_mood"`
+	IdleMoodDecay  int `json:"idle_mood_decay"`
+	// FocusMood is earned per completed pomodoro in gh pet focus and gh pet
+	// pomodoro; PomodoroLogic per session the pet watched to the end.
+	FocusMood     int `json:"focus_mood"`
+	PomodoroLogic int `json:"pomodoro_logic"`
+	// BugHuntLogic is earned per gh pet play bughunt won; GameLogicPerDay
+	// caps what games earn in a day.
+	BugHuntLogic    int `json:"bughunt_logic"`
+	GameLogicPerDay int `json:"game_logic_per_day"`
+	// GoalMood is earned per goal met, when its day, week, or month ends.
+	GoalMood int `json:"goal_mood"`
+	// HelpKindness is earned per request answered within the maintainer SLA.
+	HelpKindness int `json:"help_kindness"`
+	// QueueKindness is earned for clearing the review requests waiting on
+	// you within a day.
+	QueueKindness int `json:"queue_kindness"`
+	// FirstTimerKindness is earned per pull request from a first-time
+	// contributor you reviewed or commented on.
+	FirstTimerKindness int `json:"first_timer_kindness"`
+	// ChecklistKindness is earned per item ticked off a pull request's
+	// review checklist from the MCP server's pet_pr_checklist.
+	ChecklistKindness int `json:"checklist_kindness"`
+	// RedBuildMood is held back per red build on your branches until it is
+	// fixed; FirefighterMood is the bonus for each one you fix.
+	RedBuildMood    int `json:"red_build_mood"`
+	FirefighterMood int `json:"firefighter_mood"`
+	// CompanionLogic is earned per feed for each fork companion that counts
+	// toward the bonus; see Apply.
+	CompanionLogic int `json:"companion_logic"`
+
+	// Mentor is earned by reviewing well rather than often: per inline
+	// review comment, per review asking for changes, per approval that says
+	// something, and per requested review answered within QuickReviewHours.
+	ReviewCommentMentor int `json:"review_comment_mentor"`
+	ChangeRequestMentor int `json:"change_request_mentor"`
+	ApprovalMentor      int `json:"approval_mentor"`
+	QuickReviewMentor   int `json:"quick_review_mentor"`
+	QuickReviewHours    int `json:"quick_review_hours"`
+
+	// HourlyCommits is how many commits pushed in one hour count in full.
+	// Past it, returns diminish: only the 1st, 2nd, 4th, 8th… extra commit
+	// counts. 0 counts them all.
+	HourlyCommits int `json:"hourly_commits"`
+	// MaxFeedMood caps the mood one feed can add. 0 leaves it uncapped.
+	MaxFeedMood int `json:"max_feed_mood"`
+	// ThrowawayBranches are branch patterns, as path.Match reads them, whose
+	// commits earn nothing.
+	ThrowawayBranches []string `json:"throwaway_branches"`
+}
+
+func DefaultScoring() ScoringConfig {
+	return ScoringConfig{
+		CommitLogic:       1,
+		MergedPRLogic:     3,
+		TestLogic:         1,
+		GreatMessageLogic: 2,
+
+		ReviewKindness:       2,
+		IssueClosedKindness:  1,
+		IssueCommentKindness: 1,
+		CommunityKindness:    1,
+		SponsorshipKindness:  5,
+		DuetKindness:         1,
+		FirstTimerKindness:   3,
+		ChecklistKindness:    1,
+
+		CommitMood:     1,
+		MergedPRMood:   5,
+		ReviewMood:     1,
+		DocCommentMood: 1,
+		IssueMood:      1,
+		ThoughtMood:    1,
+		PostCommitMood: 3,
+		IdleMoodDecay:  1,
+		FocusMood:      1,
+		PomodoroLogic:  1,
+		BugHuntLogic:   2,
+		GoalMood:       3,
+		HelpKindness:   2,
+		QueueKindness:  3,
+
+		RedBuildMood:    5,
+		FirefighterMood: 3,
+		CompanionLogic:  1,
+
+		ReviewCommentMentor: 1,
+		ChangeRequestMentor: 2,
+		ApprovalMentor:      1,
+		QuickReviewMentor:   2,
+		QuickReviewHours:    24,
+
+		GameLogicPerDay:   6,
+		HourlyCommits:     5,
+		MaxFeedMood:       20,
+		ThrowawayBranches: []string{"tmp/*", "temp/*", "wip/*", "scratch/*", "throwaway/*", "backup/*"},
+	}
+}
+
+const MaxWeight = 100
+
+func (c ScoringConfig) Validate() error {
+	weights := map[string]int{
+		"commit_logic":           c.CommitLogic,
+		"merged_pr_logic":        c.MergedPRLogic,
+		"test_logic":             c.TestLogic,
+		"great_message_logic":    c.GreatMessageLogic,
+		"review_kindness":        c.ReviewKindness,
+		"issue_closed_kindness":  c.IssueClosedKindness,
+		"issue_comment_kindness": c.IssueCommentKindness,
+		"community_kindness":     c.CommunityKindness,
+		"sponsorship_kindness":   c.SponsorshipKindness,
+		"duet_kindness":          c.DuetKindness,
+		"commit_mood":            c.CommitMood,
+		"merged_pr_mood":         c.MergedPRMood,
+		"review_mood":            c.ReviewMood,
+		"doc_comment_mood":       c.DocCommentMood,
+		"issue_mood":             c.IssueMood,
+		"thought_mood":           c.ThoughtMood,
+		"post_commit_mood":       c.PostCommitMood,
+		"idle_mood_decay":        c.IdleMoodDecay,
+		"focus_mood":             c.FocusMood,
+		"pomodoro_logic":         c.PomodoroLogic,
+		"bughunt_logic":          c.BugHuntLogic,
+		"game_logic_per_day":     c.GameLogicPerDay,
+		"goal_mood":              c.GoalMood,
+		"queue_kindness":         c.QueueKindness,
+		"first_timer_kindness":   c.FirstTimerKindness,
+		"checklist_kindness":     c.ChecklistKindness,
+		"help_kindness":          c.HelpKindness,
+		"red_build_mood":         c.RedBuildMood,
+		"firefighter_mood":       c.FirefighterMood,
+		"companion_logic":        c.CompanionLogic,
+		"hourly_commits":         c.HourlyCommits,
+		"max_feed_mood":          c.MaxFeedMood,
+		"review_comment_mentor":  c.ReviewCommentMentor,
+		"change_request_mentor":  c.ChangeRequestMentor,
+		"approval_mentor":        c.ApprovalMentor,
+		"quick_review_mentor":    c.QuickReviewMentor,
+	}
+	for name, weight := range weights {
+		if weight < 0 || weight > MaxWeight {
+			return fmt.Errorf("scoring.%s must be between 0 and %d, got %d", name, MaxWeight, weight)
+		}
+	}
+	if c.QuickReviewHours < 1 || c.QuickReviewHours > 168 {
+		return fmt.Errorf("scoring.quick_review_hours must be between 1 and 168, got %d", c.QuickReviewHours)
+	}
+	for _, pattern := range c.ThrowawayBranches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("scoring.throwaway_branches: bad pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// Contribution is one line of a score breakdown: Count of something at
+// Weight points each, or a lump of Points, such as a cap, with no weight.
+type Contribution struct {
+	// Stat is mood, kindness, logic, or mentor; evolution scores leave it
+	// out.
+	Stat   string `json:"stat,omitempty"`
+	Source string `json:"source"`
+	Count  int    `json:"count,omitempty"`
+	Weight int    `json:"weight,omitempty"`
+	Points int    `json:"points"`
+}
+
+func Part(stat, source string, count, weight int) Contribution {
+	return Contribution{Stat: stat, Source: source, Count: count, Weight: weight, Points: count * weight}
+}
+
+func Total(parts []Contribution) int {
+	sum := 0
+	for _, p := range parts {
+		sum += p.Points
+	}
+	return sum
+}
+
+func (c ScoringConfig) LogicParts(s ActivitySummary) []Contribution {
+	return []Contribution{
+		Part("logic", "commits", s.Commits, c.CommitLogic),
+		Part("logic", "merged pull requests", s.MergedPRs, c.MergedPRLogic),
+	}
+}
+
+func (c ScoringConfig) KindnessParts(s ActivitySummary) []Contribution {
+	return []Contribution{
+		Part("kindness", "reviews", s.Reviews, c.ReviewKindness),
+		Part("kindness", "closed issues", s.IssuesClosed, c.IssueClosedKindness),
+		Part("kindness", "issue comments", s.IssueComments, c.IssueCommentKindness),
+		Part("kindness", "discussions", s.Discussions+s.DiscussionComments, c.CommunityKindness),
+		Part("kindness", "sponsorships", s.Sponsorships, c.SponsorshipKindness),
+		Part("kindness", "co-authored commits", s.DuetCommits, c.DuetKindness),
+		Part("kindness", "first-time contributors helped", s.FirstTimers, c.FirstTimerKindness),
+	}
+}
+
+func (c ScoringConfig) MentorParts(s ActivitySummary) []Contribution {
+	d := s.ReviewDepth
+	return []Contribution{
+		Part("mentor", "review comments", d.Comments, c.ReviewCommentMentor),
+		Part("mentor", "change requests", d.ChangeRequests, c.ChangeRequestMentor),
+		Part("mentor", "approvals with feedback", d.Approvals-d.SilentApprovals, c.ApprovalMentor),
+		Part("mentor", "quick reviews", d.Quick, c.QuickReviewMentor),
+	}
+}
+
+func (c ScoringConfig) MoodParts(s ActivitySummary) []Contribution {
+	return []Contribution{
+		Part("mood", "commits", s.Commits, c.CommitMood),
+		Part("mood", "merged pull requests", s.MergedPRs, c.MergedPRMood),
+		Part("mood", "reviews", s.Reviews, c.ReviewMood),
+		Part("mood", "docs and comments", s.DocComments, c.DocCommentMood),
+		Part("mood", "issue triage", IssueTriage(s), c.IssueMood),
+	}
+}
+
+func (c ScoringConfig) LogicFor(s ActivitySummary) int {
+	return Total(c.LogicParts(s))
+}
+
+func (c ScoringConfig) KindnessFor(s ActivitySummary) int {
+	return Total(c.KindnessParts(s))
+}
+
+func (c ScoringConfig) MentorFor(s ActivitySummary) int {
+	return Total(c.MentorParts(s))
+}
+
+func (c ScoringConfig) MoodGainFor(s ActivitySummary) int {
+	return Total(c.MoodParts(s))
+}
+
+// Stats are the scores activity moves.
+type Stats struct {
+	Mood     int
+	Kindness int
+	Logic    int
+	Mentor   int
+}
+
+// Apply folds a freshly synced summary into stats, for a pet followed by
+// companions fork companions that earn their bonus.
+func (c ScoringConfig) Apply(stats *Stats, summary ActivitySummary, companions int) {
+	stats.Logic += c.LogicFor(summary) + c.CompanionLogic*companions
+	stats.Kindness += c.KindnessFor(summary)
+	stats.Mentor += c.MentorFor(summary)
+	gain := 0
+	if Activity(summary) == 0 {
+		stats.Mood = max(0, stats.Mood-c.IdleMoodDecay)
+	} else {
+		gain = c.MoodGainFor(summary)
+	}
+	if summary.Thoughts > 0 {
+		gain += c.ThoughtMood
+	}
+	if c.MaxFeedMood > 0 {
+		gain = min(gain, c.MaxFeedMood)
+	}
+	stats.Mood = min(100, stats.Mood+gain)
+}
+
+// DiscountCommits takes the commits that shouldn't earn anything out of
+// summary, which Summarize made from events since cutoff: repeats of a
+// commit already counted, such as one rewritten by a rebase and
+// force-pushed again; commits to throwaway branches; and those past the
+// hourly allowance.
+func (c ScoringConfig) DiscountCommits(events []Event, summary ActivitySummary, cutoff time.Time) ActivitySummary {
+	seen := map[string]bool{}
+	rewritable := map[string]bool{}
+	heads := map[string]string{}
+	perHour := map[time.Time]int{}
+	var dropped ActivitySummary
+	// Events come newest first; going oldest first credits the original push
+	// rather than its repeat.
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if event.Type != "PushEvent" || event.CreatedAt.Before(cutoff) {
+			continue
+		}
+		var payload PushPayload
+		if json.Unmarshal(event.Payload, &payload) != nil {
+			continue
+		}
+		throwaway := c.throwaway(payload.Ref)
+		branch := event.Repo.Name + "\x00" + payload.Ref
+		// A push that doesn't start where the branch's last push ended
+		// rewrote its history.
+		last, pushed := heads[branch]
+		forced := payload.Forced || pushed && payload.Before != "" && payload.Before != last
+		heads[branch] = payload.NewHead()
+		for _, commit := range payload.Commits {
+			// A rebased commit gets a new SHA but keeps its message, so
+			// the message only marks a repeat when history was rewritten.
+			message := event.Repo.Name + "\x00" + strings.TrimSpace(commit.Message)
+			counts := !throwaway && !seen[commit.SHA] && !(forced && rewritable[message])
+			if counts {
+				hour := event.CreatedAt.Truncate(time.Hour)
+				perHour[hour]++
+				counts = c.withinHour(perHour[hour])
+			}
+			// A commit on a throwaway branch may still count once it lands
+			// somewhere real.
+			if !throwaway {
+				if commit.SHA != "" {
+					seen[commit.SHA] = true
+				}
+				rewritable[message] = true
+			}
+			if !counts {
+				dropped.Commits++
+				ClassifyCommit(commit.Message, &dropped)
+			}
+		}
+	}
+	summary.Commits -= dropped.Commits
+	summary.FixCommits -= dropped.FixCommits
+	summary.DocCommits -= dropped.DocCommits
+	summary.RefactorCommits -= dropped.RefactorCommits
+	summary.TestCommits -= dropped.TestCommits
+	summary.DuetCommits -= dropped.DuetCommits
+	summary.IgnoredCommits += dropped.Commits
+	return summary
+}
+
+// DiscountReviews takes the reviews in credited, by ID, out of summary,
+// which Summarize made from events since cutoff: they earned their kindness
+// when they were submitted.
+func DiscountReviews(events []Event, summary ActivitySummary, credited []int64, cutoff time.Time) ActivitySummary {
+	if len(credited) == 0 {
+		return summary
+	}
+	for _, event := range events {
+		if event.Type != "PullRequestReviewEvent" || event.CreatedAt.Before(cutoff) {
+			continue
+		}
+		var payload PullRequestReviewPayload
+		if json.Unmarshal(event.Payload, &payload) == nil && slices.Contains(credited, payload.Review.ID) {
+			summary.Reviews--
+		}
+	}
+	return summary
+}
+
+// throwaway reports whether ref, such as refs/heads/tmp/try, is a branch
+// whose commits earn nothing.
+func (c ScoringConfig) throwaway(ref string) bool {
+	branch := strings.TrimPrefix(ref, "refs/heads/")
+	for _, pattern := range c.ThrowawayBranches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// withinHour reports whether the nth commit in an hour still counts.
+func (c ScoringConfig) withinHour(n int) bool {
+	extra := n - c.HourlyCommits
+	return c.HourlyCommits == 0 || extra <= 0 || extra&(extra-1) == 0
+}
@@ -0,0 +1,266 @@
+package pet
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testCommit struct{ sha, message string }
+
+func pushEvent(t *testing.T, at time.Time, repo, ref string, commits ...testCommit) Event {
+	t.Helper()
+	var payload PushPayload
+	payload.Ref = ref
+	payload.Size = len(commits)
+	for _, c := range commits {
+		payload.Commits = append(payload.Commits, struct {
+			SHA     string `json:"sha"`
+			Message string `json:"message"`
+		}{c.sha, c.message})
+	}
+	if len(commits) > 0 {
+		payload.Head = commits[len(commits)-1].sha
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Event{Type: "PushEvent", CreatedAt: at, Repo: EventRepo{Name: repo}, Payload: raw}
+}
+
+// editPush changes the payload of event, a push.
+func editPush(t *testing.T, event Event, edit func(*PushPayload)) Event {
+	t.Helper()
+	var payload PushPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	edit(&payload)
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.Payload = raw
+	return event
+}
+
+// after sets the head the branch had before event's push.
+func after(t *testing.T, before string, event Event) Event {
+	t.Helper()
+	return editPush(t, event, func(p *PushPayload) { p.Before = before })
+}
+
+// forced marks event's push as a webhook marks a force push.
+func forced(t *testing.T, event Event) Event {
+	t.Helper()
+	return editPush(t, event, func(p *PushPayload) { p.Forced = true })
+}
+
+func numbered(n int, prefix string) []testCommit {
+	commits := make([]testCommit, n)
+	for i := range commits {
+		commits[i] = testCommit{fmt.Sprintf("%s%d", prefix, i), fmt.Sprintf("%s %d", prefix, i)}
+	}
+	return commits
+}
+
+// scored summarizes a rolling week of events and discounts its commits.
+func scored(c ScoringConfig, events []Event) ActivitySummary {
+	cutoff := time.Now().Add(-SummaryWindow)
+	return c.DiscountCommits(events, Summarize(events, cutoff), cutoff)
+}
+
+func TestDiminishingReturnsPerHour(t *testing.T) {
+	hour := time.Now().Add(-2 * time.Hour).Truncate(time.Hour)
+	events := []Event{pushEvent(t, hour.Add(10*time.Minute), "me/app", "refs/heads/main", numbered(50, "empty")...)}
+
+	got := scored(DefaultScoring(), events)
+	// 5 in full, then the 1st, 2nd, 4th, 8th, 16th, and 32nd extra.
+	if got.Commits != 11 || got.IgnoredCommits != 39 {
+		t.Errorf("50 commits in an hour: got %d counted, %d ignored; want 11, 39", got.Commits, got.IgnoredCommits)
+	}
+
+	spread := []Event{
+		pushEvent(t, hour.Add(-time.Hour), "me/app", "refs/heads/main", numbered(5, "a")...),
+		pushEvent(t, hour, "me/app", "refs/heads/main", numbered(5, "b")...),
+	}
+	if got := scored(DefaultScoring(), spread); got.Commits != 10 || got.IgnoredCommits != 0 {
+		t.Errorf("5 commits in each of two hours: got %d counted, %d ignored; want 10, 0", got.Commits, got.IgnoredCommits)
+	}
+
+	off := DefaultScoring()
+	off.HourlyCommits = 0
+	if got := scored(off, events); got.Commits != 50 {
+		t.Errorf("hourly_commits 0: got %d counted, want 50", got.Commits)
+	}
+}
+
+func TestForcePushedCommitsCountOnce(t *testing.T) {
+	now := time.Now()
+	// Newest first, as GitHub returns them: the branch was pushed, then
+	// rebased onto 999 and force-pushed, then the same commits pushed again.
+	events := []Event{
+		after(t, "ddd", pushEvent(t, now.Add(-1*time.Hour), "me/app", "refs/heads/feature", testCommit{"ccc", "Fix login bug"}, testCommit{"ddd", "Add docs"})),
+		after(t, "999", pushEvent(t, now.Add(-3*time.Hour), "me/app", "refs/heads/feature", testCommit{"ccc", "Fix login bug"}, testCommit{"ddd", "Add docs"})),
+		after(t, "000", pushEvent(t, now.Add(-5*time.Hour), "me/app", "refs/heads/feature", testCommit{"aaa", "Fix login bug"}, testCommit{"bbb", "Add docs"})),
+	}
+	got := scored(DefaultScoring(), events)
+	if got.Commits != 2 || got.IgnoredCommits != 4 {
+		t.Errorf("got %d counted, %d ignored; want 2, 4", got.Commits, got.IgnoredCommits)
+	}
+	if got.FixCommits != 1 || got.DocCommits != 1 {
+		t.Errorf("got %d fix and %d doc commits, want 1 each", got.FixCommits, got.DocCommits)
+	}
+
+	// The same message in another repo is different work.
+	other := append(events, pushEvent(t, now, "me/lib", "refs/heads/main", testCommit{"eee", "Fix login bug"}))
+	if got := scored(DefaultScoring(), other); got.Commits != 3 {
+		t.Errorf("with another repo: got %d counted, want 3", got.Commits)
+	}
+}
+
+func TestRepeatedMessagesStillCount(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		events []Event
+		want   int
+	}{
+		{"one push", []Event{
+			pushEvent(t, now.Add(-1*time.Hour), "me/app", "refs/heads/main", testCommit{"aaa", "wip"}, testCommit{"bbb", "wip"}),
+		}, 2},
+		{"pushes that follow on", []Event{
+			after(t, "aaa", pushEvent(t, now.Add(-1*time.Hour), "me/app", "refs/heads/main", testCommit{"bbb", "fix typo"})),
+			after(t, "000", pushEvent(t, now.Add(-3*time.Hour), "me/app", "refs/heads/main", testCommit{"aaa", "fix typo"})),
+		}, 2},
+		{"pushes without before", []Event{
+			pushEvent(t, now.Add(-1*time.Hour), "me/app", "refs/heads/main", testCommit{"bbb", "fix typo"}),
+			pushEvent(t, now.Add(-3*time.Hour), "me/app", "refs/heads/main", testCommit{"aaa", "fix typo"}),
+		}, 2},
+		{"forced by a webhook", []Event{
+			forced(t, pushEvent(t, now.Add(-1*time.Hour), "me/app", "refs/heads/main", testCommit{"bbb", "fix typo"})),
+			pushEvent(t, now.Add(-3*time.Hour), "me/app", "refs/heads/main", testCommit{"aaa", "fix typo"}),
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scored(DefaultScoring(), tt.events); got.Commits != tt.want {
+				t.Errorf("got %d counted, want %d", got.Commits, tt.want)
+			}
+		})
+	}
+}
+
+func TestThrowawayBranchesEarnNothing(t *testing.T) {
+	now := time.Now()
+	events := []Event{
+		pushEvent(t, now.Add(-1*time.Hour), "me/app", "refs/heads/main", testCommit{"aaa", "Try a thing"}),
+		pushEvent(t, now.Add(-2*time.Hour), "me/app", "refs/heads/tmp/try", testCommit{"aaa", "Try a thing"}, testCommit{"bbb", "Test the thing"}),
+	}
+	got := scored(DefaultScoring(), events)
+	// The commit that later landed on main still counts there.
+	if got.Commits != 1 || got.IgnoredCommits != 2 || got.TestCommits != 0 {
+		t.Errorf("got %d counted, %d ignored, %d test; want 1, 2, 0", got.Commits, got.IgnoredCommits, got.TestCommits)
+	}
+
+	c := DefaultScoring()
+	c.ThrowawayBranches = nil
+	if got := scored(c, events); got.Commits != 2 {
+		t.Errorf("with no throwaway branches: got %d counted, want 2", got.Commits)
+	}
+}
+
+func TestOldPushesAreLeftAlone(t *testing.T) {
+	old := time.Now().Add(-SummaryWindow - time.Hour)
+	events := []Event{pushEvent(t, old, "me/app", "refs/heads/tmp/x", numbered(3, "old")...)}
+	if got := scored(DefaultScoring(), events); got.Commits != 0 || got.IgnoredCommits != 0 {
+		t.Errorf("got %d counted, %d ignored; want 0, 0", got.Commits, got.IgnoredCommits)
+	}
+}
+
+func TestMoodGainIsCappedPerFeed(t *testing.T) {
+	c := DefaultScoring()
+	stats := Stats{Mood: 10}
+	c.Apply(&stats, ActivitySummary{Commits: 50, MergedPRs: 3, Thoughts: 1}, 0)
+	if stats.Mood != 10+c.MaxFeedMood {
+		t.Errorf("mood = %d, want %d", stats.Mood, 10+c.MaxFeedMood)
+	}
+
+	stats = Stats{Mood: 10}
+	c.Apply(&stats, ActivitySummary{Commits: 2}, 0)
+	if stats.Mood != 12 {
+		t.Errorf("small feed: mood = %d, want 12", stats.Mood)
+	}
+
+	stats = Stats{Mood: 95}
+	c.Apply(&stats, ActivitySummary{MergedPRs: 3}, 0)
+	if stats.Mood != 100 {
+		t.Errorf("near the top: mood = %d, want 100", stats.Mood)
+	}
+
+	stats = Stats{Mood: 10}
+	c.Apply(&stats, ActivitySummary{}, 0)
+	if stats.Mood != 10-c.IdleMoodDecay {
+		t.Errorf("idle feed: mood = %d, want %d", stats.Mood, 10-c.IdleMoodDecay)
+	}
+
+	c.MaxFeedMood = 0
+	stats = Stats{Mood: 10}
+	c.Apply(&stats, ActivitySummary{Commits: 50}, 0)
+	if stats.Mood != 60 {
+		t.Errorf("uncapped: mood = %d, want 60", stats.Mood)
+	}
+}
+
+func TestScoringValidatesAntiGamingSettings(t *testing.T) {
+	c := DefaultScoring()
+	c.ThrowawayBranches = []string{"tmp/["}
+	if c.Validate() == nil {
+		t.Error("bad throwaway pattern: want an error")
+	}
+	c = DefaultScoring()
+	c.MaxFeedMood = -1
+	if c.Validate() == nil {
+		t.Error("negative max_feed_mood: want an error")
+	}
+	if err := DefaultScoring().Validate(); err != nil {
+		t.Errorf("defaults: %v", err)
+	}
+}
+
+func reviewEvent(t *testing.T, at time.Time, id int64) Event {
+	t.Helper()
+	var payload PullRequestReviewPayload
+	payload.Review.ID = id
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Event{Type: "PullRequestReviewEvent", CreatedAt: at, Repo: EventRepo{Name: "me/app"}, Payload: raw}
+}
+
+func TestCreditedReviewsAreDiscounted(t *testing.T) {
+	now := time.Now()
+	cutoff := now.Add(-SummaryWindow)
+	events := []Event{reviewEvent(t, now.Add(-time.Hour), 1), reviewEvent(t, now.Add(-2*time.Hour), 2)}
+	tests := []struct {
+		name     string
+		credited []int64
+		want     int
+	}{
+		{"none credited", nil, 2},
+		{"one credited", []int64{2}, 1},
+		{"both credited", []int64{1, 2, 3}, 0},
+		{"others credited", []int64{7}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiscountReviews(events, Summarize(events, cutoff), tt.credited, cutoff)
+			if got.Reviews != tt.want {
+				t.Errorf("got %d reviews, want %d", got.Reviews, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package pet
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// How a feed's window is counted: the last seven days, or the calendar week
+// so far.
+const (
+	WeeksRolling  = "rolling"
+	WeeksCalendar = "calendar"
+)
+
+// firstWeekday is the day weeks start on and calendarWeeks whether a feed
+// counts only the current week, both set at startup from the config.
+var (
+	firstWeekday  = time.Monday
+	calendarWeeks bool
+)
+
+// regionWeekdays are the regions whose calendars usually start the week on
+// Sunday or Saturday; everywhere else starts on Monday.
+var regionWeekdays = map[string]time.Weekday{
+	"US": time.Sunday, "CA": time.Sunday, "MX": time.Sunday, "BR": time.Sunday, "JP": time.Sunday, "KR": time.Sunday,
+	"TW": time.Sunday, "HK": time.Sunday, "IL": time.Sunday, "IN": time.Sunday, "PH": time.Sunday, "ZA": time.Sunday,
+	"AE": time.Saturday, "AF": time.Saturday, "BH": time.Saturday, "DZ": time.Saturday, "EG": time.Saturday, "IQ": time.Saturday,
+	"IR": time.Saturday, "JO": time.Saturday, "KW": time.Saturday, "LY": time.Saturday, "OM": time.Saturday, "QA": time.Saturday,
+	"SA": time.Saturday, "SD": time.Saturday, "SY": time.Saturday,
+}
+
+// SetWeeks applies the week_start and weeks config. An empty weekStart
+// follows the region of LC_ALL, LC_TIME, or LANG.
+func SetWeeks(weekStart, weeks string) {
+	firstWeekday = localeWeekday()
+	if wd, ok := ParseWeekday(weekStart); ok {
+		firstWeekday = wd
+	}
+	calendarWeeks = weeks == WeeksCalendar
+}
+
+func ValidateWeeks(weekStart, weeks string) error {
+	if _, ok := ParseWeekday(weekStart); weekStart != "" && !ok {
+		return fmt.Errorf("week_start must be a day of the week, not %q", weekStart)
+	}
+	if weeks != "" && weeks != WeeksRolling && weeks != WeeksCalendar {
+		return fmt.Errorf("weeks must be rolling or calendar, not %q", weeks)
+	}
+	return nil
+}
+
+// localeWeekday is the first day of the week in the region named by the
+// environment's locale, such as the US in "en_US.UTF-8".
+func localeWeekday() time.Weekday {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		value, _, _ = strings.Cut(value, ".")
+		value, _, _ = strings.Cut(value, "@")
+		_, region, _ := strings.Cut(strings.ReplaceAll(value, "-", "_"), "_")
+		if wd, ok := regionWeekdays[strings.ToUpper(region)]; ok {
+			return wd
+		}
+		return time.Monday
+	}
+	return time.Monday
+}
+
+// WeekStart returns local midnight of the first day of the week on or
+// before t.
+func WeekStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) - int(firstWeekday) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// SummaryCutoff is the oldest activity a feed at now counts: seven days
+// back, or with calendar weeks the start of this week.
+func SummaryCutoff(now time.Time) time.Time {
+	if calendarWeeks {
+		return WeekStart(now)
+	}
+	return now.Add(-SummaryWindow)
+}
@@ -0,0 +1,55 @@
+package pet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummaryCutoff(t *testing.T) {
+	defer func(wd time.Weekday, calendar bool) { firstWeekday, calendarWeeks = wd, calendar }(firstWeekday, calendarWeeks)
+
+	// A Wednesday afternoon.
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		weekday  time.Weekday
+		calendar bool
+		want     time.Time
+	}{
+		{"rolling", time.Monday, false, now.Add(-7 * 24 * time.Hour)},
+		{"calendar from Monday", time.Monday, true, time.Date(2026, time.October, 12, 0, 0, 0, 0, time.Local)},
+		{"calendar from Sunday", time.Sunday, true, time.Date(2026, time.October, 11, 0, 0, 0, 0, time.Local)},
+		{"calendar from Saturday", time.Saturday, true, time.Date(2026, time.October, 10, 0, 0, 0, 0, time.Local)},
+		{"calendar from Wednesday", time.Wednesday, true, time.Date(2026, time.October, 14, 0, 0, 0, 0, time.Local)},
+		{"calendar from Thursday", time.Thursday, true, time.Date(2026, time.October, 8, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			firstWeekday, calendarWeeks = tt.weekday, tt.calendar
+			if got := SummaryCutoff(now); !got.Equal(tt.want) {
+				t.Errorf("SummaryCutoff(%v) = %v, want %v", now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleWeekday(t *testing.T) {
+	tests := []struct {
+		lang string
+		want time.Weekday
+	}{
+		{"en_US.UTF-8", time.Sunday},
+		{"en_GB.UTF-8", time.Monday},
+		{"ar_SA.UTF-8@calendar", time.Saturday},
+		{"pt-BR", time.Sunday},
+		{"C", time.Monday},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_TIME", "")
+		t.Setenv("LANG", tt.lang)
+		if got := localeWeekday(); got != tt.want {
+			t.Errorf("LANG=%s: got %v, want %v", tt.lang, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package pet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+)
+
+// WellnessConfig controls the burnout guardian. RestDays are weekday names
+// ("saturday", "sunday") on which an idle feed doesn't cost mood.
+type WellnessConfig struct {
+	RestDays    []string `json:"rest_days"`
+	StreakLimit int      `json:"streak_limit"`
+}
+
+func DefaultWellness() WellnessConfig {
+	return WellnessConfig{StreakLimit: 14}
+}
+
+func (w WellnessConfig) Validate() error {
+	for _, day := range w.RestDays {
+		if _, ok := ParseWeekday(day); !ok {
+			return fmt.Errorf("wellness.rest_days: unknown weekday %q", day)
+		}
+	}
+	if w.StreakLimit < 0 {
+		return fmt.Errorf("wellness.streak_limit must not be negative, got %d", w.StreakLimit)
+	}
+	return nil
+}
+
+func (w WellnessConfig) IsRestDay(t time.Time) bool {
+	for _, day := range w.RestDays {
+		if wd, ok := ParseWeekday(day); ok && wd == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if strings.EqualFold(wd.String(), strings.TrimSpace(name)) {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// WellnessConcerns lists the patterns the pet is worried about, gentlest
+// first. An empty result means a balanced week.
+func WellnessConcerns(summary ActivitySummary, streak int, cfg WellnessConfig) []string {
+	var concerns []string
+	if summary.LateNightPushes >= 2 {
+		concerns = append(concerns, i18n.Tr("🌙 %d late-night pushes this week. Sleep is a feature too.", summary.LateNightPushes))
+	}
+	if summary.WeekendEvents > 0 && summary.WeekdayEvents == 0 {
+		concerns = append(concerns, i18n.Tr("📅 All your work landed on the weekend. Maybe borrow a weekday back?"))
+	}
+	if cfg.StreakLimit > 0 && streak >= cfg.StreakLimit {
+		concerns = append(concerns, i18n.Tr("🔥 %d days in a row without a break. Rest days keep streaks healthy.", streak))
+	}
+	return concerns
+}
+
+// PostCommitNudge returns a one-line reminder for the post-commit hook, or
+// "" when there is nothing to worry about.
+func PostCommitNudge(now time.Time, concerns []string) string {
+	if IsLateNight(now) {
+		return i18n.Tr("🌙 It's late. I'll keep watch over the code; go get some rest.")
+	}
+	if len(concerns) > 0 {
+		return concerns[0]
+	}
+	return ""
+}
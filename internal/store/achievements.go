@@ -0,0 +1,83 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+// marathonLines is how many lines changed in a week earn Marathon.
+const marathonLines = 5000
+
+// Achievement is a milestone the pet can unlock once.
+type Achievement struct {
+	Name     string
+	Icon     string
+	unlocked func(pet.ActivitySummary) bool
+}
+
+// Achievements are checked against the latest 7d summary on every sync;
+// once earned they stay in PetState.Achievements for good.
+var Achievements = []Achievement{
+	{Name: "Community Voice", Icon: "🗣️", unlocked: func(s pet.ActivitySummary) bool {
+		return s.Discussions+s.DiscussionComments >= 3
+	}},
+	{Name: "Patron", Icon: "💝", unlocked: func(s pet.ActivitySummary) bool {
+		return s.Sponsorships > 0
+	}},
+	{Name: "Firefighter", Icon: "🧯", unlocked: func(s pet.ActivitySummary) bool {
+		return s.FixedBuilds > 0
+	}},
+	{Name: "Thoughtful Reviewer", Icon: "🔍", unlocked: func(s pet.ActivitySummary) bool {
+		return s.ReviewDepth.Comments >= 10
+	}},
+	{Name: "Guiding Hand", Icon: "🧭", unlocked: func(s pet.ActivitySummary) bool {
+		d := s.ReviewDepth
+		return d.ChangeRequests >= 2 && d.Approvals-d.SilentApprovals >= 2
+	}},
+	{Name: "Quick Responder", Icon: "⚡", unlocked: func(s pet.ActivitySummary) bool {
+		return s.ReviewDepth.Quick >= 3
+	}},
+	{Name: "Duet", Icon: "🎶", unlocked: func(s pet.ActivitySummary) bool {
+		return s.DuetCommits > 0
+	}},
+	{Name: "Mentor", Icon: "🌱", unlocked: func(s pet.ActivitySummary) bool {
+		return s.FirstTimers > 0
+	}},
+	{Name: "Marathon", Icon: "🏃", unlocked: func(s pet.ActivitySummary) bool {
+		return s.LinesChanged >= marathonLines
+	}},
+}
+
+// UnlockAchievements records newly earned achievements on the state and
+// returns their display names.
+func UnlockAchievements(state *PetState) []string {
+	var unlocked []string
+	for _, a := range Achievements {
+		if HasAchievement(*state, a.Name) || !a.unlocked(state.Activity) {
+			continue
+		}
+		state.Achievements = append(state.Achievements, a.Name)
+		unlocked = append(unlocked, a.Icon+" "+a.Name)
+	}
+	return unlocked
+}
+
+func HasAchievement(state PetState, name string) bool {
+	for _, have := range state.Achievements {
+		if have == name {
+			return true
+		}
+	}
+	return false
+}
+
+func AchievementBadges(state PetState) string {
+	var badges []string
+	for _, a := range Achievements {
+		if HasAchievement(state, a.Name) {
+			badges = append(badges, a.Icon)
+		}
+	}
+	return strings.Join(badges, " ")
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+)
+
+// The pet keeps its own little schedule, in the Keeper's local time.
+const (
+	bedtimeHour = 22
+	wakeHour    = 6
+	noonHour    = 11
+)
+
+func IsAsleep(now time.Time) bool {
+	hour := now.Hour()
+	return hour >= bedtimeHour || hour < wakeHour
+}
+
+func isMorning(now time.Time) bool {
+	hour := now.Hour()
+	return hour >= wakeHour && hour < noonHour
+}
+
+func SleepingArt() string {
+	return "" +
+		"          z Z\n" +
+		"   ╭───╮ z\n" +
+		"  ( -_- )\n" +
+		"  ╭┤   ├╮\n" +
+		"  │╰───╯│\n" +
+		"  ╰─────╯ ~~"
+}
+
+// seasonalCosmetic returns a cosmetic line for the date, or "" when nothing
+// is in season. accountCreated is the Keeper's GitHub signup timestamp.
+func seasonalCosmetic(now time.Time, accountCreated string) string {
+	if created, err := time.Parse(time.RFC3339, accountCreated); err == nil {
+		created = created.Local()
+		if created.Month() == now.Month() && created.Day() == now.Day() && created.Year() < now.Year() {
+			return i18n.Tr("🎉🎊 Happy GitHub anniversary! 🎊🎉")
+		}
+	}
+	switch now.Month() {
+	case time.October:
+		return i18n.Tr("🎃 pumpkin hat")
+	case time.December, time.January, time.February:
+		return i18n.Tr("🧣 cozy scarf")
+	}
+	return ""
+}
+
+// ApplyBehavior swaps in time-of-day and seasonal touches on top of the
+// evolution art, showing sleeping in its place while the pet sleeps.
+func ApplyBehavior(art, special, sleeping string, state PetState, now time.Time) (string, string) {
+	if IsAsleep(now) {
+		art = sleeping
+		special = "\n" + i18n.Tr("💤 Sleeping. Dreaming of green builds.")
+	} else if isMorning(now) {
+		special += "\n" + i18n.Tr("☀️  Bright-eyed and ready to ship!")
+	}
+	if cosmetic := seasonalCosmetic(now, state.AccountCreated); cosmetic != "" {
+		art = "  " + cosmetic + "\n" + art
+	}
+	for _, event := range ActiveEvents(now) {
+		art = "  " + i18n.Tr(event.Overlay) + "\n" + art
+	}
+	return art, special
+}
+
+func AccountCreated(ctx context.Context) string {
+	var user struct {
+		CreatedAt string `json:"created_at"`
+	}
+	if gh.Get(ctx, "user", &user) != nil {
+		return ""
+	}
+	return user.CreatedAt
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+)
+
+// changesFileName is the undo log. How many saves it keeps, and for how
+// long, is up to retention; older snapshots are dropped first.
+const changesFileName = "gh-pet-changes.json"
+
+// StateChange is one save of the pet: which command made it, what it
+// altered, and the states on either side so it can be undone.
+type StateChange struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Changed []string  `json:"changed"`
+	Before  PetState  `json:"before"`
+	After   PetState  `json:"after"`
+}
+
+// recordChange logs a save that took the state file from before to after,
+// both as JSON. Saves that change nothing, and a new pet's first, aren't
+// logged.
+func recordChange(command string, before, after []byte) error {
+	if len(before) == 0 || bytes.Equal(before, after) {
+		return nil
+	}
+	var change StateChange
+	if err := json.Unmarshal(before, &change.Before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(after, &change.After); err != nil {
+		return err
+	}
+	change.Time = time.Now().UTC()
+	change.Command = command
+	change.Changed = StateChanges(change.Before, change.After)
+	changes, err := LoadChanges()
+	if err != nil {
+		return err
+	}
+	return SaveChanges(append(changes, change))
+}
+
+// StateChanges describes what differs between two states, such as
+// "mood 62 → 70" or "unlocked Duet".
+func StateChanges(before, after PetState) []string {
+	var changed []string
+	number := func(name string, from, to int) {
+		if from != to {
+			changed = append(changed, fmt.Sprintf("%s %d → %d", name, from, to))
+		}
+	}
+	text := func(name, from, to string) {
+		if from != to {
+			changed = append(changed, fmt.Sprintf("%s %s → %s", name, orNone(from), orNone(to)))
+		}
+	}
+	number("mood", before.Mood, after.Mood)
+	number("kindness", before.Kindness, after.Kindness)
+	number("logic", before.Logic, after.Logic)
+	number("mentor", before.Mentor, after.Mentor)
+	text("evolution", before.Evolution, after.Evolution)
+	text("name", before.Name, after.Name)
+	for _, name := range after.Achievements {
+		if !containsFold(before.Achievements, name) {
+			changed = append(changed, "unlocked "+name)
+		}
+	}
+	for _, badge := range after.Badges {
+		if !containsFold(before.Badges, badge) {
+			changed = append(changed, "earned "+badge)
+		}
+	}
+	number("companions", len(before.Companions), len(after.Companions))
+	if len(changed) == 0 {
+		changed = append(changed, "details only")
+	}
+	return changed
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// LoadChanges returns the logged saves, oldest first.
+func LoadChanges() ([]StateChange, error) {
+	path, err := ChangesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var changes []StateChange
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SaveChanges writes the undo log, keeping the snapshots retention allows.
+func SaveChanges(changes []StateChange) error {
+	changes = PruneChanges(changes, Retention, time.Now())
+	path, err := ChangesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func ChangesPath() (string, error) {
+	return dirs.DataPath(changesFileName)
+}
+
+// containsFold reports whether list holds s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
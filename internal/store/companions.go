@@ -0,0 +1,54 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// MaxCompanions caps the band following the pet; later forks don't
+	// hatch once it's full.
+	MaxCompanions = 8
+	// trailCompanions is how many fit behind the pet in its art.
+	trailCompanions = 3
+	// BonusCompanions is how many companions count toward the passive bonus.
+	BonusCompanions = 3
+)
+
+// Companion is a tiny sprite hatched from a fork: one the Keeper made, or one
+// someone made of the Keeper's repo. It follows the pet in its art.
+type Companion struct {
+	// Fork is the new repo as owner/name, and Source the repo it was forked
+	// from.
+	Fork   string `json:"fork"`
+	Source string `json:"source"`
+	Name   string `json:"name,omitempty"`
+	Sprite string `json:"sprite"`
+	Born   string `json:"born"`
+}
+
+// DisplayName is the companion's given name, or its fork's repo name.
+func (c Companion) DisplayName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	_, repo, _ := strings.Cut(c.Fork, "/")
+	return repo
+}
+
+// CompanionTrail is the line of companions walking behind the pet, or ""
+// when it has none.
+func CompanionTrail(companions []Companion) string {
+	if len(companions) == 0 {
+		return ""
+	}
+	var sprites []string
+	for _, c := range companions[:min(len(companions), trailCompanions)] {
+		sprites = append(sprites, c.Sprite)
+	}
+	trail := "   " + strings.Join(sprites, "  ")
+	if extra := len(companions) - trailCompanions; extra > 0 {
+		trail += fmt.Sprintf("  +%d", extra)
+	}
+	return "\n" + trail
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type MonthDay struct {
+	Month time.Month
+	Day   int
+}
+
+// SeasonalEvent is a yearly window on the calendar. While it runs, the pet
+// wears its overlay and its quest counts toward a badge that can only be
+// earned that year.
+type SeasonalEvent struct {
+	Name string
+	Icon string
+	// From and To are the first and last days. To may fall in the next
+	// year, as New Year's does.
+	From, To MonthDay
+	Overlay  string
+	Quest    string
+	Target   int
+	progress func(days []DayRecord) int
+}
+
+var SeasonalEvents = []SeasonalEvent{
+	{Name: "Hacktoberfest", Icon: "🎃", From: MonthDay{time.October, 1}, To: MonthDay{time.October, 31},
+		Overlay: "👕 Hacktoberfest tee", Quest: "Merge 4 pull requests", Target: 4,
+		progress: func(days []DayRecord) int {
+			merged := 0
+			for _, d := range days {
+				merged += d.MergedPRs
+			}
+			return merged
+		}},
+	{Name: "Advent of Code", Icon: "🎄", From: MonthDay{time.December, 1}, To: MonthDay{time.December, 25},
+		Overlay: "⭐ advent star", Quest: "Commit on 12 days", Target: 12,
+		progress: func(days []DayRecord) int {
+			active := 0
+			for _, d := range days {
+				if d.Commits > 0 {
+					active++
+				}
+			}
+			return active
+		}},
+	{Name: "New Year", Icon: "🎆", From: MonthDay{time.December, 31}, To: MonthDay{time.January, 7},
+		Overlay: "🥳 party hat", Quest: "Push the year's first commit", Target: 1,
+		progress: func(days []DayRecord) int {
+			for _, d := range days {
+				if d.Commits > 0 && d.Day().Month() == time.January {
+					return 1
+				}
+			}
+			return 0
+		}},
+}
+
+// EventWindow is one year's run of an event, from the start of its first
+// day up to the start of the day after its last.
+type EventWindow struct {
+	SeasonalEvent
+	Start, End time.Time
+}
+
+func (e SeasonalEvent) window(year int, loc *time.Location) EventWindow {
+	start := time.Date(year, e.From.Month, e.From.Day, 0, 0, 0, 0, loc)
+	end := time.Date(year, e.To.Month, e.To.Day, 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	if end.Before(start) {
+		end = end.AddDate(1, 0, 0)
+	}
+	return EventWindow{e, start, end}
+}
+
+// Badge is the limited badge this window awards, named for the year it ends.
+func (w EventWindow) Badge() string {
+	return fmt.Sprintf("%s %d", w.Name, w.End.AddDate(0, 0, -1).Year())
+}
+
+func (w EventWindow) QuestProgress(history History) int {
+	return w.progress(history.Between(w.Start, w.End))
+}
+
+// ActiveEvents are the events running at now.
+func ActiveEvents(now time.Time) []EventWindow {
+	var active []EventWindow
+	for _, e := range SeasonalEvents {
+		// A window that wraps the new year may have started last year.
+		for _, year := range []int{now.Year() - 1, now.Year()} {
+			if w := e.window(year, now.Location()); !now.Before(w.Start) && now.Before(w.End) {
+				active = append(active, w)
+			}
+		}
+	}
+	return active
+}
+
+// AwardEventBadges grants the badge of every running event whose quest is
+// done, and returns the new ones for display.
+func AwardEventBadges(state *PetState, now time.Time) []string {
+	history, err := LoadHistory()
+	if err != nil {
+		return nil
+	}
+	var earned []string
+	for _, w := range ActiveEvents(now) {
+		if HasBadge(*state, w.Badge()) || w.QuestProgress(history) < w.Target {
+			continue
+		}
+		state.Badges = append(state.Badges, w.Badge())
+		earned = append(earned, w.Icon+" "+w.Badge())
+	}
+	return earned
+}
+
+func HasBadge(state PetState, name string) bool {
+	for _, have := range state.Badges {
+		if have == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EventBadges shows one icon per event badge earned, so a second
+// Hacktoberfest reads as two pumpkins.
+func EventBadges(state PetState) string {
+	var icons []string
+	for _, badge := range state.Badges {
+		icons = append(icons, BadgeIcon(badge))
+	}
+	return strings.Join(icons, " ")
+}
+
+// BadgeIcon is the icon of the event a badge such as "New Year 2027" came
+// from.
+func BadgeIcon(badge string) string {
+	for _, e := range SeasonalEvents {
+		if strings.HasPrefix(badge, e.Name+" ") {
+			return e.Icon
+		}
+	}
+	return "🏅"
+}
+
+// NextWindow is the event's next run that hasn't started yet.
+func (e SeasonalEvent) NextWindow(now time.Time) EventWindow {
+	w := e.window(now.Year(), now.Location())
+	if !w.Start.After(now) {
+		w = e.window(now.Year()+1, now.Location())
+	}
+	return w
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+const (
+	whyFileName = "gh-pet-why.json"
+	// maxExplanations is how many feeds gh pet why remembers.
+	maxExplanations = 20
+)
+
+// Explanation is why one feed changed the pet the way it did: what each
+// kind of activity added or took away, and how the evolution was chosen.
+type Explanation struct {
+	Time          time.Time             `json:"time"`
+	Source        string                `json:"source"`
+	Contributions []pet.Contribution    `json:"contributions"`
+	Stats         map[string]StatChange `json:"stats"`
+	Evolution     EvolutionChoice       `json:"evolution"`
+}
+
+// StatChange is one stat before and after a feed.
+type StatChange struct {
+	Before int `json:"before"`
+	After  int `json:"after"`
+}
+
+// EvolutionChoice is how a feed picked the pet's form: the highest score
+// wins, and ties go to the evolution listed first.
+type EvolutionChoice struct {
+	From   string               `json:"from"`
+	To     string               `json:"to"`
+	Scores []pet.EvolutionScore `json:"scores"`
+	// Reason is set when the scores didn't decide it.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ExplainFeed itemizes how a feed took the pet from before to after. fixed
+// is how many red builds it fixed. Whatever caps and limits took off, such
+// as max_feed_mood or mood topping out at 100, gets a line of its own, so
+// each stat's lines add up to its change.
+func ExplainFeed(scoring pet.ScoringConfig, before, after PetState, summary pet.ActivitySummary, fixed int) Explanation {
+	parts := scoring.LogicParts(summary)
+	parts = append(parts, pet.Part("logic", "fork companions", min(len(after.Companions), BonusCompanions), scoring.CompanionLogic))
+	parts = append(parts, scoring.KindnessParts(summary)...)
+	parts = append(parts, scoring.MentorParts(summary)...)
+	if pet.Activity(summary) == 0 {
+		parts = append(parts, pet.Contribution{Stat: "mood", Source: "a quiet week", Points: -scoring.IdleMoodDecay})
+	} else {
+		parts = append(parts, scoring.MoodParts(summary)...)
+	}
+	if summary.Thoughts > 0 {
+		parts = append(parts, pet.Contribution{Stat: "mood", Source: "thought fragments", Count: summary.Thoughts, Points: scoring.ThoughtMood})
+	}
+	parts = append(parts,
+		pet.Contribution{Stat: "mood", Source: "red builds", Points: before.CIDebuff - after.CIDebuff},
+		pet.Part("mood", "fixed builds", fixed, scoring.FirefighterMood))
+
+	stats := map[string]StatChange{
+		"mood":     {before.Mood, after.Mood},
+		"kindness": {before.Kindness, after.Kindness},
+		"logic":    {before.Logic, after.Logic},
+		"mentor":   {before.Mentor, after.Mentor},
+	}
+	var kept []pet.Contribution
+	sums := map[string]int{}
+	for _, p := range parts {
+		if p.Points != 0 {
+			kept = append(kept, p)
+			sums[p.Stat] += p.Points
+		}
+	}
+	for _, stat := range []string{"mood", "kindness", "logic", "mentor"} {
+		change := stats[stat]
+		if rest := change.After - change.Before - sums[stat]; rest != 0 {
+			kept = append(kept, pet.Contribution{Stat: stat, Source: "caps and limits", Points: rest})
+		}
+	}
+
+	choice := EvolutionChoice{From: before.Evolution, To: after.Evolution, Scores: pet.EvolutionScores(summary)}
+	if after.Evolution == "Lonely" {
+		choice.Reason = "no activity this week"
+	}
+	return Explanation{Time: time.Now().UTC(), Source: "feed", Contributions: kept, Stats: stats, Evolution: choice}
+}
+
+// RecordExplanation keeps e as the latest feed's explanation.
+func RecordExplanation(e Explanation) error {
+	explanations, err := LoadExplanations()
+	if err != nil {
+		return err
+	}
+	explanations = append(explanations, e)
+	if len(explanations) > maxExplanations {
+		explanations = explanations[len(explanations)-maxExplanations:]
+	}
+	path, err := WhyPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(explanations, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// LoadExplanations returns the remembered feeds' explanations, oldest first.
+func LoadExplanations() ([]Explanation, error) {
+	path, err := WhyPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var explanations []Explanation
+	if err := json.Unmarshal(data, &explanations); err != nil {
+		return nil, err
+	}
+	return explanations, nil
+}
+
+func WhyPath() (string, error) {
+	return dirs.DataPath(whyFileName)
+}
+
+// Itemize adds count from source, worth weight each, that took stat from
+// from to to after scoring, such as the mood goals met added in a feed.
+func (e *Explanation) Itemize(stat, source string, count, weight, from, to int) {
+	if to == from {
+		return
+	}
+	e.Contributions = append(e.Contributions, pet.Contribution{Stat: stat, Source: source, Count: count, Weight: weight, Points: to - from})
+	e.Stats[stat] = StatChange{e.Stats[stat].Before, to}
+}
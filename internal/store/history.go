@@ -0,0 +1,296 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+const historyFileName = "gh-pet-history.json"
+
+// DayRecord is one local calendar day of activity. Records are rebuilt from
+// the events feed on every sync, so the counts are the best view GitHub has
+// of that day rather than a running tally.
+type DayRecord struct {
+	Date        string `json:"date"`
+	Commits     int    `json:"commits"`
+	MergedPRs   int    `json:"merged_prs"`
+	Reviews     int    `json:"reviews"`
+	DocComments int    `json:"doc_comments"`
+	Issues      int    `json:"issues"`
+	TestCommits int    `json:"test_commits"`
+	Mood        int    `json:"mood"`
+	// Pomodoros and FocusMinutes are focus sessions finished that day. The
+	// events feed knows nothing of them, so syncs leave them alone.
+	Pomodoros    int `json:"pomodoros,omitempty"`
+	FocusMinutes int `json:"focus_minutes,omitempty"`
+	// GameLogic is the logic shards won at gh pet play that day, which the
+	// daily cap counts against.
+	GameLogic int `json:"game_logic,omitempty"`
+}
+
+const DayLayout = "2006-01-02"
+
+func (d DayRecord) Day() time.Time {
+	t, _ := time.ParseInLocation(DayLayout, d.Date, time.Local)
+	return t
+}
+
+func (d DayRecord) Total() int {
+	return d.Commits + d.MergedPRs + d.Reviews + d.DocComments + d.Issues
+}
+
+// RecordHistory folds the fetched events into per-day records and stamps
+// today's mood. Counts only ever grow, because a day that has partly aged
+// out of the events window would otherwise lose activity on re-sync.
+func RecordHistory(events []pet.Event, mood int) error {
+	history, err := LoadHistory()
+	if err != nil {
+		return err
+	}
+	for date, dayEvents := range EventsByDay(events) {
+		history.Day(date).Fold(pet.Summarize(dayEvents, time.Time{}))
+	}
+	history.Day(time.Now().Format(DayLayout)).Mood = mood
+	return SaveHistory(history)
+}
+
+// EventsByDay groups events by the local date they happened on.
+func EventsByDay(events []pet.Event) map[string][]pet.Event {
+	byDay := map[string][]pet.Event{}
+	for _, event := range events {
+		date := event.CreatedAt.Local().Format(DayLayout)
+		byDay[date] = append(byDay[date], event)
+	}
+	return byDay
+}
+
+// Fold raises the day's counts to what s saw, if it saw more.
+func (d *DayRecord) Fold(s pet.ActivitySummary) {
+	d.Commits = max(d.Commits, s.Commits)
+	d.MergedPRs = max(d.MergedPRs, s.MergedPRs)
+	d.Reviews = max(d.Reviews, s.Reviews)
+	d.DocComments = max(d.DocComments, s.DocComments)
+	d.Issues = max(d.Issues, pet.IssueTriage(s))
+	d.TestCommits = max(d.TestCommits, s.TestCommits)
+}
+
+// RecordFocus adds a finished focus session of minutes to today's record.
+func RecordFocus(pomodoros, minutes int) error {
+	history, err := LoadHistory()
+	if err != nil {
+		return err
+	}
+	rec := history.Day(time.Now().Format(DayLayout))
+	rec.Pomodoros += pomodoros
+	rec.FocusMinutes += minutes
+	return SaveHistory(history)
+}
+
+type History struct {
+	Days []DayRecord `json:"days"`
+	// Away are the Keeper's holidays, from gh pet away.
+	Away []AwaySpan `json:"away,omitempty"`
+	// Months are days too old to keep one by one, rolled into monthly
+	// totals by retention; see retention.go. They all come before Days.
+	Months []MonthRecord `json:"months,omitempty"`
+}
+
+// MonthRecord is the total of a calendar month's day records, once they've
+// been compacted.
+type MonthRecord struct {
+	Month        string `json:"month"`
+	ActiveDays   int    `json:"active_days"`
+	Commits      int    `json:"commits"`
+	MergedPRs    int    `json:"merged_prs"`
+	Reviews      int    `json:"reviews"`
+	DocComments  int    `json:"doc_comments"`
+	Issues       int    `json:"issues"`
+	TestCommits  int    `json:"test_commits"`
+	Pomodoros    int    `json:"pomodoros,omitempty"`
+	FocusMinutes int    `json:"focus_minutes,omitempty"`
+	// Mood is the average of the moods stamped on MoodDays of its days.
+	Mood     int `json:"mood"`
+	MoodDays int `json:"mood_days,omitempty"`
+}
+
+const MonthLayout = "2006-01"
+
+func (m MonthRecord) total() int {
+	return m.Commits + m.MergedPRs + m.Reviews + m.DocComments + m.Issues
+}
+
+// AwaySpan is a holiday, from and to local dates inclusive. Its days neither
+// count toward a streak nor break one.
+type AwaySpan struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// AwayOn reports whether date falls on a holiday.
+func (h History) AwayOn(date string) bool {
+	for _, a := range h.Away {
+		if a.From <= date && date <= a.To {
+			return true
+		}
+	}
+	return false
+}
+
+// AwayBetween reports whether every day after from and before to falls on a
+// holiday, so activity on either side counts as one unbroken run.
+func (h History) AwayBetween(from, to time.Time) bool {
+	for day := from.AddDate(0, 0, 1); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !h.AwayOn(day.Format(DayLayout)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Day returns the record for date, creating it in sorted position if needed.
+func (h *History) Day(date string) *DayRecord {
+	i := sort.Search(len(h.Days), func(i int) bool { return h.Days[i].Date >= date })
+	if i < len(h.Days) && h.Days[i].Date == date {
+		return &h.Days[i]
+	}
+	h.Days = append(h.Days, DayRecord{})
+	copy(h.Days[i+1:], h.Days[i:])
+	h.Days[i] = DayRecord{Date: date}
+	return &h.Days[i]
+}
+
+// Between returns the records with from <= day < to.
+func (h History) Between(from, to time.Time) []DayRecord {
+	var out []DayRecord
+	for _, d := range h.Days {
+		t := d.Day()
+		if !t.Before(from) && t.Before(to) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func LoadHistory() (History, error) {
+	path, err := HistoryPath()
+	if err != nil {
+		return History{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return History{}, nil
+		}
+		return History{}, err
+	}
+	var history History
+	if err := json.Unmarshal(data, &history); err != nil {
+		return History{}, err
+	}
+	sort.Slice(history.Days, func(i, j int) bool { return history.Days[i].Date < history.Days[j].Date })
+	sort.Slice(history.Months, func(i, j int) bool { return history.Months[i].Month < history.Months[j].Month })
+	return history, nil
+}
+
+// SaveHistory writes history, first compacting days older than the
+// retention window.
+func SaveHistory(history History) error {
+	history.Compact(Retention.HistoryDays, time.Now())
+	path, err := HistoryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func HistoryPath() (string, error) {
+	return dirs.DataPath(historyFileName)
+}
+
+// CurrentStreak counts consecutive active days ending today, or yesterday if
+// nothing has happened yet today.
+func CurrentStreak(history History, now time.Time) int {
+	streak, _ := streakRun(history, now)
+	return streak
+}
+
+// streakRun is the current streak and the day it began, which is zero when
+// there's no streak.
+func streakRun(history History, now time.Time) (int, time.Time) {
+	active := map[string]bool{}
+	for _, d := range history.Days {
+		if d.Total() > 0 {
+			active[d.Date] = true
+		}
+	}
+	day := now
+	if !active[day.Format(DayLayout)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	// Days away are stepped over without counting.
+	streak, since := 0, time.Time{}
+	for {
+		date := day.Format(DayLayout)
+		if active[date] {
+			streak++
+			since = day
+		} else if !history.AwayOn(date) {
+			return streak, since
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+// RolledUp reports whether date's month is already kept as a monthly total.
+func (h History) RolledUp(date string) bool {
+	month := date[:len(MonthLayout)]
+	for _, m := range h.Months {
+		if m.Month == month {
+			return true
+		}
+	}
+	return false
+}
+
+// AddCounts adds what s counted to the day.
+func (d *DayRecord) AddCounts(s pet.ActivitySummary) {
+	d.Commits += s.Commits
+	d.MergedPRs += s.MergedPRs
+	d.Reviews += s.Reviews
+	d.DocComments += s.DocComments
+	d.Issues += pet.IssueTriage(s)
+	d.TestCommits += s.TestCommits
+}
+
+// Add adds o's counts to the day's.
+func (d *DayRecord) Add(o DayRecord) {
+	d.Commits += o.Commits
+	d.MergedPRs += o.MergedPRs
+	d.Reviews += o.Reviews
+	d.DocComments += o.DocComments
+	d.Issues += o.Issues
+	d.TestCommits += o.TestCommits
+}
+
+// Raise lifts each of the day's counts to o's, where o saw more.
+func (d *DayRecord) Raise(o DayRecord) {
+	d.Commits = max(d.Commits, o.Commits)
+	d.MergedPRs = max(d.MergedPRs, o.MergedPRs)
+	d.Reviews = max(d.Reviews, o.Reviews)
+	d.DocComments = max(d.DocComments, o.DocComments)
+	d.Issues = max(d.Issues, o.Issues)
+	d.TestCommits = max(d.TestCommits, o.TestCommits)
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"fmt"
+)
+
+const (
+	DefaultPetName  = "GitPet"
+	defaultPetEmoji = "🐾"
+)
+
+// DisplayName is what every renderer calls the pet.
+func (s PetState) DisplayName() string {
+	if s.Name == "" {
+		return DefaultPetName
+	}
+	return s.Name
+}
+
+// Signature is the pet's emoji, shown wherever the paw print used to be.
+func (s PetState) Signature() string {
+	if s.Emoji == "" {
+		return defaultPetEmoji
+	}
+	return s.Emoji
+}
+
+// Introduction is the name with pronouns, for places with room for both.
+func (s PetState) Introduction() string {
+	if s.Pronouns == "" {
+		return s.DisplayName()
+	}
+	return fmt.Sprintf("%s (%s)", s.DisplayName(), s.Pronouns)
+}
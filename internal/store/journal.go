@@ -0,0 +1,193 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/i18n"
+)
+
+const journalFileName = "gh-pet-journal.json"
+
+// JournalEntry is one diary page, written in the pet's voice.
+type JournalEntry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Text   string    `json:"text"`
+	// Evolved and Unlocked record milestones reached on this page, so the
+	// story can find them without reading the text.
+	Evolved  string   `json:"evolved,omitempty"`
+	Unlocked []string `json:"unlocked,omitempty"`
+	// CoAuthors are who the Keeper paired with, from Co-authored-by
+	// trailers.
+	CoAuthors []string `json:"co_authors,omitempty"`
+}
+
+type Journal struct {
+	Entries []JournalEntry `json:"entries"`
+	// Started is the local date of the first page, once retention has
+	// dropped it.
+	Started string `json:"started,omitempty"`
+}
+
+// Began is when the diary began, or zero if it has no pages yet.
+func (j Journal) Began() time.Time {
+	if j.Started != "" {
+		t, _ := time.ParseInLocation(DayLayout, j.Started, time.Local)
+		return t
+	}
+	if len(j.Entries) == 0 {
+		return time.Time{}
+	}
+	return j.Entries[0].Time
+}
+
+// dayNumber counts days since the first diary page, starting at 1.
+func (j Journal) dayNumber(t time.Time) int {
+	if j.Began().IsZero() {
+		return 1
+	}
+	first := j.Began().Local()
+	y, m, d := first.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	return int(t.Local().Sub(start).Hours()/24) + 1
+}
+
+// WriteJournal appends an entry describing how the pet changed from before
+// to after. source is the command that triggered it, and coAuthors who the
+// Keeper paired with.
+func WriteJournal(source string, before, after PetState, unlocked []string, commitMsg string, coAuthors []string) error {
+	return AppendJournal(NewJournalEntry(source, before, after, unlocked, commitMsg, coAuthors))
+}
+
+// NewJournalEntry is the page WriteJournal would add, before it's dated.
+func NewJournalEntry(source string, before, after PetState, unlocked []string, commitMsg string, coAuthors []string) JournalEntry {
+	entry := JournalEntry{Source: source, Text: diaryLine(before, after, unlocked, commitMsg, coAuthors), Unlocked: unlocked, CoAuthors: coAuthors}
+	if Evolved(before, after) {
+		entry.Evolved = after.Evolution
+	}
+	return entry
+}
+
+// AddJournalEntry records line as today's diary entry from source.
+func AddJournalEntry(source, line string) error {
+	return AppendJournal(JournalEntry{Source: source, Text: line})
+}
+
+// AppendJournal stamps entry with the time and day number and saves it.
+func AppendJournal(entry JournalEntry) error {
+	journal, err := LoadJournal()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	entry.Time = now.UTC()
+	entry.Text = fmt.Sprintf("Day %d: %s", journal.dayNumber(now), entry.Text)
+	journal.Entries = append(journal.Entries, entry)
+	return SaveJournal(journal)
+}
+
+// diaryLine turns what happened into a sentence or two the pet would write.
+func diaryLine(before, after PetState, unlocked []string, commitMsg string, coAuthors []string) string {
+	var deeds []string
+	a := after.Activity
+	if commitMsg != "" {
+		deed := fmt.Sprintf("Keeper committed %q", commitMsg)
+		if len(coAuthors) > 0 {
+			deed += " with " + i18n.JoinNames(coAuthors)
+		}
+		deeds = append(deeds, deed)
+	} else {
+		if a.MergedPRs > 0 {
+			deeds = append(deeds, fmt.Sprintf("Keeper merged %s", i18n.Plural(a.MergedPRs, "PR")))
+		}
+		if a.Reviews > 0 {
+			deeds = append(deeds, fmt.Sprintf("reviewed %s", i18n.Plural(a.Reviews, "change")))
+		}
+		if a.Commits > 0 && a.MergedPRs == 0 {
+			deeds = append(deeds, fmt.Sprintf("Keeper pushed %s", i18n.Plural(a.Commits, "commit")))
+		}
+		if len(coAuthors) > 0 {
+			deeds = append(deeds, "paired with "+i18n.JoinNames(coAuthors))
+		}
+		if a.FirstTimers > 0 {
+			deeds = append(deeds, fmt.Sprintf("welcomed %s", i18n.Plural(a.FirstTimers, "first-time contributor")))
+		}
+	}
+
+	var feelings []string
+	switch {
+	case Evolved(before, after):
+		feelings = append(feelings, fmt.Sprintf("I became a %s!", after.Evolution))
+	case after.Mood > before.Mood+5:
+		feelings = append(feelings, "I grew braver.")
+	case after.Mood < before.Mood:
+		feelings = append(feelings, "I waited by the terminal.")
+	default:
+		feelings = append(feelings, "I felt content.")
+	}
+	for _, name := range unlocked {
+		feelings = append(feelings, fmt.Sprintf("We earned %s.", name))
+	}
+
+	if len(deeds) == 0 {
+		return "A quiet day. " + strings.Join(feelings, " ")
+	}
+	line := strings.Join(deeds, " and ")
+	if commitMsg == "" {
+		line = "This week " + line
+	}
+	return line + "; " + strings.Join(feelings, " ")
+}
+
+// Evolved reports whether the pet took a new form between two saves. Falling
+// back to Lonely, or the first save of a new pet, doesn't count.
+func Evolved(before, after PetState) bool {
+	return before.Evolution != "" && before.Evolution != after.Evolution && after.Evolution != "Lonely"
+}
+
+func LoadJournal() (Journal, error) {
+	path, err := JournalPath()
+	if err != nil {
+		return Journal{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Journal{}, nil
+		}
+		return Journal{}, err
+	}
+	var journal Journal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return Journal{}, err
+	}
+	return journal, nil
+}
+
+// SaveJournal writes journal, first dropping the pages retention doesn't
+// keep.
+func SaveJournal(journal Journal) error {
+	journal.Prune(Retention, time.Now())
+	path, err := JournalPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func JournalPath() (string, error) {
+	return dirs.DataPath(journalFileName)
+}
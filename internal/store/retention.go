@@ -0,0 +1,137 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MinHistoryDays is the shortest history retention allowed. The events feed
+// reaches back 90 days, so a shorter window would see a sync rebuild days
+// that were already rolled into their month.
+const MinHistoryDays = 90
+
+// RetentionConfig bounds how much history, diary, and undo log GitPet keeps
+// on disk. Ages are in days; 0 keeps everything of that kind.
+type RetentionConfig struct {
+	// HistoryDays is how long day records stay one by one before they're
+	// rolled into monthly totals, a whole month at a time.
+	HistoryDays int `json:"history_days"`
+	// JournalDays and JournalEntries bound the diary by age and by count.
+	JournalDays    int `json:"journal_days"`
+	JournalEntries int `json:"journal_entries"`
+	// SnapshotDays and Snapshots bound the undo log of state snapshots.
+	SnapshotDays int `json:"snapshot_days"`
+	Snapshots    int `json:"snapshots"`
+}
+
+func DefaultRetention() RetentionConfig {
+	return RetentionConfig{HistoryDays: 400, JournalEntries: 2000, SnapshotDays: 30, Snapshots: 20}
+}
+
+func (r RetentionConfig) Validate() error {
+	if r.HistoryDays < 0 || r.HistoryDays > 0 && r.HistoryDays < MinHistoryDays {
+		return fmt.Errorf("retention.history_days must be 0 or at least %d", MinHistoryDays)
+	}
+	if r.JournalDays < 0 || r.JournalEntries < 0 || r.SnapshotDays < 0 || r.Snapshots < 0 {
+		return fmt.Errorf("retention limits can't be negative")
+	}
+	return nil
+}
+
+// Retention is set from the config at startup; until then the defaults
+// apply.
+var Retention = DefaultRetention()
+
+// Compact rolls the day records of every month that ended more than
+// keepDays ago into monthly totals, and returns how many days it rolled.
+// Months the current streak reaches into are kept, so a long streak
+// survives, and holidays end up dropped with the days they covered.
+func (h *History) Compact(keepDays int, now time.Time) int {
+	if keepDays <= 0 || len(h.Days) == 0 {
+		return 0
+	}
+	first := now.AddDate(0, 0, -keepDays)
+	if streak, since := streakRun(*h, now); streak > 0 && since.Before(first) {
+		first = since
+	}
+	cutoff := first.Format(MonthLayout)
+	n := sort.Search(len(h.Days), func(i int) bool { return h.Days[i].Date[:len(MonthLayout)] >= cutoff })
+	for _, d := range h.Days[:n] {
+		h.Month(d.Date[:len(MonthLayout)]).Add(d)
+	}
+	h.Days = append([]DayRecord(nil), h.Days[n:]...)
+	away := h.Away[:0]
+	for _, a := range h.Away {
+		if a.To[:len(MonthLayout)] >= cutoff {
+			away = append(away, a)
+		}
+	}
+	h.Away = away
+	return n
+}
+
+// Month returns the record for month, creating it in sorted position if
+// needed.
+func (h *History) Month(month string) *MonthRecord {
+	i := sort.Search(len(h.Months), func(i int) bool { return h.Months[i].Month >= month })
+	if i < len(h.Months) && h.Months[i].Month == month {
+		return &h.Months[i]
+	}
+	h.Months = append(h.Months, MonthRecord{})
+	copy(h.Months[i+1:], h.Months[i:])
+	h.Months[i] = MonthRecord{Month: month}
+	return &h.Months[i]
+}
+
+// Add folds a day record into the month.
+func (m *MonthRecord) Add(d DayRecord) {
+	if d.Total() > 0 {
+		m.ActiveDays++
+	}
+	m.Commits += d.Commits
+	m.MergedPRs += d.MergedPRs
+	m.Reviews += d.Reviews
+	m.DocComments += d.DocComments
+	m.Issues += d.Issues
+	m.TestCommits += d.TestCommits
+	m.Pomodoros += d.Pomodoros
+	m.FocusMinutes += d.FocusMinutes
+	// Days the pet was never fed have no mood stamped.
+	if d.Mood > 0 {
+		m.Mood = (m.Mood*m.MoodDays + d.Mood) / (m.MoodDays + 1)
+		m.MoodDays++
+	}
+}
+
+// Prune drops diary pages older than r allows, then the oldest pages past
+// its count, and returns how many it dropped. The first page's date is
+// kept, so day numbers carry on.
+func (j *Journal) Prune(r RetentionConfig, now time.Time) int {
+	keep := j.Entries
+	if r.JournalDays > 0 {
+		cutoff := now.AddDate(0, 0, -r.JournalDays)
+		keep = keep[sort.Search(len(keep), func(i int) bool { return !keep[i].Time.Before(cutoff) }):]
+	}
+	if r.JournalEntries > 0 && len(keep) > r.JournalEntries {
+		keep = keep[len(keep)-r.JournalEntries:]
+	}
+	dropped := len(j.Entries) - len(keep)
+	if dropped > 0 {
+		j.Started = j.Began().Local().Format(DayLayout)
+		j.Entries = append([]JournalEntry(nil), keep...)
+	}
+	return dropped
+}
+
+// PruneChanges keeps the undo log's snapshots that r allows, newest last.
+func PruneChanges(changes []StateChange, r RetentionConfig, now time.Time) []StateChange {
+	if r.SnapshotDays > 0 {
+		cutoff := now.AddDate(0, 0, -r.SnapshotDays)
+		changes = changes[sort.Search(len(changes), func(i int) bool { return !changes[i].Time.Before(cutoff) }):]
+	}
+	if r.Snapshots > 0 && len(changes) > r.Snapshots {
+		changes = changes[len(changes)-r.Snapshots:]
+	}
+	return changes
+}
@@ -0,0 +1,176 @@
+// Package store keeps the pet and its records on disk: the state file,
+// history, journal, undo log, and the other files both the gh pet CLI and
+// the MCP server read and write.
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/pet"
+)
+
+// PetState is the pet, as saved in the state file.
+type PetState struct {
+	Version   int                 `json:"version"`
+	LastSync  string              `json:"last_sync"`
+	Mood      int                 `json:"mood"`
+	Kindness  int                 `json:"kindness"`
+	Logic     int                 `json:"logic_shards"`
+	Evolution string              `json:"evolution"`
+	Activity  pet.ActivitySummary `json:"activity"`
+
+	// Mentor grows with review depth; see pet.ScoringConfig.MentorFor.
+	Mentor int `json:"mentor,omitempty"`
+
+	Achievements   []string `json:"achievements,omitempty"`
+	AccountCreated string   `json:"account_created,omitempty"`
+
+	Name     string `json:"name,omitempty"`
+	Pronouns string `json:"pronouns,omitempty"`
+	Emoji    string `json:"emoji,omitempty"`
+
+	// PendingThoughts are thought fragments gathered by gh pet focus since
+	// the last feed.
+	PendingThoughts int `json:"pending_thoughts,omitempty"`
+
+	// CI is the latest build weather per repo, and CIDebuff the mood held
+	// back while any of those builds is red.
+	CI       []RepoWeather `json:"ci,omitempty"`
+	CIDebuff int           `json:"ci_debuff,omitempty"`
+
+	// Badges are limited-time badges from seasonal events, such as
+	// "Hacktoberfest 2026".
+	Badges []string `json:"badges,omitempty"`
+
+	// Companions hatched from forks follow the pet around.
+	Companions []Companion `json:"companions,omitempty"`
+
+	// GoalsChecked is the local date of the last feed that reviewed goals,
+	// so each goal's period is judged once after it ends.
+	GoalsChecked string `json:"goals_checked,omitempty"`
+
+	// MoodZeroSince is the local date mood last fell to 0, cleared when it
+	// recovers. Left there too long, the pet drifts into the Void on the
+	// date in Departed.
+	MoodZeroSince string `json:"mood_zero_since,omitempty"`
+	Departed      string `json:"departed,omitempty"`
+	// Generation counts the eggs hatched since the first pet, and Hatched
+	// is the local date this one hatched.
+	Generation int    `json:"generation,omitempty"`
+	Hatched    string `json:"hatched,omitempty"`
+	// Backfilled is the local date gh pet backfill credited activity back
+	// to, so running it again never credits the same weeks twice.
+	Backfilled string `json:"backfilled,omitempty"`
+
+	// ReviewQueue is how many open pull requests awaited the Keeper's review
+	// at the last feed, and ReviewQueueSince when the queue last filled.
+	ReviewQueue      int    `json:"review_queue,omitempty"`
+	ReviewQueueSince string `json:"review_queue_since,omitempty"`
+
+	// AwayUntil is the last local date of the Keeper's holiday, set by gh
+	// pet away and cleared on the first feed after it.
+	AwayUntil string `json:"away_until,omitempty"`
+
+	// Checklists are the pull requests, as owner/repo#number, whose review
+	// checklist from the MCP server's pet_pr_checklist has already earned
+	// kindness, newest last.
+	Checklists []string `json:"checklists,omitempty"`
+
+	// CreditedReviews are the IDs of reviews gh pet review already earned
+	// kindness for, newest last, so feeds don't credit them again.
+	CreditedReviews []int64 `json:"credited_reviews,omitempty"`
+
+	// Login is the GitHub account that last fed the pet; see dirs.Account.
+	Login string `json:"login,omitempty"`
+}
+
+func Load() (PetState, error) {
+	path, err := Path()
+	if err != nil {
+		return PetState{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return PetState{Mood: 5, Evolution: "Lonely"}, nil
+		}
+		return PetState{}, err
+	}
+	var state PetState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return PetState{}, err
+	}
+	return state, nil
+}
+
+func Save(state PetState) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	previous, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	// The change log backs gh pet undo; losing an entry isn't worth failing
+	// the save over.
+	if err := recordChange(Command, previous, data); err != nil {
+		logging.Logger.Debug("change log", "err", err)
+	}
+	return nil
+}
+
+// Path is the pet state file: $GITPET_STATE_FILE, else the one in
+// the data directory.
+func Path() (string, error) {
+	if path := os.Getenv("GITPET_STATE_FILE"); path != "" {
+		return path, nil
+	}
+	return dirs.DataPath(dirs.StateFileName)
+}
+
+// Command is the full name of the command being run, such as "skin use",
+// or "mcp" in the MCP server, for the change log.
+var Command string
+
+// RepoWeather is the latest workflow outcome on each of your branches in one
+// repository.
+type RepoWeather struct {
+	Repo    string   `json:"repo"`
+	Red     []string `json:"red,omitempty"`
+	Passing []string `json:"passing,omitempty"`
+}
+
+func (w RepoWeather) Icon() string {
+	if len(w.Red) > 0 {
+		return "⛈️"
+	}
+	return "☀️"
+}
+
+// Anxious reports whether a red build is weighing on the pet.
+func (s PetState) Anxious() bool {
+	for _, w := range s.CI {
+		if len(w.Red) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Away reports whether the Keeper is on holiday at now.
+func (s PetState) Away(now time.Time) bool {
+	return s.AwayUntil != "" && now.Format(DayLayout) <= s.AwayUntil
+}
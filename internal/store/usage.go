@@ -0,0 +1,79 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/logging"
+)
+
+const usageFileName = "gh-pet-usage.json"
+
+// Usage is the opt-in anonymous usage counter: how many times each command or
+// MCP tool ran, and nothing else. It stays on this machine; share it with
+// `gh pet telemetry show` when reporting a bug.
+type Usage struct {
+	Since  time.Time      `json:"since"`
+	Counts map[string]int `json:"counts"`
+}
+
+// CountUsage bumps name's counter when telemetry is on. Failures are only
+// logged: counting must never break a command.
+func CountUsage(telemetry bool, name string) {
+	if !telemetry {
+		return
+	}
+	usage, err := LoadUsage()
+	if err != nil {
+		logging.Logger.Debug("usage counter unreadable", "err", err)
+		return
+	}
+	usage.Counts[name]++
+	if err := saveUsage(usage); err != nil {
+		logging.Logger.Debug("usage counter not saved", "err", err)
+	}
+}
+
+func LoadUsage() (Usage, error) {
+	usage := Usage{Since: time.Now().UTC(), Counts: map[string]int{}}
+	path, err := UsagePath()
+	if err != nil {
+		return usage, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return usage, nil
+		}
+		return usage, err
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return usage, err
+	}
+	if usage.Counts == nil {
+		usage.Counts = map[string]int{}
+	}
+	return usage, nil
+}
+
+func saveUsage(usage Usage) error {
+	path, err := UsagePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func UsagePath() (string, error) {
+	return dirs.DataPath(usageFileName)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+func runJournal(args []string) error {
+	fs := newFlagSet("journal")
+	since := fs.String("since", "", "only entries on or after this date (YYYY-MM-DD)")
+	until := fs.String("until", "", "only entries on or before this date (YYYY-MM-DD)")
+	last := fs.Int("last", 0, "only the most recent N entries")
+	export := fs.String("export", "", "write the entries to a Markdown file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	journal, err := store.LoadJournal()
+	if err != nil {
+		return err
+	}
+	entries, err := filterJournal(journal.Entries, *since, *until)
+	if err != nil {
+		return err
+	}
+	if *last > 0 && len(entries) > *last {
+		entries = entries[len(entries)-*last:]
+	}
+	if len(entries) == 0 {
+		fmt.Println("The journal has no entries here yet. Feed your pet or commit to fill it.")
+		return nil
+	}
+
+	state, _ := store.Load()
+	if *export != "" {
+		if err := os.WriteFile(*export, []byte(journalMarkdown(state, entries)), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("%s✓ Exported %d journal entries to %s%s\n", colorGreen, len(entries), *export, colorReset)
+		return nil
+	}
+	fmt.Printf("\n%s📔 %s's journal%s\n\n", colorBold, state.DisplayName(), colorReset)
+	for _, e := range entries {
+		fmt.Printf("%s%s%s  %s\n", colorDim, e.Time.Local().Format("2006-01-02 15:04"), colorReset, e.Text)
+	}
+	return nil
+}
+
+func filterJournal(entries []store.JournalEntry, since, until string) ([]store.JournalEntry, error) {
+	from, to := time.Time{}, time.Time{}
+	if since != "" {
+		t, err := time.ParseInLocation(store.DayLayout, since, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("--since: expected YYYY-MM-DD, got %q", since)
+		}
+		from = t
+	}
+	if until != "" {
+		t, err := time.ParseInLocation(store.DayLayout, until, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("--until: expected YYYY-MM-DD, got %q", until)
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	var out []store.JournalEntry
+	for _, e := range entries {
+		if !from.IsZero() && e.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !e.Time.Before(to) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func journalMarkdown(state store.PetState, entries []store.JournalEntry) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s %s's journal\n", state.Signature(), state.DisplayName()))
+	date := ""
+	for _, e := range entries {
+		local := e.Time.Local()
+		if d := local.Format(store.DayLayout); d != date {
+			date = d
+			sb.WriteString(fmt.Sprintf("\n## %s\n\n", local.Format("Monday, January 2, 2006")))
+		}
+		sb.WriteString(fmt.Sprintf("- *%s* — %s\n", local.Format("15:04"), e.Text))
+	}
+	return sb.String()
+}
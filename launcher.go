@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// alfredItem is one row of an Alfred Script Filter.
+type alfredItem struct {
+	UID      string `json:"uid,omitempty"`
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+	Arg      string `json:"arg,omitempty"`
+	Valid    bool   `json:"valid"`
+}
+
+// reviewRequestsURL lists the pull requests waiting on the signed-in user.
+const reviewRequestsURL = "https://github.com/pulls/review-requested"
+
+// runPromptCommand prints the prompt pet, or with --raycast or --alfred
+// the same glance in the format those launchers read.
+func runPromptCommand(args []string) error {
+	fs := newFlagSet("prompt")
+	raycast := fs.Bool("raycast", false, "print one plain line for a Raycast script command in inline mode")
+	alfred := fs.Bool("alfred", false, "print Alfred Script Filter JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *raycast && *alfred {
+		return usageErrorf("--raycast and --alfred can't be combined")
+	}
+	if !*raycast && !*alfred {
+		runPrompt()
+		return nil
+	}
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if *raycast {
+		fmt.Println(raycastLine(state, time.Now()))
+		return nil
+	}
+	data, err := json.Marshal(map[string][]alfredItem{"items": alfredItems(state, time.Now())})
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// raycastLine is the glance a Raycast inline script command shows next to
+// its title, which comes from the script's own metadata.
+func raycastLine(state store.PetState, now time.Time) string {
+	if professional() {
+		return professionalPrompt(state)
+	}
+	evolution := state.Evolution
+	if evolution == "" {
+		evolution = "Lonely"
+	}
+	line := fmt.Sprintf("%s %s · %s · %s · %s%d", state.Signature(), state.DisplayName(), evolution, moodDescriptor(state.Mood), promptBar(state.Mood), state.Mood)
+	if state.ReviewQueue > 0 {
+		line += fmt.Sprintf(" · 📬%d", state.ReviewQueue)
+	}
+	if last, err := time.Parse(time.RFC3339, state.LastSync); err == nil && now.Sub(last) >= 24*time.Hour {
+		line += " · fed " + i18n.ShortAge(now.Sub(last)) + " ago"
+	}
+	return line
+}
+
+// alfredItems are the pet and, when pull requests await review, a row that
+// opens them.
+func alfredItems(state store.PetState, now time.Time) []alfredItem {
+	evolution := state.Evolution
+	if evolution == "" {
+		evolution = "Lonely"
+	}
+	pet := alfredItem{
+		UID:      "gitpet",
+		Title:    fmt.Sprintf("%s %s the %s", state.Signature(), state.DisplayName(), evolution),
+		Subtitle: fmt.Sprintf("Mood %d/100 (%s) · Kindness %d · Logic shards %d", state.Mood, moodDescriptor(state.Mood), state.Kindness, state.Logic),
+	}
+	if last, err := time.Parse(time.RFC3339, state.LastSync); err == nil {
+		pet.Subtitle += " · fed " + i18n.RelativeTime(last, now)
+	}
+	items := []alfredItem{pet}
+	if state.ReviewQueue > 0 {
+		items = append(items, alfredItem{
+			UID:      "gitpet-reviews",
+			Title:    fmt.Sprintf("📬 %s awaiting your review", i18n.Plural(state.ReviewQueue, "pull request")),
+			Subtitle: "Open them on GitHub",
+			Arg:      reviewRequestsURL,
+			Valid:    true,
+		})
+	}
+	if professional() {
+		for i := range items {
+			items[i].Title = neutralize(items[i].Title)
+		}
+	}
+	return items
+}
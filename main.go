@@ -2,186 +2,467 @@ package main
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
-)
-
-type PetState struct {
-	Version   int             `json:"version"`
-	LastSync  string          `json:"last_sync"`
-	Mood      int             `json:"mood"`
-	Kindness  int             `json:"kindness"`
-	Logic     int             `json:"logic_shards"`
-	Evolution string          `json:"evolution"`
-	Activity  ActivitySummary `json:"activity"`
-}
-
-type ActivitySummary struct {
-	Commits        int `json:"commits"`
-	MergedPRs      int `json:"merged_prs"`
-	Reviews        int `json:"reviews"`
-	DocComments    int `json:"doc_comments"`
-	RefactorCommits int `json:"refactor_commits"`
-	NewRepos       int `json:"new_repos"`
-	LargeCommits   int `json:"large_commits"`
-	Thoughts       int `json:"thought_fragments"`
-	FixCommits     int `json:"fix_commits"`
-	DocCommits     int `json:"doc_commits"`
-}
-
-type Event struct {
-	Type      string          `json:"type"`
-	CreatedAt time.Time       `json:"created_at"`
-	Payload   json.RawMessage `json:"payload"`
-}
-
-type PushPayload struct {
-	Size    int `json:"size"`
-	Commits []struct {
-		Message string `json:"message"`
-	} `json:"commits"`
-}
-
-type PullRequestPayload struct {
-	PullRequest struct {
-		Merged bool `json:"merged"`
-	} `json:"pull_request"`
-}
 
-type CreatePayload struct {
-	RefType string `json:"ref_type"`
-}
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+	"golang.org/x/sync/errgroup"
+)
 
 const (
-	configFileName = "gh-pet.json"
-	colorRed       = "\x1b[31m"
-	colorGreen     = "\x1b[32m"
-	colorYellow    = "\x1b[33m"
-	colorBlue      = "\x1b[34m"
-	colorMagenta   = "\x1b[35m"
-	colorCyan      = "\x1b[36m"
-	colorGrey      = "\x1b[37m"
-	colorBold      = "\x1b[1m"
-	colorDim       = "\x1b[2m"
-	colorReset     = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+	colorGrey    = "\x1b[37m"
+	colorBold    = "\x1b[1m"
+	colorDim     = "\x1b[2m"
+	colorReset   = "\x1b[0m"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		usage()
-		os.Exit(1)
+	// --verbose may appear anywhere; strip it before commands parse flags.
+	verbose := false
+	args := os.Args[:1]
+	for _, arg := range os.Args[1:] {
+		if arg == "--verbose" {
+			verbose = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+	logging.Setup(verbose)
+	cfg, _ := loadConfig()
+	cfg.Apply()
+	if cfg.Mode != "" {
+		outputMode = cfg.Mode
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "__complete" {
+		// Runs on every tab press, so it skips logging and usage counting.
+		runComplete(os.Args[2:])
+		return
 	}
 	rand.Seed(time.Now().UnixNano())
+	enableVirtualTerminal()
+	start := time.Now()
 
-	switch os.Args[1] {
-	case "feed":
-		if err := runFeed(); err != nil {
-			fatal(err)
+	command, err := dispatch(commands, "", os.Args[1:])
+	if err != nil {
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			if !usageErr.shown {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				fmt.Fprintf(os.Stderr, "Run '%s' for usage.\n", helpCommand(command))
+			}
+			os.Exit(exitUsage)
 		}
-	case "status":
-		if err := runStatus(); err != nil {
-			fatal(err)
+		fatal(gh.ExplainAccess(err, "gh pet "+command))
+	}
+	logging.Logger.Debug("done", "command", command, "duration", time.Since(start).Round(time.Millisecond))
+	if cfg, err := loadConfig(); err == nil {
+		store.CountUsage(cfg.Telemetry, command)
+	}
+}
+
+// feedResult is what one sync changed, for callers to report however they
+// like.
+type feedResult struct {
+	Before   store.PetState
+	State    store.PetState
+	Summary  ActivitySummary
+	Unlocked []string
+	Hatched  []store.Companion
+	// Plugins are the installed plugins, and PluginNotes what they said
+	// about the activity they added.
+	Plugins     []plugin
+	PluginNotes []string
+	// Why itemizes the changes, as gh pet why shows them.
+	Why store.Explanation
+	// Goals are the verdicts on goals whose period ended since the last feed.
+	Goals []goalReview
+	// QueueCleared is set when the review queue emptied within a day.
+	QueueCleared bool
+	// Reunited is how many days the Keeper was away, on the first feed
+	// after a holiday.
+	Reunited int
+}
+
+// feedPet syncs GitHub activity into the pet and saves it, along with the
+// day's history and a journal entry.
+func feedPet(ctx context.Context, cfg Config) (feedResult, error) {
+	state, _ := store.Load()
+	if state.Departed != "" {
+		return feedResult{}, errDeparted
+	}
+	before := state
+
+	// GitHub and the local repository are read concurrently, so a feed
+	// takes about as long as its slowest call rather than all of them.
+	var (
+		login     string
+		events    []Event
+		languages map[string]int
+		weather   []store.RepoWeather
+		forks     []Event
+		thoughts  int
+		tests     int
+		private   ActivitySummary
+		privErr   error
+		depth     ReviewDepth
+		lines     int
+		large     int
+		newcomers int
+		queue     = -1
+		plugins   []plugin
+		extra     ActivitySummary
+		notes     []string
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		if login, err = ghLogin(gctx); err != nil {
+			return err
 		}
-	case "suggest":
-		if err := runSuggest(); err != nil {
-			fatal(err)
+		if events, err = ghEvents(gctx, login); err != nil {
+			return err
 		}
-	case "post-commit":
-		if err := runPostCommit(); err != nil {
-			fatal(err)
+		events = cfg.repoFilter().Events(cfg.Bots.HumanEvents(events))
+		newcomers = activity.FirstTimerHelps(events, login)
+		var more errgroup.Group
+		more.Go(func() error {
+			languages = activity.LanguageBreakdown(gctx, events)
+			return nil
+		})
+		more.Go(func() error {
+			weather = ciWeather(gctx, login, events)
+			return nil
+		})
+		more.Go(func() error {
+			forks = ghForkEvents(gctx, login)
+			return nil
+		})
+		more.Go(func() error {
+			depth = activity.ReviewDepth(gctx, login, events, time.Duration(cfg.Scoring.QuickReviewHours)*time.Hour)
+			return nil
+		})
+		more.Go(func() error {
+			lines, large = activity.CommitStats(gctx, events)
+			return nil
+		})
+		if cfg.PrivateActivity && !cfg.PublicOnly {
+			more.Go(func() error {
+				private, privErr = activity.PrivateActivity(gctx, login, pet.SummaryCutoff(time.Now()), activity.EventRepos(events), cfg.repoFilter())
+				return nil
+			})
 		}
-	case "install-hook":
-		if err := runInstallHook(); err != nil {
-			fatal(err)
+		return more.Wait()
+	})
+	g.Go(func() error {
+		n, err := fetchReviewQueue(gctx)
+		if err != nil {
+			logging.Logger.Debug("review queue", "err", err)
+			return nil
 		}
-	case "prompt":
-		runPrompt()
-	case "install-prompt":
-		if err := runInstallPrompt(); err != nil {
-			fatal(err)
+		queue = n
+		return nil
+	})
+	g.Go(func() error {
+		plugins = loadPlugins(gctx)
+		extra, notes = pluginActivity(gctx, plugins, pet.SummaryCutoff(time.Now()))
+		return nil
+	})
+	g.Go(func() error {
+		thoughts = localThoughtFragments(gctx)
+		return nil
+	})
+	g.Go(func() error {
+		tests = activity.LocalTestFragments(gctx)
+		return nil
+	})
+	if state.AccountCreated == "" {
+		g.Go(func() error {
+			state.AccountCreated = store.AccountCreated(gctx)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return feedResult{}, err
+	}
+
+	summary := discountCommits(cfg.Scoring, events, summarize(events))
+	summary = pet.DiscountReviews(events, summary, state.CreditedReviews, pet.SummaryCutoff(time.Now()))
+	if privErr != nil {
+		fmt.Fprintln(os.Stderr, "GitPet:", activity.PrivateError(privErr))
+	}
+	summary.AddPrivate(private)
+	summary.ReviewDepth = depth
+	summary.LinesChanged, summary.LargeCommits = lines, large
+	summary.FirstTimers = newcomers
+	summary.Languages = languages
+	summary.Thoughts = thoughts + state.PendingThoughts
+	state.PendingThoughts = 0
+	summary.TestCommits += tests
+	summary.AddPlugin(extra)
+	hatched := hatchCompanions(&state, login, append(events, forks...))
+	reunited := 0
+	if history, err := store.LoadHistory(); err == nil {
+		reunited = comeHome(&state, history, time.Now(), false)
+	}
+	scoring := cfg.Scoring.WithAbility(state.Evolution, time.Now())
+	if cfg.Wellness.IsRestDay(time.Now()) || state.Away(time.Now()) {
+		scoring.IdleMoodDecay = 0
+	}
+	applyActivity(scoring, &state, summary)
+	summary.FixedBuilds = applyCIWeather(&state, weather, cfg.Scoring)
+
+	state.Evolution = pet.EvolutionFor(summary)
+	state.Activity = summary
+	state.LastSync = time.Now().UTC().Format(time.RFC3339)
+	state.Login = login
+	state.Version = 1
+	unlocked := store.UnlockAchievements(&state)
+	// Event quests count from history, so it has to include this feed.
+	if err := store.RecordHistory(events, state.Mood); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not record history:", err)
+	}
+	unlocked = append(unlocked, store.AwardEventBadges(&state, time.Now())...)
+	beforeGoals := state
+	var goals []goalReview
+	if history, err := store.LoadHistory(); err == nil {
+		goals = reviewGoals(cfg, &state, history, time.Now())
+	}
+	beforeQueue := state.Kindness
+	queueCleared := queue >= 0 && trackReviewQueue(&state, queue, cfg.Scoring.QueueKindness, time.Now())
+	if trackNeglect(cfg, &state, time.Now()) {
+		if _, err := driftIntoVoid(&state, time.Now()); err != nil {
+			fmt.Fprintln(os.Stderr, "GitPet: could not dig a grave:", err)
 		}
-	case "-h", "--help", "help":
-		usage()
-	default:
-		usage()
-		os.Exit(1)
 	}
-}
 
-func usage() {
-	fmt.Println("GitPet (gh extension)")
-	fmt.Println("Usage: gh pet <command>")
-	fmt.Println("Commands: feed | status | suggest | post-commit | install-hook | prompt | install-prompt")
+	if err := store.Save(state); err != nil {
+		return feedResult{}, err
+	}
+	if err := store.WriteJournal("feed", before, state, unlocked, "", summary.CoAuthors); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+	why := store.ExplainFeed(scoring, before, beforeGoals, summary, summary.FixedBuilds)
+	why.Itemize("mood", "goals met", goalsMet(goals), cfg.Scoring.GoalMood, beforeGoals.Mood, state.Mood)
+	why.Itemize("kindness", "review queue cleared", 1, cfg.Scoring.QueueKindness, beforeQueue, state.Kindness)
+	if queueCleared {
+		if err := store.AddJournalEntry("queue", "You cleared every review waiting on you within a day. Your teammates are grateful, and so am I."); err != nil {
+			fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+		}
+	}
+	for _, r := range goals {
+		if err := store.AddJournalEntry("goal", r.message()); err != nil {
+			fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+		}
+	}
+	if err := store.RecordExplanation(why); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not record why:", err)
+	}
+	notifyChanges(cfg.Notifications, before, state, unlocked)
+	if !cfg.Notifications.QuietHours.active(time.Now()) {
+		playChanges(cfg.Sounds, before, state, unlocked)
+	}
+	runEventHooks(cfg.Hooks, before, state, unlocked)
+	gh.LogRateLimit(ctx)
+	return feedResult{Before: before, State: state, Summary: summary, Unlocked: unlocked, Hatched: hatched, Plugins: plugins, PluginNotes: notes, Why: why, Goals: goals, QueueCleared: queueCleared, Reunited: reunited}, nil
 }
 
-func runFeed() error {
-	state, _ := loadState()
-
-	login, err := ghLogin()
+func runFeed(args []string) error {
+	fs := newFlagSet("feed")
+	var repos activity.RepoList
+	fs.Var(&repos, "repo", "feed only activity from this owner/repo, which may use *; repeat for more")
+	login := fs.String("login", "", "feed this GitHub account's pet instead of the configured or active one")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if err := activity.ValidateRepoPatterns(repos); err != nil {
+		return usageErrorf("--repo: %v", err)
+	}
+	if err := dirs.PinAccount(*login); err != nil {
+		return usageErrorf("--login: %v", err)
+	}
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
-
-	events, err := ghEvents(login)
+	cfg.onlyRepos = repos
+	stop := startSpinner("Fetching your GitHub activity…")
+	result, err := feedPet(context.Background(), cfg)
+	stop()
 	if err != nil {
 		return err
 	}
-
-	summary := summarize(events)
-	thoughts := localThoughtFragments()
-	summary.Thoughts = thoughts
-
-	activityTotal := summary.Commits + summary.MergedPRs + summary.Reviews + summary.DocComments + summary.RefactorCommits + summary.NewRepos
-	state.Logic += summary.Commits + summary.MergedPRs*3
-	state.Kindness += summary.Reviews * 2
-	if activityTotal == 0 {
-		state.Mood = max(0, state.Mood-1)
-	} else {
-		state.Mood = min(100, state.Mood+summary.Commits+summary.MergedPRs*5+summary.Reviews+summary.DocComments)
+	state, summary := result.State, result.Summary
+	if state.Departed != "" {
+		fmt.Printf("%s🌌 After %s or more at zero mood, %s drifted into the Void.%s\n", colorDim, i18n.Plural(cfg.VoidDays, "day"), state.DisplayName(), colorReset)
+		fmt.Println("Run gh pet graveyard to remember it, or gh pet hatch for a new egg.")
+		return nil
 	}
-	if thoughts > 0 {
-		state.Mood = min(100, state.Mood+1)
+	if result.Reunited > 0 {
+		printReunion(state, result.Reunited)
 	}
-
-	state.Evolution = evolutionFor(summary)
-	state.Activity = summary
-	state.LastSync = time.Now().UTC().Format(time.RFC3339)
-	state.Version = 1
-
-	if err := saveState(state); err != nil {
-		return err
+	if professional() {
+		printProfessionalFeed(result)
+		return nil
 	}
 
 	if summary.LargeCommits > 0 {
 		shake()
 	}
 
-	fmt.Println("Fed GitPet with fresh activity.")
+	fmt.Printf("Fed %s with fresh activity.\n", state.DisplayName())
 	fmt.Printf("Commits: %d | Merged PRs: %d | Reviews: %d | Docs/Comments: %d\n", summary.Commits, summary.MergedPRs, summary.Reviews, summary.DocComments)
+	if summary.LinesChanged > 0 {
+		fmt.Printf("%s📏 %s changed, %s of %d or more.%s\n", colorDim, i18n.Plural(summary.LinesChanged, "line"), i18n.Plural(summary.LargeCommits, "large commit"), activity.LargeCommitLines, colorReset)
+	}
+	if summary.Private > 0 {
+		fmt.Printf("%s🔒 %s from private repos included.%s\n", colorDim, i18n.Plural(summary.Private, "contribution"), colorReset)
+	}
+	for _, note := range result.PluginNotes {
+		fmt.Printf("%s🧩 %s%s\n", colorDim, note, colorReset)
+	}
+	if summary.IgnoredCommits > 0 {
+		fmt.Printf("%s%s earned nothing: repeats, throwaway branches, or past the hourly allowance.%s\n", colorDim, i18n.Plural(summary.IgnoredCommits, "commit"), colorReset)
+	}
+	if summary.FixedBuilds > 0 {
+		fmt.Printf("🧯 Firefighter! You fixed %s. +%d mood\n", i18n.Plural(summary.FixedBuilds, "red build"), summary.FixedBuilds*cfg.Scoring.FirefighterMood)
+	}
+	if state.Anxious() {
+		fmt.Printf("%s😰 Red builds are making me anxious. Fix them to get my mood back.%s\n", colorYellow, colorReset)
+	}
 	if summary.MergedPRs > 0 {
-		printFireworks(state.Evolution)
+		printFireworks(state.Evolution, cfg.activeTheme())
+	}
+	fmt.Printf("Mood: %d | Kindness: %d | Logic Shards: %d | Mentor: %d\n", state.Mood, state.Kindness, state.Logic, state.Mentor)
+	if d := summary.ReviewDepth; d.Approvals+d.ChangeRequests+d.Comments > 0 {
+		fmt.Printf("%s🧭 Reviews: %d approved, %d asked for changes, %s left.%s\n", colorDim, d.Approvals, d.ChangeRequests, i18n.Plural(d.Comments, "comment"), colorReset)
+	}
+	if summary.FirstTimers > 0 {
+		fmt.Printf("🌱 You helped %s find their way. +%d kindness\n", i18n.Plural(summary.FirstTimers, "first-time contributor"), summary.FirstTimers*cfg.Scoring.FirstTimerKindness)
+	}
+	if summary.DuetCommits > 0 {
+		fmt.Printf("🎶 Paired with %s on %s. +%d kindness\n", i18n.JoinNames(summary.CoAuthors), i18n.Plural(summary.DuetCommits, "commit"), summary.DuetCommits*cfg.Scoring.DuetKindness)
 	}
-	fmt.Printf("Mood: %d | Kindness: %d | Logic Shards: %d\n", state.Mood, state.Kindness, state.Logic)
 	fmt.Printf("Evolution: %s\n", state.Evolution)
+	for _, r := range result.Goals {
+		fmt.Println(r.message())
+	}
+	if result.QueueCleared {
+		fmt.Printf("📬 Review queue cleared within a day. +%d kindness\n", cfg.Scoring.QueueKindness)
+	} else if state.ReviewQueue > 0 {
+		fmt.Printf("%s%s%s\n", colorYellow, reviewQueueLine(state.ReviewQueue), colorReset)
+	}
+	for _, name := range result.Unlocked {
+		fmt.Printf("%s🏆 Achievement unlocked: %s%s\n", colorBold, name, colorReset)
+	}
+	for _, c := range result.Hatched {
+		fmt.Printf("🐣 A companion hatched from %s: %s %s\n", c.Fork, c.Sprite, c.DisplayName())
+	}
+	for _, r := range pluginReactions(context.Background(), result.Plugins, result) {
+		if r.Art != "" {
+			fmt.Println(r.Art)
+		}
+		if r.Message != "" {
+			fmt.Printf("🧩 %s: %s\n", r.Plugin, r.Message)
+		}
+	}
+	if history, err := store.LoadHistory(); err == nil {
+		if warning, ok := streakWarning(cfg.Notifications, history, time.Now()); ok {
+			fmt.Printf("%s⏳ %s%s\n", colorYellow, warning, colorReset)
+			notify(cfg.Notifications, fmt.Sprintf("%s %s misses you", state.Signature(), state.DisplayName()), warning)
+		}
+	}
 	return nil
 }
 
+// promptBudget is how long the shell prompt waits for the pet. On a slow or
+// unreachable home directory it shows a bare paw rather than stall the shell.
+const promptBudget = 200 * time.Millisecond
+
 func runPrompt() {
-	state, _ := loadState()
+	line := make(chan string, 1)
+	go func() {
+		state, _ := store.Load()
+		var branch *branchState
+		if cfg, err := loadConfig(); err == nil && cfg.Prompt.Branch {
+			ctx, cancel := context.WithTimeout(context.Background(), promptGitBudget)
+			if b, ok := readBranchState(ctx); ok {
+				branch = &b
+			}
+			cancel()
+		}
+		showPending(time.Now())
+		if professional() {
+			line <- professionalPrompt(state)
+			return
+		}
+		line <- terminalRenderer{now: time.Now()}.Prompt(state, branch)
+	}()
+	select {
+	case l := <-line:
+		fmt.Print(l)
+	case <-time.After(promptBudget):
+		logging.Logger.Debug("prompt over budget", "budget", promptBudget)
+		fmt.Print("🐾")
+	}
+}
+
+// promptLine renders the prompt pet, with branch details when branch is
+// set.
+func promptLine(state store.PetState, branch *branchState, now time.Time) string {
 	if state.Evolution == "" {
 		state.Evolution = "Lonely"
 	}
-	// Compact one-line prompt: 🐾Pioneer(◕‿◕)██░░░░░░░░
+	tag := ""
+	if branch != nil {
+		tag = branch.promptTag()
+	}
+	if store.IsAsleep(now) {
+		return state.Signature() + "💤 zzz" + tag
+	}
+	// Compact one-line prompt: 🐾(◕‿◕)██░░░░░░░░Pioneer, plus " ·3d" when
+	// the last feed is old.
 	face := promptFace(state.Mood)
+	if state.Anxious() {
+		face = "°□° "
+	}
+	// Nothing frightens the pet like a merge conflict.
+	if branch != nil && branch.Conflicts {
+		face = "⊙﹏⊙ "
+	}
 	bar := promptBar(state.Mood)
-	fmt.Printf("🐾%s%s%s", face, bar, state.Evolution)
+	line := fmt.Sprintf("%s%s%s%s", state.Signature(), face, bar, state.Evolution)
+	if state.ReviewQueue > 0 {
+		line += fmt.Sprintf(" 📬%d", state.ReviewQueue)
+	}
+	// A pet left unfed for a day or more says how long it has waited.
+	if last, err := time.Parse(time.RFC3339, state.LastSync); err == nil && now.Sub(last) >= 24*time.Hour {
+		line += " ·" + i18n.ShortAge(now.Sub(last))
+	}
+	return line + tag
 }
 
 func promptFace(mood int) string {
@@ -218,6 +499,10 @@ func runInstallPrompt() error {
 	}
 	exePath, _ = filepath.Abs(exePath)
 
+	if usePowerShell() {
+		return installPowerShellPrompt(exePath)
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return err
@@ -243,10 +528,12 @@ gitpet_prompt() {
 		rcFile = filepath.Join(home, ".zshrc")
 		snippet = gitpetPrompt + `setopt PROMPT_SUBST
 RPROMPT='$(gitpet_prompt)'
+# End GitPet prompt
 `
 	} else {
 		rcFile = filepath.Join(home, ".bashrc")
 		snippet = gitpetPrompt + `PS1='$(gitpet_prompt)'"$PS1"
+# End GitPet prompt
 `
 	}
 
@@ -282,56 +569,192 @@ RPROMPT='$(gitpet_prompt)'
 	return nil
 }
 
-func runPostCommit() error {
-	state, _ := loadState()
+func runPostCommit(args []string) error {
+	fs := newFlagSet("post-commit")
+	background := fs.Bool("background", false, "sync detached from the hook and leave the card for the next prompt")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	// With async-hook on, the hook only starts the sync, so the commit never
+	// waits on GitHub.
+	if cfg, err := loadConfig(); err == nil && cfg.AsyncHook && !*background {
+		err := spawnBackgroundSync()
+		if err == nil {
+			return nil
+		}
+		fmt.Fprintln(os.Stderr, "GitPet: could not sync in the background, syncing now:", err)
+	}
+	out := io.Writer(os.Stdout)
+	var card strings.Builder
+	if *background {
+		out = &card
+	}
+	ctx := context.Background()
+	state, _ := store.Load()
+	if state.Departed != "" {
+		// A hook mustn't fail the commit; just remind.
+		fmt.Printf("🪦 %s\n", errDeparted)
+		return nil
+	}
+	before := state
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: using default scoring:", err)
+	}
 
-	// Get the latest commit message
+	// Get the latest commit message, and who it was written with
 	commitMsg := ""
-	if out, err := exec.Command("git", "log", "-1", "--pretty=%s").Output(); err == nil {
-		commitMsg = strings.TrimSpace(string(out))
+	var pairs []string
+	if out, err := gh.GitOutput(ctx, "log", "-1", "--pretty=%B"); err == nil {
+		subject, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		commitMsg = strings.TrimSpace(subject)
+		pairs = pet.CoAuthors(string(out))
 	}
 
 	// Auto-sync GitHub activity (replaces manual feed)
-	login, err := ghLogin()
+	var unlocked []string
+	var events []Event
+	login, err := ghLogin(ctx)
 	if err == nil {
-		events, err := ghEvents(login)
+		state.Login = login
+		fetched, err := ghEvents(ctx, login)
 		if err == nil {
-			summary := summarize(events)
+			events = cfg.repoFilter().Events(cfg.Bots.HumanEvents(fetched))
+			summary := discountCommits(cfg.Scoring, events, summarize(events))
+			summary = pet.DiscountReviews(events, summary, state.CreditedReviews, pet.SummaryCutoff(time.Now()))
+			summary.Languages = activity.LanguageBreakdown(ctx, events)
+			// Line counts come from a feed; the hook adds only its own commit.
+			summary.LinesChanged, summary.LargeCommits = state.Activity.LinesChanged, state.Activity.LargeCommits
 			state.Activity = summary
-			state.Evolution = evolutionFor(summary)
-			state.Logic += summary.Commits + summary.MergedPRs*3
-			state.Kindness += summary.Reviews * 2
+			state.Evolution = pet.EvolutionFor(summary)
+			unlocked = store.UnlockAchievements(&state)
+			state.Logic += cfg.Scoring.LogicFor(summary)
+			state.Kindness += cfg.Scoring.WithAbility(before.Evolution, time.Now()).KindnessFor(summary)
 		}
 	}
 
-	// Boost mood for this commit
-	state.Mood = min(100, state.Mood+3)
-	state.Logic += 1
+	// The first commit after a holiday is a homecoming.
+	reunited := 0
+	if history, err := store.LoadHistory(); err == nil {
+		reunited = comeHome(&state, history, time.Now(), false)
+	}
+
+	// Boost mood for this commit, with a bonus shard for touching tests
+	state.Mood = min(100, state.Mood+cfg.Scoring.PostCommitMood)
+	state.Logic += cfg.Scoring.CommitLogic
+	if activity.LastCommitTouchesTests(ctx) {
+		state.Activity.TestCommits++
+		state.Logic += cfg.Scoring.TestLogic
+		state.Evolution = pet.EvolutionFor(state.Activity)
+	}
+	if lines, ok := activity.LastCommitLines(ctx); ok {
+		state.Activity.LinesChanged += lines
+		if lines >= activity.LargeCommitLines {
+			state.Activity.LargeCommits++
+		}
+		unlocked = append(unlocked, store.UnlockAchievements(&state)...)
+	}
+	// A commit made together is kind before it's even pushed.
+	if len(pairs) > 0 {
+		state.Activity.AddCoAuthors(pairs)
+		state.Kindness += cfg.Scoring.DuetKindness
+		unlocked = append(unlocked, store.UnlockAchievements(&state)...)
+	}
 	state.LastSync = time.Now().UTC().Format(time.RFC3339)
 	state.Version = 1
 	if state.Evolution == "" || state.Evolution == "Lonely" {
 		state.Evolution = "Pioneer"
 	}
 
-	if err := saveState(state); err != nil {
+	if err := store.RecordHistory(events, state.Mood); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not record history:", err)
+	}
+	unlocked = append(unlocked, store.AwardEventBadges(&state, time.Now())...)
+
+	if err := store.Save(state); err != nil {
 		return err
 	}
+	if err := store.WriteJournal("post-commit", before, state, unlocked, commitMsg, pairs); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+
+	// Quiet hours get one line and no sounds.
+	if cfg.Notifications.QuietHours.active(time.Now()) {
+		fmt.Fprintln(out, quietPostCommit(state, cfg.Scoring.PostCommitMood, unlocked))
+		runEventHooks(cfg.Hooks, before, state, unlocked)
+		if *background {
+			return savePending(card.String())
+		}
+		return nil
+	}
+	if professional() {
+		fmt.Fprintln(out, professionalPostCommit(state, time.Now()))
+		if reunited > 0 {
+			fmt.Fprintf(out, "Back after %s away.\n", i18n.Plural(reunited, "day"))
+		}
+		for _, name := range unlocked {
+			fmt.Fprintf(out, "Achievement: %s.\n", name)
+		}
+		runEventHooks(cfg.Hooks, before, state, unlocked)
+		if *background {
+			return savePending(card.String())
+		}
+		return nil
+	}
 
 	// Proactively display GitPet status with praise
-	fmt.Println()
-	fmt.Println(renderPostCommit(state, commitMsg))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, terminalRenderer{theme: cfg.activeTheme()}.PostCommit(state, commitMsg, cfg.Scoring.PostCommitMood))
+	if reunited > 0 {
+		fmt.Fprintln(out, reunionLine(state, reunited))
+	}
+	for _, name := range unlocked {
+		fmt.Fprintf(out, "%s🏆 Achievement unlocked: %s%s\n", colorBold, name, colorReset)
+	}
+	notifyChanges(cfg.Notifications, before, state, unlocked)
+	playChanges(cfg.Sounds, before, state, unlocked)
+	runEventHooks(cfg.Hooks, before, state, unlocked)
+	history, _ := store.LoadHistory()
+	concerns := pet.WellnessConcerns(state.Activity, store.CurrentStreak(history, time.Now()), cfg.Wellness)
+	if nudge := pet.PostCommitNudge(time.Now(), concerns); nudge != "" {
+		fmt.Fprintf(out, "%s%s%s\n", colorDim, nudge, colorReset)
+	}
+	if reminder := postCommitReminder(ctx, cfg.WIP); reminder != "" {
+		fmt.Fprintf(out, "%s%s%s\n", colorDim, reminder, colorReset)
+	}
+	if *background {
+		// Nobody is watching the hook any more, so say it's done.
+		notify(cfg.Notifications, fmt.Sprintf("%s %s synced", state.Signature(), state.DisplayName()),
+			fmt.Sprintf("Mood %d · %s · %s", state.Mood, state.Evolution, orDefault(commitMsg, "new commit")))
+		return savePending(card.String())
+	}
 	return nil
 }
 
-func runInstallHook() error {
+func runInstallHook(args []string) error {
+	fs := newFlagSet("install-hook")
+	shell := fs.String("shell", hookShellSh, "hook script flavor: sh, powershell, or cmd")
+	hook := fs.String("hook", "post-commit", "which git hook to install: post-commit, commit-msg, or pre-commit")
+	strict := fs.Bool("strict", false, "commit-msg/pre-commit: block the commit instead of only warning")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	var flags []string
+	if *strict {
+		if *hook != "commit-msg" && *hook != "pre-commit" {
+			return fmt.Errorf("--strict only applies to --hook commit-msg or pre-commit")
+		}
+		flags = append(flags, "--strict")
+	}
+
 	// Find the git root
-	out, err := exec.Command("git", "rev-parse", "--git-dir").Output()
+	out, err := gh.GitOutput(context.Background(), "rev-parse", "--git-dir")
 	if err != nil {
 		return fmt.Errorf("not a git repository")
 	}
 	gitDir := strings.TrimSpace(string(out))
 	hookDir := filepath.Join(gitDir, "hooks")
-	hookPath := filepath.Join(hookDir, "post-commit")
+	hookPath := filepath.Join(hookDir, *hook)
 
 	// Get the absolute path to gh-pet binary
 	exePath, err := os.Executable()
@@ -340,10 +763,10 @@ func runInstallHook() error {
 	}
 	exePath, _ = filepath.Abs(exePath)
 
-	hookContent := fmt.Sprintf(`#!/usr/bin/env bash
-# GitPet post-commit hook — auto-feed & show status
-"%s" post-commit
-`, exePath)
+	hookContent, siblings, err := hookScripts(*shell, *hook, exePath, flags)
+	if err != nil {
+		return err
+	}
 
 	if err := os.MkdirAll(hookDir, 0o755); err != nil {
 		return err
@@ -359,110 +782,213 @@ func runInstallHook() error {
 		hookContent = string(data) + "\n" + hookContent
 	}
 
+	for name, content := range siblings {
+		if err := os.WriteFile(filepath.Join(hookDir, name), []byte(content), 0o755); err != nil {
+			return err
+		}
+	}
 	if err := os.WriteFile(hookPath, []byte(hookContent), 0o755); err != nil {
 		return err
 	}
-	fmt.Printf("%s✓ GitPet post-commit hook installed!%s\n", colorGreen, colorReset)
+	if err := trackRepo(); err != nil {
+		fmt.Fprintf(os.Stderr, "  could not record repo for uninstall: %v\n", err)
+	}
+	fmt.Printf("%s✓ GitPet %s hook installed!%s\n", colorGreen, *hook, colorReset)
 	fmt.Printf("  → %s\n", hookPath)
-	fmt.Println("  GitPet will now auto-show after every commit 🐾")
+	switch *hook {
+	case "commit-msg":
+		fmt.Println("  GitPet will now grade every commit message 📝")
+	case "pre-commit":
+		fmt.Println("  GitPet will now look over staged changes before each commit 🔍")
+	default:
+		fmt.Println("  GitPet will now auto-show after every commit 🐾")
+	}
 	return nil
 }
 
-func runStatus() error {
-	state, _ := loadState()
+func runStatus(args []string) error {
+	fs := newFlagSet("status")
+	absolute := fs.Bool("absolute", false, "show exact times in your time zone instead of \"2h ago\"")
+	graphics := fs.String("graphics", "auto", "auto, ascii, or pixel: draw the pet as a pixel-art sprite on terminals that show images")
+	login := fs.String("login", "", "show this GitHub account's pet instead of the configured or active one")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if err := dirs.PinAccount(*login); err != nil {
+		return usageErrorf("--login: %v", err)
+	}
+	protocol, err := spriteGraphics(*graphics)
+	if err != nil {
+		return err
+	}
+	if !petExists() {
+		if *login != "" {
+			fmt.Printf("🥚 @%s has no pet yet. Run gh pet feed --login %s to hatch one.\n", dirs.Account, dirs.Account)
+			return nil
+		}
+		fmt.Println("🥚 There's no pet yet. Run gh pet hatch to meet yours.")
+		return nil
+	}
+	state, _ := store.Load()
 	if state.Evolution == "" {
 		state.Evolution = "Lonely"
 	}
-	fmt.Println(renderStatus(state))
+	if state.Departed != "" {
+		return printDeparted(state)
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if professional() {
+		fmt.Print(renderProfessionalStatus(state, *absolute))
+		return nil
+	}
+	history, _ := store.LoadHistory()
+	concerns := pet.WellnessConcerns(state.Activity, store.CurrentStreak(history, time.Now()), cfg.Wellness)
+	render := terminalRenderer{theme: cfg.activeTheme(), absolute: *absolute, sprite: protocol != "", now: time.Now()}
+	card := render.Status(state, concerns) + "\n"
+	if protocol != "" {
+		withSprite, ok := overlaySprite(card, spriteFor(state, time.Now()), protocol)
+		if !ok {
+			render.sprite = false
+			withSprite = render.Status(state, concerns) + "\n"
+		}
+		card = withSprite
+	}
+	fmt.Print(card)
+	if desk, err := loadHelpDesk(); err == nil {
+		if lines := helpRequestLines(desk, cfg.Maintainer.sla(), time.Now()); len(lines) > 0 {
+			fmt.Println("🙋 " + i18n.Tr("Requests for help:"))
+			for _, line := range lines {
+				fmt.Println(line)
+			}
+		}
+	}
+	printGoals(cfg.Goals, time.Now())
+	if state.ReviewQueue > 0 {
+		fmt.Println(reviewQueueLine(state.ReviewQueue))
+	}
+	if lines := wipReminders(cfg.WIP, 3); len(lines) > 0 {
+		fmt.Println("🧺 " + i18n.Tr("Forgotten work:"))
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+	}
 	return nil
 }
 
-func runSuggest() error {
-	state, _ := loadState()
-	personality := state.Evolution
-	if personality == "" || personality == "Lonely" {
-		personality = "Companion"
-	}
-	prompt := fmt.Sprintf("Generate 5 creative git commit messages in the voice of the %s GitPet. Mood: %s. Be supportive and witty, one line each.", personality, moodDescriptor(state.Mood))
-	cmd := exec.Command("gh", "copilot", "suggest", prompt)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
-}
-
-func renderStatus(state PetState) string {
-	color := colorFor(state.Evolution)
-	art := renderArt(state)
-	moodBar := renderMoodBar(state.Mood)
+// renderStatus draws the status card. With sprite, the pet's art is left as
+// blank space for overlaySprite to draw in.
+func renderStatus(state store.PetState, concerns []string, theme Theme, absolute, sprite bool) string {
+	art := renderArt(state, sprite)
+	moodBar := renderMoodBar(state.Mood, theme)
 	face := moodFace(state.Mood)
-	tone := activityTone(state.Activity)
-
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("\n%s%s╭──────────────────────────────────╮%s\n", colorBold, color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s│%s       🐾 GitPet Status           %s│%s\n", color, colorReset, color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s├──────────────────────────────────┤%s\n", color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s│%s  Evolution : %-20s%s│%s\n", color, colorReset, state.Evolution, color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s│%s  Mood      : %s %s%s\n", color, colorReset, moodBar, face, colorReset))
-	sb.WriteString(fmt.Sprintf("%s│%s  Kindness  : %-5d  Shards: %-5d%s│%s\n", color, colorReset, state.Kindness, state.Logic, color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s│%s  Synced    : %-20s%s│%s\n", color, colorReset, displayTime(state.LastSync), color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s├──────────────────────────────────┤%s\n", color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s│%s  7d: %dc %dp %dr %dd\n", color, colorReset,
-		state.Activity.Commits, state.Activity.MergedPRs, state.Activity.Reviews, state.Activity.DocComments))
-	sb.WriteString(fmt.Sprintf("%s├──────────────────────────────────┤%s\n", color, colorReset))
-	for _, line := range strings.Split(art, "\n") {
-		sb.WriteString(fmt.Sprintf("%s│%s  %s\n", color, colorReset, line))
-	}
-	sb.WriteString(fmt.Sprintf("%s├──────────────────────────────────┤%s\n", color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s│%s  %s\n", color, colorReset, tone))
-	sb.WriteString(fmt.Sprintf("%s╰──────────────────────────────────╯%s\n", color, colorReset))
-	return sb.String()
+	if state.Anxious() {
+		face = "(°□°;)"
+	}
+	tone := activityTone(state.DisplayName(), state.Activity)
+
+	// Field labels share one column, as wide as the longest translation.
+	labels := []string{"Evolution", "Mood", "Kindness", "Synced"}
+	if state.Pronouns != "" {
+		labels = append(labels, "Pronouns")
+	}
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, displayWidth(i18n.Tr(label)))
+	}
+	field := func(label, value string) string {
+		return padRight(i18n.Tr(label), labelWidth) + " : " + value
+	}
+
+	bx := newBox(theme, theme.accent(state.Evolution))
+	bx.title(i18n.Tr("%s Status", state.Signature()+" "+state.DisplayName()))
+	bx.divider()
+	if state.Pronouns != "" {
+		bx.line(field("Pronouns", state.Pronouns))
+	}
+	bx.line(field("Evolution", i18n.Tr(state.Evolution)))
+	bx.line(field("Mood", moodBar+" "+face))
+	stats := fmt.Sprintf("%-5d  %s: %d", state.Kindness, i18n.Tr("Shards"), state.Logic)
+	if state.Mentor > 0 {
+		stats += fmt.Sprintf("  %s: %d", i18n.Tr("Mentor"), state.Mentor)
+	}
+	bx.line(field("Kindness", stats))
+	bx.line(field("Synced", i18n.DisplayTime(state.LastSync, absolute)))
+	bx.divider()
+	bx.line(fmt.Sprintf("7d: %dc %dp %dr %dd %dt",
+		state.Activity.Commits, state.Activity.MergedPRs, state.Activity.Reviews, state.Activity.DocComments, state.Activity.TestCommits))
+	if pet.IssueTriage(state.Activity)+state.Activity.IssueComments > 0 {
+		bx.line(i18n.Tr("Issues: %d opened %d closed %d labeled %d comments",
+			state.Activity.IssuesOpened, state.Activity.IssuesClosed, state.Activity.IssuesLabeled, state.Activity.IssueComments))
+	}
+	if d := state.Activity.ReviewDepth; d.Approvals+d.ChangeRequests+d.Comments > 0 {
+		line := i18n.Tr("Reviews: %d approved %d changes %d comments", d.Approvals, d.ChangeRequests, d.Comments)
+		if d.Requested > 0 {
+			line += " · " + i18n.Tr("answered in %s", i18n.ShortAge(time.Duration(d.LatencyMinutes)*time.Minute))
+		}
+		bx.line(line)
+	}
+	if langs := activity.LanguageLine(state.Activity.Languages); langs != "" {
+		bx.line(i18n.Tr("Langs") + ": " + langs)
+	}
+	for _, line := range ciWeatherLines(state.CI) {
+		bx.line("CI: " + line)
+	}
+	if badges := strings.TrimSpace(store.AchievementBadges(state) + " " + store.EventBadges(state)); badges != "" {
+		bx.line(i18n.Tr("Badges") + ": " + badges)
+	}
+	if a, ok := pet.AbilityFor(state.Evolution); ok {
+		bx.line(i18n.Tr("Ability") + ": " + a.Name)
+	}
+	bx.divider()
+	bx.line(i18n.Tr("Wellness"))
+	if len(concerns) == 0 {
+		bx.line(i18n.Tr("💚 Balanced rhythm. Keep it gentle."))
+	}
+	for _, concern := range concerns {
+		bx.line(concern)
+	}
+	bx.divider()
+	bx.lines(art)
+	bx.divider()
+	bx.line(tone)
+	return "\n" + bx.render(34) + "\n"
 }
 
-func renderPostCommit(state PetState, commitMsg string) string {
-	color := colorFor(state.Evolution)
-	art := renderArt(state)
-	praise := randomPraise()
-	face := moodFace(state.Mood)
-	moodBar := renderMoodBar(state.Mood)
-
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("%s%s╭──── 🐾 GitPet ─────────────────╮%s\n", colorBold, color, colorReset))
-	for _, line := range strings.Split(art, "\n") {
-		sb.WriteString(fmt.Sprintf("%s│%s  %s\n", color, colorReset, line))
-	}
-	sb.WriteString(fmt.Sprintf("%s│%s\n", color, colorReset))
-	sb.WriteString(fmt.Sprintf("%s│%s  %s %s\n", color, colorReset, face, praise))
-	sb.WriteString(fmt.Sprintf("%s│%s  Mood: %s  +3 ⬆\n", color, colorReset, moodBar))
+func renderPostCommit(state store.PetState, commitMsg string, moodGain int, theme Theme) string {
+	bx := newBox(theme, theme.accent(state.Evolution))
+	bx.label = fmt.Sprintf(" %s %s ", state.Signature(), state.DisplayName())
+	bx.lines(renderArt(state, false))
+	bx.line("")
+	bx.line(moodFace(state.Mood) + " " + randomPraise())
+	bx.line(fmt.Sprintf("%s: %s  +%d ⬆", i18n.Tr("Mood"), renderMoodBar(state.Mood, theme), moodGain))
 	if commitMsg != "" {
-		display := commitMsg
-		if len(display) > 28 {
-			display = display[:25] + "..."
-		}
-		sb.WriteString(fmt.Sprintf("%s│%s  📝 %s\n", color, colorReset, display))
+		bx.line("📝 " + truncateWidth(commitMsg, 28))
 	}
-	sb.WriteString(fmt.Sprintf("%s╰──────────────────────────────────╯%s\n", color, colorReset))
-	return sb.String()
+	return bx.render(34) + "\n"
 }
 
-func renderMoodBar(mood int) string {
+func renderMoodBar(mood int, theme Theme) string {
 	filled := mood / 10
 	if filled > 10 {
 		filled = 10
 	}
 	empty := 10 - filled
-	var color string
-	switch {
-	case mood >= 70:
-		color = colorGreen
-	case mood >= 40:
-		color = colorYellow
-	case mood > 0:
-		color = colorRed
-	default:
-		color = colorGrey
+	if theme.Gradient {
+		var sb strings.Builder
+		for i := 0; i < filled; i++ {
+			sb.WriteString(gradientAt(float64(i) / 9).ansi(theme.Depth))
+			sb.WriteString("█")
+		}
+		return sb.String() + theme.Dim + strings.Repeat("░", empty) + theme.Reset
 	}
-	return fmt.Sprintf("%s%s%s%s", color, strings.Repeat("█", filled), colorDim+strings.Repeat("░", empty)+colorReset, colorReset)
+	color := theme.moodColor(mood)
+	return fmt.Sprintf("%s%s%s%s", color, strings.Repeat("█", filled), theme.Dim+strings.Repeat("░", empty)+theme.Reset, theme.Reset)
 }
 
 func moodFace(mood int) string {
@@ -497,21 +1023,58 @@ func randomPraise() string {
 		"Well done! 🏆",
 		"Commit hero! 🦸",
 	}
-	return praises[rand.Intn(len(praises))]
+	return i18n.Tr(praises[rand.Intn(len(praises))])
 }
 
-func renderArt(state PetState) string {
-	art := artFor(state.Evolution)
-	special := ""
-	if state.Evolution == "Pioneer" && rand.Intn(5) == 0 {
-		special = "\n🗝️  Found a tiny treasure chest!"
+func renderArt(state store.PetState, sprite bool) string {
+	if state.Away(time.Now()) {
+		return beachArt + "\n" + awayLine(state)
+	}
+	art, skinned := skinFrame(state.Evolution)
+	switch {
+	case skinned:
+		// A skin is the Keeper's choice of art, even where a sprite could go.
+	case sprite:
+		art = spritePlaceholder()
+	default:
+		art = artFor(state.Evolution)
 	}
+	special := ""
 	if state.Evolution == "Guardian" {
-		special = "\n🛡️  Shielding your logs."
+		special = "\n" + i18n.Tr("🛡️  Shielding your logs.")
 	}
 	if state.Evolution == "Bard" {
-		special = fmt.Sprintf("\n📜 %s", dailyProverb())
+		extra := 0
+		if a, ok := pet.AbilityFor(state.Evolution); ok {
+			extra = a.ExtraProverbs
+		}
+		for _, proverb := range dailyProverbs(1 + extra) {
+			special += fmt.Sprintf("\n📜 %s", proverb)
+		}
+	}
+	if state.Evolution == "Sentinel" {
+		special = "\n" + i18n.Tr("🧪 Every test is a watchtower.")
 	}
+	if state.Evolution == "Curator" {
+		special = "\n" + i18n.Tr("🗂️  Tending the issue garden.")
+	}
+	if pet.FoundTreasure(state.Evolution) {
+		special += "\n" + i18n.Tr("🗝️  Found a tiny treasure chest!")
+	}
+	lang := activity.DominantLanguage(state.Activity.Languages)
+	if accessory := activity.LanguageAccessory(lang); accessory != "" {
+		art = "  " + i18n.Tr(accessory) + "\n" + art
+	}
+	if proverb := activity.LanguageProverb(lang); proverb != "" {
+		special += fmt.Sprintf("\n💬 %s", i18n.Tr(proverb))
+	}
+	sleeping := store.SleepingArt()
+	if strings.ContainsRune(art, spriteMark) {
+		// The sleeping sprite takes the same space.
+		sleeping = spritePlaceholder()
+	}
+	art, special = store.ApplyBehavior(art, special, sleeping, state, time.Now())
+	art += store.CompanionTrail(state.Companions)
 	return art + special
 }
 
@@ -546,6 +1109,25 @@ func artFor(evolution string) string {
 			"  ╰┬───┬╯\n" +
 			"   │   │\n" +
 			"   ╰─♪─╯"
+	case "Sentinel":
+		return "" +
+			"   ┌─🧪─┐\n" +
+			"   ╭───╮\n" +
+			"  (◎_◎ )\n" +
+			"  ╭┤ ✓ ├╮\n" +
+			"  │╰───╯│\n" +
+			"  ╰┬───┬╯\n" +
+			"   │   │\n" +
+			"   ╰───╯"
+	case "Curator":
+		return "" +
+			"   ╭─🏷─╮\n" +
+			"  (◔ ◡ ◔)\n" +
+			"  ╭┤ ☰ ├╮  🗂️\n" +
+			"  │╰───╯│\n" +
+			"  ╰┬───┬╯\n" +
+			"   │   │\n" +
+			"   ╰───╯"
 	case "Void":
 		return "" +
 			"    · · ·\n" +
@@ -576,183 +1158,46 @@ func artFor(evolution string) string {
 	}
 }
 
-func evolutionFor(summary ActivitySummary) string {
-	if summary.Commits+summary.MergedPRs+summary.Reviews+summary.DocComments+summary.RefactorCommits+summary.NewRepos == 0 {
-		return "Lonely"
-	}
-	pioneer := summary.Commits + summary.NewRepos*2
-	guardian := summary.Reviews*2 + summary.MergedPRs*2 + summary.FixCommits
-	bard := summary.DocComments*2 + summary.DocCommits
-	voidScore := summary.RefactorCommits * 2
-	best := "Pioneer"
-	bestScore := pioneer
-	if guardian > bestScore {
-		best = "Guardian"
-		bestScore = guardian
-	}
-	if bard > bestScore {
-		best = "Bard"
-		bestScore = bard
-	}
-	if voidScore > bestScore {
-		best = "Void"
-		bestScore = voidScore
-	}
-	_ = bestScore
-	return best
-}
-
-func colorFor(evolution string) string {
-	switch evolution {
-	case "Pioneer":
-		return colorYellow
-	case "Guardian":
-		return colorBlue
-	case "Bard":
-		return colorMagenta
-	case "Void":
-		return colorGrey
-	default:
-		return colorGrey
-	}
-}
-
-func summarize(events []Event) ActivitySummary {
-	cutoff := time.Now().Add(-7 * 24 * time.Hour)
-	summary := ActivitySummary{}
-	for _, event := range events {
-		if event.CreatedAt.Before(cutoff) {
-			continue
-		}
-		switch event.Type {
-		case "PushEvent":
-			var payload PushPayload
-			if json.Unmarshal(event.Payload, &payload) == nil {
-				summary.Commits += len(payload.Commits)
-				if payload.Size >= 10 {
-					summary.LargeCommits++
-				}
-				for _, commit := range payload.Commits {
-					classifyCommit(commit.Message, &summary)
-				}
-			}
-		case "PullRequestEvent":
-			var payload PullRequestPayload
-			if json.Unmarshal(event.Payload, &payload) == nil && payload.PullRequest.Merged {
-				summary.MergedPRs++
-			}
-		case "PullRequestReviewEvent":
-			summary.Reviews++
-		case "PullRequestReviewCommentEvent":
-			summary.Reviews++
-			summary.DocComments++
-		case "IssueCommentEvent":
-			summary.DocComments++
-		case "CreateEvent":
-			var payload CreatePayload
-			if json.Unmarshal(event.Payload, &payload) == nil && payload.RefType == "repository" {
-				summary.NewRepos++
-			}
-		}
-	}
-	return summary
-}
-
-func classifyCommit(message string, summary *ActivitySummary) {
-	lower := strings.ToLower(message)
-	if strings.Contains(lower, "fix") || strings.Contains(lower, "bug") {
-		summary.FixCommits++
+func ghLogin(ctx context.Context) (string, error) {
+	var user struct {
+		Login string `json:"login"`
 	}
-	if strings.Contains(lower, "doc") || strings.Contains(lower, "readme") || strings.Contains(lower, "comment") {
-		summary.DocCommits++
-	}
-	if strings.Contains(lower, "refactor") || strings.Contains(lower, "cleanup") || strings.Contains(lower, "remove") || strings.Contains(lower, "delete") {
-		summary.RefactorCommits++
-	}
-}
-
-func ghLogin() (string, error) {
-	out, err := exec.Command("gh", "api", "user", "--jq", ".login").Output()
-	if err != nil {
-		return "", fmt.Errorf("gh api user failed: %w", err)
+	if err := gh.Get(ctx, "user", &user); err != nil {
+		return "", err
 	}
-	login := strings.TrimSpace(string(out))
+	login := user.Login
 	if login == "" {
 		return "", errors.New("unable to determine GitHub login")
 	}
+	if err := dirs.CheckAccount(login); err != nil {
+		return "", err
+	}
 	return login, nil
 }
 
-func ghEvents(login string) ([]Event, error) {
-	cmd := exec.Command("gh", "api", fmt.Sprintf("users/%s/events", login))
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("gh api events failed: %w", err)
-	}
+func ghEvents(ctx context.Context, login string) ([]Event, error) {
 	var events []Event
-	if err := json.Unmarshal(out, &events); err != nil {
-		return nil, fmt.Errorf("unable to parse events: %w", err)
+	if err := gh.Get(ctx, fmt.Sprintf("users/%s/events", login), &events); err != nil {
+		return nil, err
 	}
 	return events, nil
 }
 
-func localThoughtFragments() int {
-	if exec.Command("git", "rev-parse", "--is-inside-work-tree").Run() != nil {
+func localThoughtFragments(ctx context.Context) int {
+	if !gh.InWorkTree(ctx) {
 		return 0
 	}
-	status, _ := exec.Command("git", "status", "--porcelain").Output()
-	diff, _ := exec.Command("git", "diff", "--stat").Output()
+	status, _ := gh.GitOutput(ctx, "status", "--porcelain")
+	diff, _ := gh.GitOutput(ctx, "diff", "--stat")
 	if len(bytes.TrimSpace(status)) > 0 || len(bytes.TrimSpace(diff)) > 0 {
 		return 1
 	}
 	return 0
 }
 
-func loadState() (PetState, error) {
-	path, err := configPath()
-	if err != nil {
-		return PetState{}, err
-	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return PetState{Mood: 5, Evolution: "Lonely"}, nil
-		}
-		return PetState{}, err
-	}
-	var state PetState
-	if err := json.Unmarshal(data, &state); err != nil {
-		return PetState{}, err
-	}
-	return state, nil
-}
-
-func saveState(state PetState) error {
-	path, err := configPath()
-	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0o600)
-}
-
-func configPath() (string, error) {
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(configDir, "gh", configFileName), nil
-}
-
-func printFireworks(evolution string) {
-	color := colorFor(evolution)
-	reset := colorReset
+func printFireworks(evolution string, theme Theme) {
+	color := theme.accent(evolution)
+	reset := theme.Reset
 	fmt.Println(color + "  .''." + reset)
 	fmt.Println(color + " ( * )" + reset)
 	fmt.Println(color + "  .''." + reset)
@@ -778,21 +1223,22 @@ func moodDescriptor(mood int) string {
 	}
 }
 
-func activityTone(summary ActivitySummary) string {
-	total := summary.Commits + summary.MergedPRs + summary.Reviews + summary.DocComments + summary.NewRepos + summary.RefactorCommits
+func activityTone(name string, summary ActivitySummary) string {
+	total := pet.Activity(summary)
 	switch {
 	case total >= 20:
-		return "Intensity: blazing. GitPet is thriving in the Cache."
+		return i18n.Tr("Intensity: blazing. %s is thriving in the Cache.", name)
 	case total >= 8:
-		return "Intensity: steady. GitPet hums with creative heat."
+		return i18n.Tr("Intensity: steady. %s hums with creative heat.", name)
 	case total >= 1:
-		return "Intensity: gentle. GitPet feels acknowledged."
+		return i18n.Tr("Intensity: gentle. %s feels acknowledged.", name)
 	default:
-		return "Intensity: quiet. GitPet grows a little lonely."
+		return i18n.Tr("Intensity: quiet. %s grows a little lonely.", name)
 	}
 }
 
-func dailyProverb() string {
+// dailyProverbs is today's n proverbs, the same all day.
+func dailyProverbs(n int) []string {
 	proverbs := []string{
 		"Small diffs travel far.",
 		"Tests are lanterns in the fog.",
@@ -801,14 +1247,11 @@ func dailyProverb() string {
 		"Bugs fear patient eyes.",
 	}
 	today := time.Now().YearDay()
-	return proverbs[today%len(proverbs)]
-}
-
-func displayTime(ts string) string {
-	if ts == "" {
-		return "Never"
+	var out []string
+	for i := 0; i < min(n, len(proverbs)); i++ {
+		out = append(out, i18n.Tr(proverbs[(today+i)%len(proverbs)]))
 	}
-	return ts
+	return out
 }
 
 func min(a, b int) int {
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const helpDeskFileName = "gh-pet-maintainer.json"
+
+// MaintainerConfig scopes maintainer mode and sets how quickly a response
+// still earns Kindness.
+type MaintainerConfig struct {
+	// Repos limits maintainer mode to these owner/repo names. Empty means
+	// the repositories you own that were pushed to most recently.
+	Repos    []string `json:"repos,omitempty"`
+	SLAHours int      `json:"sla_hours"`
+}
+
+func defaultMaintainer() MaintainerConfig {
+	return MaintainerConfig{SLAHours: 24}
+}
+
+func (m MaintainerConfig) validate() error {
+	if m.SLAHours < 1 || m.SLAHours > 720 {
+		return fmt.Errorf("maintainer.sla_hours must be between 1 and 720")
+	}
+	for _, repo := range m.Repos {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" {
+			return fmt.Errorf("maintainer.repos entries must be owner/repo, got %q", repo)
+		}
+	}
+	return nil
+}
+
+func (m MaintainerConfig) sla() time.Duration {
+	return time.Duration(m.SLAHours) * time.Hour
+}
+
+const maintainerQuery = `query($issues: String!, $reviews: String!) {
+  viewer {
+    repositories(first: 10, ownerAffiliations: OWNER, isFork: false, orderBy: {field: PUSHED_AT, direction: DESC}) {
+      nodes { nameWithOwner defaultBranchRef { target { ... on Commit { statusCheckRollup { state } } } } }
+    }
+  }
+  issues: search(query: $issues, type: ISSUE, first: 30) {
+    nodes { ... on Issue { url number title createdAt repository { nameWithOwner } comments(first: 50) { nodes { author { login } createdAt } } } }
+  }
+  reviews: search(query: $reviews, type: ISSUE, first: 30) {
+    nodes { ... on PullRequest { url number title repository { nameWithOwner } } }
+  }
+}`
+
+// HelpRequest is something on your repos that needs you: a new issue, a
+// review request, or a red default branch.
+type HelpRequest struct {
+	Key    string    `json:"key"`
+	Kind   string    `json:"kind"`
+	Repo   string    `json:"repo"`
+	Title  string    `json:"title"`
+	URL    string    `json:"url,omitempty"`
+	Opened time.Time `json:"opened"`
+	// Answered is set once you respond. Answered requests stay on the desk
+	// until they leave GitHub's results, so they're only rewarded once.
+	Answered *time.Time `json:"answered,omitempty"`
+}
+
+// HelpDesk is the requests carried between polls.
+type HelpDesk struct {
+	Checked  time.Time     `json:"checked"`
+	Requests []HelpRequest `json:"requests"`
+}
+
+func (d HelpDesk) open() []HelpRequest {
+	var open []HelpRequest
+	for _, r := range d.Requests {
+		if r.Answered == nil {
+			open = append(open, r)
+		}
+	}
+	return open
+}
+
+func (r HelpRequest) label() string {
+	switch r.Kind {
+	case "issue":
+		return "🐛 " + r.Title
+	case "review":
+		return "👀 Review: " + r.Title
+	default:
+		return "🔥 CI is red on " + r.Repo
+	}
+}
+
+func runMaintain(args []string) error {
+	fs := newFlagSet("maintain")
+	watch := fs.Duration("watch", 0, "keep polling at this interval (e.g. 5m) until interrupted")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *watch != 0 && *watch < time.Minute {
+		return fmt.Errorf("--watch must be at least 1m to stay within API limits")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	login, err := ghLogin(ctx)
+	if err != nil {
+		return err
+	}
+	if *watch == 0 {
+		return maintainOnce(ctx, cfg, login)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(*watch)
+	defer ticker.Stop()
+	for {
+		// Quiet hours skip the poll; anything new is announced after them.
+		if cfg.Notifications.QuietHours.active(time.Now()) {
+			logging.Logger.Debug("maintain: quiet hours, skipping poll")
+		} else if err := maintainOnce(ctx, cfg, login); err != nil {
+			fmt.Fprintln(os.Stderr, "GitPet: could not check your repos:", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// maintainOnce polls GitHub, announces new requests, and rewards the ones you
+// answered within the SLA.
+func maintainOnce(ctx context.Context, cfg Config, login string) error {
+	desk, err := loadHelpDesk()
+	if err != nil {
+		return err
+	}
+	firstPoll := desk.Checked.IsZero()
+	now := time.Now().UTC()
+	current, err := fetchHelpRequests(ctx, cfg.Maintainer, login)
+	if err != nil {
+		return err
+	}
+	fresh, answered := reconcileHelpDesk(&desk, current, now)
+	desk.Checked = now
+	if err := saveHelpDesk(desk); err != nil {
+		return err
+	}
+
+	state, _ := store.Load()
+	voice := state.Signature() + " " + state.DisplayName()
+	onTime := 0
+	if firstPoll {
+		// Old answers were already their own reward.
+		answered = nil
+	}
+	for _, r := range answered {
+		if r.Answered.Sub(r.Opened) <= cfg.Maintainer.sla() {
+			onTime++
+		}
+	}
+	if onTime > 0 {
+		reward := onTime * cfg.Scoring.HelpKindness
+		state.Kindness += reward
+		if err := store.Save(state); err != nil {
+			return err
+		}
+		fmt.Printf("%s💗 You answered %s within %dh. +%d kindness%s\n", colorGreen, i18n.Plural(onTime, "request"), cfg.Maintainer.SLAHours, reward, colorReset)
+	}
+	for _, r := range fresh {
+		fmt.Printf("🙋 %s asks for help: %s\n", voice, r.label())
+		if r.URL != "" {
+			fmt.Printf("   %s%s%s\n", colorDim, r.URL, colorReset)
+		}
+	}
+	switch len(fresh) {
+	case 0:
+		fmt.Printf("%s: nothing new, %s still open.\n", voice, i18n.Plural(len(desk.open()), "request"))
+	case 1:
+		notify(cfg.Notifications, voice+" needs a hand", fresh[0].label())
+	default:
+		notify(cfg.Notifications, voice+" needs a hand", fmt.Sprintf("%d new requests for help on your repos", len(fresh)))
+	}
+	return nil
+}
+
+// reconcileHelpDesk folds a fresh poll into the desk. It returns requests
+// seen for the first time and requests answered since the last poll; a review
+// request or red build that disappears counts as answered now.
+func reconcileHelpDesk(desk *HelpDesk, current []HelpRequest, now time.Time) (fresh, answered []HelpRequest) {
+	seen := map[string]HelpRequest{}
+	for _, r := range desk.Requests {
+		seen[r.Key] = r
+	}
+	var kept []HelpRequest
+	present := map[string]bool{}
+	for _, r := range current {
+		present[r.Key] = true
+		prev, had := seen[r.Key]
+		switch {
+		case had && prev.Answered != nil:
+			kept = append(kept, prev)
+			continue
+		case had && r.Kind != "issue":
+			// Review requests and red builds carry no start time of their
+			// own, so keep the time they were first seen.
+			r.Opened = prev.Opened
+		case r.Opened.IsZero():
+			r.Opened = now
+		}
+		if r.Answered != nil {
+			answered = append(answered, r)
+		} else if !had {
+			fresh = append(fresh, r)
+		}
+		kept = append(kept, r)
+	}
+	for _, r := range desk.Requests {
+		if !present[r.Key] && r.Answered == nil {
+			r.Answered = &now
+			answered = append(answered, r)
+		}
+	}
+	desk.Requests = kept
+	return fresh, answered
+}
+
+func fetchHelpRequests(ctx context.Context, cfg MaintainerConfig, login string) ([]HelpRequest, error) {
+	scope := "user:" + login
+	if len(cfg.Repos) > 0 {
+		scope = "repo:" + strings.Join(cfg.Repos, " repo:")
+	}
+	vars := map[string]any{
+		"issues":  "is:issue is:open -author:" + login + " sort:created-desc " + scope,
+		"reviews": "is:pr is:open review-requested:" + login,
+	}
+	var data struct {
+		Viewer struct {
+			Repositories struct {
+				Nodes []struct {
+					NameWithOwner    string `json:"nameWithOwner"`
+					DefaultBranchRef *struct {
+						Target struct {
+							StatusCheckRollup *struct {
+								State string `json:"state"`
+							} `json:"statusCheckRollup"`
+						} `json:"target"`
+					} `json:"defaultBranchRef"`
+				} `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"viewer"`
+		Issues struct {
+			Nodes []struct {
+				URL        string    `json:"url"`
+				Number     int       `json:"number"`
+				Title      string    `json:"title"`
+				CreatedAt  time.Time `json:"createdAt"`
+				Repository struct {
+					NameWithOwner string `json:"nameWithOwner"`
+				} `json:"repository"`
+				Comments struct {
+					Nodes []struct {
+						Author *struct {
+							Login string `json:"login"`
+						} `json:"author"`
+						CreatedAt time.Time `json:"createdAt"`
+					} `json:"nodes"`
+				} `json:"comments"`
+			} `json:"nodes"`
+		} `json:"issues"`
+		Reviews struct {
+			Nodes []struct {
+				URL        string `json:"url"`
+				Number     int    `json:"number"`
+				Title      string `json:"title"`
+				Repository struct {
+					NameWithOwner string `json:"nameWithOwner"`
+				} `json:"repository"`
+			} `json:"nodes"`
+		} `json:"reviews"`
+	}
+	if err := gh.GraphQL(ctx, maintainerQuery, vars, &data); err != nil {
+		return nil, err
+	}
+
+	var requests []HelpRequest
+	for _, issue := range data.Issues.Nodes {
+		if issue.URL == "" {
+			continue
+		}
+		r := HelpRequest{
+			Key: issue.URL, Kind: "issue", Repo: issue.Repository.NameWithOwner, URL: issue.URL,
+			Title:  fmt.Sprintf("%s#%d %s", issue.Repository.NameWithOwner, issue.Number, issue.Title),
+			Opened: issue.CreatedAt,
+		}
+		for _, c := range issue.Comments.Nodes {
+			if c.Author != nil && strings.EqualFold(c.Author.Login, login) {
+				answered := c.CreatedAt
+				r.Answered = &answered
+				break
+			}
+		}
+		requests = append(requests, r)
+	}
+	for _, pr := range data.Reviews.Nodes {
+		if pr.URL == "" {
+			continue
+		}
+		requests = append(requests, HelpRequest{
+			Key: pr.URL, Kind: "review", Repo: pr.Repository.NameWithOwner, URL: pr.URL,
+			Title: fmt.Sprintf("%s#%d %s", pr.Repository.NameWithOwner, pr.Number, pr.Title),
+		})
+	}
+
+	red := func(repo, state string) {
+		if state == "FAILURE" || state == "ERROR" {
+			requests = append(requests, HelpRequest{Key: "ci:" + repo, Kind: "ci", Repo: repo, Title: repo})
+		}
+	}
+	if len(cfg.Repos) > 0 {
+		for _, repo := range cfg.Repos {
+			if health, err := fetchRepoHealth(ctx, repo, time.Now()); err == nil {
+				red(repo, health.CI)
+			}
+		}
+	} else {
+		for _, repo := range data.Viewer.Repositories.Nodes {
+			if repo.DefaultBranchRef != nil && repo.DefaultBranchRef.Target.StatusCheckRollup != nil {
+				red(repo.NameWithOwner, repo.DefaultBranchRef.Target.StatusCheckRollup.State)
+			}
+		}
+	}
+	return requests, nil
+}
+
+// helpRequestLines lists open requests for the status screen.
+func helpRequestLines(desk HelpDesk, sla time.Duration, now time.Time) []string {
+	const shown = 5
+	open := desk.open()
+	var lines []string
+	for i, r := range open {
+		if i == shown {
+			lines = append(lines, fmt.Sprintf("   …and %d more (gh pet maintain)", len(open)-shown))
+			break
+		}
+		due := r.Opened.Add(sla).Sub(now)
+		when := fmt.Sprintf("answer within %dh for Kindness", int(due.Hours())+1)
+		if due <= 0 {
+			when = "overdue"
+		}
+		lines = append(lines, fmt.Sprintf("   • %s (%s)", r.label(), when))
+	}
+	return lines
+}
+
+func loadHelpDesk() (HelpDesk, error) {
+	path, err := helpDeskPath()
+	if err != nil {
+		return HelpDesk{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return HelpDesk{}, nil
+		}
+		return HelpDesk{}, err
+	}
+	var desk HelpDesk
+	if err := json.Unmarshal(data, &desk); err != nil {
+		return HelpDesk{}, err
+	}
+	return desk, nil
+}
+
+func saveHelpDesk(desk HelpDesk) error {
+	path, err := helpDeskPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(desk, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func helpDeskPath() (string, error) {
+	return dirs.DataPath(helpDeskFileName)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// metric is one Prometheus gauge: a name, its help text, and its samples.
+type metric struct {
+	Name, Help string
+	Samples    []sample
+}
+
+// sample is one value of a gauge. Labels is already formatted, e.g.
+// `kind="commits"`, or empty.
+type sample struct {
+	Labels string
+	Value  float64
+}
+
+// petMetrics are the gauges GET /metrics exports. Activity counts cover the
+// window the last feed looked at, like gh pet status.
+func petMetrics(state store.PetState, history store.History, now time.Time) []metric {
+	gauge := func(name, help string, value float64) metric {
+		return metric{Name: name, Help: help, Samples: []sample{{Value: value}}}
+	}
+	metrics := []metric{
+		gauge("gitpet_mood", "The pet's mood, 0 to 100.", float64(state.Mood)),
+		gauge("gitpet_kindness", "Kindness earned from reviews and helping others.", float64(state.Kindness)),
+		gauge("gitpet_logic_shards", "Logic shards earned from commits and tests.", float64(state.Logic)),
+		gauge("gitpet_mentor", "Mentor points earned from review depth.", float64(state.Mentor)),
+		gauge("gitpet_streak_days", "Consecutive active days ending today or yesterday.", float64(store.CurrentStreak(history, now))),
+		gauge("gitpet_review_queue", "Open pull requests awaiting your review at the last feed.", float64(state.ReviewQueue)),
+		gauge("gitpet_achievements", "Achievements unlocked.", float64(len(state.Achievements))),
+	}
+	if last, err := time.Parse(time.RFC3339, state.LastSync); err == nil {
+		metrics = append(metrics, gauge("gitpet_last_feed_timestamp_seconds", "When the pet was last fed, in Unix time.", float64(last.Unix())))
+	}
+	evolution := state.Evolution
+	if evolution == "" {
+		evolution = "Lonely"
+	}
+	metrics = append(metrics, metric{Name: "gitpet_evolution_info", Help: "The pet's current evolution, as a label.",
+		Samples: []sample{{Labels: fmt.Sprintf("evolution=%q", evolution), Value: 1}}})
+
+	a := state.Activity
+	activity := metric{Name: "gitpet_activity", Help: "Activity over the window the last feed counted, by kind."}
+	for _, c := range []struct {
+		kind  string
+		count int
+	}{
+		{"commits", a.Commits}, {"merged_prs", a.MergedPRs}, {"reviews", a.Reviews}, {"doc_comments", a.DocComments},
+		{"fix_commits", a.FixCommits}, {"test_commits", a.TestCommits}, {"refactor_commits", a.RefactorCommits},
+		{"issues_opened", a.IssuesOpened}, {"issues_closed", a.IssuesClosed}, {"issue_comments", a.IssueComments},
+		{"new_repos", a.NewRepos}, {"large_commits", a.LargeCommits}, {"lines_changed", a.LinesChanged}, {"first_timers", a.FirstTimers},
+	} {
+		activity.Samples = append(activity.Samples, sample{Labels: fmt.Sprintf("kind=%q", c.kind), Value: float64(c.count)})
+	}
+	return append(metrics, activity)
+}
+
+// prometheusText renders metrics in the Prometheus text exposition format.
+func prometheusText(metrics []metric) string {
+	var sb strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s gauge\n", m.Name, m.Help, m.Name)
+		for _, s := range m.Samples {
+			if s.Labels != "" {
+				fmt.Fprintf(&sb, "%s{%s} %s\n", m.Name, s.Labels, strconv.FormatFloat(s.Value, 'f', -1, 64))
+			} else {
+				fmt.Fprintf(&sb, "%s %s\n", m.Name, strconv.FormatFloat(s.Value, 'f', -1, 64))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	state, _ := store.Load()
+	history, _ := store.LoadHistory()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprint(w, prometheusText(petMetrics(state, history, time.Now())))
+}
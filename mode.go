@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Output modes. Professional trades the pet's emoji, exclamations, and
+// jokes for a short neutral summary, for when the screen is shared.
+const (
+	modePlayful      = "playful"
+	modeProfessional = "professional"
+)
+
+var modes = []string{modePlayful, modeProfessional}
+
+// outputMode is the mode output is rendered in, set at startup from the
+// config.
+var outputMode = modePlayful
+
+func professional() bool {
+	return outputMode == modeProfessional
+}
+
+func validateMode(mode string) error {
+	if mode != "" && !containsString(modes, mode) {
+		return fmt.Errorf("mode must be playful or professional, not %q", mode)
+	}
+	return nil
+}
+
+// runMode shows the output mode, or switches it.
+func runMode(args []string) error {
+	if len(args) > 1 {
+		return usageErrorf("usage: gh pet mode [playful|professional]")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		fmt.Println(configKeys["mode"].get(cfg))
+		return nil
+	}
+	if err := configKeys["mode"].set(&cfg, args[0]); err != nil {
+		return usageErrorf("%v", err)
+	}
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+	if args[0] == modeProfessional {
+		fmt.Println("Professional mode on. Output is neutral and quiet until gh pet mode playful.")
+	} else {
+		state, _ := store.Load()
+		fmt.Printf("%s✓ Playful mode on. %s is back to its usual self!%s\n", colorGreen, state.DisplayName(), colorReset)
+	}
+	return nil
+}
+
+// renderProfessionalStatus is the status card without art, faces, or
+// flavor text.
+func renderProfessionalStatus(state store.PetState, absolute bool) string {
+	labels := []string{"Evolution", "Mood", "Kindness", "Shards", "Synced"}
+	width := 0
+	for _, label := range labels {
+		width = max(width, displayWidth(i18n.Tr(label)))
+	}
+	values := []string{
+		state.Evolution,
+		fmt.Sprintf("%d/100", state.Mood),
+		fmt.Sprint(state.Kindness),
+		fmt.Sprint(state.Logic),
+		i18n.DisplayTime(state.LastSync, absolute),
+	}
+	var sb strings.Builder
+	sb.WriteString(i18n.Tr("%s Status", state.DisplayName()) + "\n")
+	for i, label := range labels {
+		sb.WriteString("  " + padRight(i18n.Tr(label), width) + " : " + values[i] + "\n")
+	}
+	if state.Away(time.Now()) {
+		sb.WriteString("  " + awayLine(state) + "\n")
+	}
+	return sb.String()
+}
+
+// printProfessionalFeed reports a feed in a few plain lines.
+func printProfessionalFeed(result feedResult) {
+	state, s := result.State, result.Summary
+	fmt.Printf("Synced: %s, %s, %s, %d docs and comments.\n", i18n.Plural(s.Commits, "commit"), i18n.Plural(s.MergedPRs, "merged PR"), i18n.Plural(s.Reviews, "review"), s.DocComments)
+	fmt.Printf("Mood %d, kindness %d, logic shards %d, mentor %d. Evolution: %s.\n", state.Mood, state.Kindness, state.Logic, state.Mentor, state.Evolution)
+	for _, r := range result.Goals {
+		fmt.Println(neutralize(r.message()))
+	}
+	for _, name := range result.Unlocked {
+		fmt.Printf("Achievement: %s.\n", name)
+	}
+}
+
+// professionalPrompt is the prompt segment in professional mode.
+func professionalPrompt(state store.PetState) string {
+	return fmt.Sprintf("mood %d", state.Mood)
+}
+
+// neutralize strips emoji from a line and calms its exclamation marks.
+func neutralize(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 0x1F000, r >= 0x2600 && r <= 0x27BF, r == 0xFE0F, r == 0x200D:
+			continue
+		case r == '!':
+			sb.WriteRune('.')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// professionalPostCommit is what the hook prints after a commit in
+// professional mode.
+func professionalPostCommit(state store.PetState, now time.Time) string {
+	return fmt.Sprintf("GitPet: commit recorded at %s. Mood %d/100, %s.", now.Format("15:04"), state.Mood, state.Evolution)
+}
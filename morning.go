@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const (
+	morningFileName = "gh-pet-morning.json"
+	// morningTimeout keeps the briefing from holding up a new shell when
+	// GitHub is slow; whatever hasn't arrived by then is left out.
+	morningTimeout = 4 * time.Second
+	// morningListed is how many review requests and issues are shown by
+	// name; the rest are counted.
+	morningListed = 3
+)
+
+const morningQuery = `query($reviews: String!, $issues: String!) {
+  reviews: search(query: $reviews, type: ISSUE, first: 3) {
+    issueCount
+    nodes { ... on PullRequest { number title createdAt repository { nameWithOwner } } }
+  }
+  issues: search(query: $issues, type: ISSUE, first: 3) {
+    issueCount
+    nodes { ... on Issue { number title createdAt repository { nameWithOwner } } }
+  }
+}`
+
+// morningItem is a pull request or issue waiting on you.
+type morningItem struct {
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+type morningSearch struct {
+	IssueCount int           `json:"issueCount"`
+	Nodes      []morningItem `json:"nodes"`
+}
+
+// morningDesk is what's waiting on GitHub: pull requests asking for your
+// review and open issues assigned to you.
+type morningDesk struct {
+	Reviews morningSearch `json:"reviews"`
+	Issues  morningSearch `json:"issues"`
+}
+
+// morningSeen remembers the last day the briefing was shown, for --once.
+type morningSeen struct {
+	Shown string `json:"shown"`
+}
+
+// runMorning prints a compact briefing to start the day: the pet's mood,
+// yesterday's activity, today's quests, and what's waiting on GitHub. With
+// --once it shows only the first time it runs each day, so it can go in a
+// shell's startup file.
+func runMorning(args []string) error {
+	fs := newFlagSet("morning")
+	once := fs.Bool("once", false, "show only the first time it runs each day, for shell startup files")
+	offline := fs.Bool("offline", false, "skip GitHub and show only what's stored locally")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	now := time.Now()
+	today := now.Format(store.DayLayout)
+	if *once {
+		seen, _ := loadMorningSeen()
+		if seen.Shown == today {
+			return nil
+		}
+		// Mark it first, so a second shell opened while this one waits on
+		// GitHub stays quiet.
+		if err := saveMorningSeen(morningSeen{Shown: today}); err != nil {
+			logging.Logger.Debug("morning", "err", err)
+		}
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+
+	var desk *morningDesk
+	if !*offline {
+		ctx, cancel := context.WithTimeout(context.Background(), morningTimeout)
+		desk, err = fetchMorningDesk(ctx)
+		cancel()
+		if err != nil {
+			// A briefing in a new shell shouldn't print network errors.
+			logging.Logger.Debug("morning", "err", err)
+		}
+	}
+
+	fmt.Printf("\n%s☀️  Good morning! %s%s %s\n", colorBold, now.Format("Monday, Jan 2"), colorReset, colorDim+moodFace(state.Mood)+colorReset)
+	fmt.Printf("   %s is %s %s %d/100\n", state.DisplayName(), moodDescriptor(state.Mood), promptBar(state.Mood), state.Mood)
+	if state.LastSync != "" {
+		fmt.Printf("   %sLast fed %s%s\n", colorDim, i18n.DisplayTime(state.LastSync, false), colorReset)
+	}
+
+	fmt.Printf("\n%sYesterday%s  %s\n", colorBold, colorReset, yesterdayLine(dayOf(history, now.AddDate(0, 0, -1))))
+
+	fmt.Printf("\n%sToday's quests%s\n", colorBold, colorReset)
+	for _, quest := range morningQuests(state, history, now) {
+		fmt.Printf("   %s\n", quest)
+	}
+
+	if desk != nil {
+		printMorningList("👀 Awaiting your review", desk.Reviews, now)
+		printMorningList("📌 Assigned to you", desk.Issues, now)
+	} else if !*offline {
+		fmt.Printf("\n%sCouldn't reach GitHub for reviews and issues.%s\n", colorDim, colorReset)
+	}
+	fmt.Println()
+	return nil
+}
+
+// yesterdayLine summarizes a day's record, such as "5 commits · 1 review".
+func yesterdayLine(d store.DayRecord) string {
+	if d.Total() == 0 {
+		return colorDim + i18n.Tr("A quiet day.") + colorReset
+	}
+	line := ""
+	for _, part := range []struct {
+		n    int
+		noun string
+	}{{d.Commits, "commit"}, {d.MergedPRs, "merged PR"}, {d.Reviews, "review"}, {d.DocComments, "doc or comment"}, {d.Issues, "issue"}} {
+		if part.n == 0 {
+			continue
+		}
+		if line != "" {
+			line += " · "
+		}
+		line += i18n.Plural(part.n, part.noun)
+	}
+	return line
+}
+
+// morningQuests are today's goals: keeping the streak alive and the quests
+// of any running events not yet done.
+func morningQuests(state store.PetState, history store.History, now time.Time) []string {
+	var quests []string
+	today := dayOf(history, now)
+	switch streak := store.CurrentStreak(history, now); {
+	case today.Total() > 0:
+		quests = append(quests, fmt.Sprintf("%s✓ Active today, %s streak%s", colorGreen, i18n.Plural(streak, "day"), colorReset))
+	case streak > 0:
+		quests = append(quests, fmt.Sprintf("🔥 Keep your %s streak going", i18n.Plural(streak, "day")))
+	default:
+		quests = append(quests, "🌱 Start a streak with one contribution")
+	}
+	for _, w := range store.ActiveEvents(now) {
+		if store.HasBadge(state, w.Badge()) {
+			continue
+		}
+		daysLeft := int(w.End.Sub(now).Hours()/24) + 1
+		quests = append(quests, fmt.Sprintf("%s %s (%d/%d) %s%s left%s", w.Icon, w.Quest,
+			min(w.QuestProgress(history), w.Target), w.Target, colorDim, i18n.Plural(daysLeft, "day"), colorReset))
+	}
+	return quests
+}
+
+// dayOf is the record for t's calendar day, or an empty one, without adding
+// it to history.
+func dayOf(history store.History, t time.Time) store.DayRecord {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for _, d := range history.Between(start, start.AddDate(0, 0, 1)) {
+		return d
+	}
+	return store.DayRecord{}
+}
+
+func printMorningList(title string, list morningSearch, now time.Time) {
+	fmt.Printf("\n%s%s%s  %d\n", colorBold, title, colorReset, list.IssueCount)
+	for i, item := range list.Nodes {
+		if i == morningListed {
+			break
+		}
+		fmt.Printf("   %s#%d%s %s %s%s · %s%s\n", colorDim, item.Number, colorReset, truncateWidth(item.Title, 50),
+			colorDim, item.Repository.NameWithOwner, i18n.RelativeTime(item.CreatedAt, now), colorReset)
+	}
+	if more := list.IssueCount - min(len(list.Nodes), morningListed); more > 0 {
+		fmt.Printf("   %s…and %d more%s\n", colorDim, more, colorReset)
+	}
+}
+
+func fetchMorningDesk(ctx context.Context) (*morningDesk, error) {
+	var desk morningDesk
+	vars := map[string]any{
+		"reviews": "is:pr is:open archived:false review-requested:@me sort:created-asc",
+		"issues":  "is:issue is:open archived:false assignee:@me sort:updated-desc",
+	}
+	if err := gh.GraphQL(ctx, morningQuery, vars, &desk); err != nil {
+		return nil, err
+	}
+	return &desk, nil
+}
+
+func loadMorningSeen() (morningSeen, error) {
+	var seen morningSeen
+	path, err := morningPath()
+	if err != nil {
+		return seen, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return seen, nil
+		}
+		return seen, err
+	}
+	err = json.Unmarshal(data, &seen)
+	return seen, err
+}
+
+func saveMorningSeen(seen morningSeen) error {
+	path, err := morningPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(seen)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func morningPath() (string, error) {
+	return dirs.DataPath(morningFileName)
+}
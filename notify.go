@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// NotificationsConfig controls how GitPet gets your attention when something
+// happens outside the terminal you're looking at.
+type NotificationsConfig struct {
+	// Desktop pops up notifications; it's off until asked for.
+	Desktop bool `json:"desktop"`
+	Bell    bool `json:"bell"`
+	// StreakWarningHours is how close to midnight an unbroken streak with no
+	// activity today triggers a warning; 0 turns the warning off.
+	StreakWarningHours int `json:"streak_warning_hours"`
+	// QuietHours silences notifications, shortens the post-commit card to a
+	// line, and pauses gh pet maintain --watch; see quiet.go.
+	QuietHours QuietHours `json:"quiet_hours"`
+}
+
+func defaultNotifications() NotificationsConfig {
+	return NotificationsConfig{StreakWarningHours: 3}
+}
+
+func (n NotificationsConfig) validate() error {
+	if n.StreakWarningHours < 0 || n.StreakWarningHours > 24 {
+		return fmt.Errorf("notifications.streak_warning_hours must be between 0 and 24")
+	}
+	return n.QuietHours.validate()
+}
+
+// notify raises a desktop notification and/or rings the terminal bell,
+// unless it's quiet hours. It never fails the calling command: a missing
+// notifier just means no popup.
+func notify(cfg NotificationsConfig, title, body string) {
+	if cfg.QuietHours.active(time.Now()) {
+		return
+	}
+	if cfg.Bell {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+	if !cfg.Desktop {
+		return
+	}
+	if cmd := desktopNotifier(title, body); cmd != nil && cmd.Start() == nil {
+		// Reap the notifier without waiting on it, so a long-running serve,
+		// focus, or watch doesn't collect zombies. A short-lived hook may
+		// exit first, which is fine.
+		go cmd.Wait()
+	}
+}
+
+func desktopNotifier(title, body string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return exec.Command("osascript", "-e", script)
+	case "windows":
+		return exec.Command("powershell.exe", "-NoProfile", "-Command", windowsToastScript(title, body))
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil
+		}
+		return exec.Command("notify-send", "--app-name=GitPet", title, body)
+	}
+}
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func windowsToastScript(title, body string) string {
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+	return fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode(%s)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('GitPet').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`, quote(title), quote(body))
+}
+
+// notifyChanges alerts on what changed between two saves of the pet.
+func notifyChanges(cfg NotificationsConfig, before, after store.PetState, unlocked []string) {
+	name := after.DisplayName()
+	if store.Evolved(before, after) {
+		notify(cfg, fmt.Sprintf("%s %s evolved!", after.Signature(), name), fmt.Sprintf("%s → %s", before.Evolution, after.Evolution))
+	}
+	for _, achievement := range unlocked {
+		notify(cfg, "🏆 Achievement unlocked", fmt.Sprintf("%s earned %s", name, achievement))
+	}
+}
+
+// streakWarning returns the nudge to show when today's activity is still
+// missing and the streak would break within the warning window.
+func streakWarning(cfg NotificationsConfig, history store.History, now time.Time) (string, bool) {
+	if cfg.StreakWarningHours == 0 || history.AwayOn(now.Format(store.DayLayout)) {
+		return "", false
+	}
+	y, m, d := now.Date()
+	midnight := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+	for _, day := range history.Between(midnight.AddDate(0, 0, -1), midnight) {
+		if day.Total() > 0 {
+			return "", false
+		}
+	}
+	streak := store.CurrentStreak(history, now)
+	if streak == 0 {
+		return "", false
+	}
+	left := midnight.Sub(now)
+	hours := int(left.Hours()) + 1
+	if hours > cfg.StreakWarningHours {
+		return "", false
+	}
+	return fmt.Sprintf("Commit in the next %d hours to keep your %d-day streak", hours, streak), true
+}
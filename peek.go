@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// runPeek shows the shadow pet any public GitHub user would have, built from
+// their public events the way the web card builds one. Nothing is saved.
+func runPeek(args []string) error {
+	fs := newFlagSet("peek")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("usage: gh pet peek <username>")
+	}
+	login := strings.TrimPrefix(fs.Arg(0), "@")
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	stop := startSpinner(fmt.Sprintf("Peeking at @%s…", login))
+	events, err := ghEvents(ctx, login)
+	stop()
+	if err != nil {
+		return fmt.Errorf("cannot fetch @%s's public events: %w", login, err)
+	}
+	events = cfg.Bots.HumanEvents(events)
+	state := shadowPet(login, discountCommits(cfg.Scoring, events, summarize(events)), cfg.Scoring, time.Now())
+
+	if professional() {
+		fmt.Print(renderProfessionalStatus(state, false))
+		return nil
+	}
+	fmt.Printf("%s👀 @%s's shadow pet, from public events only. Nothing is saved.%s\n", colorDim, login, colorReset)
+	// Wellness advice is for the Keeper, so the card leaves it out.
+	fmt.Print(terminalRenderer{theme: cfg.activeTheme()}.Status(state, nil) + "\n")
+	return nil
+}
+
+// shadowPet is a pet fed once with summary: it starts from a low mood, like
+// a fresh egg, and holds only what that one feed earns.
+func shadowPet(login string, summary ActivitySummary, scoring ScoringConfig, now time.Time) store.PetState {
+	state := store.PetState{
+		Name:      "@" + login,
+		Emoji:     "👤",
+		Mood:      10,
+		Evolution: pet.EvolutionFor(summary),
+		Activity:  summary,
+		LastSync:  now.UTC().Format(time.RFC3339),
+	}
+	applyActivity(scoring, &state, summary)
+	return state
+}
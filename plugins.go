@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Plugins are executables in the plugins directory that feed the pet
+// signals GitHub doesn't see, such as closed Jira tickets or journal
+// entries, and react to feeds. Each call runs the plugin once with a
+// pluginRequest as JSON on stdin; it answers with a pluginResponse as JSON on
+// stdout, or {"error": "…"}. Hooks:
+//
+//   - describe: name, description, and the other hooks it handles.
+//   - activity: counts since a time, under ActivitySummary's JSON keys, and a
+//     note saying what they were.
+//   - react: a message and optional art after a feed.
+const (
+	pluginsDirName = "gh-pet-plugins"
+	pluginProtocol = 1
+	pluginTimeout  = 10 * time.Second
+)
+
+type pluginRequest struct {
+	Protocol int    `json:"protocol"`
+	Hook     string `json:"hook"`
+	// Since is when activity starts counting, for the activity hook.
+	Since string `json:"since,omitempty"`
+	// The react hook gets the pet before and after the feed, what the feed
+	// counted, and the achievements it unlocked.
+	Before   *petSummary      `json:"before,omitempty"`
+	After    *petSummary      `json:"after,omitempty"`
+	Activity *ActivitySummary `json:"activity,omitempty"`
+	Unlocked []string         `json:"unlocked,omitempty"`
+}
+
+// petSummary is the pet as plugins and event hooks see it.
+type petSummary struct {
+	Name      string `json:"name"`
+	Evolution string `json:"evolution"`
+	Mood      int    `json:"mood"`
+	Kindness  int    `json:"kindness"`
+	Logic     int    `json:"logic_shards"`
+}
+
+func summarizePet(s store.PetState) petSummary {
+	return petSummary{Name: s.DisplayName(), Evolution: s.Evolution, Mood: s.Mood, Kindness: s.Kindness, Logic: s.Logic}
+}
+
+type pluginResponse struct {
+	Error       string          `json:"error,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Hooks       []string        `json:"hooks,omitempty"`
+	Activity    ActivitySummary `json:"activity"`
+	Note        string          `json:"note,omitempty"`
+	Message     string          `json:"message,omitempty"`
+	Art         string          `json:"art,omitempty"`
+}
+
+// plugin is an installed plugin and what it said about itself.
+type plugin struct {
+	Path        string
+	Name        string
+	Description string
+	Hooks       []string
+}
+
+func (p plugin) handles(hook string) bool {
+	for _, h := range p.Hooks {
+		if h == hook {
+			return true
+		}
+	}
+	return false
+}
+
+func pluginsDir() (string, error) {
+	dir, err := dirs.Config()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, pluginsDirName), nil
+}
+
+// pluginPaths lists the executables in the plugins directory. Hidden files,
+// directories, and files that can't be run are skipped.
+func pluginPaths() ([]string, error) {
+	dir, err := pluginsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !executable(info) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	return paths, nil
+}
+
+func executable(info os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(info.Name())) {
+		case ".exe", ".bat", ".cmd":
+			return true
+		}
+		return false
+	}
+	return info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
+
+// callPlugin runs the plugin at path with req and decodes its answer.
+func callPlugin(ctx context.Context, path string, req pluginRequest) (pluginResponse, error) {
+	req.Protocol = pluginProtocol
+	input, err := json.Marshal(req)
+	if err != nil {
+		return pluginResponse{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, pluginTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path)
+	cmd.WaitDelay = gh.KillGrace
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return pluginResponse{}, fmt.Errorf("no answer to %s within %s", req.Hook, pluginTimeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return pluginResponse{}, fmt.Errorf("%s failed: %s", req.Hook, msg)
+		}
+		return pluginResponse{}, fmt.Errorf("%s failed: %w", req.Hook, err)
+	}
+	var resp pluginResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return pluginResponse{}, fmt.Errorf("%s answered with invalid JSON: %w", req.Hook, err)
+	}
+	if resp.Error != "" {
+		return pluginResponse{}, fmt.Errorf("%s: %s", req.Hook, resp.Error)
+	}
+	return resp, nil
+}
+
+// describePlugin asks the plugin at path what it is. Its name defaults to
+// the file name without an extension.
+func describePlugin(ctx context.Context, path string) (plugin, error) {
+	p := plugin{Path: path, Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}
+	resp, err := callPlugin(ctx, path, pluginRequest{Hook: "describe"})
+	if err != nil {
+		return p, err
+	}
+	if resp.Name != "" {
+		p.Name = resp.Name
+	}
+	p.Description = resp.Description
+	p.Hooks = resp.Hooks
+	return p, nil
+}
+
+// loadPlugins describes every installed plugin at once. Plugins that fail
+// to are left out, with a warning on stderr.
+func loadPlugins(ctx context.Context) []plugin {
+	paths, err := pluginPaths()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not list plugins:", err)
+		return nil
+	}
+	plugins := make([]plugin, len(paths))
+	errs := make([]error, len(paths))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			plugins[i], errs[i] = describePlugin(ctx, path)
+		}()
+	}
+	wg.Wait()
+	var loaded []plugin
+	for i, p := range plugins {
+		if errs[i] != nil {
+			fmt.Fprintf(os.Stderr, "GitPet: plugin %s: %v\n", p.Name, errs[i])
+			continue
+		}
+		loaded = append(loaded, p)
+	}
+	return loaded
+}
+
+// pluginActivity collects what the plugins counted since then, and a note
+// from each about it. A plugin that fails or reports a negative count adds
+// nothing, with a warning on stderr.
+func pluginActivity(ctx context.Context, plugins []plugin, since time.Time) (ActivitySummary, []string) {
+	results := make([]pluginResponse, len(plugins))
+	errs := make([]error, len(plugins))
+	var wg sync.WaitGroup
+	for i, p := range plugins {
+		if !p.handles("activity") {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = callPlugin(ctx, p.Path, pluginRequest{Hook: "activity", Since: since.UTC().Format(time.RFC3339)})
+			if errs[i] == nil && results[i].Activity.Negative() {
+				errs[i] = errors.New("activity has a negative count")
+			}
+		}()
+	}
+	wg.Wait()
+
+	var summary ActivitySummary
+	var notes []string
+	for i, p := range plugins {
+		if errs[i] != nil {
+			fmt.Fprintf(os.Stderr, "GitPet: plugin %s: %v\n", p.Name, errs[i])
+			continue
+		}
+		summary.AddPlugin(results[i].Activity)
+		if note := strings.TrimSpace(results[i].Note); note != "" {
+			notes = append(notes, p.Name+": "+truncateWidth(note, 60))
+		}
+	}
+	return summary, notes
+}
+
+// pluginReaction is what a plugin had to say about a feed.
+type pluginReaction struct {
+	Plugin  string
+	Message string
+	Art     string
+}
+
+// pluginReactions asks each plugin with a react hook what it makes of a
+// feed. Art that wouldn't fit the status box, like a skin frame, is dropped.
+func pluginReactions(ctx context.Context, plugins []plugin, result feedResult) []pluginReaction {
+	before, after := summarizePet(result.Before), summarizePet(result.State)
+	req := pluginRequest{Hook: "react", Before: &before, After: &after, Activity: &result.Summary, Unlocked: result.Unlocked}
+
+	var reactions []pluginReaction
+	for _, p := range plugins {
+		if !p.handles("react") {
+			continue
+		}
+		resp, err := callPlugin(ctx, p.Path, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "GitPet: plugin %s: %v\n", p.Name, err)
+			continue
+		}
+		art := strings.TrimRight(resp.Art, "\n")
+		if !fitsArt(art) {
+			fmt.Fprintf(os.Stderr, "GitPet: plugin %s: art is larger than %d×%d; leaving it out\n", p.Name, maxSkinWidth, maxSkinHeight)
+			art = ""
+		}
+		if resp.Message == "" && art == "" {
+			continue
+		}
+		reactions = append(reactions, pluginReaction{Plugin: p.Name, Message: strings.TrimSpace(resp.Message), Art: art})
+	}
+	return reactions
+}
+
+// fitsArt reports whether art is within a skin frame's size.
+func fitsArt(art string) bool {
+	lines := strings.Split(art, "\n")
+	if len(lines) > maxSkinHeight {
+		return false
+	}
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > maxSkinWidth {
+			return false
+		}
+	}
+	return true
+}
+
+func runPluginsList() error {
+	dir, err := pluginsDir()
+	if err != nil {
+		return err
+	}
+	paths, err := pluginPaths()
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		fmt.Printf("No plugins installed. Put executables in %s\n", dir)
+		return nil
+	}
+	type listed struct {
+		plugin
+		err error
+	}
+	var all []listed
+	for _, path := range paths {
+		p, err := describePlugin(context.Background(), path)
+		all = append(all, listed{p, err})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	for _, l := range all {
+		if l.err != nil {
+			fmt.Printf("  %s %s✗ %v%s\n", l.Name, colorRed, l.err, colorReset)
+			continue
+		}
+		hooks := "no hooks"
+		if len(l.Hooks) > 0 {
+			hooks = strings.Join(l.Hooks, ", ")
+		}
+		fmt.Printf("  %s %s(%s)%s\n", l.Name, colorDim, hooks, colorReset)
+		if l.Description != "" {
+			fmt.Printf("    %s\n", l.Description)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// minRewardedPomodoro is the shortest session that earns anything, so a
+// one-minute timer can't farm mood.
+const minRewardedPomodoro = 15 * time.Minute
+
+// watchingFaces are the pet's frames while it keeps an eye on you: glancing
+// one way, the other, and now and then a blink.
+var watchingFaces = []string{"( ◉_◉)", "( ◉_◉)", "(◉_◉ )", "(◉_◉ )", "( -_- )"}
+
+// runPomodoro runs one focus session with the pet watching: a countdown,
+// then mood and logic shards for finishing it. Ctrl+C gives up without a
+// reward.
+func runPomodoro(args []string) error {
+	fs := newFlagSet("pomodoro")
+	length := fs.Duration("length", 25*time.Minute, "how long to focus")
+	task := fs.String("task", "", "what you're working on, for the countdown and the journal")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *length < time.Minute || *length > 2*time.Hour {
+		return fmt.Errorf("--length must be between 1m and 2h")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	start := time.Now()
+	end := start.Add(*length)
+	fmt.Printf("🍅 %s is watching you focus for %s, until %s. Ctrl+C gives up.\n", state.DisplayName(), i18n.Plural(int(length.Minutes()), "minute"), end.Format("15:04"))
+	if *task != "" {
+		fmt.Printf("   %sTask: %s%s\n", colorDim, *task, colorReset)
+	}
+
+	defer startPresence(cfg.Presence, presenceSession{Kind: "pomodoro", Task: *task, Start: start, End: end})()
+	live := stdoutIsTerminal()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		now := time.Now()
+		if !now.Before(end) {
+			break
+		}
+		if live {
+			fmt.Printf("\r%s", pomodoroLine(now, start, end, frame))
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			if live {
+				fmt.Print("\r\033[K")
+			}
+			fmt.Printf("🥀 Gave up after %s. %s looks a little let down.\n", i18n.Plural(int(time.Since(start).Minutes()), "minute"), state.DisplayName())
+			return nil
+		}
+	}
+	if live {
+		fmt.Print("\r\033[K")
+	}
+	return finishPomodoro(cfg, *length, *task)
+}
+
+// pomodoroLine is the countdown: the pet's face, a progress bar, and the
+// time left.
+func pomodoroLine(now, start, end time.Time, frame int) string {
+	face := watchingFaces[(frame/2)%len(watchingFaces)]
+	total := end.Sub(start)
+	filled := int(now.Sub(start) * 20 / total)
+	left := end.Sub(now).Round(time.Second)
+	return fmt.Sprintf("%s  %s%s%s%s  %02d:%02d left\033[K", face,
+		colorGreen, strings.Repeat("█", filled), colorDim+strings.Repeat("░", 20-filled), colorReset,
+		int(left.Minutes()), int(left.Seconds())%60)
+}
+
+// finishPomodoro rewards a session watched to the end and records it in
+// history and the journal.
+func finishPomodoro(cfg Config, length time.Duration, task string) error {
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	minutes := int(length.Minutes())
+	if err := store.RecordFocus(1, minutes); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not record focus session:", err)
+	}
+	if length < minRewardedPomodoro {
+		fmt.Printf("✅ %s of focus done. Sessions under %s are recorded but earn nothing.\n", i18n.Plural(minutes, "minute"), i18n.Plural(int(minRewardedPomodoro.Minutes()), "minute"))
+		return nil
+	}
+
+	state.Mood = min(100, state.Mood+cfg.Scoring.FocusMood)
+	state.Logic += cfg.Scoring.PomodoroLogic
+	if err := store.Save(state); err != nil {
+		return err
+	}
+	fmt.Printf("%s🍅 Pomodoro done! %s of focus. +%d mood, +%d logic shards%s\n", colorGreen, i18n.Plural(minutes, "minute"), cfg.Scoring.FocusMood, cfg.Scoring.PomodoroLogic, colorReset)
+	if cfg.Sounds.Enabled {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+	msg := fmt.Sprintf("%s of focus done. Stretch for five minutes?", i18n.Plural(minutes, "minute"))
+	notify(cfg.Notifications, state.Signature()+" "+state.DisplayName(), msg)
+
+	line := fmt.Sprintf("I watched Keeper focus for %s without looking away once.", i18n.Plural(minutes, "minute"))
+	if task != "" {
+		line = fmt.Sprintf("I watched Keeper focus on %q for %s without looking away once.", task, i18n.Plural(minutes, "minute"))
+	}
+	if err := store.AddJournalEntry("pomodoro", line); err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: could not write journal:", err)
+	}
+	return nil
+}
+
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0 && os.Getenv("TERM") != "dumb"
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// prCommit is one commit on the branch being drafted.
+type prCommit struct {
+	Short, Subject, Body string
+}
+
+// prDraft is a pull request GitPet wrote from a branch's commits.
+type prDraft struct {
+	Base, Branch string
+	Commits      []prCommit
+	// Summary counts the commits the way a feed would, and Persona is the
+	// evolution that summary would grow.
+	Summary ActivitySummary
+	Persona string
+	Change  activity.StagedChange
+	Title   string
+	Body    string
+}
+
+// runPRDraft drafts a title and description for the current branch, and
+// with --create opens the pull request through gh.
+func runPRDraft(args []string) error {
+	fs := newFlagSet("pr-draft")
+	base := fs.String("base", "", "the branch the pull request merges into (default: the repo's default branch)")
+	create := fs.Bool("create", false, "open the pull request with gh pr create")
+	draft := fs.Bool("draft", false, "with --create, open it as a draft")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *draft && !*create {
+		return usageErrorf("--draft only applies with --create")
+	}
+	ctx := context.Background()
+	if !gh.InWorkTree(ctx) {
+		return errors.New("not in a git repository")
+	}
+	if *base == "" {
+		*base = defaultBranch(ctx, ".")
+	}
+	state, _ := store.Load()
+	d, err := loadPRDraft(ctx, *base)
+	if err != nil {
+		return err
+	}
+	d.Title = prTitle(d)
+	d.Body = prBody(state, d)
+
+	if !*create {
+		fmt.Printf("%s\n\n%s", d.Title, d.Body)
+		return nil
+	}
+	ghArgs := []string{"pr", "create", "--base", d.Base, "--title", d.Title, "--body", d.Body}
+	if *draft {
+		ghArgs = append(ghArgs, "--draft")
+	}
+	fmt.Printf("%sOpening “%s” into %s…%s\n", colorDim, d.Title, d.Base, colorReset)
+	cmd := exec.Command("gh", ghArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// loadPRDraft reads the commits on HEAD that aren't on base, preferring
+// origin's copy of base, which is what the pull request is compared with.
+func loadPRDraft(ctx context.Context, base string) (prDraft, error) {
+	d := prDraft{Base: base}
+	out, err := gh.GitOutput(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return d, err
+	}
+	d.Branch = strings.TrimSpace(string(out))
+	if d.Branch == base || d.Branch == "HEAD" {
+		return d, fmt.Errorf("check out the branch to propose first; you're on %s", d.Branch)
+	}
+	ref := ""
+	for _, candidate := range []string{"origin/" + base, base} {
+		if _, err := gh.GitOutput(ctx, "rev-parse", "--verify", "--quiet", candidate+"^{commit}"); err == nil {
+			ref = candidate
+			break
+		}
+	}
+	if ref == "" {
+		return d, fmt.Errorf("can't find the base branch %q; pass --base", base)
+	}
+
+	out, err = gh.GitOutput(ctx, "log", "--reverse", "--no-merges", "--format=%h%x00%s%x00%b%x1e", ref+"..HEAD")
+	if err != nil {
+		return d, err
+	}
+	for _, record := range strings.Split(string(out), "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x00", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		d.Commits = append(d.Commits, prCommit{Short: fields[0], Subject: fields[1], Body: strings.TrimSpace(fields[2])})
+	}
+	if len(d.Commits) == 0 {
+		return d, fmt.Errorf("%s has no commits that aren't on %s yet", d.Branch, ref)
+	}
+	d.Summary.Commits = len(d.Commits)
+	for _, c := range d.Commits {
+		pet.ClassifyCommit(c.Subject+"\n\n"+c.Body, &d.Summary)
+	}
+	d.Persona = pet.EvolutionFor(d.Summary)
+	d.Change, _ = activity.ReadChange(ctx, "diff", ref+"...HEAD")
+	return d, nil
+}
+
+// prTitle is the only commit's subject, or the branch name written out as
+// a sentence, such as "Add quiet hours" for feature/add-quiet-hours.
+func prTitle(d prDraft) string {
+	if len(d.Commits) == 1 {
+		return d.Commits[0].Subject
+	}
+	name := d.Branch[strings.LastIndex(d.Branch, "/")+1:]
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' }), " ")
+	if name == "" {
+		return d.Commits[0].Subject
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// prBody is the description: a summary in the pet's voice, the commits, and
+// a checklist fitted to what they touch.
+func prBody(state store.PetState, d prDraft) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Summary\n\n%s\n\n## Changes\n\n", prSummary(state, d))
+	for _, c := range d.Commits {
+		fmt.Fprintf(&sb, "- %s (%s)\n", c.Subject, c.Short)
+	}
+	sb.WriteString("\n## Checklist\n\n")
+	for _, item := range prChecklist(d) {
+		sb.WriteString(item + "\n")
+	}
+	return sb.String()
+}
+
+// prSummary says what the branch does. The pet speaks in its current form,
+// and names the evolution the branch itself would grow.
+func prSummary(state store.PetState, d prDraft) string {
+	s := d.Summary
+	size := fmt.Sprintf("%s changing %s (+%d/-%d)", i18n.Plural(len(d.Commits), "commit"), i18n.Plural(len(d.Change.Files), "file"), d.Change.Insert, d.Change.Delete)
+	if d.Change.Scope != "" {
+		size += " in " + d.Change.Scope
+	}
+	var kinds []string
+	for _, k := range []struct {
+		count int
+		noun  string
+	}{{s.FixCommits, "bug fix"}, {s.TestCommits, "test commit"}, {s.RefactorCommits, "cleanup"}, {s.DocCommits, "docs commit"}} {
+		if k.count > 0 {
+			kinds = append(kinds, i18n.Plural(k.count, k.noun))
+		}
+	}
+	if professional() {
+		line := fmt.Sprintf("This pull request merges %s into %s: %s.", d.Branch, d.Base, size)
+		if len(kinds) > 0 {
+			line += " It includes " + i18n.JoinNames(kinds) + "."
+		}
+		return line
+	}
+	opener, ok := activity.CommitOpeners[state.Evolution]
+	if !ok {
+		opener = activity.CommitOpeners["Companion"]
+	}
+	lines := []string{opener, fmt.Sprintf("%s brings %s.", d.Branch, size)}
+	if len(kinds) > 0 {
+		lines = append(lines, fmt.Sprintf("Among them: %s.", i18n.JoinNames(kinds)))
+	}
+	if d.Persona != "" && d.Persona != "Lonely" {
+		lines = append(lines, fmt.Sprintf("If this branch were a pet, it would be a %s.", d.Persona))
+	}
+	lines = append(lines, fmt.Sprintf("— %s %s", state.Signature(), state.DisplayName()))
+	return strings.Join(lines, " ")
+}
+
+// prChecklist ticks what the commits already show and leaves the rest for
+// the author.
+func prChecklist(d prDraft) []string {
+	item := func(done bool, text string) string {
+		if done {
+			return "- [x] " + text
+		}
+		return "- [ ] " + text
+	}
+	s := d.Summary
+	list := []string{
+		item(s.TestCommits > 0 || d.Change.Type == "test", "Tests added or updated"),
+		item(s.DocCommits > 0 || d.Change.Type == "docs", "Documentation updated"),
+	}
+	if s.FixCommits > 0 {
+		list = append(list, item(false, "A test covers the bug that was fixed"))
+	}
+	if s.RefactorCommits > 0 {
+		list = append(list, item(false, "Behavior is unchanged by the cleanup"))
+	}
+	if lines := d.Change.Insert + d.Change.Delete; lines >= activity.LargeCommitLines {
+		list = append(list, item(false, fmt.Sprintf("Considered splitting this %d-line change", lines)))
+	}
+	return append(list, item(false, "Self-reviewed the diff"), item(false, "CI is green"))
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Staged changes beyond these sizes make the pet worry about reviewability.
+const (
+	hugeDiffLines = 500
+	hugeDiffFiles = 20
+	todoSpike     = 3
+)
+
+// debugPatterns match leftovers from a debugging session, by language.
+var debugPatterns = []struct {
+	Ext     string
+	Pattern *regexp.Regexp
+}{
+	// Printing a bare variable, or anything mentioning debug, rather than a message.
+	{".go", regexp.MustCompile(`fmt\.Print(ln)?\(\s*[A-Za-z_][\w.]*\s*\)|fmt\.Print(ln|f)?\("(?i:debug|here|xxx)`)},
+	{".go", regexp.MustCompile(`\bspew\.Dump\(`)},
+	{".js", regexp.MustCompile(`\bconsole\.log\(|\bdebugger;`)},
+	{".ts", regexp.MustCompile(`\bconsole\.log\(|\bdebugger;`)},
+	{".py", regexp.MustCompile(`\bpdb\.set_trace\(|\bbreakpoint\(\)`)},
+	{".rb", regexp.MustCompile(`\bbinding\.pry\b|\bbyebug\b`)},
+	{".rs", regexp.MustCompile(`\bdbg!\(`)},
+	{".php", regexp.MustCompile(`\bvar_dump\(|\bdd\(`)},
+}
+
+var (
+	todoPattern    = regexp.MustCompile(`\b(TODO|FIXME|XXX|HACK)\b`)
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+		regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{36}`),
+		regexp.MustCompile(`xox[abprs]-[A-Za-z0-9-]{10,}`),
+		regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`),
+		regexp.MustCompile(`(?i)(api[_-]?key|secret|passw(or)?d|token)\s*[:=]\s*["'][^"'\s]{8,}["']`),
+	}
+)
+
+// stagedAddition is one line the commit would add.
+type stagedAddition struct {
+	Path string
+	Line string
+}
+
+func runPreCommit(args []string) error {
+	fs := newFlagSet("pre-commit")
+	strict := fs.Bool("strict", false, "block the commit when there are worries")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	numstat, err := gh.GitOutput(ctx, "diff", "--cached", "--numstat")
+	if err != nil {
+		return fmt.Errorf("cannot read staged changes: %w", err)
+	}
+	diff, err := gh.GitOutput(ctx, "diff", "--cached", "-U0", "--no-color")
+	if err != nil {
+		return fmt.Errorf("cannot read staged changes: %w", err)
+	}
+	worries := stagedWorries(string(numstat), stagedAdditions(string(diff)))
+	if len(worries) == 0 {
+		return nil
+	}
+
+	state, _ := store.Load()
+	fmt.Printf("%s %s has some worries about this commit:\n", state.Signature(), state.DisplayName())
+	for _, worry := range worries {
+		fmt.Printf("  %s• %s%s\n", colorYellow, worry, colorReset)
+	}
+	if *strict {
+		return fmt.Errorf("commit blocked; fix the worries above or commit with --no-verify")
+	}
+	return nil
+}
+
+func stagedAdditions(diff string) []stagedAddition {
+	var out []stagedAddition
+	path := ""
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "+"):
+			out = append(out, stagedAddition{Path: path, Line: line[1:]})
+		}
+	}
+	return out
+}
+
+// stagedWorries turns the staged diff into the pet's concerns, most serious
+// first.
+func stagedWorries(numstat string, additions []stagedAddition) []string {
+	var worries []string
+
+	secrets := map[string]bool{}
+	for _, a := range additions {
+		for _, pattern := range secretPatterns {
+			if pattern.MatchString(a.Line) && !secrets[a.Path] {
+				secrets[a.Path] = true
+				worries = append(worries, fmt.Sprintf("%s has something that looks like a secret. Please don't feed me credentials!", a.Path))
+			}
+		}
+	}
+
+	files, lines := 0, 0
+	for _, row := range strings.Split(strings.TrimSpace(numstat), "\n") {
+		fields := strings.Fields(row)
+		if len(fields) < 3 {
+			continue
+		}
+		files++
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		lines += added + removed
+	}
+	if lines > hugeDiffLines || files > hugeDiffFiles {
+		worries = append(worries, fmt.Sprintf("%d lines across %d files is a big bite. Could it be split?", lines, files))
+	}
+
+	debug := map[string]int{}
+	var debugPaths []string
+	todos := 0
+	for _, a := range additions {
+		for _, p := range debugPatterns {
+			if strings.HasSuffix(a.Path, p.Ext) && p.Pattern.MatchString(a.Line) {
+				if debug[a.Path] == 0 {
+					debugPaths = append(debugPaths, a.Path)
+				}
+				debug[a.Path]++
+				break
+			}
+		}
+		if todoPattern.MatchString(a.Line) {
+			todos++
+		}
+	}
+	for _, path := range debugPaths {
+		worries = append(worries, fmt.Sprintf("%s still has %s of debug output.", path, i18n.Plural(debug[path], "line")))
+	}
+	if todos >= todoSpike {
+		worries = append(worries, fmt.Sprintf("%d new TODOs in one commit. That's a lot of promises.", todos))
+	}
+	return worries
+}
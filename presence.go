@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// presenceInterval is how often the Discord status picks up mood changes
+// and retries a Discord client that wasn't running.
+const presenceInterval = 15 * time.Second
+
+// PresenceConfig publishes the pet to Discord Rich Presence while gh pet
+// focus or pomodoro runs. The Show fields decide what the status reveals;
+// with all of them off it only says a session is running.
+type PresenceConfig struct {
+	Enabled bool `json:"enabled"`
+	// ClientID is the Discord application the status appears under; its
+	// name is what Discord shows as the game.
+	ClientID      string `json:"client_id"`
+	ShowName      bool   `json:"show_name"`
+	ShowEvolution bool   `json:"show_evolution"`
+	ShowMood      bool   `json:"show_mood"`
+	// ShowTask shares a pomodoro's --task, which may name private work.
+	ShowTask bool `json:"show_task"`
+}
+
+func defaultPresence() PresenceConfig {
+	return PresenceConfig{ShowName: true, ShowEvolution: true, ShowMood: true}
+}
+
+func (p PresenceConfig) validate() error {
+	if p.Enabled && p.ClientID == "" {
+		return fmt.Errorf("presence.client_id is required; create an application at https://discord.com/developers/applications and use its ID")
+	}
+	for _, r := range p.ClientID {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("presence.client_id must be a numeric Discord application ID")
+		}
+	}
+	return nil
+}
+
+// presenceSession is the session a status describes.
+type presenceSession struct {
+	// Kind is "pomodoro" or "focus".
+	Kind  string
+	Task  string
+	Start time.Time
+	// End is when a pomodoro finishes, so Discord can count down to it.
+	End time.Time
+}
+
+// activity is the status for the pet as it is now, with only what p allows.
+func (p PresenceConfig) activity(state store.PetState, session presenceSession) *discordActivity {
+	name := "GitPet"
+	if p.ShowName {
+		name = state.DisplayName()
+	}
+	details := "Feeding " + name
+	var about []string
+	if p.ShowEvolution && state.Evolution != "" {
+		about = append(about, state.Evolution)
+	}
+	if p.ShowMood {
+		about = append(about, fmt.Sprintf("Mood %d", state.Mood))
+	}
+	if len(about) > 0 {
+		details += " — " + strings.Join(about, ", ")
+	}
+	status := "🍅 In a pomodoro"
+	if session.Kind == "focus" {
+		status = "👀 Focusing"
+	}
+	if p.ShowTask && session.Task != "" {
+		status += ": " + session.Task
+	}
+	if professional() {
+		status = neutralize(status)
+	}
+	a := &discordActivity{Details: truncateWidth(details, 128), State: truncateWidth(status, 128), Timestamps: &discordTimestamps{Start: session.Start.Unix()}}
+	if !session.End.IsZero() {
+		a.Timestamps = &discordTimestamps{End: session.End.Unix()}
+	}
+	return a
+}
+
+// startPresence shows session on Discord until stop is called, when the
+// status is cleared. It never fails the session: without Discord running,
+// nothing is shown and it quietly tries again.
+func startPresence(cfg PresenceConfig, session presenceSession) (stop func()) {
+	if !cfg.Enabled {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		var conn *discordIPC
+		ticker := time.NewTicker(presenceInterval)
+		defer ticker.Stop()
+		for {
+			if conn == nil {
+				var err error
+				if conn, err = dialDiscord(cfg.ClientID); err != nil {
+					logging.Logger.Debug("presence", "err", err)
+				}
+			}
+			if conn != nil {
+				state, _ := store.Load()
+				if err := conn.setActivity(cfg.activity(state, session)); err != nil {
+					logging.Logger.Debug("presence", "err", err)
+					conn.Close()
+					conn = nil
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				if conn != nil {
+					conn.setActivity(nil)
+					conn.Close()
+				}
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// QuietHours is a daily do-not-disturb window. Start and End are local
+// times like "22:00"; a window that ends before it starts runs past
+// midnight. Both empty turns quiet hours off.
+type QuietHours struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+	// Days limits the window to the weekdays it starts on, named as in
+	// wellness.rest_days; empty is every day.
+	Days []string `json:"days,omitempty"`
+}
+
+func (q QuietHours) validate() error {
+	if (q.Start == "") != (q.End == "") {
+		return fmt.Errorf("notifications.quiet_hours needs both start and end")
+	}
+	for _, clock := range []string{q.Start, q.End} {
+		if _, ok := parseClock(clock); clock != "" && !ok {
+			return fmt.Errorf("notifications.quiet_hours: %q is not a time like 22:00", clock)
+		}
+	}
+	for _, day := range q.Days {
+		if _, ok := pet.ParseWeekday(day); !ok {
+			return fmt.Errorf("notifications.quiet_hours.days: unknown weekday %q", day)
+		}
+	}
+	return nil
+}
+
+// parseClock reads "22:00" as minutes after midnight.
+func parseClock(clock string) (int, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// active reports whether t falls inside quiet hours.
+func (q QuietHours) active(t time.Time) bool {
+	start, ok1 := parseClock(q.Start)
+	end, ok2 := parseClock(q.End)
+	if !ok1 || !ok2 || start == end {
+		return false
+	}
+	t = t.Local()
+	minute := t.Hour()*60 + t.Minute()
+	day := t
+	switch {
+	case start < end:
+		if minute < start || minute >= end {
+			return false
+		}
+	case minute >= start:
+	case minute < end:
+		// Past midnight, the window belongs to the day it started on.
+		day = t.AddDate(0, 0, -1)
+	default:
+		return false
+	}
+	if len(q.Days) == 0 {
+		return true
+	}
+	for _, name := range q.Days {
+		if wd, ok := pet.ParseWeekday(name); ok && wd == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// String is the window as gh pet config shows it, e.g. "22:00-07:00".
+func (q QuietHours) String() string {
+	if q.Start == "" {
+		return "off"
+	}
+	return q.Start + "-" + q.End
+}
+
+// quietPostCommit is the one line the post-commit hook prints during quiet
+// hours.
+func quietPostCommit(state store.PetState, moodGain int, unlocked []string) string {
+	line := fmt.Sprintf("🌙 %s %s +%d mood (%d)", state.Signature(), state.DisplayName(), moodGain, state.Mood)
+	if professional() {
+		line = fmt.Sprintf("GitPet: commit recorded. Mood %d/100.", state.Mood)
+	}
+	if len(unlocked) > 0 {
+		line += fmt.Sprintf(" · %s", i18n.Plural(len(unlocked), "achievement"))
+	}
+	return line
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Renderer draws the pet's views for one kind of output. The terminal
+// renderer draws boxes in ANSI color for the commands; the HTML renderer
+// draws the same views for the web dashboard gh pet serve hosts.
+type Renderer interface {
+	// Status is the full status view, with the wellness concerns.
+	Status(state store.PetState, concerns []string) string
+	// PostCommit is what the post-commit hook shows after a commit.
+	PostCommit(state store.PetState, commitMsg string, moodGain int) string
+	// Prompt is the pet in one line, as the shell prompt shows it.
+	Prompt(state store.PetState, branch *branchState) string
+	// Card is the pet at a glance, small enough to put somewhere else.
+	Card(state store.PetState) string
+}
+
+var (
+	_ Renderer = terminalRenderer{}
+	_ Renderer = htmlRenderer{}
+)
+
+// terminalRenderer draws for the terminal in theme. With sprite, the pet's
+// art is left as blank space for overlaySprite to draw in.
+type terminalRenderer struct {
+	theme    Theme
+	absolute bool
+	sprite   bool
+	now      time.Time
+}
+
+func (t terminalRenderer) Status(state store.PetState, concerns []string) string {
+	return renderStatus(state, concerns, t.theme, t.absolute, t.sprite)
+}
+
+func (t terminalRenderer) PostCommit(state store.PetState, commitMsg string, moodGain int) string {
+	return renderPostCommit(state, commitMsg, moodGain, t.theme)
+}
+
+func (t terminalRenderer) Prompt(state store.PetState, branch *branchState) string {
+	return promptLine(state, branch, t.now)
+}
+
+// Card is the pet's art in a box with its name, mood, and evolution.
+func (t terminalRenderer) Card(state store.PetState) string {
+	bx := newBox(t.theme, t.theme.accent(state.Evolution))
+	bx.label = " " + state.Signature() + " " + state.DisplayName() + " "
+	bx.lines(renderArt(state, t.sprite))
+	bx.line("")
+	bx.line(i18n.Tr("Mood") + ": " + renderMoodBar(state.Mood, t.theme) + " " + moodFace(state.Mood))
+	bx.line(i18n.Tr("Evolution") + ": " + i18n.Tr(orDefault(state.Evolution, "Lonely")))
+	return bx.render(34) + "\n"
+}
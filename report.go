@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// weeklyReport is everything a digest says, independent of its format.
+type weeklyReport struct {
+	Name         string
+	Emoji        string
+	Evolution    string
+	From, To     time.Time
+	Week         rollup
+	PrevWeek     rollup
+	Days         []store.DayRecord
+	Achievements []string
+	Narrative    []string
+}
+
+func runReport(args []string) error {
+	fs := newFlagSet("report")
+	fs.Bool("week", true, "report on the current week (the only period for now)")
+	format := fs.String("format", "markdown", "output format: markdown or html")
+	out := fs.String("out", "", "write the report to a file instead of stdout")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "markdown" && *format != "md" && *format != "html" {
+		return fmt.Errorf("unknown format %q (markdown, html)", *format)
+	}
+
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	state, _ := store.Load()
+	report := buildWeeklyReport(state, history, time.Now())
+
+	var text string
+	if *format == "html" {
+		text = report.html()
+	} else {
+		text = report.markdown()
+	}
+	if *out == "" {
+		fmt.Print(text)
+		return nil
+	}
+	if err := os.WriteFile(*out, []byte(text), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Weekly report written to %s%s\n", colorGreen, *out, colorReset)
+	return nil
+}
+
+func buildWeeklyReport(state store.PetState, history store.History, now time.Time) weeklyReport {
+	weeks := weeklyRollups(history, 2, now)
+	from := weeks[1].Start
+	r := weeklyReport{
+		Name:      state.DisplayName(),
+		Emoji:     state.Signature(),
+		Evolution: state.Evolution,
+		From:      from,
+		To:        from.AddDate(0, 0, 6),
+		Week:      weeks[1],
+		PrevWeek:  weeks[0],
+		Days:      history.Between(from, from.AddDate(0, 0, 7)),
+	}
+	if r.Evolution == "" {
+		r.Evolution = "Lonely"
+	}
+	for _, a := range store.Achievements {
+		if store.HasAchievement(state, a.Name) {
+			r.Achievements = append(r.Achievements, a.Icon+" "+a.Name)
+		}
+	}
+	r.Narrative = r.narrate()
+	return r
+}
+
+// narrate is the pet's own take on the week, written in its voice.
+func (r weeklyReport) narrate() []string {
+	cur, prev := r.Week.total(), r.PrevWeek.total()
+	if cur == 0 {
+		return []string{"I spent the week napping beside a quiet repo. Come back soon?"}
+	}
+	var lines []string
+	switch {
+	case prev == 0:
+		lines = append(lines, fmt.Sprintf("After a quiet stretch, I counted %d events this week. Welcome back!", cur))
+	case cur > prev:
+		lines = append(lines, fmt.Sprintf("What a week! I counted %d events, up from %d.", cur, prev))
+	case cur < prev:
+		lines = append(lines, fmt.Sprintf("A gentler week: %d events after last week's %d. I approve of the pace.", cur, prev))
+	default:
+		lines = append(lines, fmt.Sprintf("Steady as ever: %d events, same as last week.", cur))
+	}
+	if r.Week.MergedPRs > 0 {
+		lines = append(lines, fmt.Sprintf("%d merged PR(s) set off fireworks in my Cache.", r.Week.MergedPRs))
+	}
+	if r.Week.Reviews > r.Week.Commits {
+		lines = append(lines, "You reviewed more than you committed, and every review made me a little kinder.")
+	}
+	if len(r.Days) >= 2 {
+		first, last := r.Days[0].Mood, r.Days[len(r.Days)-1].Mood
+		switch {
+		case last > first:
+			lines = append(lines, fmt.Sprintf("My mood climbed from %d to %d.", first, last))
+		case last < first:
+			lines = append(lines, fmt.Sprintf("My mood dipped from %d to %d. Maybe a rest day is in order.", first, last))
+		}
+	}
+	return lines
+}
+
+// moodSparkline draws the week's mood, one block per recorded day.
+func moodSparkline(days []store.DayRecord) string {
+	blocks := []rune("▁▂▃▄▅▆▇█")
+	var sb strings.Builder
+	for _, d := range days {
+		i := min(d.Mood*len(blocks)/101, len(blocks)-1)
+		sb.WriteRune(blocks[max(i, 0)])
+	}
+	return sb.String()
+}
+
+func (r weeklyReport) title() string {
+	return fmt.Sprintf("%s %s's week: %s – %s", r.Emoji, r.Name, r.From.Format("Jan 2"), r.To.Format("Jan 2, 2006"))
+}
+
+func (r weeklyReport) markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", r.title()))
+	sb.WriteString(fmt.Sprintf("**Evolution:** %s\n\n", r.Evolution))
+
+	sb.WriteString("## Activity\n\n")
+	sb.WriteString("| | This week | Last week |\n|---|---:|---:|\n")
+	for _, row := range r.activityRows() {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d |\n", row.label, row.cur, row.prev))
+	}
+
+	sb.WriteString("\n## Mood\n\n")
+	if len(r.Days) == 0 {
+		sb.WriteString("No days recorded yet.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("`%s` (%d → %d)\n", moodSparkline(r.Days), r.Days[0].Mood, r.Days[len(r.Days)-1].Mood))
+	}
+
+	sb.WriteString("\n## Achievements\n\n")
+	if len(r.Achievements) == 0 {
+		sb.WriteString("None yet.\n")
+	}
+	for _, a := range r.Achievements {
+		sb.WriteString(fmt.Sprintf("- %s\n", a))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n## From %s\n\n", r.Name))
+	for _, line := range r.Narrative {
+		sb.WriteString(fmt.Sprintf("> %s\n", line))
+	}
+	return sb.String()
+}
+
+func (r weeklyReport) html() string {
+	e := html.EscapeString
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\">")
+	sb.WriteString(fmt.Sprintf("<title>%s</title></head><body>\n", e(r.title())))
+	sb.WriteString(fmt.Sprintf("<h1>%s</h1>\n<p><strong>Evolution:</strong> %s</p>\n", e(r.title()), e(r.Evolution)))
+
+	sb.WriteString("<h2>Activity</h2>\n<table>\n<tr><th></th><th>This week</th><th>Last week</th></tr>\n")
+	for _, row := range r.activityRows() {
+		sb.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%d</td><td>%d</td></tr>\n", e(row.label), row.cur, row.prev))
+	}
+	sb.WriteString("</table>\n")
+
+	sb.WriteString("<h2>Mood</h2>\n")
+	if len(r.Days) == 0 {
+		sb.WriteString("<p>No days recorded yet.</p>\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("<p><code>%s</code> (%d → %d)</p>\n", moodSparkline(r.Days), r.Days[0].Mood, r.Days[len(r.Days)-1].Mood))
+	}
+
+	sb.WriteString("<h2>Achievements</h2>\n")
+	if len(r.Achievements) == 0 {
+		sb.WriteString("<p>None yet.</p>\n")
+	} else {
+		sb.WriteString("<ul>\n")
+		for _, a := range r.Achievements {
+			sb.WriteString(fmt.Sprintf("<li>%s</li>\n", e(a)))
+		}
+		sb.WriteString("</ul>\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("<h2>From %s</h2>\n<blockquote>\n", e(r.Name)))
+	for _, line := range r.Narrative {
+		sb.WriteString(fmt.Sprintf("<p>%s</p>\n", e(line)))
+	}
+	sb.WriteString("</blockquote>\n</body></html>\n")
+	return sb.String()
+}
+
+type reportRow struct {
+	label     string
+	cur, prev int
+}
+
+func (r weeklyReport) activityRows() []reportRow {
+	return []reportRow{
+		{"Commits", r.Week.Commits, r.PrevWeek.Commits},
+		{"Merged PRs", r.Week.MergedPRs, r.PrevWeek.MergedPRs},
+		{"Reviews", r.Week.Reviews, r.PrevWeek.Reviews},
+		{"Docs/Comments", r.Week.DocComments, r.PrevWeek.DocComments},
+		{"Issues", r.Week.Issues, r.PrevWeek.Issues},
+	}
+}
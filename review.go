@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Pull requests beyond these line counts get a size warning.
+const (
+	largePRLines = 400
+	hugePRLines  = 1000
+)
+
+const pullRequestQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      title number url additions deletions changedFiles reviewDecision
+      files(first: 100) { nodes { path } }
+      reviewThreads(first: 100) { nodes { isResolved } }
+      commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }
+    }
+  }
+}`
+
+// PullRequestInfo is the slice of a pull request the pet comments on.
+type PullRequestInfo struct {
+	Title          string `json:"title"`
+	Number         int    `json:"number"`
+	URL            string `json:"url"`
+	Additions      int    `json:"additions"`
+	Deletions      int    `json:"deletions"`
+	ChangedFiles   int    `json:"changedFiles"`
+	ReviewDecision string `json:"reviewDecision"`
+	Files          struct {
+		Nodes []struct {
+			Path string `json:"path"`
+		} `json:"nodes"`
+	} `json:"files"`
+	ReviewThreads struct {
+		Nodes []struct {
+			IsResolved bool `json:"isResolved"`
+		} `json:"nodes"`
+	} `json:"reviewThreads"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+func (pr PullRequestInfo) ciState() string {
+	if n := len(pr.Commits.Nodes); n > 0 && pr.Commits.Nodes[n-1].Commit.StatusCheckRollup != nil {
+		return pr.Commits.Nodes[n-1].Commit.StatusCheckRollup.State
+	}
+	return ""
+}
+
+func (pr PullRequestInfo) unresolvedThreads() int {
+	n := 0
+	for _, t := range pr.ReviewThreads.Nodes {
+		if !t.IsResolved {
+			n++
+		}
+	}
+	return n
+}
+
+func (pr PullRequestInfo) touchesTests() bool {
+	for _, f := range pr.Files.Nodes {
+		if activity.IsTestPath(f.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+var pullURLPattern = regexp.MustCompile(`github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
+
+// parsePullRef accepts 123, #123, owner/repo#123, or a pull request URL. An
+// empty owner and repo mean the current repository.
+func parsePullRef(ref string) (owner, repo string, number int, err error) {
+	if m := pullURLPattern.FindStringSubmatch(ref); m != nil {
+		number, _ = strconv.Atoi(m[3])
+		return m[1], m[2], number, nil
+	}
+	if slug, num, ok := strings.Cut(ref, "#"); ok && slug != "" {
+		owner, repo, ok = strings.Cut(slug, "/")
+		if !ok {
+			return "", "", 0, fmt.Errorf("expected owner/repo#number, got %q", ref)
+		}
+		ref = num
+	}
+	number, err = strconv.Atoi(strings.TrimPrefix(ref, "#"))
+	if err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("not a pull request number or URL: %q", ref)
+	}
+	return owner, repo, number, nil
+}
+
+func fetchPullRequest(ctx context.Context, owner, repo string, number int) (PullRequestInfo, error) {
+	if owner == "" {
+		var err error
+		if owner, repo, err = currentRepo(ctx); err != nil {
+			return PullRequestInfo{}, err
+		}
+	}
+	var data struct {
+		Repository *struct {
+			PullRequest *PullRequestInfo `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	vars := map[string]any{"owner": owner, "repo": repo, "number": number}
+	if err := gh.GraphQL(ctx, pullRequestQuery, vars, &data); err != nil {
+		return PullRequestInfo{}, err
+	}
+	if data.Repository == nil || data.Repository.PullRequest == nil {
+		return PullRequestInfo{}, fmt.Errorf("pull request #%d not found", number)
+	}
+	return *data.Repository.PullRequest, nil
+}
+
+// currentRepo asks gh which repository the working directory belongs to, so
+// forks resolve the same way they do for gh itself.
+func currentRepo(ctx context.Context) (owner, repo string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(gh.Timeouts.GitHubSeconds)*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gh", "repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner")
+	cmd.WaitDelay = gh.KillGrace
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot tell which repository this is; pass owner/repo#number: %w", err)
+	}
+	owner, repo, _ = strings.Cut(strings.TrimSpace(string(out)), "/")
+	return owner, repo, nil
+}
+
+// reviewRemarks is the pet's read on the pull request, in its own voice.
+func reviewRemarks(pr PullRequestInfo) []string {
+	var remarks []string
+	size := pr.Additions + pr.Deletions
+	switch {
+	case size > hugePRLines:
+		remarks = append(remarks, fmt.Sprintf("🐘 %d changed lines! Maybe suggest splitting it up.", size))
+	case size > largePRLines:
+		remarks = append(remarks, fmt.Sprintf("📦 %d changed lines — grab a snack before diving in.", size))
+	default:
+		remarks = append(remarks, fmt.Sprintf("🍪 %d changed lines. A bite-sized review!", size))
+	}
+	if !pr.touchesTests() {
+		remarks = append(remarks, "🧪 No test files touched. Ask how this was verified?")
+	}
+	if n := pr.unresolvedThreads(); n > 0 {
+		remarks = append(remarks, fmt.Sprintf("💬 %s still waiting for an answer.", i18n.Plural(n, "review thread")))
+	}
+	switch pr.ciState() {
+	case "SUCCESS":
+		remarks = append(remarks, "✅ CI is green.")
+	case "FAILURE", "ERROR":
+		remarks = append(remarks, "❌ CI is failing. The author may want to look first.")
+	case "PENDING", "EXPECTED":
+		remarks = append(remarks, "⏳ CI is still running.")
+	}
+	switch pr.ReviewDecision {
+	case "APPROVED":
+		remarks = append(remarks, "👍 Already approved; a second look is still a kindness.")
+	case "CHANGES_REQUESTED":
+		remarks = append(remarks, "✋ Changes were requested earlier.")
+	case "REVIEW_REQUIRED":
+		remarks = append(remarks, "👀 Still needs a review. That could be you!")
+	}
+	return remarks
+}
+
+func runReview(args []string) error {
+	fs := newFlagSet("review")
+	approve := fs.Bool("approve", false, "approve the pull request")
+	comment := fs.String("comment", "", "submit a review comment")
+	changes := fs.String("request-changes", "", "request changes, with this explanation")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	// Allow the pull request before or after the flags.
+	if fs.NArg() == 0 {
+		return usageErrorf("usage: gh pet review <number|url> [--approve | --comment text | --request-changes text]")
+	}
+	ref := fs.Arg(0)
+	if err := parseFlags(fs, fs.Args()[1:]); err != nil {
+		return err
+	}
+
+	owner, repo, number, err := parsePullRef(ref)
+	if err != nil {
+		return err
+	}
+	pr, err := fetchPullRequest(context.Background(), owner, repo, number)
+	if err != nil {
+		return err
+	}
+
+	state, _ := store.Load()
+	theme := loadTheme()
+	color := theme.accent(state.Evolution)
+	fmt.Printf("\n%s%s%s looks over #%d: %s%s\n", theme.Bold, color, state.Signature()+" "+state.DisplayName(), pr.Number, pr.Title, theme.Reset)
+	fmt.Printf("%s  +%d −%d across %s%s\n", theme.Dim, pr.Additions, pr.Deletions, i18n.Plural(pr.ChangedFiles, "file"), theme.Reset)
+	for _, remark := range reviewRemarks(pr) {
+		fmt.Printf("  %s\n", remark)
+	}
+
+	var reviewArgs []string
+	switch {
+	case *approve:
+		reviewArgs = []string{"--approve"}
+	case *changes != "":
+		reviewArgs = []string{"--request-changes", "--body", *changes}
+	case *comment != "":
+		reviewArgs = []string{"--comment", "--body", *comment}
+	default:
+		fmt.Printf("\n%sSubmit with --approve, --comment, or --request-changes to earn Kindness.%s\n", theme.Dim, theme.Reset)
+		return nil
+	}
+
+	cmd := exec.Command("gh", append([]string{"pr", "review", pr.URL}, reviewArgs...)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("gh pr review failed: %w", err)
+	}
+
+	// The next feed sees this review too, so it's credited now only if its
+	// ID can be recorded for that feed to skip.
+	id, err := submittedReviewID(context.Background(), pr)
+	if err != nil {
+		logging.Logger.Debug("review id", "err", err)
+		fmt.Printf("%s💗 Thank you for reviewing! Your next feed will count it.%s\n", theme.Good, theme.Reset)
+		return nil
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "GitPet: using default scoring:", err)
+	}
+	kindness := cfg.Scoring.WithAbility(state.Evolution, time.Now()).ReviewKindness
+	state.Kindness += kindness
+	state.CreditedReviews = append(state.CreditedReviews, id)
+	if n := len(state.CreditedReviews); n > maxCreditedReviews {
+		state.CreditedReviews = state.CreditedReviews[n-maxCreditedReviews:]
+	}
+	if err := store.Save(state); err != nil {
+		return err
+	}
+	fmt.Printf("%s💗 Thank you for reviewing! +%d kindness%s\n", theme.Good, kindness, theme.Reset)
+	return nil
+}
+
+// maxCreditedReviews is how many credited review IDs the state keeps, more
+// than a week's events ever hold.
+const maxCreditedReviews = 100
+
+// submittedReviewID is the ID of the Keeper's latest review of pr.
+func submittedReviewID(ctx context.Context, pr PullRequestInfo) (int64, error) {
+	m := pullURLPattern.FindStringSubmatch(pr.URL)
+	if m == nil {
+		return 0, fmt.Errorf("not a pull request URL: %q", pr.URL)
+	}
+	login, err := ghLogin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var reviews []struct {
+		ID   int64 `json:"id"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := gh.Get(ctx, fmt.Sprintf("repos/%s/%s/pulls/%d/reviews?per_page=100", m[1], m[2], pr.Number), &reviews); err != nil {
+		return 0, err
+	}
+	for i := len(reviews) - 1; i >= 0; i-- {
+		if strings.EqualFold(reviews[i].User.Login, login) {
+			return reviews[i].ID, nil
+		}
+	}
+	return 0, fmt.Errorf("no review by %s on #%d", login, pr.Number)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const reviewQueueQuery = `query {
+  search(query: "is:pr is:open archived:false review-requested:@me", type: ISSUE, first: 1) { issueCount }
+}`
+
+// reviewQueueWindow is how soon after it filled a cleared queue earns
+// kindness.
+const reviewQueueWindow = 24 * time.Hour
+
+// fetchReviewQueue counts the open pull requests waiting on the Keeper's
+// review.
+func fetchReviewQueue(ctx context.Context) (int, error) {
+	var data struct {
+		Search struct {
+			IssueCount int `json:"issueCount"`
+		} `json:"search"`
+	}
+	if err := gh.GraphQL(ctx, reviewQueueQuery, nil, &data); err != nil {
+		return 0, err
+	}
+	return data.Search.IssueCount, nil
+}
+
+// trackReviewQueue records the queue on state. When it empties within
+// reviewQueueWindow of filling, the Keeper earns kindness, and it reports
+// true.
+func trackReviewQueue(state *store.PetState, queue, kindness int, now time.Time) bool {
+	before := state.ReviewQueue
+	state.ReviewQueue = queue
+	if queue > 0 {
+		if state.ReviewQueueSince == "" {
+			state.ReviewQueueSince = now.UTC().Format(time.RFC3339)
+		}
+		return false
+	}
+	since, err := time.Parse(time.RFC3339, state.ReviewQueueSince)
+	state.ReviewQueueSince = ""
+	if before == 0 || err != nil || now.Sub(since) > reviewQueueWindow {
+		return false
+	}
+	state.Kindness += kindness
+	return true
+}
+
+// reviewQueueLine nudges the Keeper toward teammates waiting on them.
+func reviewQueueLine(queue int) string {
+	if queue == 1 {
+		return "📬 A pull request is waiting on your review. A teammate would love your eyes on it."
+	}
+	return fmt.Sprintf("📬 %d pull requests are waiting on your review. Your teammates would love your eyes on them.", queue)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Scoring lives in internal/pet, where the MCP server and the Vercel
+// handler share it.
+type (
+	ScoringConfig      = pet.ScoringConfig
+	Contribution       = pet.Contribution
+	ActivitySummary    = pet.ActivitySummary
+	ReviewDepth        = pet.ReviewDepth
+	Event              = pet.Event
+	EventRepo          = pet.EventRepo
+	PushPayload        = pet.PushPayload
+	PullRequestPayload = pet.PullRequestPayload
+	CreatePayload      = pet.CreatePayload
+	IssuesPayload      = pet.IssuesPayload
+	SponsorshipPayload = pet.SponsorshipPayload
+)
+
+// summarize counts what events did in the current week; see
+// pet.SummaryCutoff.
+func summarize(events []Event) ActivitySummary {
+	return pet.Summarize(events, pet.SummaryCutoff(time.Now()))
+}
+
+// discountCommits takes the commits that shouldn't earn anything out of a
+// summary of the current week; see pet.ScoringConfig.DiscountCommits.
+func discountCommits(c ScoringConfig, events []Event, summary ActivitySummary) ActivitySummary {
+	return c.DiscountCommits(events, summary, pet.SummaryCutoff(time.Now()))
+}
+
+// applyActivity folds a freshly synced summary into the pet's stats.
+func applyActivity(c ScoringConfig, state *store.PetState, summary ActivitySummary) {
+	stats := pet.Stats{Mood: state.Mood, Kindness: state.Kindness, Logic: state.Logic, Mentor: state.Mentor}
+	c.Apply(&stats, summary, min(len(state.Companions), store.BonusCompanions))
+	state.Mood, state.Kindness, state.Logic, state.Mentor = stats.Mood, stats.Kindness, stats.Logic, stats.Mentor
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// feedMu keeps concurrent POST /feed requests from interleaving writes to
+// the state file.
+var feedMu sync.Mutex
+
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	addr := fs.String("addr", "127.0.0.1:7878", "address to listen on")
+	token := fs.String("token", os.Getenv("GITPET_TOKEN"), "require this bearer token (default $GITPET_TOKEN)")
+	insecure := fs.Bool("insecure", false, "accept POST /feed without a token")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if *token == "" && !isLoopback(*addr) {
+		fmt.Fprintln(os.Stderr, "GitPet: serving beyond localhost without --token; anyone on the network can read your pet")
+	}
+	if *token == "" && *insecure {
+		fmt.Fprintln(os.Stderr, "GitPet: --insecure lets anything that can reach the server feed your pet")
+	}
+
+	fmt.Printf("🐾 GitPet listening on http://%s\n", *addr)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           withAuth(serveAuth{token: *token, origins: cfg.ServeOrigins, insecure: *insecure, loopback: isLoopback(*addr)}, serveMux()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+func isLoopback(addr string) bool {
+	return strings.HasPrefix(addr, "127.") || strings.HasPrefix(addr, "localhost:") || strings.HasPrefix(addr, "[::1]:")
+}
+
+// loopbackHost reports whether a request's Host names this machine.
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+func serveMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", handleDashboard)
+	mux.HandleFunc("GET /card", handleCard)
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		state, _ := store.Load()
+		writeJSON(w, state)
+	})
+	mux.HandleFunc("GET /prompt", func(w http.ResponseWriter, r *http.Request) {
+		state, _ := store.Load()
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, terminalRenderer{now: time.Now()}.Prompt(state, nil))
+	})
+	mux.HandleFunc("GET /history", func(w http.ResponseWriter, r *http.Request) {
+		history, err := store.LoadHistory()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, history)
+	})
+	mux.HandleFunc("GET /svg", func(w http.ResponseWriter, r *http.Request) {
+		state, _ := store.Load()
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "no-cache")
+		fmt.Fprint(w, statusSVG(state))
+	})
+	mux.HandleFunc("POST /feed", func(w http.ResponseWriter, r *http.Request) {
+		cfg, err := loadConfig()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		feedMu.Lock()
+		result, err := feedPet(r.Context(), cfg)
+		feedMu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, map[string]any{"state": result.State, "unlocked": result.Unlocked, "why": result.Why})
+	})
+	mux.HandleFunc("GET /why", func(w http.ResponseWriter, r *http.Request) {
+		explanations, err := store.LoadExplanations()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if explanations == nil {
+			explanations = []store.Explanation{}
+		}
+		writeJSON(w, explanations)
+	})
+	mux.HandleFunc("GET /metrics", handleMetrics)
+	mux.HandleFunc("GET /vscode", handleStatusBar)
+	mux.HandleFunc("GET /vscode/status", handleStatusBarItem)
+	mux.HandleFunc("GET /vscode/events", handleStatusBarEvents)
+	return mux
+}
+
+// serveAuth is who may use gh pet serve: callers with the token, when one
+// is set, and browser pages on the origins listed in serve_origins.
+type serveAuth struct {
+	token   string
+	origins []string
+	// insecure accepts POSTs without a token.
+	insecure bool
+	// loopback is set when listening on localhost only, so any other Host
+	// is a web page that rebound its own name to 127.0.0.1.
+	loopback bool
+}
+
+// allowsOrigin reports whether a browser page from origin may call the
+// server. The server's own pages, such as the dashboard, always may.
+func (a serveAuth) allowsOrigin(r *http.Request, origin string) bool {
+	if origin == "http://"+r.Host {
+		return true
+	}
+	for _, o := range a.origins {
+		if strings.EqualFold(strings.TrimSuffix(o, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// withAuth requires "Authorization: Bearer <token>" when a token is set. A
+// browser opening the dashboard can't send the header, so GETs may pass
+// ?token= instead. Only origins in serve_origins get CORS headers, so other
+// pages open in the browser can't read the pet, and a POST from another
+// origin, or without a token unless --insecure, is refused outright.
+func withAuth(auth serveAuth, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth.loopback && !loopbackHost(r.Host) {
+			http.Error(w, "unexpected host", http.StatusForbidden)
+			return
+		}
+		origin := r.Header.Get("Origin")
+		allowed := origin == "" || auth.allowsOrigin(r, origin)
+		if origin != "" {
+			w.Header().Add("Vary", "Origin")
+		}
+		if origin != "" && allowed {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization")
+		}
+		if r.Method == http.MethodOptions {
+			if !allowed {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if !allowed {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			if auth.token == "" && !auth.insecure {
+				http.Error(w, "POST needs gh pet serve --token, or --insecure", http.StatusForbidden)
+				return
+			}
+		}
+		if auth.token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok && r.Method == http.MethodGet && r.URL.Query().Has("token") {
+				got, ok = r.URL.Query().Get("token"), true
+			}
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(auth.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="gitpet"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// statusSVG is a small card for overlays and READMEs: name, evolution, and a
+// mood bar in the evolution's color.
+func statusSVG(state store.PetState) string {
+	evolution := state.Evolution
+	if evolution == "" {
+		evolution = "Lonely"
+	}
+	color, ok := evolutionHex[evolution]
+	if !ok {
+		color = evolutionHex["Void"]
+	}
+	mood := min(max(state.Mood, 0), 100)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="260" height="84" viewBox="0 0 260 84">
+  <rect x="1" y="1" width="258" height="82" rx="10" fill="#1e1e24" stroke="%[1]s" stroke-width="2"/>
+  <text x="14" y="30" font-family="sans-serif" font-size="16" font-weight="bold" fill="%[1]s">%[2]s %[3]s</text>
+  <text x="14" y="50" font-family="sans-serif" font-size="12" fill="#c8c8d0">%[4]s · %[5]s</text>
+  <rect x="14" y="60" width="200" height="10" rx="5" fill="#3a3a44"/>
+  <rect x="14" y="60" width="%[6]d" height="10" rx="5" fill="%[1]s"/>
+  <text x="222" y="70" font-family="sans-serif" font-size="11" fill="#c8c8d0">%[7]d</text>
+</svg>
+`, color, html.EscapeString(state.Signature()), html.EscapeString(state.DisplayName()), evolution, moodDescriptor(state.Mood), mood*2, mood)
+}
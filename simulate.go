@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// simCount is a flag of gh pet simulate that sets one count of the
+// synthetic week.
+type simCount struct {
+	flag, usage string
+	field       func(*ActivitySummary) *int
+}
+
+var simCounts = []simCount{
+	{"commits", "commits pushed", func(s *ActivitySummary) *int { return &s.Commits }},
+	{"merged-prs", "pull requests merged", func(s *ActivitySummary) *int { return &s.MergedPRs }},
+	{"reviews", "reviews given", func(s *ActivitySummary) *int { return &s.Reviews }},
+	{"doc-comments", "docs and comments written", func(s *ActivitySummary) *int { return &s.DocComments }},
+	{"fix-commits", "commits that fix bugs", func(s *ActivitySummary) *int { return &s.FixCommits }},
+	{"doc-commits", "commits that touch docs", func(s *ActivitySummary) *int { return &s.DocCommits }},
+	{"refactor-commits", "refactoring commits", func(s *ActivitySummary) *int { return &s.RefactorCommits }},
+	{"test-commits", "commits that touch tests", func(s *ActivitySummary) *int { return &s.TestCommits }},
+	{"new-repos", "repositories created", func(s *ActivitySummary) *int { return &s.NewRepos }},
+	{"issues-opened", "issues opened", func(s *ActivitySummary) *int { return &s.IssuesOpened }},
+	{"issues-closed", "issues closed", func(s *ActivitySummary) *int { return &s.IssuesClosed }},
+	{"issues-labeled", "issues labeled", func(s *ActivitySummary) *int { return &s.IssuesLabeled }},
+	{"issue-comments", "issue comments", func(s *ActivitySummary) *int { return &s.IssueComments }},
+	{"discussions", "discussions started", func(s *ActivitySummary) *int { return &s.Discussions }},
+	{"sponsorships", "sponsorships started", func(s *ActivitySummary) *int { return &s.Sponsorships }},
+	{"co-authored", "commits with Co-authored-by trailers", func(s *ActivitySummary) *int { return &s.DuetCommits }},
+	{"thoughts", "thought fragments (TODOs and FIXMEs)", func(s *ActivitySummary) *int { return &s.Thoughts }},
+}
+
+// runSimulate feeds the pet a made-up week and shows what would happen,
+// without the network and without saving anything.
+func runSimulate(args []string) error {
+	fs := newFlagSet("simulate")
+	file := fs.String("file", "", "read the week from JSON shaped like the state file's activity; - for stdin")
+	fresh := fs.Bool("fresh", false, "start from a newly hatched pet instead of yours")
+	asJSON := fs.Bool("json", false, "print the resulting state and breakdown as JSON")
+	values := map[string]*int{}
+	for _, c := range simCounts {
+		values[c.flag] = fs.Int(c.flag, 0, c.usage)
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	var summary ActivitySummary
+	if *file != "" {
+		var err error
+		if summary, err = readSummary(*file); err != nil {
+			return err
+		}
+	}
+	var bad error
+	fs.Visit(func(f *flag.Flag) {
+		for _, c := range simCounts {
+			if c.flag == f.Name {
+				if *values[f.Name] < 0 {
+					bad = usageErrorf("--%s can't be negative", f.Name)
+				}
+				*c.field(&summary) = *values[f.Name]
+			}
+		}
+	})
+	if bad != nil {
+		return bad
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	state := store.PetState{Mood: 5, Evolution: "Lonely"}
+	if !*fresh {
+		state, _ = store.Load()
+	}
+	before := state
+	state.Activity = summary
+	scoring := cfg.Scoring.WithAbility(state.Evolution, time.Now())
+	applyActivity(scoring, &state, summary)
+	state.Evolution = pet.EvolutionFor(summary)
+	unlocked := store.UnlockAchievements(&state)
+	why := store.ExplainFeed(scoring, before, state, summary, 0)
+	why.Source = "simulation"
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"state": state, "unlocked": unlocked, "why": why})
+	}
+	fmt.Printf("%s🧪 A simulated week — nothing here is saved.%s\n", colorDim, colorReset)
+	fmt.Println(terminalRenderer{theme: cfg.activeTheme()}.Status(state, pet.WellnessConcerns(summary, 0, cfg.Wellness)))
+	if store.Evolved(before, state) {
+		fmt.Printf("%s✨ %s would become a %s.%s\n", colorBold, state.DisplayName(), state.Evolution, colorReset)
+	}
+	for _, name := range unlocked {
+		fmt.Printf("%s🏆 Would unlock: %s%s\n", colorBold, name, colorReset)
+	}
+	fmt.Println()
+	fmt.Print(renderWhy(state, why, time.Now()))
+	return nil
+}
+
+// readSummary reads an ActivitySummary from a JSON file, or stdin for "-".
+func readSummary(name string) (ActivitySummary, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return ActivitySummary{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var summary ActivitySummary
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&summary); err != nil {
+		return ActivitySummary{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return summary, nil
+}
+
+func simulateFlags() []string {
+	flags := []string{"--file=", "--fresh", "--json"}
+	for _, c := range simCounts {
+		flags = append(flags, "--"+c.flag+"=")
+	}
+	return flags
+}
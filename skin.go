@@ -0,0 +1,239 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"gopkg.in/yaml.v3"
+)
+
+// Skin frames must fit inside the status box.
+const (
+	maxSkinWidth  = 28
+	maxSkinHeight = 12
+	skinsDirName  = "gh-pet-skins"
+)
+
+// Skin is a community art pack. Art is keyed by evolution name, with
+// "default" used for any evolution the pack doesn't draw. Skins are YAML,
+// which also makes plain JSON files valid.
+type Skin struct {
+	Name   string            `yaml:"name"`
+	Author string            `yaml:"author"`
+	Art    map[string]string `yaml:"art"`
+}
+
+var skinNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func (s Skin) validate() error {
+	if !skinNamePattern.MatchString(s.Name) {
+		return fmt.Errorf("skin name %q must be lowercase letters, digits, - or _", s.Name)
+	}
+	if len(s.Art) == 0 {
+		return errors.New("skin has no art frames")
+	}
+	for evolution, frame := range s.Art {
+		lines := strings.Split(strings.TrimRight(frame, "\n"), "\n")
+		if len(lines) > maxSkinHeight {
+			return fmt.Errorf("frame %q is %d lines tall (max %d)", evolution, len(lines), maxSkinHeight)
+		}
+		for i, line := range lines {
+			if w := utf8.RuneCountInString(line); w > maxSkinWidth {
+				return fmt.Errorf("frame %q line %d is %d columns wide (max %d)", evolution, i+1, w, maxSkinWidth)
+			}
+		}
+	}
+	return nil
+}
+
+func (s Skin) frameFor(evolution string) (string, bool) {
+	if frame, ok := s.Art[evolution]; ok {
+		return strings.TrimRight(frame, "\n"), true
+	}
+	if frame, ok := s.Art["default"]; ok {
+		return strings.TrimRight(frame, "\n"), true
+	}
+	return "", false
+}
+
+func runSkinList() error {
+	names, err := installedSkins()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("No skins installed. Try: gh pet skin install <path|url>")
+		return nil
+	}
+	cfg, _ := loadConfig()
+	for _, name := range names {
+		var uses []string
+		for evolution, skin := range cfg.Skins {
+			if skin == name {
+				uses = append(uses, evolution)
+			}
+		}
+		sort.Strings(uses)
+		marker := ""
+		if len(uses) > 0 {
+			marker = fmt.Sprintf(" %s(active: %s)%s", colorGreen, strings.Join(uses, ", "), colorReset)
+		}
+		fmt.Printf("  %s%s\n", name, marker)
+	}
+	return nil
+}
+
+func runSkinInstall(source string) error {
+	data, err := readSkinSource(source)
+	if err != nil {
+		return err
+	}
+	var skin Skin
+	if err := yaml.Unmarshal(data, &skin); err != nil {
+		return fmt.Errorf("unable to parse skin: %w", err)
+	}
+	if err := skin.validate(); err != nil {
+		return fmt.Errorf("invalid skin: %w", err)
+	}
+	dir, err := skinsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, skin.Name+".yaml"), data, 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Installed skin %s%s\n", colorGreen, skin.Name, colorReset)
+	fmt.Printf("  Activate it with: gh pet skin use %s\n", skin.Name)
+	return nil
+}
+
+func runSkinUse(name, evolution string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if name == "default" {
+		delete(cfg.Skins, evolution)
+	} else {
+		if _, err := loadSkin(name); err != nil {
+			return err
+		}
+		if cfg.Skins == nil {
+			cfg.Skins = map[string]string{}
+		}
+		cfg.Skins[evolution] = name
+	}
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+	target := "all evolutions"
+	if evolution != "*" {
+		target = evolution
+	}
+	fmt.Printf("%s✓ Using %s art for %s%s\n", colorGreen, name, target, colorReset)
+	return nil
+}
+
+func readSkinSource(source string) ([]byte, error) {
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		client := http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("download failed: %s", resp.Status)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	}
+	return os.ReadFile(source)
+}
+
+func installedSkins() ([]string, error) {
+	dir, err := skinsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".yaml"); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func loadSkin(name string) (Skin, error) {
+	dir, err := skinsDir()
+	if err != nil {
+		return Skin{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Skin{}, fmt.Errorf("skin %q is not installed", name)
+		}
+		return Skin{}, err
+	}
+	var skin Skin
+	if err := yaml.Unmarshal(data, &skin); err != nil {
+		return Skin{}, err
+	}
+	return skin, skin.validate()
+}
+
+// skinnedArt returns the active skin's frame for the evolution, falling back
+// to the built-in art when no skin applies or the skin fails to load.
+func skinnedArt(evolution string) string {
+	if frame, ok := skinFrame(evolution); ok {
+		return frame
+	}
+	return artFor(evolution)
+}
+
+// skinFrame is the active skin's frame for the evolution, if a skin applies
+// and loads.
+func skinFrame(evolution string) (string, bool) {
+	cfg, _ := loadConfig()
+	name, ok := cfg.Skins[evolution]
+	if !ok {
+		name, ok = cfg.Skins["*"]
+	}
+	if !ok {
+		return "", false
+	}
+	skin, err := loadSkin(name)
+	if err != nil {
+		return "", false
+	}
+	return skin.frameFor(evolution)
+}
+
+func skinsDir() (string, error) {
+	dir, err := dirs.Config()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, skinsDirName), nil
+}
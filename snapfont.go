@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"unicode/utf8"
+)
+
+// PNG snapshots are drawn cell by cell: ASCII from a 5×7 bitmap font
+// doubled in size, box-drawing and block characters as shapes, and emoji as
+// a rounded block in their color, since no font ships with the binary.
+const (
+	pxCellW = 12
+	pxCellH = 22
+	pxPad   = 20
+	pxScale = 2
+)
+
+// font5x7 holds printable ASCII from the space on, one byte per column with
+// the top row in the lowest bit.
+var font5x7 = [95][5]byte{
+	{0x00, 0x00, 0x00, 0x00, 0x00}, {0x00, 0x00, 0x5F, 0x00, 0x00}, {0x00, 0x07, 0x00, 0x07, 0x00}, {0x14, 0x7F, 0x14, 0x7F, 0x14},
+	{0x24, 0x2A, 0x7F, 0x2A, 0x12}, {0x23, 0x13, 0x08, 0x64, 0x62}, {0x36, 0x49, 0x56, 0x20, 0x50}, {0x00, 0x05, 0x03, 0x00, 0x00},
+	{0x00, 0x1C, 0x22, 0x41, 0x00}, {0x00, 0x41, 0x22, 0x1C, 0x00}, {0x2A, 0x1C, 0x7F, 0x1C, 0x2A}, {0x08, 0x08, 0x3E, 0x08, 0x08},
+	{0x00, 0x50, 0x30, 0x00, 0x00}, {0x08, 0x08, 0x08, 0x08, 0x08}, {0x00, 0x60, 0x60, 0x00, 0x00}, {0x20, 0x10, 0x08, 0x04, 0x02},
+	{0x3E, 0x51, 0x49, 0x45, 0x3E}, {0x00, 0x42, 0x7F, 0x40, 0x00}, {0x42, 0x61, 0x51, 0x49, 0x46}, {0x21, 0x41, 0x45, 0x4B, 0x31},
+	{0x18, 0x14, 0x12, 0x7F, 0x10}, {0x27, 0x45, 0x45, 0x45, 0x39}, {0x3C, 0x4A, 0x49, 0x49, 0x30}, {0x01, 0x71, 0x09, 0x05, 0x03},
+	{0x36, 0x49, 0x49, 0x49, 0x36}, {0x06, 0x49, 0x49, 0x29, 0x1E}, {0x00, 0x36, 0x36, 0x00, 0x00}, {0x00, 0x56, 0x36, 0x00, 0x00},
+	{0x08, 0x14, 0x22, 0x41, 0x00}, {0x14, 0x14, 0x14, 0x14, 0x14}, {0x00, 0x41, 0x22, 0x14, 0x08}, {0x02, 0x01, 0x51, 0x09, 0x06},
+	{0x32, 0x49, 0x79, 0x41, 0x3E}, {0x7E, 0x11, 0x11, 0x11, 0x7E}, {0x7F, 0x49, 0x49, 0x49, 0x36}, {0x3E, 0x41, 0x41, 0x41, 0x22},
+	{0x7F, 0x41, 0x41, 0x22, 0x1C}, {0x7F, 0x49, 0x49, 0x49, 0x41}, {0x7F, 0x09, 0x09, 0x09, 0x01}, {0x3E, 0x41, 0x49, 0x49, 0x7A},
+	{0x7F, 0x08, 0x08, 0x08, 0x7F}, {0x00, 0x41, 0x7F, 0x41, 0x00}, {0x20, 0x40, 0x41, 0x3F, 0x01}, {0x7F, 0x08, 0x14, 0x22, 0x41},
+	{0x7F, 0x40, 0x40, 0x40, 0x40}, {0x7F, 0x02, 0x0C, 0x02, 0x7F}, {0x7F, 0x04, 0x08, 0x10, 0x7F}, {0x3E, 0x41, 0x41, 0x41, 0x3E},
+	{0x7F, 0x09, 0x09, 0x09, 0x06}, {0x3E, 0x41, 0x51, 0x21, 0x5E}, {0x7F, 0x09, 0x19, 0x29, 0x46}, {0x46, 0x49, 0x49, 0x49, 0x31},
+	{0x01, 0x01, 0x7F, 0x01, 0x01}, {0x3F, 0x40, 0x40, 0x40, 0x3F}, {0x1F, 0x20, 0x40, 0x20, 0x1F}, {0x3F, 0x40, 0x38, 0x40, 0x3F},
+	{0x63, 0x14, 0x08, 0x14, 0x63}, {0x07, 0x08, 0x70, 0x08, 0x07}, {0x61, 0x51, 0x49, 0x45, 0x43}, {0x00, 0x7F, 0x41, 0x41, 0x00},
+	{0x02, 0x04, 0x08, 0x10, 0x20}, {0x00, 0x41, 0x41, 0x7F, 0x00}, {0x04, 0x02, 0x01, 0x02, 0x04}, {0x40, 0x40, 0x40, 0x40, 0x40},
+	{0x00, 0x01, 0x02, 0x04, 0x00}, {0x20, 0x54, 0x54, 0x54, 0x78}, {0x7F, 0x48, 0x44, 0x44, 0x38}, {0x38, 0x44, 0x44, 0x44, 0x20},
+	{0x38, 0x44, 0x44, 0x48, 0x7F}, {0x38, 0x54, 0x54, 0x54, 0x18}, {0x08, 0x7E, 0x09, 0x01, 0x02}, {0x0C, 0x52, 0x52, 0x52, 0x3E},
+	{0x7F, 0x08, 0x04, 0x04, 0x78}, {0x00, 0x44, 0x7D, 0x40, 0x00}, {0x20, 0x40, 0x44, 0x3D, 0x00}, {0x7F, 0x10, 0x28, 0x44, 0x00},
+	{0x00, 0x41, 0x7F, 0x40, 0x00}, {0x7C, 0x04, 0x18, 0x04, 0x78}, {0x7C, 0x08, 0x04, 0x04, 0x78}, {0x38, 0x44, 0x44, 0x44, 0x38},
+	{0x7C, 0x14, 0x14, 0x14, 0x08}, {0x08, 0x14, 0x14, 0x18, 0x7C}, {0x7C, 0x08, 0x04, 0x04, 0x08}, {0x48, 0x54, 0x54, 0x54, 0x20},
+	{0x04, 0x3F, 0x44, 0x40, 0x20}, {0x3C, 0x40, 0x40, 0x20, 0x7C}, {0x1C, 0x20, 0x40, 0x20, 0x1C}, {0x3C, 0x40, 0x30, 0x40, 0x3C},
+	{0x44, 0x28, 0x10, 0x28, 0x44}, {0x0C, 0x50, 0x50, 0x50, 0x3C}, {0x44, 0x64, 0x54, 0x4C, 0x44}, {0x00, 0x08, 0x36, 0x41, 0x00},
+	{0x00, 0x00, 0x7F, 0x00, 0x00}, {0x00, 0x41, 0x36, 0x08, 0x00}, {0x10, 0x08, 0x08, 0x10, 0x08},
+}
+
+// boxSegments says which way each box-drawing character reaches from the
+// middle of its cell, left, right, up, and down: 1 for a single line, 2 for
+// a double.
+var boxSegments = map[rune][4]int{
+	'─': {1, 1, 0, 0}, '│': {0, 0, 1, 1}, '╭': {0, 1, 0, 1}, '╮': {1, 0, 0, 1}, '╰': {0, 1, 1, 0}, '╯': {1, 0, 1, 0},
+	'┌': {0, 1, 0, 1}, '┐': {1, 0, 0, 1}, '└': {0, 1, 1, 0}, '┘': {1, 0, 1, 0}, '├': {0, 1, 1, 1}, '┤': {1, 0, 1, 1},
+	'┬': {1, 1, 0, 1}, '┴': {1, 1, 1, 0}, '┼': {1, 1, 1, 1},
+	'═': {2, 2, 0, 0}, '║': {0, 0, 2, 2}, '╔': {0, 2, 0, 2}, '╗': {2, 0, 0, 2}, '╚': {0, 2, 2, 0}, '╝': {2, 0, 2, 0},
+	'╠': {0, 2, 2, 2}, '╣': {2, 0, 2, 2}, '╦': {2, 2, 0, 2}, '╩': {2, 2, 2, 0}, '╬': {2, 2, 2, 2},
+	'╤': {2, 2, 0, 1}, '╧': {2, 2, 1, 0}, '╥': {1, 1, 0, 2}, '╨': {1, 1, 2, 0},
+}
+
+// blockShades are the fill characters mood bars are drawn with, as the
+// share of pixels each one lights.
+var blockShades = map[rune]int{'█': 4, '▓': 3, '▒': 2, '░': 1}
+
+// lookalikes stand in for the non-ASCII characters in faces and markers.
+var lookalikes = map[rune]rune{
+	'◕': 'o', '‿': '_', 'ᐛ': '>', 'ᕕ': '(', 'ᕗ': ')', '•': '*', '·': '.', '…': '.', '°': 'o', 'º': 'o',
+	'ᴗ': 'u', 'ᴥ': 'w', 'ω': 'w', 'ᵔ': '^', '˘': '^', 'ᵕ': 'u', '□': '#', '﹏': '~', '⊙': 'O',
+	'⊕': '+', '◉': 'o', '↑': '^', '↓': 'v', '⌂': '^', '⑂': 'Y', '⊘': '0', '⧉': '#', '✓': 'v', '×': 'x', '–': '-', '—': '-',
+}
+
+func snapshotPNG(rows [][]snapCell) ([]byte, error) {
+	cols := 0
+	for _, row := range rows {
+		w := 0
+		for _, c := range row {
+			w += c.width
+		}
+		cols = max(cols, w)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, cols*pxCellW+2*pxPad, len(rows)*pxCellH+2*pxPad))
+	fillRect(img, img.Bounds(), snapBackground)
+	for y, row := range rows {
+		col := 0
+		for _, c := range row {
+			drawCell(img, c, pxPad+col*pxCellW, pxPad+y*pxCellH)
+			col += c.width
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawCell(img *image.RGBA, c snapCell, x, y int) {
+	r, _ := utf8.DecodeRuneInString(c.text)
+	fg := c.style.color()
+	if segs, ok := boxSegments[r]; ok {
+		drawBox(img, segs, x, y, fg)
+		return
+	}
+	if shade, ok := blockShades[r]; ok {
+		for py := y; py < y+pxCellH; py++ {
+			for px := x; px < x+pxCellW; px++ {
+				if shade == 4 || (px/2+py/2)%4 < shade {
+					img.Set(px, py, rgba(fg))
+				}
+			}
+		}
+		return
+	}
+	if c.width == 2 {
+		// An emoji or wide character: a rounded block in its color.
+		fillRect(img, image.Rect(x+3, y+4, x+2*pxCellW-3, y+pxCellH-4), fg)
+		fillRect(img, image.Rect(x+2, y+6, x+2*pxCellW-2, y+pxCellH-6), fg)
+		return
+	}
+	if alt, ok := lookalikes[r]; ok {
+		r = alt
+	}
+	if r < ' ' || r > '~' {
+		fillRect(img, image.Rect(x+pxCellW/2-2, y+pxCellH/2-2, x+pxCellW/2+2, y+pxCellH/2+2), fg)
+		return
+	}
+	drawGlyph(img, r, x+1, y+4, fg)
+	if c.style.bold {
+		drawGlyph(img, r, x+2, y+4, fg)
+	}
+}
+
+func drawGlyph(img *image.RGBA, r rune, x, y int, fg rgb) {
+	glyph := font5x7[r-' ']
+	for col, bits := range glyph {
+		for row := 0; row < 7; row++ {
+			if bits&(1<<row) != 0 {
+				fillRect(img, image.Rect(x+col*pxScale, y+row*pxScale, x+(col+1)*pxScale, y+(row+1)*pxScale), fg)
+			}
+		}
+	}
+}
+
+// drawBox draws a box-drawing character's lines from the middle of its
+// cell; double lines run 3 pixels either side of the middle.
+func drawBox(img *image.RGBA, segs [4]int, x, y int, fg rgb) {
+	cx, cy := x+pxCellW/2, y+pxCellH/2
+	offsets := func(kind int) []int {
+		switch kind {
+		case 1:
+			return []int{0}
+		case 2:
+			return []int{-3, 3}
+		}
+		return nil
+	}
+	for _, d := range offsets(segs[0]) {
+		fillRect(img, image.Rect(x, cy+d-1, cx+1, cy+d+1), fg)
+	}
+	for _, d := range offsets(segs[1]) {
+		fillRect(img, image.Rect(cx-1, cy+d-1, x+pxCellW, cy+d+1), fg)
+	}
+	for _, d := range offsets(segs[2]) {
+		fillRect(img, image.Rect(cx+d-1, y, cx+d+1, cy+1), fg)
+	}
+	for _, d := range offsets(segs[3]) {
+		fillRect(img, image.Rect(cx+d-1, cy-1, cx+d+1, y+pxCellH), fg)
+	}
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c rgb) {
+	rect = rect.Intersect(img.Bounds())
+	fill := rgba(c)
+	for py := rect.Min.Y; py < rect.Max.Y; py++ {
+		for px := rect.Min.X; px < rect.Max.X; px++ {
+			img.SetRGBA(px, py, fill)
+		}
+	}
+}
+
+func rgba(c rgb) color.RGBA {
+	return color.RGBA{c.r, c.g, c.b, 0xff}
+}
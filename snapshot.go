@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// snapCell is one character of a rendered card, with the style it was
+// printed in.
+type snapCell struct {
+	text  string
+	width int
+	style snapStyle
+}
+
+type snapStyle struct {
+	fg        rgb
+	bold, dim bool
+}
+
+// Colors a snapshot is painted in: the same dark card as gh pet serve's SVG.
+var (
+	snapBackground = rgb{0x1e, 0x1e, 0x24}
+	snapForeground = rgb{0xc8, 0xc8, 0xd0}
+)
+
+// snapPalette is how the sixteen basic ANSI colors look on the card.
+var snapPalette = [16]rgb{
+	{0x3a, 0x3a, 0x44}, {0xe0, 0x52, 0x52}, {0x5c, 0xc6, 0x6a}, {0xf2, 0xc9, 0x4c},
+	{0x4a, 0x90, 0xe2}, {0xc8, 0x6d, 0xd7}, {0x2e, 0xc4, 0xb6}, {0xc8, 0xc8, 0xd0},
+	{0x8a, 0x8f, 0x98}, {0xff, 0x6e, 0x6e}, {0x7e, 0xe0, 0x8a}, {0xff, 0xdf, 0x6e},
+	{0x6e, 0xaa, 0xff}, {0xe0, 0x8e, 0xee}, {0x5e, 0xe0, 0xd2}, {0xff, 0xff, 0xff},
+}
+
+func runSnapshot(args []string) error {
+	fs := newFlagSet("snapshot")
+	format := fs.String("format", "", "text, svg, png, or inline (default: from --output's extension, else text)")
+	output := fs.String("output", "", "write the snapshot to a file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *format == "" {
+		*format = "text"
+		if ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(*output)), "."); ext == "svg" || ext == "png" {
+			*format = ext
+		}
+	}
+	switch *format {
+	case "text", "svg", "png":
+	case "inline":
+		if *output != "" {
+			return usageErrorf("--format inline shows the image in the terminal; use --format png to save it")
+		}
+	default:
+		return usageErrorf("unknown format %q (text, svg, png, or inline)", *format)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	state, _ := store.Load()
+	if state.Evolution == "" {
+		state.Evolution = "Lonely"
+	}
+	journal, _ := store.LoadJournal()
+	history, _ := store.LoadHistory()
+	share := shareText(state, journal, store.CurrentStreak(history, time.Now()), time.Now())
+	// Images are painted in rich color whatever this terminal can show.
+	card := strings.Trim(terminalRenderer{theme: cfg.themeAt(depth256)}.Status(state, nil), "\n")
+
+	var out []byte
+	switch *format {
+	case "text":
+		out = []byte(stripANSI(card) + "\n")
+	case "svg":
+		out = []byte(snapshotSVG(parseScreen(card)))
+	case "png", "inline":
+		if out, err = snapshotPNG(parseScreen(card)); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case *output != "":
+		if err := os.WriteFile(*output, out, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("%s✓ Saved the snapshot to %s%s\n", colorGreen, *output, colorReset)
+	case *format == "inline":
+		image, ok := inlineImage(out)
+		if !ok {
+			return fmt.Errorf("this terminal can't show inline images; kitty, iTerm2, WezTerm, and sixel terminals such as foot can. Try --output pet.png")
+		}
+		fmt.Println(image)
+	case *format == "text":
+		fmt.Print(string(out))
+	default:
+		// Image data goes to stdout for redirecting, so the share text
+		// mustn't end up inside it.
+		os.Stdout.Write(out)
+		fmt.Fprintf(os.Stderr, "\nShare: %s\n", share)
+		return nil
+	}
+	fmt.Printf("\n%sShare:%s %s\n", colorBold, colorReset, share)
+	return nil
+}
+
+// shareText is a ready-to-post line about the pet, leading with an
+// evolution from the past week when there was one.
+func shareText(state store.PetState, journal store.Journal, streak int, now time.Time) string {
+	pet := "My GitPet"
+	if name := state.DisplayName(); name != "GitPet" {
+		pet += " " + name
+	}
+	for i := len(journal.Entries) - 1; i >= 0; i-- {
+		e := journal.Entries[i]
+		if now.Sub(e.Time) > 7*24*time.Hour {
+			break
+		}
+		if e.Evolved != "" {
+			return fmt.Sprintf("%s evolved into a %s! %s #GitPet", pet, e.Evolved, state.Signature())
+		}
+	}
+	text := fmt.Sprintf("%s is a %s, feeling %s at %d/100 mood", pet, state.Evolution, moodDescriptor(state.Mood), state.Mood)
+	if streak > 1 {
+		text += fmt.Sprintf(" on a %d-day streak", streak)
+	}
+	return text + ". " + state.Signature() + " #GitPet"
+}
+
+// stripANSI drops the color codes from s.
+func stripANSI(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		token, _ := nextCell(s[i:])
+		i += len(token)
+		if escapeLen(token) == 0 {
+			sb.WriteString(token)
+		}
+	}
+	return sb.String()
+}
+
+// parseScreen splits rendered output into rows of styled cells, following
+// the SGR codes the renderers use: reset, bold, dim, and the basic, 256, and
+// true-color foregrounds.
+func parseScreen(s string) [][]snapCell {
+	var rows [][]snapCell
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		var row []snapCell
+		style := snapStyle{fg: snapForeground}
+		for i := 0; i < len(line); {
+			token, w := nextCell(line[i:])
+			i += len(token)
+			if n := escapeLen(token); n > 0 {
+				if strings.HasSuffix(token, "m") {
+					style = style.apply(token[2 : n-1])
+				}
+				continue
+			}
+			if w == 0 && len(row) > 0 {
+				// Joiners and variation selectors belong to the cell before.
+				row[len(row)-1].text += token
+				continue
+			}
+			row = append(row, snapCell{text: token, width: w, style: style})
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (st snapStyle) apply(params string) snapStyle {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, _ := strconv.Atoi(codes[i])
+		switch {
+		case code == 0:
+			st = snapStyle{fg: snapForeground}
+		case code == 1:
+			st.bold = true
+		case code == 2:
+			st.dim = true
+		case code == 22:
+			st.bold, st.dim = false, false
+		case code >= 30 && code <= 37:
+			st.fg = snapPalette[code-30]
+		case code >= 90 && code <= 97:
+			st.fg = snapPalette[code-90+8]
+		case code == 39:
+			st.fg = snapForeground
+		case code == 38 && i+2 < len(codes) && codes[i+1] == "5":
+			n, _ := strconv.Atoi(codes[i+2])
+			st.fg = xterm256(n)
+			i += 2
+		case code == 38 && i+4 < len(codes) && codes[i+1] == "2":
+			c := [3]uint8{}
+			for j := range c {
+				v, _ := strconv.Atoi(codes[i+2+j])
+				c[j] = uint8(v)
+			}
+			st.fg = rgb{c[0], c[1], c[2]}
+			i += 4
+		}
+	}
+	return st
+}
+
+// xterm256 is the color at index n of the 256-color palette.
+func xterm256(n int) rgb {
+	switch {
+	case n < 16:
+		return snapPalette[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return uint8(55 + v*40)
+		}
+		return rgb{level(n / 36), level(n / 6 % 6), level(n % 6)}
+	default:
+		v := uint8(8 + (n-232)*10)
+		return rgb{v, v, v}
+	}
+}
+
+// color is the style's foreground as drawn, faded when dim.
+func (st snapStyle) color() rgb {
+	if st.dim {
+		return st.fg.lerp(snapBackground, 0.45)
+	}
+	return st.fg
+}
+
+func (c rgb) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+}
+
+const (
+	snapFontSize = 14
+	snapCellW    = 8.4
+	snapCellH    = 18
+	snapPad      = 16
+)
+
+// snapshotSVG draws the card as positioned text. Every character gets its
+// own x, so wide emoji and fallback fonts can't throw the frame out of line.
+func snapshotSVG(rows [][]snapCell) string {
+	cols := 0
+	for _, row := range rows {
+		w := 0
+		for _, c := range row {
+			w += c.width
+		}
+		cols = max(cols, w)
+	}
+	width := float64(cols)*snapCellW + 2*snapPad
+	height := len(rows)*snapCellH + 2*snapPad
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%d" viewBox="0 0 %.0f %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&sb, `  <rect width="100%%" height="100%%" rx="10" fill="%s"/>`+"\n", snapBackground.hex())
+	fmt.Fprintf(&sb, `  <g font-family="ui-monospace, Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="%d" xml:space="preserve">`+"\n", snapFontSize)
+	for y, row := range rows {
+		baseline := snapPad + y*snapCellH + snapCellH - 5
+		col := 0
+		for i := 0; i < len(row); {
+			// A run is a stretch of non-blank cells in one style.
+			if strings.TrimSpace(row[i].text) == "" {
+				col += row[i].width
+				i++
+				continue
+			}
+			style := row[i].style
+			var xs []string
+			var text strings.Builder
+			for ; i < len(row) && row[i].style == style && strings.TrimSpace(row[i].text) != ""; i++ {
+				xs = append(xs, strconv.FormatFloat(snapPad+float64(col)*snapCellW, 'f', 1, 64))
+				text.WriteString(row[i].text)
+				col += row[i].width
+			}
+			weight := ""
+			if style.bold {
+				weight = ` font-weight="bold"`
+			}
+			fmt.Fprintf(&sb, `    <text x="%s" y="%d" fill="%s"%s>%s</text>`+"\n",
+				strings.Join(xs, " "), baseline, style.color().hex(), weight, html.EscapeString(text.String()))
+		}
+	}
+	sb.WriteString("  </g>\n</svg>\n")
+	return sb.String()
+}
+
+// inlineImage wraps a PNG in the escape sequence that shows it in this
+// terminal. It reports false for terminals that can't.
+func inlineImage(png []byte) (string, bool) {
+	protocol := graphicsProtocol()
+	if protocol == "" {
+		return "", false
+	}
+	image, err := encodeImage(png, protocol, 0, 0)
+	return image, err == nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/logging"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// SoundsConfig opts in to short sounds on milestones. Each event can be
+// switched off on its own once sounds are enabled.
+type SoundsConfig struct {
+	Enabled bool `json:"enabled"`
+	// Player is "bell" for terminal bell patterns, or "audio" for chimes
+	// played through the system's audio player, falling back to the bell.
+	Player      string `json:"player"`
+	MergedPR    bool   `json:"merged_pr"`
+	Evolution   bool   `json:"evolution"`
+	Achievement bool   `json:"achievement"`
+}
+
+func defaultSounds() SoundsConfig {
+	return SoundsConfig{Player: "bell", MergedPR: true, Evolution: true, Achievement: true}
+}
+
+func (s SoundsConfig) validate() error {
+	if s.Player != "bell" && s.Player != "audio" {
+		return fmt.Errorf("sounds.player must be bell or audio")
+	}
+	return nil
+}
+
+// note is one tone of a chime: a frequency in hertz held for a duration.
+type note struct {
+	freq float64
+	dur  time.Duration
+}
+
+// soundEffect is how one milestone sounds: a bell pattern, given as the
+// gaps between rings, and a chime for audio players.
+type soundEffect struct {
+	name  string
+	bells []time.Duration
+	chime []note
+}
+
+var (
+	fireworksSound = soundEffect{
+		name:  "fireworks",
+		bells: []time.Duration{0, 90 * time.Millisecond, 90 * time.Millisecond},
+		chime: []note{{523.25, 70 * time.Millisecond}, {659.25, 70 * time.Millisecond}, {783.99, 70 * time.Millisecond}, {1046.5, 220 * time.Millisecond}},
+	}
+	evolutionSound = soundEffect{
+		name:  "evolution",
+		bells: []time.Duration{0, 250 * time.Millisecond},
+		chime: []note{{392, 140 * time.Millisecond}, {523.25, 140 * time.Millisecond}, {659.25, 140 * time.Millisecond}, {783.99, 380 * time.Millisecond}},
+	}
+	achievementSound = soundEffect{
+		name:  "achievement",
+		bells: []time.Duration{0},
+		chime: []note{{659.25, 100 * time.Millisecond}, {987.77, 260 * time.Millisecond}},
+	}
+)
+
+// soundForChanges picks the one sound a save deserves, so a feed that
+// evolves the pet and unlocks a badge doesn't play over itself.
+func soundForChanges(cfg SoundsConfig, before, after store.PetState, unlocked []string) (soundEffect, bool) {
+	if !cfg.Enabled {
+		return soundEffect{}, false
+	}
+	switch {
+	case cfg.Evolution && store.Evolved(before, after):
+		return evolutionSound, true
+	case cfg.Achievement && len(unlocked) > 0:
+		return achievementSound, true
+	case cfg.MergedPR && after.Activity.MergedPRs > before.Activity.MergedPRs:
+		return fireworksSound, true
+	}
+	return soundEffect{}, false
+}
+
+// playChanges sounds off for what changed between two saves of the pet.
+// Like notify, it never fails the calling command.
+func playChanges(cfg SoundsConfig, before, after store.PetState, unlocked []string) {
+	effect, ok := soundForChanges(cfg, before, after, unlocked)
+	if !ok {
+		return
+	}
+	if cfg.Player == "audio" {
+		if cmd := audioPlayer(effect); cmd != nil && cmd.Start() == nil {
+			return
+		}
+	}
+	for _, gap := range effect.bells {
+		time.Sleep(gap)
+		fmt.Fprint(os.Stderr, "\a")
+	}
+}
+
+// audioPlayer returns a command playing effect's chime, or nil when this
+// system has no player we know.
+func audioPlayer(effect soundEffect) *exec.Cmd {
+	path, err := chimeFile(effect)
+	if err != nil {
+		logging.Logger.Debug("no chime file", "sound", effect.name, "err", err)
+		return nil
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("afplay", path)
+	case "windows":
+		script := fmt.Sprintf("(New-Object Media.SoundPlayer '%s').PlaySync()", strings.ReplaceAll(path, "'", "''"))
+		return exec.Command("powershell.exe", "-NoProfile", "-Command", script)
+	default:
+		for _, player := range [][]string{{"paplay"}, {"pw-play"}, {"aplay", "-q"}} {
+			if _, err := exec.LookPath(player[0]); err == nil {
+				return exec.Command(player[0], append(player[1:], path)...)
+			}
+		}
+		return nil
+	}
+}
+
+// chimeFile writes effect's chime as a WAV file in the user cache the first
+// time it's needed, and returns its path.
+func chimeFile(effect soundEffect) (string, error) {
+	dir, err := dirs.Cache()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "sounds", effect.name+".wav")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, chimeWAV(effect.chime), 0o600)
+}
+
+const chimeSampleRate = 22050
+
+// chimeWAV renders notes as 8-bit mono PCM, each note fading out so the
+// chime sounds like soft bells rather than beeps.
+func chimeWAV(notes []note) []byte {
+	var samples []byte
+	for _, n := range notes {
+		count := int(n.dur.Seconds() * chimeSampleRate)
+		for i := 0; i < count; i++ {
+			t := float64(i) / chimeSampleRate
+			envelope := math.Exp(-4 * float64(i) / float64(count))
+			value := math.Sin(2*math.Pi*n.freq*t) * envelope * 0.5
+			samples = append(samples, byte(128+value*127))
+		}
+	}
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(samples)))
+	buf.WriteString("WAVEfmt ")
+	for _, field := range []any{
+		uint32(16), uint16(1), uint16(1), // PCM, mono
+		uint32(chimeSampleRate), uint32(chimeSampleRate), // byte rate at 1 byte per sample
+		uint16(1), uint16(8), // block align, bits per sample
+	} {
+		binary.Write(&buf, binary.LittleEndian, field)
+	}
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(samples)))
+	buf.Write(samples)
+	return buf.Bytes()
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// maxStandupCommits is how many commit messages a repo's line quotes; the
+// rest are counted.
+const maxStandupCommits = 3
+
+// standup is a "yesterday / today / blockers" draft.
+type standup struct {
+	Yesterday []string
+	Today     []string
+	Blockers  []string
+	// Cheer is the pet's closing line, empty in professional mode.
+	Cheer string
+}
+
+// standupPayload is the part of an event's payload a standup quotes.
+type standupPayload struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Merged bool   `json:"merged"`
+	} `json:"pull_request"`
+	Issue struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	} `json:"issue"`
+}
+
+// runStandup drafts a standup from the last day of GitHub events and local
+// branches, ready to paste.
+func runStandup(args []string) error {
+	fs := newFlagSet("standup")
+	hours := fs.Int("hours", 24, "how far back yesterday goes, e.g. 48 after a day off")
+	format := fs.String("format", "text", "output format: text or slack")
+	offline := fs.Bool("offline", false, "skip GitHub and use only local branches")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *hours < 1 || *hours > 168 {
+		return fmt.Errorf("--hours must be between 1 and 168")
+	}
+	if *format != "text" && *format != "slack" {
+		return fmt.Errorf("unknown format %q (text, slack)", *format)
+	}
+	ctx := context.Background()
+	now := time.Now()
+	since := now.Add(-time.Duration(*hours) * time.Hour)
+	state, _ := store.Load()
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	var events []Event
+	var desk *morningDesk
+	if !*offline {
+		stop := startSpinner("Gathering your standup…")
+		login, err := ghLogin(ctx)
+		if err == nil {
+			events, err = ghEvents(ctx, login)
+		}
+		if err == nil {
+			desk, err = fetchMorningDesk(ctx)
+		}
+		stop()
+		if err != nil {
+			return fmt.Errorf("%w (try --offline for local branches only)", err)
+		}
+		events = cfg.repoFilter().Events(events)
+	}
+
+	s := draftStandup(ctx, state, events, desk, since)
+	fmt.Print(s.render(*format))
+	return nil
+}
+
+// draftStandup gathers what happened since since, what's next, and what's
+// in the way.
+func draftStandup(ctx context.Context, state store.PetState, events []Event, desk *morningDesk, since time.Time) standup {
+	var s standup
+	s.Yesterday = standupDone(events, localCommits(ctx, since), since)
+
+	s.Today = localBranchPlans(ctx, since)
+	if desk != nil {
+		for i, item := range desk.Reviews.Nodes {
+			if i == morningListed {
+				break
+			}
+			s.Today = append(s.Today, "Review "+standupRef(item.Number, item.Title, item.Repository.NameWithOwner))
+		}
+		if more := desk.Reviews.IssueCount - min(len(desk.Reviews.Nodes), morningListed); more > 0 {
+			s.Today = append(s.Today, fmt.Sprintf("Review %s more", i18n.Plural(more, "pull request")))
+		}
+		if len(desk.Issues.Nodes) > 0 {
+			item := desk.Issues.Nodes[0]
+			s.Today = append(s.Today, "Work on "+standupRef(item.Number, item.Title, item.Repository.NameWithOwner))
+		}
+	}
+
+	for _, w := range state.CI {
+		for _, branch := range w.Red {
+			s.Blockers = append(s.Blockers, fmt.Sprintf("CI is failing on %s in %s", branch, w.Repo))
+		}
+	}
+	if !professional() {
+		s.Cheer = state.Signature() + " " + state.DisplayName() + ": " + standupCheer(s)
+	}
+	return s
+}
+
+// standupDone turns events since since into standup lines: commits grouped
+// by repo, then pull requests, reviews, and issues. local are commits made
+// in this repository; those GitHub hasn't seen yet, such as unpushed work,
+// get a line of their own.
+func standupDone(events []Event, local []string, since time.Time) []string {
+	var repos []string
+	commits := map[string][]string{}
+	var lines []string
+	seen := map[string]bool{}
+	add := func(line string) {
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	comments := 0
+	// Events come newest first; walk them oldest first so the draft reads
+	// in order.
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if event.CreatedAt.Before(since) {
+			continue
+		}
+		repo := event.Repo.Name
+		switch event.Type {
+		case "PushEvent":
+			var push PushPayload
+			if json.Unmarshal(event.Payload, &push) != nil {
+				continue
+			}
+			for _, c := range push.Commits {
+				msg, _, _ := strings.Cut(c.Message, "\n")
+				if msg == "" || strings.HasPrefix(msg, "Merge ") || containsString(commits[repo], msg) {
+					continue
+				}
+				if len(commits[repo]) == 0 {
+					repos = append(repos, repo)
+				}
+				commits[repo] = append(commits[repo], msg)
+			}
+			continue
+		case "IssueCommentEvent", "PullRequestReviewCommentEvent":
+			comments++
+			continue
+		}
+		var p standupPayload
+		if json.Unmarshal(event.Payload, &p) != nil {
+			continue
+		}
+		switch event.Type {
+		case "PullRequestEvent":
+			switch {
+			case p.Action == "opened":
+				add("Opened " + standupRef(p.PullRequest.Number, p.PullRequest.Title, repo))
+			case p.Action == "closed" && p.PullRequest.Merged:
+				add("Merged " + standupRef(p.PullRequest.Number, p.PullRequest.Title, repo))
+			}
+		case "PullRequestReviewEvent":
+			add("Reviewed " + standupRef(p.PullRequest.Number, p.PullRequest.Title, repo))
+		case "IssuesEvent":
+			switch p.Action {
+			case "opened":
+				add("Opened issue " + standupRef(p.Issue.Number, p.Issue.Title, repo))
+			case "closed":
+				add("Closed issue " + standupRef(p.Issue.Number, p.Issue.Title, repo))
+			}
+		}
+	}
+
+	pushed := map[string]bool{}
+	for _, repo := range repos {
+		for _, msg := range commits[repo] {
+			pushed[msg] = true
+		}
+	}
+	const unpushed = "Committed locally"
+	for _, msg := range local {
+		if !pushed[msg] {
+			if len(commits[unpushed]) == 0 {
+				repos = append(repos, unpushed)
+			}
+			commits[unpushed] = append(commits[unpushed], msg)
+		}
+	}
+
+	var out []string
+	for _, repo := range repos {
+		msgs := commits[repo]
+		line := fmt.Sprintf("%s: %s", repo, strings.Join(msgs[:min(len(msgs), maxStandupCommits)], "; "))
+		if more := len(msgs) - maxStandupCommits; more > 0 {
+			line += fmt.Sprintf(" and %s more", i18n.Plural(more, "commit"))
+		}
+		out = append(out, line)
+	}
+	out = append(out, lines...)
+	if comments > 0 {
+		out = append(out, "Left "+i18n.Plural(comments, "comment")+" on issues and pull requests")
+	}
+	return out
+}
+
+// standupRef names a pull request or issue, such as "#12 Fix login
+// (octo/app)". Some event payloads leave the title out.
+func standupRef(number int, title, repo string) string {
+	if title == "" {
+		return fmt.Sprintf("#%d (%s)", number, repo)
+	}
+	return fmt.Sprintf("#%d %s (%s)", number, title, repo)
+}
+
+// localCommits are the subjects of your commits in this repository since
+// since, on any branch, oldest first. Outside a repository there are none.
+func localCommits(ctx context.Context, since time.Time) []string {
+	email, err := gh.GitOutput(ctx, "config", "user.email")
+	if err != nil || strings.TrimSpace(string(email)) == "" {
+		return nil
+	}
+	out, err := gh.GitOutput(ctx, "log", "--branches", "--no-merges", "--reverse", "--since=@"+strconv.FormatInt(since.Unix(), 10),
+		"--author="+strings.TrimSpace(string(email)), "--format=%s")
+	if err != nil {
+		return nil
+	}
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" && !containsString(msgs, line) {
+			msgs = append(msgs, line)
+		}
+	}
+	return msgs
+}
+
+// localBranchPlans are what's next in this repository: uncommitted work,
+// branches worked on since since, and commits that still need pushing.
+func localBranchPlans(ctx context.Context, since time.Time) []string {
+	if !gh.InWorkTree(ctx) {
+		return nil
+	}
+	var plans []string
+	current, _ := gh.GitOutput(ctx, "branch", "--show-current")
+	branch := strings.TrimSpace(string(current))
+	status, _ := gh.GitOutput(ctx, "status", "--porcelain")
+	dirty := len(strings.TrimSpace(string(status))) > 0 && branch != ""
+	if dirty {
+		files := len(strings.Split(strings.TrimSpace(string(status)), "\n"))
+		plans = append(plans, fmt.Sprintf("Finish the %s in progress on %s", i18n.Plural(files, "changed file"), branch))
+	}
+	refs, err := gh.GitOutput(ctx, "for-each-ref", "--sort=-committerdate", "--format=%(refname:short)%00%(committerdate:unix)%00%(upstream:track,nobracket)", "refs/heads")
+	if err != nil {
+		return plans
+	}
+	for _, row := range strings.Split(strings.TrimSpace(string(refs)), "\n") {
+		fields := strings.Split(row, "\x00")
+		if len(fields) < 3 {
+			continue
+		}
+		name, track := fields[0], fields[2]
+		unix, _ := strconv.ParseInt(fields[1], 10, 64)
+		if time.Unix(unix, 0).Before(since) {
+			break
+		}
+		if name == "main" || name == "master" || dirty && name == branch {
+			continue
+		}
+		line := "Continue " + name
+		if ahead, ok := strings.CutPrefix(track, "ahead "); ok {
+			n, _, _ := strings.Cut(ahead, ",")
+			line += fmt.Sprintf(" (%s to push)", n)
+		}
+		plans = append(plans, line)
+	}
+	return plans
+}
+
+// standupCheer is the pet's one line of encouragement to close a standup.
+func standupCheer(s standup) string {
+	switch {
+	case len(s.Blockers) > 0:
+		return "Blockers are just puzzles that haven't met you yet. You've got this!"
+	case len(s.Yesterday) == 0:
+		return "Quiet days count too. One small step today is plenty."
+	case len(s.Yesterday) >= 5:
+		return "What a run! Remember to take a breather between all that."
+	default:
+		return "Nice work yesterday. Let's make today a good one!"
+	}
+}
+
+// render lays the standup out as plain text or Slack markdown.
+func (s standup) render(format string) string {
+	heading, bullet := "%s\n", "- "
+	if format == "slack" {
+		heading, bullet = "*%s*\n", "• "
+	}
+	var sb strings.Builder
+	section := func(title string, lines []string, empty string) {
+		fmt.Fprintf(&sb, heading, title)
+		if len(lines) == 0 {
+			lines = []string{empty}
+		}
+		for _, line := range lines {
+			if professional() {
+				line = neutralize(line)
+			}
+			sb.WriteString(bullet + line + "\n")
+		}
+	}
+	section("Yesterday", s.Yesterday, "Nothing on record")
+	sb.WriteString("\n")
+	section("Today", s.Today, "Pick up where I left off")
+	sb.WriteString("\n")
+	section("Blockers", s.Blockers, "None")
+	if s.Cheer != "" {
+		sb.WriteString("\n" + s.Cheer + "\n")
+	}
+	return sb.String()
+}
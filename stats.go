@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+type rollup struct {
+	Label       string
+	Start       time.Time
+	Commits     int
+	MergedPRs   int
+	Reviews     int
+	DocComments int
+	Issues      int
+	// Pomodoros and FocusMinutes aren't activity, so total leaves them out.
+	Pomodoros    int
+	FocusMinutes int
+}
+
+func (r rollup) total() int {
+	return r.Commits + r.MergedPRs + r.Reviews + r.DocComments + r.Issues
+}
+
+// addMonth folds in a month whose days were compacted.
+func (r *rollup) addMonth(m store.MonthRecord) {
+	r.Commits += m.Commits
+	r.MergedPRs += m.MergedPRs
+	r.Reviews += m.Reviews
+	r.DocComments += m.DocComments
+	r.Issues += m.Issues
+	r.Pomodoros += m.Pomodoros
+	r.FocusMinutes += m.FocusMinutes
+}
+
+func (r *rollup) add(d store.DayRecord) {
+	r.Commits += d.Commits
+	r.MergedPRs += d.MergedPRs
+	r.Reviews += d.Reviews
+	r.DocComments += d.DocComments
+	r.Issues += d.Issues
+	r.Pomodoros += d.Pomodoros
+	r.FocusMinutes += d.FocusMinutes
+}
+
+func runStats(args []string) error {
+	fs := newFlagSet("stats")
+	weeks := fs.Int("weeks", 4, "number of weeks to roll up")
+	months := fs.Int("months", 3, "number of months to roll up")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *weeks < 1 || *months < 1 {
+		return fmt.Errorf("--weeks and --months must be at least 1")
+	}
+
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	if len(history.Days) == 0 && len(history.Months) == 0 {
+		fmt.Println("No history yet. Run `gh pet feed` to start recording.")
+		return nil
+	}
+	state, _ := store.Load()
+	fmt.Println(renderStats(history, loadTheme(), state.Evolution, *weeks, *months, time.Now()))
+	return nil
+}
+
+func renderStats(history store.History, theme Theme, evolution string, weeks, months int, now time.Time) string {
+	color := theme.accent(evolution)
+	weekly := weeklyRollups(history, weeks, now)
+	monthly := monthlyRollups(history, months, now)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n%s%s📊 GitPet Stats%s\n\n", theme.Bold, color, theme.Reset))
+
+	sb.WriteString(fmt.Sprintf("%sWeekly%s\n", theme.Bold, theme.Reset))
+	writeRollupChart(&sb, weekly, color, theme)
+
+	sb.WriteString(fmt.Sprintf("\n%sMonthly%s\n", theme.Bold, theme.Reset))
+	writeRollupChart(&sb, monthly, color, theme)
+
+	sb.WriteString(fmt.Sprintf("\n%sTrends (this week vs last)%s\n", theme.Bold, theme.Reset))
+	if len(weekly) >= 2 {
+		cur, prev := weekly[len(weekly)-1], weekly[len(weekly)-2]
+		sb.WriteString(fmt.Sprintf("  Commits    %s\n", trend(cur.Commits, prev.Commits, theme)))
+		sb.WriteString(fmt.Sprintf("  Merged PRs %s\n", trend(cur.MergedPRs, prev.MergedPRs, theme)))
+		sb.WriteString(fmt.Sprintf("  Reviews    %s\n", trend(cur.Reviews, prev.Reviews, theme)))
+	} else {
+		sb.WriteString("  Not enough weeks recorded yet.\n")
+	}
+
+	var all rollup
+	for _, m := range history.Months {
+		all.addMonth(m)
+	}
+	for _, d := range history.Days {
+		all.add(d)
+	}
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("  Busiest day  : %s\n", busiestWeekday(history)))
+	if all.Commits > 0 {
+		sb.WriteString(fmt.Sprintf("  Review ratio : %.2f reviews per commit\n", float64(all.Reviews)/float64(all.Commits)))
+	} else {
+		sb.WriteString("  Review ratio : n/a (no commits recorded)\n")
+	}
+	if all.Pomodoros > 0 {
+		var week rollup
+		for _, d := range history.Between(pet.WeekStart(now), now.AddDate(0, 0, 1)) {
+			week.add(d)
+		}
+		sb.WriteString(fmt.Sprintf("  Focus        : %s (%d min) this week, %s in all\n",
+			i18n.Plural(week.Pomodoros, "pomodoro"), week.FocusMinutes, i18n.Plural(all.Pomodoros, "pomodoro")))
+	}
+	return sb.String()
+}
+
+func writeRollupChart(sb *strings.Builder, rollups []rollup, color string, theme Theme) {
+	peak := 0
+	for _, r := range rollups {
+		peak = max(peak, r.total())
+	}
+	for _, r := range rollups {
+		filled := 0
+		if peak > 0 {
+			filled = r.total() * 20 / peak
+		}
+		bar := color + strings.Repeat("█", filled) + theme.Dim + strings.Repeat("░", 20-filled) + theme.Reset
+		sb.WriteString(fmt.Sprintf("  %-8s %s %3d  %dc %dp %dr %dd %di\n", r.Label, bar, r.total(), r.Commits, r.MergedPRs, r.Reviews, r.DocComments, r.Issues))
+	}
+}
+
+func weeklyRollups(history store.History, weeks int, now time.Time) []rollup {
+	start := pet.WeekStart(now).AddDate(0, 0, -7*(weeks-1))
+	out := make([]rollup, 0, weeks)
+	for i := 0; i < weeks; i++ {
+		from := start.AddDate(0, 0, 7*i)
+		r := rollup{Label: from.Format("Jan 02"), Start: from}
+		for _, d := range history.Between(from, from.AddDate(0, 0, 7)) {
+			r.add(d)
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func monthlyRollups(history store.History, months int, now time.Time) []rollup {
+	y, m, _ := now.Date()
+	start := time.Date(y, m, 1, 0, 0, 0, 0, now.Location()).AddDate(0, -(months - 1), 0)
+	out := make([]rollup, 0, months)
+	for i := 0; i < months; i++ {
+		from := start.AddDate(0, i, 0)
+		r := rollup{Label: from.Format("Jan 2006"), Start: from}
+		for _, m := range history.Months {
+			if m.Month == from.Format(store.MonthLayout) {
+				r.addMonth(m)
+			}
+		}
+		for _, d := range history.Between(from, from.AddDate(0, 1, 0)) {
+			r.add(d)
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func trend(cur, prev int, theme Theme) string {
+	switch {
+	case prev == 0 && cur == 0:
+		return "→ flat (0)"
+	case prev == 0:
+		return fmt.Sprintf("%s↑ new%s (%d)", theme.Good, theme.Reset, cur)
+	}
+	pct := (cur - prev) * 100 / prev
+	switch {
+	case pct > 0:
+		return fmt.Sprintf("%s↑ %d%%%s (%d vs %d)", theme.Good, pct, theme.Reset, cur, prev)
+	case pct < 0:
+		return fmt.Sprintf("%s↓ %d%%%s (%d vs %d)", theme.Bad, -pct, theme.Reset, cur, prev)
+	default:
+		return fmt.Sprintf("→ flat (%d)", cur)
+	}
+}
+
+func busiestWeekday(history store.History) string {
+	var totals [7]int
+	for _, d := range history.Days {
+		totals[d.Day().Weekday()] += d.Total()
+	}
+	best := -1
+	for i, total := range totals {
+		if total > 0 && (best < 0 || total > totals[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%s (%d events)", time.Weekday(best), totals[best])
+}
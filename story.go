@@ -0,0 +1,330 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// storyChapter is one week of the pet's saga, told from history and the
+// milestones recorded in the journal.
+type storyChapter struct {
+	Number     int
+	From, To   time.Time
+	Title      string
+	Paragraphs []string
+}
+
+func runStory(args []string) error {
+	fs := newFlagSet("story")
+	week := fs.Int("week", 0, "tell chapter N instead of this week's")
+	all := fs.Bool("all", false, "tell every chapter so far")
+	export := fs.String("export", "", "write the story to a Markdown file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *all && *week != 0 {
+		return usageErrorf("--week and --all can't be used together")
+	}
+
+	history, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	journal, err := store.LoadJournal()
+	if err != nil {
+		return err
+	}
+	first, ok := storyStart(history, journal)
+	if !ok {
+		fmt.Println("The story hasn't begun yet. Feed your pet to write chapter one.")
+		return nil
+	}
+	state, _ := store.Load()
+	current := chapterNumber(first, time.Now())
+
+	numbers := []int{current}
+	switch {
+	case *all:
+		numbers = numbers[:0]
+		for n := 1; n <= current; n++ {
+			numbers = append(numbers, n)
+		}
+	case *week != 0:
+		if *week < 1 || *week > current {
+			return fmt.Errorf("there is no chapter %d yet (1–%d)", *week, current)
+		}
+		numbers = []int{*week}
+	}
+	var chapters []storyChapter
+	for _, n := range numbers {
+		chapters = append(chapters, tellChapter(state, history, journal, first, n, n == current))
+	}
+
+	if *export != "" {
+		if err := os.WriteFile(*export, []byte(storyMarkdown(state, chapters)), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("%s✓ Exported %s to %s%s\n", colorGreen, i18n.Plural(len(chapters), "chapter"), *export, colorReset)
+		return nil
+	}
+	width := terminalWidth()
+	if width <= 0 || width > 80 {
+		width = 80
+	}
+	for _, c := range chapters {
+		fmt.Printf("\n%s📖 %s%s\n%s%s%s\n", colorBold, c.Title, colorReset, colorDim, c.dates(), colorReset)
+		for _, p := range c.Paragraphs {
+			fmt.Println()
+			for _, line := range wrapWidth(p, width) {
+				fmt.Println(line)
+			}
+		}
+	}
+	return nil
+}
+
+// storyStart is the first day anything was recorded about the pet.
+func storyStart(history store.History, journal store.Journal) (time.Time, bool) {
+	var first time.Time
+	if len(history.Months) > 0 {
+		first, _ = time.ParseInLocation(store.MonthLayout, history.Months[0].Month, time.Local)
+	} else if len(history.Days) > 0 {
+		first = history.Days[0].Day()
+	}
+	if t := journal.Began(); !t.IsZero() {
+		if t = t.Local(); first.IsZero() || t.Before(first) {
+			first = t
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// chapterNumber counts weeks from the one the story started in, from 1.
+func chapterNumber(first, t time.Time) int {
+	days := math.Round(pet.WeekStart(t).Sub(pet.WeekStart(first)).Hours() / 24)
+	return int(days)/7 + 1
+}
+
+// deed is one kind of work the pet's week is told through, with the noun
+// it's counted in and ways of saying it was done.
+type deed struct {
+	count int
+	noun  string
+	verbs []string
+	title string
+}
+
+func weekDeeds(week rollup, tests int) []deed {
+	deeds := []deed{
+		{week.Issues, "bug", []string{"repelled %s", "drove %s from the gates", "tracked down %s in the dark"}, "The Bug Hunt"},
+		{week.Commits, "stone", []string{"laid %s in the Cache's walls", "set %s into the foundations"}, "Stone upon Stone"},
+		{week.MergedPRs, "bridge", []string{"raised %s over the river Main", "finished %s to far-off branches"}, "The Bridge Builders"},
+		{week.Reviews, "scroll", []string{"read %s by lantern light", "weighed %s from travelling hands"}, "The Lantern Readings"},
+		{week.DocComments, "rune", []string{"carved %s into the old stones", "inscribed %s for those who come after"}, "Runes on the Walls"},
+		{tests, "ward", []string{"set %s around the Cache", "wove %s against the coming storms"}, "The Warding"},
+	}
+	var done []deed
+	for _, d := range deeds {
+		if d.count > 0 {
+			done = append(done, d)
+		}
+	}
+	sort.SliceStable(done, func(i, j int) bool { return done[i].count > done[j].count })
+	return done
+}
+
+// tellChapter writes chapter n. The same week always reads the same, since
+// its choices are seeded by the pet's name and the week.
+func tellChapter(state store.PetState, history store.History, journal store.Journal, first time.Time, n int, ongoing bool) storyChapter {
+	from := pet.WeekStart(first).AddDate(0, 0, 7*(n-1))
+	to := from.AddDate(0, 0, 7)
+	c := storyChapter{Number: n, From: from, To: to.AddDate(0, 0, -1)}
+
+	h := fnv.New64a()
+	h.Write([]byte(state.DisplayName() + from.Format(store.DayLayout)))
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+	pick := func(options ...string) string { return options[rng.Intn(len(options))] }
+
+	var week rollup
+	tests := 0
+	days := history.Between(from, to)
+	for _, d := range days {
+		week.add(d)
+		tests += d.TestCommits
+	}
+	// Until its first recorded evolution, the pet hadn't grown into any form
+	// yet; with none recorded, its current form is all we know.
+	form := state.Evolution
+	for _, e := range journal.Entries {
+		if e.Evolved != "" {
+			form = ""
+			break
+		}
+	}
+	var evolutions []store.JournalEntry
+	var unlocked []string
+	for _, e := range journal.Entries {
+		if !e.Time.Before(to) {
+			break
+		}
+		if e.Evolved != "" {
+			form = e.Evolved
+		}
+		if !e.Time.Before(from) {
+			if e.Evolved != "" {
+				evolutions = append(evolutions, e)
+			}
+			unlocked = append(unlocked, e.Unlocked...)
+		}
+	}
+	hero := "the " + form
+	switch form {
+	case "":
+		hero = "the young one"
+	case "Lonely":
+		hero = "the Lonely one"
+	}
+	Hero := strings.ToUpper(hero[:1]) + hero[1:]
+	named := state.DisplayName() + " " + hero
+
+	deeds := weekDeeds(week, tests)
+	var opening []string
+	if len(deeds) == 0 {
+		c.Title = "The Long Sleep"
+		opening = append(opening,
+			fmt.Sprintf(pick("The Cache's %s week passed in silence.", "No footsteps echoed through the Cache in its %s week."), ordinal(n)),
+			fmt.Sprintf(pick("%s slept beside the cold forge, dreaming of commits to come.", "%s kept watch alone, listening for the Keeper's return."), named))
+	} else {
+		c.Title = deeds[0].title
+		var told []string
+		for _, d := range deeds[:min(len(deeds), 2)] {
+			told = append(told, fmt.Sprintf(d.verbs[rng.Intn(len(d.verbs))], countWords(d.count, d.noun)))
+		}
+		opening = append(opening, fmt.Sprintf("In the Cache's %s week, %s %s.", ordinal(n), named, strings.Join(told, " and ")))
+		if len(deeds) > 2 {
+			told = told[:0]
+			for _, d := range deeds[2:] {
+				told = append(told, fmt.Sprintf(d.verbs[rng.Intn(len(d.verbs))], countWords(d.count, d.noun)))
+			}
+			opening = append(opening, fmt.Sprintf("%s %s %s.", Hero, pick("also", "even"), joinAnd(told)))
+		}
+		if busiest, ok := busiestDay(days); ok && len(days) > 1 {
+			opening = append(opening, fmt.Sprintf(pick("The fiercest day was %s, with %s.", "%s was the day the Cache shook most, with %s."),
+				busiest.Day().Weekday(), countWords(busiest.Total(), "deed")))
+		}
+	}
+	c.Paragraphs = append(c.Paragraphs, strings.Join(opening, " "))
+
+	var milestones []string
+	for _, e := range evolutions {
+		c.Title = fmt.Sprintf("The %s Awakens", e.Evolved)
+		milestones = append(milestones, fmt.Sprintf(pick("On %s, a light broke over the Cache, and %s awoke as a %s.", "On %s, %s took on a new shape and rose as a %s."),
+			e.Time.Local().Weekday(), state.DisplayName(), e.Evolved))
+	}
+	if len(unlocked) > 0 {
+		if rng.Intn(2) == 0 {
+			milestones = append(milestones, fmt.Sprintf("Together, the Keeper and %s earned %s.", hero, joinAnd(unlocked)))
+		} else {
+			milestones = append(milestones, fmt.Sprintf("%s and the Keeper were honoured with %s.", Hero, joinAnd(unlocked)))
+		}
+	}
+	if len(milestones) > 0 {
+		c.Paragraphs = append(c.Paragraphs, strings.Join(milestones, " "))
+	}
+
+	var ending []string
+	if len(days) >= 2 {
+		firstMood, lastMood := days[0].Mood, days[len(days)-1].Mood
+		switch {
+		case lastMood > firstMood+5:
+			ending = append(ending, pick("Spirits rose as the days went on.", "By the week's end, the Cache was full of song."))
+		case lastMood < firstMood-5:
+			ending = append(ending, pick("By the week's end, a weariness had settled over the Cache.", "The days grew heavy, and the fires burned low."))
+		}
+	}
+	if ongoing {
+		ending = append(ending, pick("And the week is not over yet…", "What the rest of the week holds, no one can say."))
+	} else {
+		ending = append(ending, fmt.Sprintf(pick("So ended the %s week.", "And so the %s chapter closed."), ordinal(n)))
+	}
+	c.Paragraphs = append(c.Paragraphs, strings.Join(ending, " "))
+	c.Title = fmt.Sprintf("Chapter %d: %s", n, c.Title)
+	return c
+}
+
+// busiestDay is the day with the most recorded activity.
+func busiestDay(days []store.DayRecord) (store.DayRecord, bool) {
+	var best store.DayRecord
+	for _, d := range days {
+		if d.Total() > best.Total() {
+			best = d
+		}
+	}
+	return best, best.Total() > 0
+}
+
+func (c storyChapter) dates() string {
+	return fmt.Sprintf("%s – %s", c.From.Format("Jan 2"), c.To.Format("Jan 2, 2006"))
+}
+
+func storyMarkdown(state store.PetState, chapters []storyChapter) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s The story of %s\n", state.Signature(), state.DisplayName()))
+	for _, c := range chapters {
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n*%s*\n", c.Title, c.dates()))
+		for _, p := range c.Paragraphs {
+			sb.WriteString("\n" + p + "\n")
+		}
+	}
+	return sb.String()
+}
+
+var numberWords = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven", "twelve"}
+
+// countWords is plural with small numbers spelled out, as a storyteller
+// would: "three bugs", but "42 bugs".
+func countWords(n int, noun string) string {
+	if n > 1 && n < len(numberWords) {
+		return numberWords[n] + " " + noun + "s"
+	}
+	return i18n.Plural(n, noun)
+}
+
+var ordinalWords = []string{"first", "second", "third", "fourth", "fifth", "sixth", "seventh", "eighth", "ninth", "tenth",
+	"eleventh", "twelfth", "thirteenth", "fourteenth", "fifteenth", "sixteenth", "seventeenth", "eighteenth", "nineteenth", "twentieth"}
+
+func ordinal(n int) string {
+	if n >= 1 && n <= len(ordinalWords) {
+		return ordinalWords[n-1]
+	}
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// joinAnd joins items as a sentence would: "a, b and c".
+func joinAnd(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
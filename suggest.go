@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gitpet/gh-pet/internal/activity"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/pet"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// suggestionTemplates are commit messages in each evolution's voice, used
+// when Copilot isn't available. Kept in step with the MCP server's
+// generateSuggestions.
+var suggestionTemplates = map[string][]string{
+	"Pioneer": {
+		"🗺️ feat: chart unknown territory in the codebase",
+		"⛏️ feat: dig deeper into the codebase mines",
+		"🏗️ feat: lay the foundation for the next expedition",
+		"🧭 feat: navigate through uncharted logic",
+		"🌄 feat: plant a flag on the summit of progress",
+		"🔭 feat: discover a new pattern in the wilderness",
+		"🚀 feat: launch into unexplored modules",
+	},
+	"Guardian": {
+		"🛡️ fix: fortify the walls against regression",
+		"🔒 fix: seal the breach in input validation",
+		"⚔️ fix: defend the tests from flaky behavior",
+		"🏰 fix: reinforce the castle of type safety",
+		"🗡️ fix: vanquish the lurking null pointer",
+		"🛡️ chore: patrol the perimeter of dependencies",
+		"⚙️ fix: repair the shield of error handling",
+	},
+	"Bard": {
+		"📜 docs: compose a ballad of API documentation",
+		"🎵 docs: sing the changelog's latest verse",
+		"📖 docs: illuminate the README with fresh wisdom",
+		"🎭 refactor: perform a dramatic code transformation",
+		"🎶 docs: harmonize the inline comments",
+		"📝 docs: inscribe the wisdom of edge cases",
+		"🎪 docs: narrate the story of this module",
+	},
+	"Void": {
+		"🌑 refactor: dissolve unnecessary complexity",
+		"✂️ refactor: trim the excess from the void",
+		"🕳️ refactor: collapse redundant abstractions",
+		"💫 refactor: distill logic to its purest form",
+		"🌌 chore: let the void reclaim dead code",
+		"⚫ refactor: simplify until nothing remains but clarity",
+		"🔮 refactor: reshape the formless into structure",
+	},
+	"Sentinel": {
+		"🧪 test: post a sentinel at the module's edge",
+		"✅ test: prove the happy path stays happy",
+		"🔬 test: catch the regression before it hatches",
+		"🧱 test: build a wall of table-driven cases",
+		"🚨 test: sound the alarm on the flaky edge case",
+		"📏 test: measure twice, assert once",
+		"🛰️ test: watch over the integration seams",
+	},
+	"Curator": {
+		"🗂️ chore: label the wandering issues",
+		"🏷️ chore: bring order to the backlog",
+		"🧹 fix: close the loop on a long-lost report",
+		"📋 docs: add a triage guide to the issue templates",
+		"🔖 chore: tag the good first issues for newcomers",
+		"🪴 chore: prune stale issues from the garden",
+		"📬 fix: answer the issue that waited patiently",
+	},
+	"Companion": {
+		"💡 feat: breathe life into the first feature",
+		"🌱 feat: plant the seed of something new",
+		"🤝 chore: set up a welcoming project structure",
+		"🎯 feat: take the first step on the journey",
+		"✨ feat: spark the initial implementation",
+	},
+}
+
+// suggestions picks up to count messages in the personality's voice, from
+// its ability's songbook first when it has one. A commitType such as "fix"
+// keeps only that kind, borrowing from the other personalities when this
+// one has too few.
+func suggestions(personality, commitType string, count int) []string {
+	msgs, ok := suggestionTemplates[personality]
+	if !ok {
+		msgs = suggestionTemplates["Companion"]
+	}
+	if a, ok := pet.AbilityFor(personality); ok && len(a.Songbook) > 0 {
+		msgs = append(append([]string(nil), a.Songbook...), msgs...)
+	}
+	if commitType == "" {
+		return msgs[:min(count, len(msgs))]
+	}
+	var out []string
+	seen := map[string]bool{}
+	add := func(candidates []string) {
+		for _, msg := range candidates {
+			if len(out) < count && !seen[msg] && suggestionType(msg) == commitType {
+				seen[msg] = true
+				out = append(out, msg)
+			}
+		}
+	}
+	add(msgs)
+	for _, name := range []string{"Pioneer", "Guardian", "Bard", "Void", "Sentinel", "Curator", "Companion"} {
+		add(suggestionTemplates[name])
+	}
+	return out
+}
+
+// suggestionType is the conventional-commit type after the leading emoji.
+func suggestionType(msg string) string {
+	msg = stripEmoji(msg)
+	kind, _, _ := strings.Cut(msg, ":")
+	return kind
+}
+
+// stripEmoji drops the decorative emoji so a message can be committed as a
+// plain conventional commit.
+func stripEmoji(msg string) string {
+	first, rest, ok := strings.Cut(msg, " ")
+	if !ok || !strings.ContainsFunc(first, func(r rune) bool { return r > unicode.MaxASCII }) {
+		return msg
+	}
+	return rest
+}
+
+func runSuggest(args []string) error {
+	fs := newFlagSet("suggest")
+	count := fs.Int("count", 5, "number of suggestions")
+	commitType := fs.String("type", "", "only suggest this kind of commit: feat, fix, docs, refactor, test, chore")
+	write := fs.Bool("write", false, "pre-fill the commit message with a suggestion")
+	pick := fs.Int("pick", 1, "which suggestion --write uses")
+	local := fs.Bool("local", false, "skip Copilot and use GitPet's own suggestions")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if *count < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+
+	state, _ := store.Load()
+	personality := state.Evolution
+	if personality == "" || personality == "Lonely" {
+		personality = "Companion"
+	}
+
+	// Copilot's answer can't be captured for --write, so that always uses
+	// the local generator.
+	if !*local && !*write && copilotAvailable(ctx) {
+		kind := "git commit messages"
+		if *commitType != "" {
+			kind = fmt.Sprintf("%q-type conventional commit messages", *commitType)
+		}
+		prompt := fmt.Sprintf("Generate %d creative %s in the voice of %s, a %s GitPet. Mood: %s. Be supportive and witty, one line each.", *count, kind, state.Introduction(), personality, moodDescriptor(state.Mood))
+		if change, ok := activity.ReadStagedChange(ctx); ok {
+			prompt += fmt.Sprintf(" The staged change touches %s (+%d/-%d lines) and looks like a %s", change.Subject, change.Insert, change.Delete, change.Type)
+			if change.Scope != "" {
+				prompt += " in " + change.Scope
+			}
+			prompt += "."
+		}
+		cmd := exec.Command("gh", "copilot", "suggest", prompt)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	var msgs []string
+	if change, ok := activity.ReadStagedChange(ctx); ok {
+		msgs = activity.ContextSuggestions(personality, change, *commitType, *count)
+	}
+	msgs = append(msgs, suggestions(personality, *commitType, *count-len(msgs))...)
+	if len(msgs) == 0 {
+		return fmt.Errorf("no suggestions of type %q", *commitType)
+	}
+	if *write {
+		if *pick < 1 || *pick > len(msgs) {
+			return fmt.Errorf("--pick must be between 1 and %d", len(msgs))
+		}
+		return writeCommitMessage(stripEmoji(msgs[*pick-1]))
+	}
+	fmt.Printf("%s %s (%s, Mood: %s) suggests:\n\n", state.Signature(), state.DisplayName(), personality, moodDescriptor(state.Mood))
+	for i, msg := range msgs {
+		fmt.Printf("%d. %s\n", i+1, msg)
+	}
+	return nil
+}
+
+func copilotAvailable(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(gh.Timeouts.GitHubSeconds)*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gh", "copilot", "--help")
+	cmd.WaitDelay = gh.KillGrace
+	return cmd.Run() == nil
+}
+
+// writeCommitMessage leaves msg in .git/COMMIT_EDITMSG for `git commit -eF`,
+// or prints a ready-to-run command outside a repository.
+func writeCommitMessage(msg string) error {
+	out, err := gh.GitOutput(context.Background(), "rev-parse", "--git-path", "COMMIT_EDITMSG")
+	if err != nil {
+		fmt.Printf("git commit -m %q\n", msg)
+		return nil
+	}
+	path := strings.TrimSpace(string(out))
+	if err := os.WriteFile(path, []byte(msg+"\n"), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Wrote %q to %s%s\n", colorGreen, msg, filepath.ToSlash(path), colorReset)
+	fmt.Printf("  Commit with: git commit -eF %s\n", filepath.ToSlash(path))
+	fmt.Printf("  Or directly: git commit -m %q\n", msg)
+	return nil
+}
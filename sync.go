@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+const (
+	syncFileName    = "gh-pet-sync.json"
+	syncGistFile    = "gh-pet.enc"
+	syncDescription = "GitPet state (encrypted)"
+	syncHeader      = "gitpet-sync v1\n"
+)
+
+// SyncSettings links this machine to the cloud copy of the pet: a secret gist
+// holding the state sealed with Key, which never leaves your machines.
+type SyncSettings struct {
+	Key    string    `json:"key"`
+	GistID string    `json:"gist_id,omitempty"`
+	Synced time.Time `json:"synced,omitempty"`
+}
+
+func runSync(args []string) error {
+	fs := newFlagSet("sync")
+	key := fs.String("key", "", "the sync key shown on your other machine")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	// Allow the action before or after the flags.
+	action := "merge"
+	if fs.NArg() > 0 {
+		action = fs.Arg(0)
+		if err := parseFlags(fs, fs.Args()[1:]); err != nil {
+			return err
+		}
+	}
+	switch action {
+	case "merge", "push", "pull", "key":
+	default:
+		return usageErrorf("usage: gh pet sync [push|pull|key] [--key key]")
+	}
+
+	settings, err := loadSyncSettings()
+	if err != nil {
+		return err
+	}
+	if *key != "" {
+		if _, err := decodeSyncKey(*key); err != nil {
+			return err
+		}
+		if *key != settings.Key {
+			settings.Key, settings.GistID = *key, ""
+		}
+	}
+	if action == "key" {
+		if settings.Key == "" {
+			return fmt.Errorf("no sync key yet; run gh pet sync first")
+		}
+		fmt.Println(settings.Key)
+		return nil
+	}
+	newKey := settings.Key == ""
+	if newKey {
+		settings.Key = newSyncKey()
+	}
+	secret, err := decodeSyncKey(settings.Key)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	remote, found, err := pullSyncedState(ctx, &settings, secret)
+	if err != nil {
+		return err
+	}
+	// A pet that can't be read must never reach the cloud copy, which may
+	// be the Keeper's only backup. Pulling replaces it, so that's how a
+	// broken local pet is recovered.
+	local, err := store.Load()
+	if err != nil && action != "pull" {
+		return fmt.Errorf("can't read this machine's pet, so nothing was synced; run gh pet sync pull to replace it with the cloud copy: %w", err)
+	}
+	hasLocal := petExists()
+	result := local
+	switch {
+	case action == "pull" && !found:
+		return fmt.Errorf("there is no pet in the cloud yet; run gh pet sync on the machine that has it")
+	case action == "pull":
+		result = remote
+	case !hasLocal && action == "push":
+		return fmt.Errorf("there's no pet on this machine to upload; run gh pet sync pull to fetch the cloud copy")
+	case !hasLocal && !found:
+		return fmt.Errorf("there's no pet here or in the cloud yet; run gh pet hatch to meet yours")
+	case !hasLocal:
+		result = remote
+	case action == "merge" && found:
+		result = mergeStates(local, remote)
+	}
+
+	if action != "push" {
+		if err := store.Save(result); err != nil {
+			return err
+		}
+	}
+	if action != "pull" {
+		if err := pushSyncedState(ctx, &settings, secret, result); err != nil {
+			return err
+		}
+	}
+	settings.Synced = time.Now().UTC()
+	if err := saveSyncSettings(settings); err != nil {
+		return err
+	}
+
+	switch {
+	case action == "push":
+		fmt.Printf("☁️  Uploaded %s, replacing the cloud copy.\n", result.DisplayName())
+	case action == "pull":
+		fmt.Printf("☁️  Downloaded %s, replacing this machine's copy.\n", result.DisplayName())
+	case !found:
+		fmt.Printf("☁️  %s now lives in a secret gist too.\n", result.DisplayName())
+	default:
+		newer := "this machine's"
+		if syncTime(remote.LastSync).After(syncTime(local.LastSync)) {
+			newer = "the cloud"
+		}
+		fmt.Printf("☁️  Synced %s, keeping %s copy as the newer one. Kindness %d | Logic Shards %d\n",
+			result.DisplayName(), newer, result.Kindness, result.Logic)
+	}
+	if newKey {
+		fmt.Println("🔑 To share this pet with another machine, run this there and keep the key secret:")
+		fmt.Printf("   gh pet sync --key %s\n", settings.Key)
+	}
+	return nil
+}
+
+// mergeStates reconciles two copies of the pet. Kindness, logic shards, and
+// mentor only ever grow, so each takes the higher value, and achievements, event
+// badges, and companions are the union of both. Everything else comes from the copy fed most recently,
+// except thought fragments still waiting for this machine's next feed, and a
+// name the newer copy simply never had.
+func mergeStates(local, remote store.PetState) store.PetState {
+	merged, older := remote, local
+	if syncTime(local.LastSync).After(syncTime(remote.LastSync)) {
+		merged, older = local, remote
+	}
+	if merged.Name == "" {
+		merged.Name, merged.Pronouns, merged.Emoji = older.Name, older.Pronouns, older.Emoji
+	}
+	merged.Kindness = max(local.Kindness, remote.Kindness)
+	merged.Logic = max(local.Logic, remote.Logic)
+	merged.Mentor = max(local.Mentor, remote.Mentor)
+	merged.Achievements = union(local.Achievements, remote.Achievements)
+	merged.Badges = union(local.Badges, remote.Badges)
+	merged.Companions = append([]store.Companion(nil), merged.Companions...)
+	for _, c := range older.Companions {
+		if !hasCompanion(merged, c.Fork) && len(merged.Companions) < store.MaxCompanions {
+			merged.Companions = append(merged.Companions, c)
+		}
+	}
+	if merged.AccountCreated == "" {
+		merged.AccountCreated = older.AccountCreated
+	}
+	merged.PendingThoughts = local.PendingThoughts
+	return merged
+}
+
+// union is a followed by whatever of b it doesn't already have.
+func union(a, b []string) []string {
+	out := append([]string(nil), a...)
+	for _, s := range b {
+		if !containsString(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func syncTime(lastSync string) time.Time {
+	t, _ := time.Parse(time.RFC3339, lastSync)
+	return t
+}
+
+// pullSyncedState fetches and opens the cloud copy, looking the gist up by
+// its description when this machine hasn't synced before.
+func pullSyncedState(ctx context.Context, settings *SyncSettings, secret []byte) (store.PetState, bool, error) {
+	if settings.GistID == "" {
+		var gists []struct {
+			ID          string              `json:"id"`
+			Description string              `json:"description"`
+			Files       map[string]struct{} `json:"files"`
+		}
+		if err := gh.Get(ctx, "gists?per_page=100", &gists); err != nil {
+			return store.PetState{}, false, err
+		}
+		for _, g := range gists {
+			if _, ok := g.Files[syncGistFile]; ok && g.Description == syncDescription {
+				settings.GistID = g.ID
+				break
+			}
+		}
+		if settings.GistID == "" {
+			return store.PetState{}, false, nil
+		}
+	}
+
+	var gist struct {
+		Files map[string]struct {
+			Content string `json:"content"`
+		} `json:"files"`
+	}
+	if err := gh.Get(ctx, "gists/"+settings.GistID, &gist); err != nil {
+		if gh.IsNotFound(err) {
+			settings.GistID = ""
+			return store.PetState{}, false, nil
+		}
+		return store.PetState{}, false, err
+	}
+	file, ok := gist.Files[syncGistFile]
+	if !ok {
+		return store.PetState{}, false, fmt.Errorf("gist %s has no %s", settings.GistID, syncGistFile)
+	}
+	state, err := openSyncedState(file.Content, secret)
+	if err != nil {
+		return store.PetState{}, false, err
+	}
+	return state, true, nil
+}
+
+func pushSyncedState(ctx context.Context, settings *SyncSettings, secret []byte, state store.PetState) error {
+	content, err := sealSyncedState(state, secret)
+	if err != nil {
+		return err
+	}
+	files := map[string]any{syncGistFile: map[string]string{"content": content}}
+	if settings.GistID != "" {
+		return gh.Send(ctx, http.MethodPatch, "gists/"+settings.GistID, map[string]any{"files": files}, nil)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	payload := map[string]any{"description": syncDescription, "public": false, "files": files}
+	if err := gh.Send(ctx, http.MethodPost, "gists", payload, &created); err != nil {
+		return err
+	}
+	settings.GistID = created.ID
+	return nil
+}
+
+// sealSyncedState encrypts the state with AES-GCM, so the gist is opaque to
+// anyone without the key, GitHub included.
+func sealSyncedState(state store.PetState, secret []byte) (string, error) {
+	plain, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := syncCipher(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, plain, nil)
+	return syncHeader + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func openSyncedState(content string, secret []byte) (store.PetState, error) {
+	encoded, ok := strings.CutPrefix(content, syncHeader)
+	if !ok {
+		return store.PetState{}, fmt.Errorf("the cloud copy is in an unknown format; upgrade GitPet")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return store.PetState{}, fmt.Errorf("the cloud copy is damaged: %w", err)
+	}
+	gcm, err := syncCipher(secret)
+	if err != nil {
+		return store.PetState{}, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return store.PetState{}, errors.New("the cloud copy is damaged")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return store.PetState{}, errors.New("this key can't open the cloud copy; run gh pet sync --key with the key from your other machine")
+	}
+	var state store.PetState
+	if err := json.Unmarshal(plain, &state); err != nil {
+		return store.PetState{}, fmt.Errorf("the cloud copy is damaged: %w", err)
+	}
+	return state, nil
+}
+
+func syncCipher(secret []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func newSyncKey() string {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	return base64.RawURLEncoding.EncodeToString(secret)
+}
+
+func decodeSyncKey(key string) ([]byte, error) {
+	secret, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil || len(secret) != 32 {
+		return nil, errors.New("not a GitPet sync key; copy it again from gh pet sync key on the other machine")
+	}
+	return secret, nil
+}
+
+func loadSyncSettings() (SyncSettings, error) {
+	path, err := syncPath()
+	if err != nil {
+		return SyncSettings{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return SyncSettings{}, nil
+		}
+		return SyncSettings{}, err
+	}
+	var settings SyncSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return SyncSettings{}, err
+	}
+	return settings, nil
+}
+
+func saveSyncSettings(settings SyncSettings) error {
+	path, err := syncPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func syncPath() (string, error) {
+	return dirs.DataPath(syncFileName)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+func TestMergeStates(t *testing.T) {
+	const older, newer = "2026-10-01T09:00:00Z", "2026-10-02T09:00:00Z"
+	tests := []struct {
+		name          string
+		local, remote store.PetState
+		want          store.PetState
+	}{
+		{
+			name:   "newer remote wins mood and evolution",
+			local:  store.PetState{Mood: 40, Evolution: "Bard", LastSync: older},
+			remote: store.PetState{Mood: 70, Evolution: "Guardian", LastSync: newer},
+			want:   store.PetState{Mood: 70, Evolution: "Guardian", LastSync: newer},
+		},
+		{
+			name:   "newer local wins mood and evolution",
+			local:  store.PetState{Mood: 40, Evolution: "Bard", LastSync: newer},
+			remote: store.PetState{Mood: 70, Evolution: "Guardian", LastSync: older},
+			want:   store.PetState{Mood: 40, Evolution: "Bard", LastSync: newer},
+		},
+		{
+			name:   "earned stats never go down",
+			local:  store.PetState{Kindness: 9, Logic: 2, Mentor: 5, LastSync: older},
+			remote: store.PetState{Kindness: 3, Logic: 8, Mentor: 1, LastSync: newer},
+			want:   store.PetState{Kindness: 9, Logic: 8, Mentor: 5, LastSync: newer},
+		},
+		{
+			name:   "achievements and badges are pooled",
+			local:  store.PetState{Achievements: []string{"First Feed", "Night Owl"}, Badges: []string{"Hacktoberfest"}, LastSync: older},
+			remote: store.PetState{Achievements: []string{"First Feed", "Reviewer"}, LastSync: newer},
+			want:   store.PetState{Achievements: []string{"First Feed", "Night Owl", "Reviewer"}, Badges: []string{"Hacktoberfest"}, LastSync: newer},
+		},
+		{
+			name:   "identity comes from whichever side has one",
+			local:  store.PetState{Name: "Bit", Pronouns: "they/them", Emoji: "🐙", AccountCreated: "2015-04-01", LastSync: older},
+			remote: store.PetState{LastSync: newer},
+			want:   store.PetState{Name: "Bit", Pronouns: "they/them", Emoji: "🐙", AccountCreated: "2015-04-01", LastSync: newer},
+		},
+		{
+			name:   "companions are pooled by fork",
+			local:  store.PetState{Companions: []store.Companion{{Fork: "me/a"}, {Fork: "me/b"}}, LastSync: older},
+			remote: store.PetState{Companions: []store.Companion{{Fork: "me/b"}, {Fork: "me/c"}}, LastSync: newer},
+			want:   store.PetState{Companions: []store.Companion{{Fork: "me/b"}, {Fork: "me/c"}, {Fork: "me/a"}}, LastSync: newer},
+		},
+		{
+			name:   "pending thoughts stay on this machine",
+			local:  store.PetState{PendingThoughts: 3, LastSync: older},
+			remote: store.PetState{PendingThoughts: 7, LastSync: newer},
+			want:   store.PetState{PendingThoughts: 3, LastSync: newer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeStates(tt.local, tt.remote); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeStates() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
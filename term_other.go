@@ -0,0 +1,26 @@
+//go:build !unix && !windows
+
+package main
+
+import "errors"
+
+// terminalWidth only knows COLUMNS on platforms without a terminal ioctl.
+func terminalWidth() int {
+	return columnsEnv()
+}
+
+// terminalRows is unknown on platforms without a terminal ioctl.
+func terminalRows() int {
+	return 0
+}
+
+// cellPixels is unknown on platforms without a terminal ioctl.
+func cellPixels() (width, height int) {
+	return 0, 0
+}
+
+// rawInput can't read keys as they're pressed on platforms without a
+// terminal ioctl.
+func rawInput() (restore func(), err error) {
+	return nil, errors.New("this platform can't read keys as they're pressed")
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// terminalWidth returns the width of the console on stdout in columns, or
+// 0 when stdout isn't one. COLUMNS overrides it, as in most shells' tools.
+func terminalWidth() int {
+	if cols := columnsEnv(); cols > 0 {
+		return cols
+	}
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(os.Stdout.Fd()), &info); err != nil {
+		return 0
+	}
+	return int(info.Window.Right-info.Window.Left) + 1
+}
+
+// terminalRows returns the height of the console window on stdout in rows,
+// or 0 when stdout isn't one.
+func terminalRows() int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(os.Stdout.Fd()), &info); err != nil {
+		return 0
+	}
+	return int(info.Window.Bottom-info.Window.Top) + 1
+}
+
+// cellPixels returns zeros: the console doesn't report its font size.
+func cellPixels() (width, height int) {
+	return 0, 0
+}
+
+// rawInput stops the console on stdin from buffering lines, echoing, and
+// treating Ctrl-C as a signal, and asks it for arrow keys as escape
+// sequences. It returns a function that puts the console back.
+func rawInput() (restore func(), err error) {
+	handle := windows.Handle(os.Stdin.Fd())
+	var old uint32
+	if err := windows.GetConsoleMode(handle, &old); err != nil {
+		return nil, err
+	}
+	raw := old&^(windows.ENABLE_LINE_INPUT|windows.ENABLE_ECHO_INPUT|windows.ENABLE_PROCESSED_INPUT) | windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(handle, raw); err != nil {
+		return nil, err
+	}
+	return func() { windows.SetConsoleMode(handle, old) }, nil
+}
@@ -0,0 +1,12 @@
+//go:build aix || linux || solaris || zos
+
+package main
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioctlReadTermios  = unix.TCGETS
+	ioctlWriteTermios = unix.TCSETS
+)
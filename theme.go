@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BorderStyle is the set of box-drawing pieces used for framed output.
+type BorderStyle struct {
+	TopLeft, TopRight, BottomLeft, BottomRight string
+	Horizontal, Vertical                       string
+	TeeLeft, TeeRight                          string
+}
+
+var borderStyles = map[string]BorderStyle{
+	"rounded": {"╭", "╮", "╰", "╯", "─", "│", "├", "┤"},
+	"ascii":   {"+", "+", "+", "+", "-", "|", "+", "+"},
+	"double":  {"╔", "╗", "╚", "╝", "═", "║", "╠", "╣"},
+}
+
+func (b BorderStyle) top(width int) string {
+	return b.TopLeft + strings.Repeat(b.Horizontal, width) + b.TopRight
+}
+
+func (b BorderStyle) divider(width int) string {
+	return b.TeeLeft + strings.Repeat(b.Horizontal, width) + b.TeeRight
+}
+
+func (b BorderStyle) bottom(width int) string {
+	return b.BottomLeft + strings.Repeat(b.Horizontal, width) + b.BottomRight
+}
+
+// Theme is everything the renderers need to paint output: an accent per
+// evolution, mood bar colors, text attributes, and the border style.
+type Theme struct {
+	Name     string
+	Accents  map[string]string
+	Fallback string
+	Good     string
+	Warn     string
+	Bad      string
+	Muted    string
+	Bold     string
+	Dim      string
+	Reset    string
+	Border   BorderStyle
+	// Depth is the detected terminal color depth; Gradient enables the
+	// smooth mood bar on 256-color and true-color terminals.
+	Depth    int
+	Gradient bool
+}
+
+func (t Theme) accent(evolution string) string {
+	if color, ok := t.Accents[evolution]; ok {
+		return color
+	}
+	return t.Fallback
+}
+
+func (t Theme) moodColor(mood int) string {
+	switch {
+	case mood >= 70:
+		return t.Good
+	case mood >= 40:
+		return t.Warn
+	case mood > 0:
+		return t.Bad
+	default:
+		return t.Muted
+	}
+}
+
+// ThemeSpec is a user-defined theme in the config file. Colors are names
+// from colorNames or "#rrggbb" hex, so nobody has to type escape codes into
+// JSON.
+type ThemeSpec struct {
+	Accents map[string]string `json:"accents"`
+	Default string            `json:"default"`
+	Good    string            `json:"good"`
+	Warn    string            `json:"warn"`
+	Bad     string            `json:"bad"`
+	Muted   string            `json:"muted"`
+}
+
+var colorNames = map[string]string{
+	"none":           "",
+	"black":          "\x1b[30m",
+	"red":            colorRed,
+	"green":          colorGreen,
+	"yellow":         colorYellow,
+	"blue":           colorBlue,
+	"magenta":        colorMagenta,
+	"cyan":           colorCyan,
+	"white":          colorGrey,
+	"grey":           "\x1b[90m",
+	"bright-red":     "\x1b[91m",
+	"bright-green":   "\x1b[92m",
+	"bright-yellow":  "\x1b[93m",
+	"bright-blue":    "\x1b[94m",
+	"bright-magenta": "\x1b[95m",
+	"bright-cyan":    "\x1b[96m",
+	"bright-white":   "\x1b[97m",
+}
+
+func builtinThemes() map[string]Theme {
+	return map[string]Theme{
+		"default": {
+			Accents: map[string]string{
+				"Pioneer": colorYellow, "Guardian": colorBlue, "Bard": colorMagenta,
+				"Void": colorGrey, "Sentinel": colorCyan, "Curator": colorGreen,
+			},
+			Fallback: colorGrey,
+			Good:     colorGreen, Warn: colorYellow, Bad: colorRed, Muted: colorGrey,
+			Bold: colorBold, Dim: colorDim, Reset: colorReset,
+		},
+		"solarized": {
+			Accents: map[string]string{
+				"Pioneer": colorYellow, "Guardian": colorBlue, "Bard": colorMagenta,
+				"Void": "\x1b[90m", "Sentinel": colorCyan, "Curator": colorGreen,
+			},
+			Fallback: "\x1b[90m",
+			Good:     colorCyan, Warn: colorYellow, Bad: colorRed, Muted: "\x1b[90m",
+			Bold: colorBold, Dim: colorDim, Reset: colorReset,
+		},
+		"dracula": {
+			Accents: map[string]string{
+				"Pioneer": "\x1b[93m", "Guardian": "\x1b[95m", "Bard": "\x1b[35m",
+				"Void": "\x1b[90m", "Sentinel": "\x1b[96m", "Curator": "\x1b[92m",
+			},
+			Fallback: "\x1b[95m",
+			Good:     "\x1b[92m", Warn: "\x1b[93m", Bad: "\x1b[91m", Muted: "\x1b[90m",
+			Bold: colorBold, Dim: colorDim, Reset: colorReset,
+		},
+		"monochrome": {
+			Accents: map[string]string{},
+		},
+		"high-contrast": {
+			Accents: map[string]string{
+				"Pioneer": "\x1b[1;93m", "Guardian": "\x1b[1;96m", "Bard": "\x1b[1;95m",
+				"Void": "\x1b[1;97m", "Sentinel": "\x1b[1;96m", "Curator": "\x1b[1;92m",
+			},
+			Fallback: "\x1b[1;97m",
+			Good:     "\x1b[1;92m", Warn: "\x1b[1;93m", Bad: "\x1b[1;91m", Muted: "\x1b[1;97m",
+			Bold: colorBold, Reset: colorReset,
+		},
+	}
+}
+
+func (s ThemeSpec) validate(name string) error {
+	colors := []string{s.Default, s.Good, s.Warn, s.Bad, s.Muted}
+	for _, color := range s.Accents {
+		colors = append(colors, color)
+	}
+	for _, color := range colors {
+		if _, hex := parseHex(color); hex && strings.HasPrefix(color, "#") {
+			continue
+		}
+		if _, ok := colorNames[color]; !ok && color != "" {
+			return fmt.Errorf("themes.%s: unknown color %q (try one of: %s)", name, color, strings.Join(knownColorNames(), ", "))
+		}
+	}
+	return nil
+}
+
+func (s ThemeSpec) theme(depth int) Theme {
+	base := builtinThemes()["default"]
+	pick := func(name, fallback string) string {
+		if name == "" {
+			return fallback
+		}
+		if c, ok := parseHex(name); ok {
+			return c.ansi(depth)
+		}
+		return colorNames[name]
+	}
+	accents := map[string]string{}
+	for evolution, color := range base.Accents {
+		accents[evolution] = color
+	}
+	for evolution, color := range s.Accents {
+		accents[evolution] = pick(color, "")
+	}
+	base.Accents = accents
+	base.Fallback = pick(s.Default, base.Fallback)
+	base.Good = pick(s.Good, base.Good)
+	base.Warn = pick(s.Warn, base.Warn)
+	base.Bad = pick(s.Bad, base.Bad)
+	base.Muted = pick(s.Muted, base.Muted)
+	return base
+}
+
+func knownColorNames() []string {
+	names := make([]string, 0, len(colorNames))
+	for name := range colorNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// activeTheme resolves the configured theme and border. Unknown names were
+// already rejected by validation, so this only falls back for a missing file.
+func (c Config) activeTheme() Theme {
+	return c.themeAt(colorDepth())
+}
+
+// themeAt is the configured theme at a given color depth, for output that
+// isn't headed for this terminal, such as gh pet snapshot's images.
+func (c Config) themeAt(depth int) Theme {
+	theme, ok := builtinThemes()[c.Theme]
+	if spec, custom := c.Themes[c.Theme]; custom {
+		theme, ok = spec.theme(depth), true
+	}
+	if !ok {
+		theme = builtinThemes()["default"]
+	}
+	switch {
+	case depth == depthNone:
+		theme = builtinThemes()["monochrome"]
+	case depth >= depth256 && (c.Theme == "default" || c.Theme == ""):
+		for evolution, hex := range evolutionHex {
+			color, _ := parseHex(hex)
+			theme.Accents[evolution] = color.ansi(depth)
+		}
+		theme.Gradient = true
+	case depth >= depth256 && c.Theme != "monochrome":
+		theme.Gradient = true
+	}
+	theme.Name = c.Theme
+	theme.Depth = depth
+	border, ok := borderStyles[c.Border]
+	if !ok {
+		border = borderStyles["rounded"]
+	}
+	theme.Border = border
+	return theme
+}
+
+func (c Config) validateTheme() error {
+	for name, spec := range c.Themes {
+		if err := spec.validate(name); err != nil {
+			return err
+		}
+	}
+	if _, builtin := builtinThemes()[c.Theme]; !builtin {
+		if _, custom := c.Themes[c.Theme]; !custom {
+			return fmt.Errorf("unknown theme %q", c.Theme)
+		}
+	}
+	if _, ok := borderStyles[c.Border]; !ok {
+		return fmt.Errorf("unknown border style %q (rounded, ascii, double)", c.Border)
+	}
+	return nil
+}
+
+func loadTheme() Theme {
+	cfg, _ := loadConfig()
+	return cfg.activeTheme()
+}
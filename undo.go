@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// runUndo puts the pet back the way it was before the last logged save.
+// Run again, it steps further back.
+func runUndo(args []string) error {
+	fs := newFlagSet("undo")
+	force := fs.Bool("force", false, "roll back even if the pet changed since, outside the change log")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	changes, err := store.LoadChanges()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("Nothing to undo.")
+		return nil
+	}
+	last := changes[len(changes)-1]
+	current, err := store.Load()
+	if err != nil {
+		return err
+	}
+	// Something that doesn't log its saves, such as an older gh-pet, may
+	// have changed the pet since; rolling back would lose that too.
+	if !*force && !sameState(current, last.After) {
+		return fmt.Errorf("your pet changed after the last logged %s; run gh pet undo --force to roll back anyway", last.Command)
+	}
+	if err := restoreState(last.Before); err != nil {
+		return err
+	}
+	if err := store.SaveChanges(changes[:len(changes)-1]); err != nil {
+		return err
+	}
+	fmt.Printf("%s↩ Undid %s from %s:%s %s\n", colorGreen, commandLabel(last.Command), i18n.RelativeTime(last.Time, time.Now()), colorReset, strings.Join(store.StateChanges(last.After, last.Before), ", "))
+	if len(changes) > 1 {
+		fmt.Printf("%sRun gh pet undo again to undo %s too.%s\n", colorDim, commandLabel(changes[len(changes)-2].Command), colorReset)
+	}
+	return nil
+}
+
+// restoreState writes state as is, without logging it as a change.
+func restoreState(state store.PetState) error {
+	path, err := store.Path()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func sameState(a, b store.PetState) bool {
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
+
+// commandLabel names the command behind a change, e.g. "gh pet feed".
+func commandLabel(command string) string {
+	if command == "" {
+		return "a change"
+	}
+	if command == "mcp" {
+		return "the MCP server"
+	}
+	return "gh pet " + command
+}
+
+func runChanges(args []string) error {
+	fs := newFlagSet("changes")
+	last := fs.Int("last", 10, "show the most recent N changes")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
+	}
+	changes, err := store.LoadChanges()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("No changes logged yet.")
+		return nil
+	}
+	if *last > 0 && len(changes) > *last {
+		changes = changes[len(changes)-*last:]
+	}
+	fmt.Printf("\n%s📜 What changed your pet, newest first%s\n\n", colorBold, colorReset)
+	now := time.Now()
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		fmt.Printf("%s%-9s%s %-22s %s\n", colorDim, i18n.RelativeTime(c.Time, now), colorReset, commandLabel(c.Command), strings.Join(c.Changed, ", "))
+	}
+	fmt.Printf("\n%sgh pet undo rolls back the newest one.%s\n", colorDim, colorReset)
+	return nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gitpet/gh-pet/internal/config"
+	"github.com/gitpet/gh-pet/internal/dirs"
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// Marker comments delimiting what GitPet writes into files it doesn't own.
+// Older installs have no end marker, so removal falls back to the known
+// shape of the snippet.
+const (
+	promptStartMarker = "# GitPet prompt"
+	promptEndMarker   = "# End GitPet prompt"
+	hookStartMarker   = "# GitPet %s hook"
+	hookEndMarker     = "# End GitPet hook"
+)
+
+func runUninstall(args []string) error {
+	fs := newFlagSet("uninstall")
+	purge := fs.Bool("purge", false, "also delete pet state, history, journal, config, and skins")
+	yes := fs.Bool("yes", false, "don't ask for confirmation before purging")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	for _, rc := range promptFiles() {
+		removed, err := stripFileBlock(rc, removePromptBlock)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  could not clean %s: %v\n", rc, err)
+		} else if removed {
+			fmt.Printf("%s✓ Removed prompt from %s%s\n", colorGreen, rc, colorReset)
+		}
+	}
+
+	for _, repo := range hookRepos() {
+		removed, err := uninstallHook(repo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  could not clean hook in %s: %v\n", repo, err)
+		} else if removed {
+			fmt.Printf("%s✓ Removed hooks from %s%s\n", colorGreen, repo, colorReset)
+		}
+	}
+
+	if !*purge {
+		fmt.Println("Prompt and hooks removed. Your pet's state was kept; use --purge to delete it too.")
+		return nil
+	}
+	if !*yes && !confirm("Delete every account's pet, with its history and journal, and GitPet's config, skins, and caches? This cannot be undone.") {
+		fmt.Println("Kept your pet's data.")
+		return nil
+	}
+	for _, path := range dataPaths() {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	fmt.Println("Goodbye from GitPet. 🐾")
+	return nil
+}
+
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func promptFiles() []string {
+	var files []string
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".bashrc"), filepath.Join(home, ".zshrc"))
+	}
+	if usePowerShell() {
+		if profile, err := powerShellProfile(); err == nil {
+			files = append(files, profile)
+		}
+	}
+	return files
+}
+
+// hookRepos is every repo GitPet was installed into, plus the current one.
+func hookRepos() []string {
+	cfg, _ := loadConfig()
+	repos := append([]string{}, cfg.Repos...)
+	if out, err := gh.GitOutput(context.Background(), "rev-parse", "--show-toplevel"); err == nil {
+		if top := strings.TrimSpace(string(out)); !containsString(repos, top) {
+			repos = append(repos, top)
+		}
+	}
+	return repos
+}
+
+func uninstallHook(repo string) (bool, error) {
+	out, err := gh.GitOutput(context.Background(), "-C", repo, "rev-parse", "--git-dir")
+	if err != nil {
+		return false, nil
+	}
+	hookDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hookDir) {
+		hookDir = filepath.Join(repo, hookDir)
+	}
+	hookDir = filepath.Join(hookDir, "hooks")
+	removed := false
+	for _, hook := range knownHooks() {
+		os.Remove(filepath.Join(hookDir, "gitpet-"+hook+".ps1"))
+		os.Remove(filepath.Join(hookDir, "gitpet-"+hook+".cmd"))
+		stripped, err := stripFileBlock(filepath.Join(hookDir, hook), hookBlockRemover(hook))
+		if err != nil {
+			return removed, err
+		}
+		removed = removed || stripped
+	}
+	return removed, nil
+}
+
+// stripFileBlock rewrites path without GitPet's block, deleting the file if
+// nothing but a shebang or blank lines would remain.
+func stripFileBlock(path string, remove func([]string) ([]string, bool)) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	lines, removed := remove(strings.Split(string(data), "\n"))
+	if !removed {
+		return false, nil
+	}
+	rest := strings.Join(lines, "\n")
+	if isEmptyScript(lines) {
+		return true, os.Remove(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, []byte(rest), info.Mode().Perm())
+}
+
+func removePromptBlock(lines []string) ([]string, bool) {
+	return removeBlock(lines, promptStartMarker, promptEndMarker, func(line string) bool {
+		return strings.Contains(line, "$(gitpet_prompt)")
+	})
+}
+
+func hookBlockRemover(hook string) func([]string) ([]string, bool) {
+	start := fmt.Sprintf(hookStartMarker, hook)
+	return func(lines []string) ([]string, bool) {
+		return removeBlock(lines, start, hookEndMarker, func(string) bool { return true })
+	}
+}
+
+// removeBlock drops every block running from a start marker to the end
+// marker, or for legacy installs to the first line legacyEnd accepts. A
+// shebang and blank separator directly above the block came with it and go
+// too.
+func removeBlock(lines []string, start, end string, legacyEnd func(string) bool) ([]string, bool) {
+	var out []string
+	removed := false
+	marked := hasEndMarker(lines, end)
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), start) {
+			out = append(out, lines[i])
+			continue
+		}
+		removed = true
+		if n := len(out); n > 0 && strings.HasPrefix(out[n-1], "#!") && n > 1 {
+			out = out[:n-1]
+		}
+		if n := len(out); n > 0 && strings.TrimSpace(out[n-1]) == "" {
+			out = out[:n-1]
+		}
+		j := i + 1
+		for ; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == end {
+				break
+			}
+			if !marked && legacyEnd(lines[j]) {
+				break
+			}
+		}
+		i = j
+	}
+	return out, removed
+}
+
+func hasEndMarker(lines []string, end string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == end {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyScript(lines []string) bool {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#!") {
+			return false
+		}
+	}
+	return true
+}
+
+// dataPaths is everything --purge deletes: this account's pet and records,
+// the same files for the pet in the data directory itself when this account
+// keeps its own, every other account's pet, and the caches.
+func dataPaths() []string {
+	var paths []string
+	for _, resolve := range []func() (string, error){store.Path, store.HistoryPath, store.JournalPath, adoptedPath, helpDeskPath, store.UsagePath, syncPath, config.Path, skinsDir, store.WhyPath, store.ChangesPath, morningPath, graveyardPath, pendingPath, archiveProgressPath, dirs.Accounts, dirs.Cache} {
+		if path, err := resolve(); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	base, err := dirs.Data()
+	if err != nil {
+		return paths
+	}
+	if dir, err := dirs.Pet(); err == nil && dir != base {
+		for _, path := range paths {
+			if rel, err := filepath.Rel(dir, path); err == nil && filepath.IsLocal(rel) {
+				paths = append(paths, filepath.Join(base, rel))
+			}
+		}
+	}
+	return paths
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// trackRepo remembers a repo GitPet installed a hook into, so uninstall can
+// find it again later.
+func trackRepo() error {
+	out, err := gh.GitOutput(context.Background(), "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+	top := strings.TrimSpace(string(out))
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if containsString(cfg.Repos, top) {
+		return nil
+	}
+	cfg.Repos = append(cfg.Repos, top)
+	return saveConfig(cfg)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+func runTelemetry(args []string) error {
+	if len(args) != 1 {
+		return usageErrorf("usage: gh pet telemetry on|off|show|reset")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "on", "off":
+		cfg.Telemetry = args[0] == "on"
+		if err := saveConfig(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Usage counting is %s.\n", args[0])
+	case "show":
+		usage, err := store.LoadUsage()
+		if err != nil {
+			return err
+		}
+		state := "off"
+		if cfg.Telemetry {
+			state = "on"
+		}
+		fmt.Printf("Usage counting is %s. Counts since %s:\n", state, usage.Since.Local().Format("2006-01-02"))
+		names := make([]string, 0, len(usage.Counts))
+		for name := range usage.Counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %-20s %d\n", name, usage.Counts[name])
+		}
+	case "reset":
+		path, err := store.UsagePath()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		fmt.Println("Usage counts cleared.")
+	default:
+		return usageErrorf("usage: gh pet telemetry on|off|show|reset")
+	}
+	return nil
+}
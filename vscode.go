@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// statusBarProtocol is bumped whenever a StatusBarItem field changes meaning,
+// so editor extensions can refuse a server they don't understand.
+const statusBarProtocol = 1
+
+// How often the event stream re-reads the state file, and how often it sends a
+// comment so proxies and editors don't drop an idle connection.
+const (
+	statusPollInterval = 2 * time.Second
+	heartbeatInterval  = 30 * time.Second
+)
+
+// StatusBarItem is everything an editor needs to draw the pet in one status
+// bar entry: Text is ready to show, the rest lets the extension style it.
+type StatusBarItem struct {
+	Name      string `json:"name"`
+	Face      string `json:"face"`
+	Mood      int    `json:"mood"`
+	Evolution string `json:"evolution"`
+	Color     string `json:"color"`
+	Asleep    bool   `json:"asleep"`
+	Text      string `json:"text"`
+	Tooltip   string `json:"tooltip"`
+	Updated   string `json:"updated,omitempty"`
+}
+
+// statusBarHandshake answers GET /vscode: the protocol version, where to
+// subscribe for changes, and the current item so the first paint needs no
+// second request.
+type statusBarHandshake struct {
+	Protocol int           `json:"protocol"`
+	Events   string        `json:"events"`
+	Status   string        `json:"status"`
+	Item     StatusBarItem `json:"item"`
+}
+
+func statusBarItem(state store.PetState, now time.Time) StatusBarItem {
+	evolution := state.Evolution
+	if evolution == "" {
+		evolution = "Lonely"
+	}
+	color, ok := evolutionHex[evolution]
+	if !ok {
+		color = evolutionHex["Void"]
+	}
+	item := StatusBarItem{
+		Name:      state.DisplayName(),
+		Face:      strings.TrimSpace(promptFace(state.Mood)),
+		Mood:      state.Mood,
+		Evolution: evolution,
+		Color:     color,
+		Asleep:    store.IsAsleep(now),
+		Updated:   state.LastSync,
+	}
+	if item.Asleep {
+		item.Face = "💤"
+	}
+	item.Text = fmt.Sprintf("%s %s %d", state.Signature(), item.Face, state.Mood)
+	item.Tooltip = fmt.Sprintf("%s the %s: %s (%d/100)", item.Name, evolution, moodDescriptor(state.Mood), state.Mood)
+	return item
+}
+
+func handleStatusBar(w http.ResponseWriter, r *http.Request) {
+	state, _ := store.Load()
+	writeJSON(w, statusBarHandshake{
+		Protocol: statusBarProtocol,
+		Events:   "/vscode/events",
+		Status:   "/vscode/status",
+		Item:     statusBarItem(state, time.Now()),
+	})
+}
+
+func handleStatusBarItem(w http.ResponseWriter, r *http.Request) {
+	state, _ := store.Load()
+	writeJSON(w, statusBarItem(state, time.Now()))
+}
+
+// handleStatusBarEvents streams a "status" event with a StatusBarItem right
+// away and again whenever the item changes, whether from a feed, a commit
+// hook, or the pet falling asleep.
+func handleStatusBarEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	var last []byte
+	send := func() error {
+		state, _ := store.Load()
+		data, err := json.Marshal(statusBarItem(state, time.Now()))
+		if err != nil || string(data) == string(last) {
+			return err
+		}
+		last = data
+		if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	if err := send(); err != nil {
+		return
+	}
+
+	poll := time.NewTicker(statusPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-poll.C:
+			// send skips unchanged items, so polling also catches the pet
+			// falling asleep without any write to the state file.
+			if err := send(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package main
+
+// enableVirtualTerminal is a no-op outside Windows, where terminals already
+// understand ANSI escapes.
+func enableVirtualTerminal() {}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// enableVirtualTerminal turns on ANSI escape handling for the Windows
+// console so colors and cursor moves render instead of printing raw codes.
+func enableVirtualTerminal() {
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		handle := syscall.Handle(f.Fd())
+		var mode uint32
+		if syscall.GetConsoleMode(handle, &mode) != nil {
+			continue
+		}
+		setConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+	}
+}
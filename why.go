@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/i18n"
+	"github.com/gitpet/gh-pet/internal/store"
+)
+
+// whyStats are the stats gh pet why explains, in the order it shows them.
+var whyStats = []string{"mood", "kindness", "logic", "mentor"}
+
+func runWhy(args []string) error {
+	fs := newFlagSet("why")
+	last := fs.Int("last", 1, "explain the most recent N feeds")
+	asJSON := fs.Bool("json", false, "print the breakdown as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *last < 1 {
+		return usageErrorf("--last must be at least 1")
+	}
+
+	explanations, err := store.LoadExplanations()
+	if err != nil {
+		return err
+	}
+	if len(explanations) > *last {
+		explanations = explanations[len(explanations)-*last:]
+	}
+	if *asJSON {
+		if explanations == nil {
+			explanations = []store.Explanation{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(explanations)
+	}
+	if len(explanations) == 0 {
+		fmt.Println("Nothing to explain yet. Run gh pet feed, then ask again.")
+		return nil
+	}
+	state, _ := store.Load()
+	for i, e := range explanations {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(renderWhy(state, e, time.Now()))
+	}
+	return nil
+}
+
+// renderWhy shows one feed's breakdown: each stat's change with the lines
+// that add up to it, then the evolution scores.
+func renderWhy(state store.PetState, e store.Explanation, now time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s🔎 Why %s changed — %s %s%s\n", colorBold, state.DisplayName(), e.Source, i18n.RelativeTime(e.Time, now), colorReset)
+	for _, stat := range whyStats {
+		change := e.Stats[stat]
+		var lines []Contribution
+		for _, c := range e.Contributions {
+			if c.Stat == stat {
+				lines = append(lines, c)
+			}
+		}
+		if change.After == change.Before && len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n%s %d → %d (%s)\n", strings.ToUpper(stat[:1])+stat[1:], change.Before, change.After, signed(change.After-change.Before))
+		for _, c := range lines {
+			fmt.Fprintf(&sb, "  %s%5s%s  %s\n", pointsColor(c.Points), signed(c.Points), colorReset, contributionLabel(c))
+		}
+	}
+
+	ev := e.Evolution
+	sb.WriteString("\n")
+	if ev.From != "" && ev.From != ev.To {
+		fmt.Fprintf(&sb, "Evolution: %s → %s\n", ev.From, ev.To)
+	} else {
+		fmt.Fprintf(&sb, "Evolution: %s\n", ev.To)
+	}
+	if ev.Reason != "" {
+		fmt.Fprintf(&sb, "  %s%s%s\n", colorDim, ev.Reason, colorReset)
+		return sb.String()
+	}
+	for _, s := range ev.Scores {
+		marker, color := "  ", colorDim
+		if s.Evolution == ev.To {
+			marker, color = "▸ ", colorBold
+		}
+		var parts []string
+		for _, p := range s.Parts {
+			parts = append(parts, contributionLabel(p))
+		}
+		fmt.Fprintf(&sb, "%s%s%-9s %3d%s", color, marker, s.Evolution, s.Score, colorReset)
+		if len(parts) > 0 {
+			fmt.Fprintf(&sb, "  %s%s%s", colorDim, strings.Join(parts, ", "), colorReset)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(colorDim + "  The highest score wins; ties go to the one listed first." + colorReset + "\n")
+	return sb.String()
+}
+
+// contributionLabel reads like "merged pull requests (2 × 5)".
+func contributionLabel(c Contribution) string {
+	switch {
+	case c.Weight != 0:
+		return fmt.Sprintf("%s (%d × %d)", c.Source, c.Count, c.Weight)
+	case c.Count != 0:
+		return fmt.Sprintf("%s (%d)", c.Source, c.Count)
+	}
+	return c.Source
+}
+
+func signed(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprint(n)
+}
+
+func pointsColor(n int) string {
+	if n < 0 {
+		return colorRed
+	}
+	return colorGreen
+}
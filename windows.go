@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// usePowerShell reports whether the prompt should be installed into a
+// PowerShell profile: always on native Windows shells, and anywhere pwsh is
+// the running shell (it sets PSModulePath but not SHELL).
+func usePowerShell() bool {
+	if os.Getenv("SHELL") != "" {
+		return false
+	}
+	return runtime.GOOS == "windows" || os.Getenv("PSModulePath") != ""
+}
+
+// powerShellProfile asks PowerShell for $PROFILE, preferring PowerShell 7.
+func powerShellProfile() (string, error) {
+	for _, shell := range []string{"pwsh", "powershell"} {
+		out, err := exec.Command(shell, "-NoProfile", "-Command", "$PROFILE").Output()
+		if err == nil && strings.TrimSpace(string(out)) != "" {
+			return strings.TrimSpace(string(out)), nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1"), nil
+}
+
+func powerShellPromptSnippet(exePath string) string {
+	return fmt.Sprintf(`
+# GitPet prompt — shows pet status in your terminal
+$global:GitPetOriginalPrompt = $function:prompt
+function global:prompt {
+  $pet = & '%s' prompt 2>$null
+  $rest = & $global:GitPetOriginalPrompt
+  if ($pet) { "$pet $rest" } else { $rest }
+}
+# End GitPet prompt
+`, strings.ReplaceAll(exePath, "'", "''"))
+}
+
+// Hook script variants. Git always runs hooks through sh, including Git for
+// Windows, so the powershell and cmd variants are a small sh shim that hands
+// off to a sibling script the user can edit natively.
+const (
+	hookShellSh         = "sh"
+	hookShellPowerShell = "powershell"
+	hookShellCmd        = "cmd"
+)
+
+// Git hooks GitPet can install, with what each one does for the hook header.
+var hookPurposes = map[string]string{
+	"post-commit": "auto-feed & show status",
+	"commit-msg":  "grade the commit message",
+	"pre-commit":  "check staged changes",
+}
+
+// hookTakesFile reports whether git passes the hook a file path (the message
+// file for commit-msg) that must be forwarded to GitPet.
+func hookTakesFile(hook string) bool {
+	return hook == "commit-msg"
+}
+
+// hookScripts returns the hook body plus any sibling script (keyed by file
+// name) for the chosen shell. flags are passed through to the GitPet command.
+func hookScripts(shell, hook, exePath string, flags []string) (string, map[string]string, error) {
+	purpose, ok := hookPurposes[hook]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown hook %q (%s)", hook, strings.Join(knownHooks(), ", "))
+	}
+	header := fmt.Sprintf("# GitPet %s hook — %s", hook, purpose)
+	sibling := "gitpet-" + hook
+	args := strings.Join(append([]string{hook}, flags...), " ")
+	shArg, psArg, cmdArg := "", "", ""
+	if hookTakesFile(hook) {
+		shArg, psArg, cmdArg = ` "$1"`, " $args[0]", " %1"
+	}
+	switch shell {
+	case hookShellSh:
+		return fmt.Sprintf(`#!/usr/bin/env bash
+%s
+"%s" %s%s
+# End GitPet hook
+`, header, filepath.ToSlash(exePath), args, shArg), nil, nil
+	case hookShellPowerShell:
+		return fmt.Sprintf(`#!/bin/sh
+%s
+powershell.exe -NoProfile -ExecutionPolicy Bypass -File "$(dirname "$0")/%s.ps1"%s
+# End GitPet hook
+`, header, sibling, shArg), map[string]string{
+			sibling + ".ps1": fmt.Sprintf("# GitPet %s hook (PowerShell)\r\n& '%s' %s%s\r\nexit $LASTEXITCODE\r\n", hook, strings.ReplaceAll(exePath, "'", "''"), args, psArg),
+		}, nil
+	case hookShellCmd:
+		return fmt.Sprintf(`#!/bin/sh
+%s
+cmd.exe //c "$(dirname "$0")/%s.cmd"%s
+# End GitPet hook
+`, header, sibling, shArg), map[string]string{
+			sibling + ".cmd": fmt.Sprintf("@echo off\r\nrem GitPet %s hook (cmd)\r\n\"%s\" %s%s\r\n", hook, exePath, args, cmdArg),
+		}, nil
+	default:
+		return "", nil, fmt.Errorf("unknown hook shell %q (sh, powershell, cmd)", shell)
+	}
+}
+
+func knownHooks() []string {
+	hooks := make([]string, 0, len(hookPurposes))
+	for hook := range hookPurposes {
+		hooks = append(hooks, hook)
+	}
+	sort.Strings(hooks)
+	return hooks
+}
+
+func installPowerShellPrompt(exePath string) error {
+	profile, err := powerShellProfile()
+	if err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(profile); err == nil && strings.Contains(string(data), "GitPet prompt") {
+		fmt.Printf("%s✓ GitPet prompt already installed in %s%s\n", colorGreen, profile, colorReset)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(profile), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(profile, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(powerShellPromptSnippet(exePath)); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ GitPet prompt installed in %s%s\n", colorGreen, profile, colorReset)
+	fmt.Println("  GitPet will show at the start of your PowerShell prompt")
+	fmt.Println("  Run: . $PROFILE")
+	return nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitpet/gh-pet/internal/gh"
+	"github.com/gitpet/gh-pet/internal/i18n"
+)
+
+// WIPConfig sets how long work may sit before the pet mentions it. A zero
+// turns that reminder off.
+type WIPConfig struct {
+	StashDays    int `json:"stash_days"`
+	UnpushedDays int `json:"unpushed_days"`
+	DirtyHours   int `json:"dirty_hours"`
+}
+
+func defaultWIP() WIPConfig {
+	return WIPConfig{StashDays: 7, UnpushedDays: 3, DirtyHours: 24}
+}
+
+func (w WIPConfig) validate() error {
+	if w.StashDays < 0 || w.UnpushedDays < 0 || w.DirtyHours < 0 {
+		return fmt.Errorf("wip.stash_days, wip.unpushed_days, and wip.dirty_hours must not be negative")
+	}
+	return nil
+}
+
+// wipItem is one piece of forgotten work: an old stash, a branch nobody else
+// has seen, or changes left uncommitted.
+type wipItem struct {
+	Repo   string
+	Kind   string
+	Detail string
+	Age    time.Duration
+}
+
+// reminder is how the pet brings the item up.
+func (w wipItem) reminder() string {
+	repo := filepath.Base(w.Repo)
+	switch w.Kind {
+	case "stash":
+		return i18n.Tr("📦 %s: a stash from %s ago, %q. Still need it?", repo, i18n.ShortAge(w.Age), w.Detail)
+	case "branch":
+		return i18n.Tr("🌱 %s: %s hasn't been pushed for %s.", repo, w.Detail, i18n.ShortAge(w.Age))
+	default:
+		return i18n.Tr("✏️  %s: changes left uncommitted for %s.", repo, i18n.ShortAge(w.Age))
+	}
+}
+
+// forgottenWork checks each repo for work older than cfg allows, oldest
+// first. Repos that can't be read are skipped.
+func forgottenWork(ctx context.Context, repos []string, cfg WIPConfig, now time.Time) []wipItem {
+	var items []wipItem
+	for _, repo := range repos {
+		if cfg.StashDays > 0 {
+			items = append(items, oldStashes(ctx, repo, days(cfg.StashDays), now)...)
+		}
+		if cfg.UnpushedDays > 0 {
+			items = append(items, unpushedBranches(ctx, repo, days(cfg.UnpushedDays), now)...)
+		}
+		if cfg.DirtyHours > 0 {
+			if item, ok := staleChanges(ctx, repo, time.Duration(cfg.DirtyHours)*time.Hour, now); ok {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Age > items[j].Age })
+	return items
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+func oldStashes(ctx context.Context, repo string, limit time.Duration, now time.Time) []wipItem {
+	out, err := gh.GitOutput(ctx, "-C", repo, "stash", "list", "--format=%ct %gs")
+	if err != nil {
+		return nil
+	}
+	var items []wipItem
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		stamp, subject, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		age := now.Sub(unixTime(stamp))
+		if age > limit {
+			items = append(items, wipItem{Repo: repo, Kind: "stash", Detail: truncateWidth(subject, 40), Age: age})
+		}
+	}
+	return items
+}
+
+// unpushedBranches are local branches not yet merged into the default
+// branch whose commits never reached their upstream, or have no upstream.
+func unpushedBranches(ctx context.Context, repo string, limit time.Duration, now time.Time) []wipItem {
+	args := []string{"-C", repo, "for-each-ref", "--format=%(committerdate:unix)\t%(refname:short)\t%(upstream)\t%(upstream:track)"}
+	if base := defaultBranch(ctx, repo); base != "" {
+		if _, err := gh.GitOutput(ctx, "-C", repo, "rev-parse", "--verify", "--quiet", "refs/heads/"+base); err == nil {
+			args = append(args, "--no-merged="+base)
+		}
+	}
+	out, err := gh.GitOutput(ctx, append(args, "refs/heads")...)
+	if err != nil {
+		return nil
+	}
+	var items []wipItem
+	// Lines end in empty fields for branches without an upstream, so they
+	// mustn't be trimmed.
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		upstream, track := fields[2], fields[3]
+		if upstream != "" && !strings.Contains(track, "ahead") {
+			continue
+		}
+		if age := now.Sub(unixTime(fields[0])); age > limit {
+			items = append(items, wipItem{Repo: repo, Kind: "branch", Detail: fields[1], Age: age})
+		}
+	}
+	return items
+}
+
+// staleChanges reports uncommitted changes to tracked files when the oldest
+// of them was last touched more than limit ago.
+func staleChanges(ctx context.Context, repo string, limit time.Duration, now time.Time) (wipItem, bool) {
+	out, err := gh.GitOutput(ctx, "-C", repo, "status", "--porcelain", "-z", "--untracked-files=no")
+	if err != nil || len(out) == 0 {
+		return wipItem{}, false
+	}
+	var oldest time.Time
+	files := 0
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		// A rename is followed by its old path, which is no longer on disk.
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+		files++
+		if info, err := os.Stat(filepath.Join(repo, entry[3:])); err == nil && (oldest.IsZero() || info.ModTime().Before(oldest)) {
+			oldest = info.ModTime()
+		}
+	}
+	if oldest.IsZero() || now.Sub(oldest) <= limit {
+		return wipItem{}, false
+	}
+	return wipItem{Repo: repo, Kind: "dirty", Detail: i18n.Plural(files, "file"), Age: now.Sub(oldest)}, true
+}
+
+func unixTime(s string) time.Time {
+	sec, _ := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return time.Unix(sec, 0)
+}
+
+// wipReminders is the status card's short list of forgotten work across
+// every repo GitPet knows.
+func wipReminders(cfg WIPConfig, limit int) []string {
+	items := forgottenWork(context.Background(), hookRepos(), cfg, time.Now())
+	var lines []string
+	for _, item := range items[:min(len(items), limit)] {
+		lines = append(lines, item.reminder())
+	}
+	if extra := len(items) - limit; extra > 0 {
+		lines = append(lines, i18n.Tr("…and %d more. See gh pet wip.", extra))
+	}
+	return lines
+}
+
+func runWIP() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	repos := hookRepos()
+	items := forgottenWork(context.Background(), repos, cfg.WIP, time.Now())
+	if len(items) == 0 {
+		fmt.Printf("Nothing forgotten across %s. Tidy!\n", i18n.Plural(len(repos), "repo"))
+		return nil
+	}
+	byRepo := map[string][]wipItem{}
+	var order []string
+	for _, item := range items {
+		if _, ok := byRepo[item.Repo]; !ok {
+			order = append(order, item.Repo)
+		}
+		byRepo[item.Repo] = append(byRepo[item.Repo], item)
+	}
+	sort.Strings(order)
+	for _, repo := range order {
+		fmt.Printf("\n%s%s%s\n", colorBold, repo, colorReset)
+		for _, item := range byRepo[repo] {
+			switch item.Kind {
+			case "stash":
+				fmt.Printf("  📦 stash %q, %s old\n", item.Detail, i18n.ShortAge(item.Age))
+			case "branch":
+				fmt.Printf("  🌱 branch %s, unpushed for %s\n", item.Detail, i18n.ShortAge(item.Age))
+			default:
+				fmt.Printf("  ✏️  %s uncommitted for %s\n", item.Detail, i18n.ShortAge(item.Age))
+			}
+		}
+	}
+	fmt.Printf("\n%sThresholds live under \"wip\" in the config: stashes after %dd, unpushed branches after %dd, changes after %dh.%s\n",
+		colorDim, cfg.WIP.StashDays, cfg.WIP.UnpushedDays, cfg.WIP.DirtyHours, colorReset)
+	return nil
+}
+
+// postCommitReminder is the oldest forgotten work in the repo just committed
+// to, or "". Only this repo is checked, to keep the hook quick.
+func postCommitReminder(ctx context.Context, cfg WIPConfig) string {
+	out, err := gh.GitOutput(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	items := forgottenWork(ctx, []string{strings.TrimSpace(string(out))}, cfg, time.Now())
+	if len(items) == 0 {
+		return ""
+	}
+	return items[0].reminder()
+}